@@ -1,4 +1,4 @@
-import React, { useEffect, lazy, Suspense } from 'react';
+import React, { useEffect, useRef, lazy, Suspense } from 'react';
 import { Switch, Route } from 'react-router-dom';
 import { useAuthorizer } from '@authorizerdev/authorizer-react';
 import styled, { ThemeProvider } from 'styled-components';
@@ -10,6 +10,7 @@ const ResetPassword = lazy(() => import('./pages/rest-password'));
 const Login = lazy(() => import('./pages/login'));
 const Dashboard = lazy(() => import('./pages/dashboard'));
 const SignUp = lazy(() => import('./pages/signup'));
+const Device = lazy(() => import('./pages/device'));
 
 const Wrapper = styled.div`
 	font-family: ${(props) => props.theme.fonts.fontStack};
@@ -40,12 +41,27 @@ export default function Root({
 		: ['openid', 'profile', 'email'];
 	const code = searchParams.get('code') || '';
 	const nonce = searchParams.get('nonce') || '';
+	const loginHint = searchParams.get('login_hint') || '';
+	// prompt=login is sent by /authorize when user needs to re-authenticate,
+	// token of existing session is not used till user logs in again
+	const isReauthenticationRequired = searchParams.get('prompt') === 'login';
+	const sessionToken = useRef<typeof token | undefined>(undefined);
+	if (!loading && sessionToken.current === undefined) {
+		sessionToken.current = token;
+	}
+	const isLoggedIn =
+		!!token && !(isReauthenticationRequired && token === sessionToken.current);
 
 	const urlProps: Record<string, any> = {
 		state,
 		scope,
 	};
 
+	// login_hint sent to /authorize is used to pre-fill the login form
+	if (loginHint) {
+		urlProps.login_hint = loginHint;
+	}
+
 	const redirectURL =
 		searchParams.get('redirect_uri') || searchParams.get('redirectURL');
 	if (redirectURL) {
@@ -57,7 +73,7 @@ export default function Root({
 	urlProps.redirect_uri = urlProps.redirectURL;
 
 	useEffect(() => {
-		if (token) {
+		if (isLoggedIn && token) {
 			let redirectURL = config.redirectURL || '/app';
 			// let params = `access_token=${token.access_token}&id_token=${token.id_token}&expires_in=${token.expires_in}&state=${globalState.state}`;
 			// Note: If OIDC breaks in the future, use the above params
@@ -82,25 +98,34 @@ export default function Root({
 				redirectURL = `${redirectURL}?${params}`;
 			}
 
-			if (url.origin !== window.location.origin) {
+			// authorization request is resumed via /authorize after login, e.g. to ask for consent
+			// and saml authentication request is resumed via /saml/sso
+			if (
+				url.origin !== window.location.origin ||
+				url.pathname === '/authorize' ||
+				url.pathname === '/saml/sso'
+			) {
 				sessionStorage.removeItem('authorizer_state');
 				window.location.replace(redirectURL);
 			}
 		}
 		return () => {};
-	}, [token, config]);
+	}, [token, config, isLoggedIn]);
 
 	if (loading) {
 		return <h1>Loading...</h1>;
 	}
 
-	if (token) {
+	if (isLoggedIn) {
 		return (
 			<Suspense fallback={<></>}>
 				<Switch>
 					<Route path="/app" exact>
 						<Dashboard />
 					</Route>
+					<Route path="/app/device">
+						<Device />
+					</Route>
 				</Switch>
 			</Suspense>
 		);
@@ -114,6 +139,9 @@ export default function Root({
 						<Route path="/app" exact>
 							<Login urlProps={urlProps} />
 						</Route>
+						<Route path="/app/device">
+							<Login urlProps={urlProps} />
+						</Route>
 						<Route path="/app/signup">
 							<SignUp urlProps={urlProps} />
 						</Route>
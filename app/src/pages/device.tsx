@@ -0,0 +1,105 @@
+import React from 'react';
+import { useAuthorizer } from '@authorizerdev/authorizer-react';
+import { hasWindow } from '../utils/common';
+
+const authorizeDeviceMutation = `
+	mutation authorizeDevice($params: AuthorizeDeviceRequest!) {
+		authorize_device(params: $params) {
+			message
+		}
+	}
+`;
+
+export default function Device() {
+	const { token, config } = useAuthorizer();
+	const searchParams = new URLSearchParams(
+		hasWindow() ? window.location.search : ``,
+	);
+	const [userCode, setUserCode] = React.useState(
+		searchParams.get('user_code') || '',
+	);
+	const [loading, setLoading] = React.useState(false);
+	const [message, setMessage] = React.useState('');
+	const [error, setError] = React.useState('');
+
+	const onSubmit = async (deny: boolean) => {
+		setLoading(true);
+		setMessage('');
+		setError('');
+		try {
+			const res = await fetch(`${config.authorizerURL}/graphql`, {
+				method: 'POST',
+				credentials: 'include',
+				headers: {
+					'Content-Type': 'application/json',
+					Authorization: `Bearer ${token?.access_token}`,
+				},
+				body: JSON.stringify({
+					query: authorizeDeviceMutation,
+					variables: {
+						params: {
+							user_code: userCode,
+							deny,
+						},
+					},
+				}),
+			});
+			const json = await res.json();
+			if (json.errors && json.errors.length) {
+				setError(json.errors[0].message);
+			} else {
+				setMessage(json.data.authorize_device.message);
+			}
+		} catch (err: any) {
+			setError(err.message || 'Failed to authorize device');
+		}
+		setLoading(false);
+	};
+
+	return (
+		<div>
+			<h1>Connect a device</h1>
+			<p>Enter the code displayed on your device.</p>
+			<input
+				type="text"
+				value={userCode}
+				placeholder="XXXX-XXXX"
+				onChange={(e) => setUserCode(e.target.value)}
+				style={{ padding: 8, fontSize: 16, textTransform: 'uppercase' }}
+			/>
+			<br />
+			<br />
+			{message && <p style={{ color: '#10B981' }}>{message}</p>}
+			{error && <p style={{ color: '#EF4444' }}>{error}</p>}
+			{loading ? (
+				<h3>Processing....</h3>
+			) : (
+				!message && (
+					<div>
+						<h3
+							style={{
+								color: '#3B82F6',
+								cursor: 'pointer',
+								display: 'inline-block',
+								marginRight: 24,
+							}}
+							onClick={() => onSubmit(false)}
+						>
+							Allow
+						</h3>
+						<h3
+							style={{
+								color: '#EF4444',
+								cursor: 'pointer',
+								display: 'inline-block',
+							}}
+							onClick={() => onSubmit(true)}
+						>
+							Deny
+						</h3>
+					</div>
+				)
+			)}
+		</div>
+	);
+}
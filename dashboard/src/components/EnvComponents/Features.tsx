@@ -187,6 +187,22 @@ const Features = ({ variables, setVariables }: any) => {
 						/>
 					</Flex>
 				</Flex>
+				<Flex>
+					<Flex w="100%" alignItems="baseline" flexDir="column">
+						<Text fontSize="sm">Refresh Token Reuse Detection:</Text>
+						<Text fontSize="x-small">
+							Note: Reusing a rotated refresh token will revoke the whole session
+						</Text>
+					</Flex>
+					<Flex justifyContent="start">
+						<InputField
+							variables={variables}
+							setVariables={setVariables}
+							inputType={SwitchInputType.DISABLE_REFRESH_TOKEN_REUSE_DETECTION}
+							hasReversedValue
+						/>
+					</Flex>
+				</Flex>
 			</Stack>
 			<Divider paddingY={5} />
 			<Text fontSize="md" paddingTop={5} fontWeight="bold" mb={5}>
@@ -94,6 +94,7 @@ export const SwitchInputType = {
 	DISABLE_PLAYGROUND: 'DISABLE_PLAYGROUND',
 	DISABLE_TOTP_LOGIN: 'DISABLE_TOTP_LOGIN',
 	DISABLE_MAIL_OTP_LOGIN: 'DISABLE_MAIL_OTP_LOGIN',
+	DISABLE_REFRESH_TOKEN_REUSE_DETECTION: 'DISABLE_REFRESH_TOKEN_REUSE_DETECTION',
 };
 
 export const DateInputType = {
@@ -187,6 +188,7 @@ export interface envVarTypes {
 	DISABLE_PLAYGROUND: boolean;
 	DISABLE_TOTP_LOGIN: boolean;
 	DISABLE_MAIL_OTP_LOGIN: boolean;
+	DISABLE_REFRESH_TOKEN_REUSE_DETECTION: boolean;
 }
 
 export const envSubViews = {
@@ -241,6 +243,7 @@ export const webhookEventNames = {
 	'User access enabled': 'user.access_enabled',
 	'User access revoked': 'user.access_revoked',
 	'User deactivated': 'user.deactivated',
+	'Refresh token reused': 'user.refresh_token_reused',
 };
 
 export const emailTemplateEventNames = {
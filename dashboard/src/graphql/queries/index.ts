@@ -83,6 +83,7 @@ export const EnvVariablesQuery = `
       DISABLE_PLAYGROUND
       DISABLE_TOTP_LOGIN
       DISABLE_MAIL_OTP_LOGIN
+      DISABLE_REFRESH_TOKEN_REUSE_DETECTION
     }
   }
 `;
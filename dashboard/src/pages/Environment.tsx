@@ -103,6 +103,7 @@ const Environment = () => {
 		DISABLE_PLAYGROUND: false,
 		DISABLE_TOTP_LOGIN: false,
 		DISABLE_MAIL_OTP_LOGIN: true,
+		DISABLE_REFRESH_TOKEN_REUSE_DETECTION: false,
 	});
 
 	const [fieldVisibility, setFieldVisibility] = React.useState<
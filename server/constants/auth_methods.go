@@ -29,4 +29,6 @@ const (
 	AuthRecipeMethodTwitch = "twitch"
 	// AuthRecipeMethodRoblox is the roblox auth method
 	AuthRecipeMethodRoblox = "roblox"
+	// AuthRecipeMethodLDAP is the ldap auth method, where user is authenticated by ldap directory
+	AuthRecipeMethodLDAP = "ldap"
 )
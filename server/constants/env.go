@@ -15,6 +15,21 @@ const (
 	EnvKeyPort = "PORT"
 	// EnvKeyAccessTokenExpiryTime key for env variable ACCESS_TOKEN_EXPIRY_TIME
 	EnvKeyAccessTokenExpiryTime = "ACCESS_TOKEN_EXPIRY_TIME"
+	// EnvKeyRefreshTokenExpiryTime key for env variable REFRESH_TOKEN_EXPIRY_TIME
+	// It is the default refresh token lifetime, used when it is not configured for client
+	EnvKeyRefreshTokenExpiryTime = "REFRESH_TOKEN_EXPIRY_TIME"
+	// EnvKeySessionExpiryTime key for env variable SESSION_EXPIRY_TIME
+	// It is the lifetime of browser session, session is extended when it is rolled over
+	EnvKeySessionExpiryTime = "SESSION_EXPIRY_TIME"
+	// EnvKeySessionIdleTimeout key for env variable SESSION_IDLE_TIMEOUT
+	// When set, browser session expires after inactivity of given duration and is extended on each use
+	EnvKeySessionIdleTimeout = "SESSION_IDLE_TIMEOUT"
+	// EnvKeySessionMaxAge key for env variable SESSION_MAX_AGE
+	// When set, browser session & refresh tokens expire after given duration from authentication,
+	// irrespective of session being extended or refresh tokens being rotated
+	EnvKeySessionMaxAge = "SESSION_MAX_AGE"
+	// EnvKeyOTPExpiryTime key for env variable OTP_EXPIRY_TIME
+	EnvKeyOTPExpiryTime = "OTP_EXPIRY_TIME"
 	// EnvKeyAdminSecret key for env variable ADMIN_SECRET
 	EnvKeyAdminSecret = "ADMIN_SECRET"
 	// EnvKeyDatabaseType key for env variable DATABASE_TYPE
@@ -146,6 +161,19 @@ const (
 	EnvKeyEncryptionKey = "ENCRYPTION_KEY"
 	// EnvKeyJWK key for env variable JWK
 	EnvKeyJWK = "JWK"
+	// EnvKeyJwtKeyring key for env variable JWT_KEYRING
+	// It holds the signing keys used for key rotation and is not exposed via env api
+	EnvKeyJwtKeyring = "JWT_KEYRING"
+	// EnvKeyPairwiseSubjectSalt key for env variable PAIRWISE_SUBJECT_SALT
+	// It is used for calculating pairwise subject identifiers and is generated once per instance,
+	// changing it changes the sub claim of all the pairwise clients
+	EnvKeyPairwiseSubjectSalt = "PAIRWISE_SUBJECT_SALT"
+	// EnvKeySAMLIdPPrivateKey key for env variable SAML_IDP_PRIVATE_KEY
+	// It is the RSA private key used for signing saml assertions, generated once per instance when not set
+	EnvKeySAMLIdPPrivateKey = "SAML_IDP_PRIVATE_KEY"
+	// EnvKeySAMLIdPCertificate key for env variable SAML_IDP_CERTIFICATE
+	// It is the x509 certificate of SAML_IDP_PRIVATE_KEY published in saml metadata
+	EnvKeySAMLIdPCertificate = "SAML_IDP_CERTIFICATE"
 
 	// Boolean variables
 	// EnvKeyIsProd key for env variable IS_PROD
@@ -184,6 +212,9 @@ const (
 	// EnvKeyDisablePlayGround is key for env variable DISABLE_PLAYGROUND
 	// this variable will disable or enable playground use in dashboard
 	EnvKeyDisablePlayGround = "DISABLE_PLAYGROUND"
+	// EnvKeyDisableRefreshTokenReuseDetection is key for env variable DISABLE_REFRESH_TOKEN_REUSE_DETECTION
+	// this variable is used to disable revoking the refresh token family when rotated refresh token is reused
+	EnvKeyDisableRefreshTokenReuseDetection = "DISABLE_REFRESH_TOKEN_REUSE_DETECTION"
 
 	// Slice variables
 	// EnvKeyRoles key for env variable ROLES
@@ -202,6 +233,10 @@ const (
 	// EnvKeyDefaultAuthorizeResponseMode key for env variable DEFAULT_AUTHORIZE_RESPONSE_MODE
 	// This env is used for setting default response mode in authorize handler
 	EnvKeyDefaultAuthorizeResponseMode = "DEFAULT_AUTHORIZE_RESPONSE_MODE"
+	// EnvKeyClientRegistrationInitialAccessToken key for env variable CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN
+	// This env is used for protecting dynamic client registration endpoint, comma separated values are supported.
+	// Dynamic client registration is disabled when it is not set
+	EnvKeyClientRegistrationInitialAccessToken = "CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN"
 
 	// Twilio env variables
 	// EnvKeyTwilioAPIKey key for env variable TWILIO_API_KEY
@@ -212,4 +247,28 @@ const (
 	EnvKeyTwilioAccountSID = "TWILIO_ACCOUNT_SID"
 	// EnvKeyTwilioSender key for env variable TWILIO_SENDER
 	EnvKeyTwilioSender = "TWILIO_SENDER"
+
+	// LDAP env variables
+	// EnvKeyLDAPURL key for env variable LDAP_URL
+	// e.g. ldap://ldap.example.com:389 or ldaps://ldap.example.com:636, ldap authentication is enabled when it is set
+	EnvKeyLDAPURL = "LDAP_URL"
+	// EnvKeyLDAPBindDN key for env variable LDAP_BIND_DN
+	// It is the dn of service account used for searching users
+	EnvKeyLDAPBindDN = "LDAP_BIND_DN"
+	// EnvKeyLDAPBindPassword key for env variable LDAP_BIND_PASSWORD
+	EnvKeyLDAPBindPassword = "LDAP_BIND_PASSWORD"
+	// EnvKeyLDAPSearchBase key for env variable LDAP_SEARCH_BASE
+	EnvKeyLDAPSearchBase = "LDAP_SEARCH_BASE"
+	// EnvKeyLDAPUserFilter key for env variable LDAP_USER_FILTER
+	// {email} in filter is replaced with the email used for login, defaults to (mail={email})
+	EnvKeyLDAPUserFilter = "LDAP_USER_FILTER"
+	// EnvKeyLDAPGroupRoleMapping key for env variable LDAP_GROUP_ROLE_MAPPING
+	// It is json object of group dn to role, groups of user are read from memberOf attribute
+	EnvKeyLDAPGroupRoleMapping = "LDAP_GROUP_ROLE_MAPPING"
+	// EnvKeyLDAPTLSCACertificate key for env variable LDAP_TLS_CA_CERTIFICATE
+	// It is the pem encoded certificate of CA used by ldap server, system CAs are used when it is not set
+	EnvKeyLDAPTLSCACertificate = "LDAP_TLS_CA_CERTIFICATE"
+	// EnvKeyLDAPStartTLS key for env variable LDAP_START_TLS
+	// It is used for upgrading ldap:// connection to TLS
+	EnvKeyLDAPStartTLS = "LDAP_START_TLS"
 )
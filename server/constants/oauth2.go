@@ -9,6 +9,14 @@ const (
 	ResponseModeFormPost = "form_post"
 	// - web_message: For Silent Authentication. Uses HTML5 web messaging.
 	ResponseModeWebMessage = "web_message"
+	// - query.jwt: query response mode with response params wrapped in signed jwt (JARM).
+	ResponseModeQueryJWT = "query.jwt"
+	// - fragment.jwt: fragment response mode with response params wrapped in signed jwt (JARM).
+	ResponseModeFragmentJWT = "fragment.jwt"
+	// - form_post.jwt: form_post response mode with response params wrapped in signed jwt (JARM).
+	ResponseModeFormPostJWT = "form_post.jwt"
+	// - jwt: query.jwt for Authorization Code grant and fragment.jwt for Implicit grant (JARM).
+	ResponseModeJWT = "jwt"
 
 	// For the Authorization Code grant, use response_type=code to include the authorization code.
 	ResponseTypeCode = "code"
@@ -17,6 +25,46 @@ const (
 	// For the Implicit grant of id_token, use response_type=id_token to include an identifier token.
 	ResponseTypeIDToken = "id_token"
 
+	// GrantTypeAuthorizationCode is the authorization_code grant used to exchange code for tokens
+	GrantTypeAuthorizationCode = "authorization_code"
+	// GrantTypeRefreshToken is the refresh_token grant used to obtain new tokens using refresh token
+	GrantTypeRefreshToken = "refresh_token"
+	// GrantTypeClientCredentials is the client_credentials grant used for machine to machine communication
+	GrantTypeClientCredentials = "client_credentials"
+	// GrantTypeDeviceCode is the device_code grant used by devices with limited input capabilities
+	GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+	// GrantTypeTokenExchange is the token exchange grant used for delegation & impersonation (RFC 8693)
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// TokenEndpointAuthMethodClientSecretBasic is the client authentication using client secret in basic auth header
+	TokenEndpointAuthMethodClientSecretBasic = "client_secret_basic"
+	// TokenEndpointAuthMethodClientSecretPost is the client authentication using client secret in request body
+	TokenEndpointAuthMethodClientSecretPost = "client_secret_post"
+	// TokenEndpointAuthMethodClientSecretJWT is the client authentication using jwt signed with client secret (RFC 7523)
+	TokenEndpointAuthMethodClientSecretJWT = "client_secret_jwt"
+	// TokenEndpointAuthMethodPrivateKeyJWT is the client authentication using jwt signed with private key of client (RFC 7523)
+	TokenEndpointAuthMethodPrivateKeyJWT = "private_key_jwt"
+	// TokenEndpointAuthMethodNone is used by public clients which only identify themselves with client id
+	TokenEndpointAuthMethodNone = "none"
+	// ClientAssertionTypeJWTBearer is the client_assertion_type used with private_key_jwt client authentication
+	ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// SubjectTypePublic is the subject type of clients which get user id as sub claim
+	SubjectTypePublic = "public"
+	// SubjectTypePairwise is the subject type of clients which get different sub claim
+	// per sector identifier, so that user cannot be correlated across clients
+	SubjectTypePairwise = "pairwise"
+
 	// Constant indicating the "signup" screen hint for customizing authentication process and redirect to a signup page.
 	ScreenHintSignUp = "signup"
+
+	// PromptNone is used for silent authentication, login page is never shown
+	// and login_required error is returned if user is not logged in.
+	PromptNone = "none"
+	// PromptLogin forces user to re-authenticate even if user is already logged in.
+	PromptLogin = "login"
+	// PromptConsent asks user for consent before returning the response to client.
+	PromptConsent = "consent"
+	// PromptSelectAccount asks user to select an account.
+	PromptSelectAccount = "select_account"
 )
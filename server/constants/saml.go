@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// SAMLNameIDFormatEmail is the name id format in which email of user is the subject of assertion
+	SAMLNameIDFormatEmail = "email"
+	// SAMLNameIDFormatPersistent is the name id format in which id of user is the subject of assertion
+	SAMLNameIDFormatPersistent = "persistent"
+)
@@ -9,4 +9,7 @@ const (
 	TokenTypeIdentityToken = "id_token"
 	// TokenTypeSessionToken is the session_token type used for browser session
 	TokenTypeSessionToken = "session_token"
+
+	// TokenTypeIdentifierAccessToken is the token type identifier of access token used in token exchange (RFC 8693)
+	TokenTypeIdentifierAccessToken = "urn:ietf:params:oauth:token-type:access_token"
 )
@@ -17,4 +17,7 @@ const (
 	UserDeletedWebhookEvent = `user.deleted`
 	// UserDeactivatedWebhookEvent name for user deactivated event
 	UserDeactivatedWebhookEvent = `user.deactivated`
+	// UserRefreshTokenReusedWebhookEvent name for refresh token reuse event
+	// This is triggered when already rotated refresh token is used again
+	UserRefreshTokenReusedWebhookEvent = `user.refresh_token_reused`
 )
@@ -3,12 +3,14 @@ package cookie
 import (
 	"net/http"
 	"net/url"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -40,11 +42,11 @@ func SetSession(gc *gin.Context, sessionID string) {
 	} else {
 		gc.SetSameSite(http.SameSiteNoneMode)
 	}
-	// TODO allow configuring from dashboard
-	year := 60 * 60 * 24 * 365
+	// session validity is checked on server, cookie is kept for lifetime of session
+	maxAge := int(utils.GetDurationStoreEnvVariable(constants.EnvKeySessionExpiryTime, time.Hour*8760).Seconds())
 
-	gc.SetCookie(constants.AppCookieName+"_session", sessionID, year, "/", host, secure, httpOnly)
-	gc.SetCookie(constants.AppCookieName+"_session_domain", sessionID, year, "/", domain, secure, httpOnly)
+	gc.SetCookie(constants.AppCookieName+"_session", sessionID, maxAge, "/", host, secure, httpOnly)
+	gc.SetCookie(constants.AppCookieName+"_session_domain", sessionID, maxAge, "/", domain, secure, httpOnly)
 }
 
 // DeleteSession sets session cookies to expire
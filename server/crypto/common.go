@@ -3,6 +3,7 @@ package crypto
 import (
 	"crypto/x509"
 	"encoding/json"
+	"fmt"
 
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/memorystore"
@@ -45,6 +46,11 @@ func GenerateJWKBasedOnEnv() (string, error) {
 	if err != nil {
 		return jwk, err
 	}
+	// use key id of active key, if keyring is initialized
+	keyID := clientID
+	if activeKey, err := GetActiveSigningKey(); err == nil {
+		keyID = activeKey.KeyID
+	}
 
 	jwtSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtSecret)
 	if err != nil {
@@ -53,7 +59,7 @@ func GenerateJWKBasedOnEnv() (string, error) {
 
 	// check if jwt secret is provided
 	if IsHMACA(algo) {
-		jwk, err = GetPubJWK(algo, clientID, []byte(jwtSecret))
+		jwk, err = GetPubJWK(algo, keyID, []byte(jwtSecret))
 		if err != nil {
 			return "", err
 		}
@@ -70,7 +76,7 @@ func GenerateJWKBasedOnEnv() (string, error) {
 			return "", err
 		}
 
-		jwk, err = GetPubJWK(algo, clientID, publicKeyInstance)
+		jwk, err = GetPubJWK(algo, keyID, publicKeyInstance)
 		if err != nil {
 			return "", err
 		}
@@ -86,7 +92,7 @@ func GenerateJWKBasedOnEnv() (string, error) {
 			return "", err
 		}
 
-		jwk, err = GetPubJWK(algo, clientID, publicKeyInstance)
+		jwk, err = GetPubJWK(algo, keyID, publicKeyInstance)
 		if err != nil {
 			return "", err
 		}
@@ -125,6 +131,36 @@ func EncryptEnvData(data map[string]interface{}) (string, error) {
 	return EncryptB64(string(encryptedConfig)), nil
 }
 
+// EncryptClientSecret is used for encrypting client secret.
+// Secret of clients using client_secret_jwt is encrypted with encryption key, as it is required
+// for verifying client assertion signed with it. Secret of other clients is hashed
+func EncryptClientSecret(tokenEndpointAuthMethod, clientSecret string) (string, error) {
+	if tokenEndpointAuthMethod != constants.TokenEndpointAuthMethodClientSecretJWT {
+		return EncryptPassword(clientSecret)
+	}
+	encryptedClientSecret, err := EncryptAESEnv([]byte(clientSecret))
+	if err != nil {
+		return "", err
+	}
+	return EncryptB64(string(encryptedClientSecret)), nil
+}
+
+// DecryptClientSecret is used for decrypting secret of clients using client_secret_jwt
+func DecryptClientSecret(encryptedClientSecret string) (string, error) {
+	data, err := DecryptB64(encryptedClientSecret)
+	if err != nil {
+		return "", err
+	}
+	clientSecret, err := DecryptAESEnv([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	if len(clientSecret) == 0 {
+		return "", fmt.Errorf("invalid client secret")
+	}
+	return string(clientSecret), nil
+}
+
 // EncryptPassword is used for encrypting password
 func EncryptPassword(password string) (string, error) {
 	pw, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -0,0 +1,347 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// SigningKeyStatusNext is the status of key which is published in JWKS,
+	// but not yet used for signing tokens
+	SigningKeyStatusNext = "next"
+	// SigningKeyStatusActive is the status of key used for signing tokens
+	SigningKeyStatusActive = "active"
+	// SigningKeyStatusRetired is the status of key which is no longer used for signing tokens,
+	// but is used for verifying tokens till it expires
+	SigningKeyStatusRetired = "retired"
+
+	// DefaultRetiredSigningKeyTTL is the duration for which retired keys are kept,
+	// it is same as the default lifetime of refresh token
+	DefaultRetiredSigningKeyTTL = time.Hour * 8760
+)
+
+// SigningKey is the key stored in JWT keyring
+// Secret is set for HMAC algorithms, PrivateKey & PublicKey are set for RSA & ECDSA algorithms
+type SigningKey struct {
+	KeyID       string `json:"kid"`
+	Algorithm   string `json:"alg"`
+	Secret      string `json:"secret,omitempty"`
+	PrivateKey  string `json:"private_key,omitempty"`
+	PublicKey   string `json:"public_key,omitempty"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"created_at"`
+	ActivatedAt int64  `json:"activated_at,omitempty"`
+	RetiredAt   int64  `json:"retired_at,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+}
+
+// IsExpired returns true if retired key has expired and cannot be used for verifying tokens
+func (k *SigningKey) IsExpired() bool {
+	return k.ExpiresAt != 0 && k.ExpiresAt < time.Now().Unix()
+}
+
+// GetVerificationKey returns the key used for verifying signature of tokens signed with this key
+func (k *SigningKey) GetVerificationKey() (interface{}, error) {
+	switch {
+	case IsHMACA(k.Algorithm):
+		return []byte(k.Secret), nil
+	case IsRSA(k.Algorithm):
+		return ParseRsaPublicKeyFromPemStr(k.PublicKey)
+	case IsECDSA(k.Algorithm):
+		return ParseEcdsaPublicKeyFromPemStr(k.PublicKey)
+	default:
+		return nil, errors.New("unsupported signing method")
+	}
+}
+
+// GetSigningKey returns the key used for signing tokens with this key
+func (k *SigningKey) GetSigningKey() (interface{}, error) {
+	switch {
+	case IsHMACA(k.Algorithm):
+		return []byte(k.Secret), nil
+	case IsRSA(k.Algorithm):
+		return ParseRsaPrivateKeyFromPemStr(k.PrivateKey)
+	case IsECDSA(k.Algorithm):
+		return ParseEcdsaPrivateKeyFromPemStr(k.PrivateKey)
+	default:
+		return nil, errors.New("unsupported signing method")
+	}
+}
+
+// GetJWK returns the public JWK of key
+func (k *SigningKey) GetJWK() (string, error) {
+	key, err := k.GetVerificationKey()
+	if err != nil {
+		return "", err
+	}
+	return GetPubJWK(k.Algorithm, k.KeyID, key)
+}
+
+// isSameKey returns true if both keys have same algorithm and key material
+func (k *SigningKey) isSameKey(key *SigningKey) bool {
+	return k.Algorithm == key.Algorithm && k.Secret == key.Secret && k.PrivateKey == key.PrivateKey && k.PublicKey == key.PublicKey
+}
+
+// NewSigningKey generates new signing key for given algorithm
+func NewSigningKey(algo, status string) (*SigningKey, error) {
+	key := &SigningKey{
+		KeyID:     uuid.New().String(),
+		Algorithm: algo,
+		Status:    status,
+		CreatedAt: time.Now().Unix(),
+	}
+	var err error
+	switch {
+	case IsHMACA(algo):
+		key.Secret, _, err = NewHMACKey(algo, key.KeyID)
+	case IsRSA(algo):
+		_, key.PrivateKey, key.PublicKey, _, err = NewRSAKey(algo, key.KeyID)
+	case IsECDSA(algo):
+		_, key.PrivateKey, key.PublicKey, _, err = NewECDSAKey(algo, key.KeyID)
+	default:
+		err = errors.New("unsupported signing method")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetKeyring returns the signing keys stored in env store
+func GetKeyring() ([]*SigningKey, error) {
+	keyring := []*SigningKey{}
+	data, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtKeyring)
+	if err != nil || data == "" {
+		return keyring, err
+	}
+	if err := json.Unmarshal([]byte(data), &keyring); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// SaveKeyring saves the signing keys in env store, expired keys are removed
+func SaveKeyring(keyring []*SigningKey) error {
+	keys := []*SigningKey{}
+	for _, key := range keyring {
+		if !key.IsExpired() {
+			keys = append(keys, key)
+		}
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtKeyring, string(data))
+}
+
+// GetActiveSigningKey returns the key used for signing tokens
+func GetActiveSigningKey() (*SigningKey, error) {
+	keyring, err := GetKeyring()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keyring {
+		if key.Status == SigningKeyStatusActive {
+			return key, nil
+		}
+	}
+	return nil, errors.New("active signing key not found")
+}
+
+// GetSigningKeyByID returns the non expired key for given key id
+func GetSigningKeyByID(keyID string) (*SigningKey, error) {
+	keyring, err := GetKeyring()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keyring {
+		if key.KeyID == keyID && !key.IsExpired() {
+			return key, nil
+		}
+	}
+	return nil, errors.New("signing key not found")
+}
+
+// GetJWKs returns the public JWKs of all the keys which can be used for verifying tokens.
+// HMAC keys are skipped, as JWK of symmetric key is the secret itself
+func GetJWKs() ([]map[string]interface{}, error) {
+	keyring, err := GetKeyring()
+	if err != nil {
+		return nil, err
+	}
+	jwks := []map[string]interface{}{}
+	for _, key := range keyring {
+		if key.IsExpired() || IsHMACA(key.Algorithm) {
+			continue
+		}
+		jwk, err := key.GetJWK()
+		if err != nil {
+			return nil, err
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(jwk), &data); err != nil {
+			return nil, err
+		}
+		jwks = append(jwks, data)
+	}
+	return jwks, nil
+}
+
+// getSigningKeyFromEnv returns the signing key configured via JWT_TYPE, JWT_SECRET,
+// JWT_PRIVATE_KEY & JWT_PUBLIC_KEY env
+func getSigningKeyFromEnv() (*SigningKey, error) {
+	algo, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+	if err != nil {
+		return nil, err
+	}
+	key := &SigningKey{
+		Algorithm: algo,
+	}
+	if IsHMACA(algo) {
+		key.Secret, err = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtSecret)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	key.PrivateKey, err = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	key.PublicKey, err = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// setSigningKeyInEnv sets the key material of active key in env,
+// so that env api reflects the key used for signing tokens
+func setSigningKeyInEnv(key *SigningKey) error {
+	if err := memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtType, key.Algorithm); err != nil {
+		return err
+	}
+	if IsHMACA(key.Algorithm) {
+		return memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtSecret, key.Secret)
+	}
+	if err := memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtPrivateKey, key.PrivateKey); err != nil {
+		return err
+	}
+	return memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtPublicKey, key.PublicKey)
+}
+
+// SyncKeyringWithEnv makes sure that key configured via env is the active key of keyring.
+// If env key is changed, previous active key is retired so that tokens signed with it
+// can be verified till retiredKeyTTL.
+// This is called while initializing app / when env is updated, it returns true if keyring is changed
+func SyncKeyringWithEnv(retiredKeyTTL time.Duration) (bool, error) {
+	envKey, err := getSigningKeyFromEnv()
+	if err != nil {
+		return false, err
+	}
+	keyring, err := GetKeyring()
+	if err != nil {
+		return false, err
+	}
+
+	var activeKey, nextKey *SigningKey
+	for _, key := range keyring {
+		switch key.Status {
+		case SigningKeyStatusActive:
+			activeKey = key
+		case SigningKeyStatusNext:
+			nextKey = key
+		}
+	}
+	if activeKey != nil && activeKey.isSameKey(envKey) {
+		return false, nil
+	}
+
+	now := time.Now().Unix()
+	if activeKey != nil {
+		activeKey.Status = SigningKeyStatusRetired
+		activeKey.RetiredAt = now
+		activeKey.ExpiresAt = now + int64(retiredKeyTTL.Seconds())
+	}
+	if nextKey != nil && nextKey.isSameKey(envKey) {
+		nextKey.Status = SigningKeyStatusActive
+		nextKey.ActivatedAt = now
+		return true, SaveKeyring(keyring)
+	}
+
+	envKey.KeyID = uuid.New().String()
+	if len(keyring) == 0 {
+		// keys issued before keyring was introduced used client id as key id
+		envKey.KeyID, err = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+		if err != nil {
+			return false, err
+		}
+	}
+	envKey.Status = SigningKeyStatusActive
+	envKey.CreatedAt = now
+	envKey.ActivatedAt = now
+	keyring = append(keyring, envKey)
+	return true, SaveKeyring(keyring)
+}
+
+// RotateKeyring activates the next key and retires the active key.
+// Retired key can be used for verifying tokens till retiredKeyTTL.
+// New next key is generated, so that it is published in JWKS before it is used for signing tokens
+func RotateKeyring(retiredKeyTTL time.Duration) ([]*SigningKey, error) {
+	algo, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := GetKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	var nextKey *SigningKey
+	for _, key := range keyring {
+		switch key.Status {
+		case SigningKeyStatusActive:
+			key.Status = SigningKeyStatusRetired
+			key.RetiredAt = now
+			key.ExpiresAt = now + int64(retiredKeyTTL.Seconds())
+		case SigningKeyStatusNext:
+			if key.Algorithm == algo {
+				nextKey = key
+			} else {
+				// next key generated for different algorithm is never used for signing
+				key.ExpiresAt = now - 1
+			}
+		}
+	}
+	if nextKey == nil {
+		nextKey, err = NewSigningKey(algo, SigningKeyStatusNext)
+		if err != nil {
+			return nil, err
+		}
+		keyring = append(keyring, nextKey)
+	}
+	nextKey.Status = SigningKeyStatusActive
+	nextKey.ActivatedAt = now
+
+	newNextKey, err := NewSigningKey(algo, SigningKeyStatusNext)
+	if err != nil {
+		return nil, err
+	}
+	keyring = append(keyring, newNextKey)
+
+	if err := setSigningKeyInEnv(nextKey); err != nil {
+		return nil, err
+	}
+	if err := SaveKeyring(keyring); err != nil {
+		return nil, err
+	}
+	return GetKeyring()
+}
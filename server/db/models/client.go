@@ -0,0 +1,165 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// Client model for db
+// RedirectURIs, GrantTypes & Scopes are stored as comma separated values
+type Client struct {
+	Key                    string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID                     string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	ClientID               string `gorm:"unique" json:"client_id" bson:"client_id" cql:"client_id" dynamo:"client_id" index:"client_id,hash"`
+	ClientSecret           string `json:"client_secret" bson:"client_secret" cql:"client_secret" dynamo:"client_secret"`
+	Name                   string `json:"name" bson:"name" cql:"name" dynamo:"name"`
+	RedirectURIs           string `json:"redirect_uris" bson:"redirect_uris" cql:"redirect_uris" dynamo:"redirect_uris"`
+	GrantTypes             string `json:"grant_types" bson:"grant_types" cql:"grant_types" dynamo:"grant_types"`
+	Scopes                 string `json:"scopes" bson:"scopes" cql:"scopes" dynamo:"scopes"`
+	AccessTokenExpiryTime  string `json:"access_token_expiry_time" bson:"access_token_expiry_time" cql:"access_token_expiry_time" dynamo:"access_token_expiry_time"`
+	RefreshTokenExpiryTime string `json:"refresh_token_expiry_time" bson:"refresh_token_expiry_time" cql:"refresh_token_expiry_time" dynamo:"refresh_token_expiry_time"`
+	// RegistrationAccessToken is the hash of token issued via dynamic client registration
+	// It is empty for clients added by admin
+	RegistrationAccessToken string `json:"registration_access_token" bson:"registration_access_token" cql:"registration_access_token" dynamo:"registration_access_token"`
+	// RequirePushedAuthorizationRequests when set, authorize requests of client are only accepted via request_uri
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests" bson:"require_pushed_authorization_requests" cql:"require_pushed_authorization_requests" dynamo:"require_pushed_authorization_requests"`
+	// RequireSignedRequestObject when set, authorize requests of client are only accepted as request object signed with keys of client
+	RequireSignedRequestObject bool `json:"require_signed_request_object" bson:"require_signed_request_object" cql:"require_signed_request_object" dynamo:"require_signed_request_object"`
+	// PostLogoutRedirectURIs are stored as comma separated values
+	PostLogoutRedirectURIs string `json:"post_logout_redirect_uris" bson:"post_logout_redirect_uris" cql:"post_logout_redirect_uris" dynamo:"post_logout_redirect_uris"`
+	FrontchannelLogoutURI  string `json:"frontchannel_logout_uri" bson:"frontchannel_logout_uri" cql:"frontchannel_logout_uri" dynamo:"frontchannel_logout_uri"`
+	BackchannelLogoutURI   string `json:"backchannel_logout_uri" bson:"backchannel_logout_uri" cql:"backchannel_logout_uri" dynamo:"backchannel_logout_uri"`
+	// TokenExchangeAudiences are the audiences for which client can exchange tokens, stored as comma separated values
+	TokenExchangeAudiences string `json:"token_exchange_audiences" bson:"token_exchange_audiences" cql:"token_exchange_audiences" dynamo:"token_exchange_audiences"`
+	// TokenEndpointAuthMethod is the method used by client to authenticate at token endpoint.
+	// Client secret is not accepted for clients using private_key_jwt, client_secret_jwt or none (public clients).
+	// Secret of clients using client_secret_jwt is stored encrypted instead of hashed, as it is used for verifying client assertion
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method" bson:"token_endpoint_auth_method" cql:"token_endpoint_auth_method" dynamo:"token_endpoint_auth_method"`
+	// JWKS & JWKSURI are the public keys of client used for validating client assertions.
+	// JWKS is stored as json web key set
+	JWKS    string `json:"jwks" bson:"jwks" cql:"jwks" dynamo:"jwks"`
+	JWKSURI string `json:"jwks_uri" bson:"jwks_uri" cql:"jwks_uri" dynamo:"jwks_uri"`
+	// SubjectType is public or pairwise, clients with pairwise subject type get sub claim
+	// calculated from sector identifier instead of user id
+	SubjectType         string `json:"subject_type" bson:"subject_type" cql:"subject_type" dynamo:"subject_type"`
+	SectorIdentifierURI string `json:"sector_identifier_uri" bson:"sector_identifier_uri" cql:"sector_identifier_uri" dynamo:"sector_identifier_uri"`
+	CreatedAt           int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt           int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// splitCommaSeparated returns the non empty values of comma separated string
+func splitCommaSeparated(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if strings.TrimSpace(v) != "" {
+			res = append(res, strings.TrimSpace(v))
+		}
+	}
+	return res
+}
+
+// GetRedirectURIs returns the list of allowed redirect uris for client
+func (c *Client) GetRedirectURIs() []string {
+	return splitCommaSeparated(c.RedirectURIs)
+}
+
+// GetPostLogoutRedirectURIs returns the list of allowed post logout redirect uris for client
+func (c *Client) GetPostLogoutRedirectURIs() []string {
+	return splitCommaSeparated(c.PostLogoutRedirectURIs)
+}
+
+// GetGrantTypes returns the list of allowed grant types for client
+func (c *Client) GetGrantTypes() []string {
+	return splitCommaSeparated(c.GrantTypes)
+}
+
+// GetTokenExchangeAudiences returns the list of audiences for which client can exchange tokens
+func (c *Client) GetTokenExchangeAudiences() []string {
+	return splitCommaSeparated(c.TokenExchangeAudiences)
+}
+
+// GetTokenEndpointAuthMethod returns the token endpoint auth method of client,
+// clients without auth method configured use client_secret_basic
+func (c *Client) GetTokenEndpointAuthMethod() string {
+	if c.TokenEndpointAuthMethod == "" {
+		return constants.TokenEndpointAuthMethodClientSecretBasic
+	}
+	return c.TokenEndpointAuthMethod
+}
+
+// IsClientAssertionRequired returns true for clients which authenticate only with client assertion,
+// i.e. clients registered with private_key_jwt or client_secret_jwt
+func (c *Client) IsClientAssertionRequired() bool {
+	method := c.GetTokenEndpointAuthMethod()
+	return method == constants.TokenEndpointAuthMethodPrivateKeyJWT || method == constants.TokenEndpointAuthMethodClientSecretJWT
+}
+
+// IsPublic returns true for public clients, which cannot keep credentials confidential
+// and are registered with token endpoint auth method none
+func (c *Client) IsPublic() bool {
+	return c.GetTokenEndpointAuthMethod() == constants.TokenEndpointAuthMethodNone
+}
+
+// GetSubjectType returns the subject type of client,
+// clients without subject type configured use public subject type
+func (c *Client) GetSubjectType() string {
+	if c.SubjectType == "" {
+		return constants.SubjectTypePublic
+	}
+	return c.SubjectType
+}
+
+// GetSectorIdentifier returns the host used for calculating pairwise subject of client.
+// It is host of sector identifier uri when configured, otherwise host of redirect uris
+func (c *Client) GetSectorIdentifier() string {
+	uris := append([]string{c.SectorIdentifierURI}, c.GetRedirectURIs()...)
+	for _, uri := range uris {
+		if u, err := url.Parse(uri); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+	return c.ClientID
+}
+
+// GetScopes returns the list of allowed scopes for client
+func (c *Client) GetScopes() []string {
+	return splitCommaSeparated(c.Scopes)
+}
+
+// AsAPIClient to return client as graphql response object
+// Note: client secret is never returned as it is stored as hash
+func (c *Client) AsAPIClient() *model.Client {
+	id := c.ID
+	if strings.Contains(id, Collections.Client+"/") {
+		id = strings.TrimPrefix(id, Collections.Client+"/")
+	}
+	return &model.Client{
+		ID:                                 id,
+		ClientID:                           c.ClientID,
+		Name:                               refs.NewStringRef(c.Name),
+		RedirectUris:                       c.GetRedirectURIs(),
+		GrantTypes:                         c.GetGrantTypes(),
+		Scopes:                             c.GetScopes(),
+		AccessTokenExpiryTime:              refs.NewStringRef(c.AccessTokenExpiryTime),
+		RefreshTokenExpiryTime:             refs.NewStringRef(c.RefreshTokenExpiryTime),
+		RequirePushedAuthorizationRequests: refs.NewBoolRef(c.RequirePushedAuthorizationRequests),
+		RequireSignedRequestObject:         refs.NewBoolRef(c.RequireSignedRequestObject),
+		PostLogoutRedirectUris:             c.GetPostLogoutRedirectURIs(),
+		FrontchannelLogoutURI:              refs.NewStringRef(c.FrontchannelLogoutURI),
+		BackchannelLogoutURI:               refs.NewStringRef(c.BackchannelLogoutURI),
+		TokenExchangeAudiences:             c.GetTokenExchangeAudiences(),
+		TokenEndpointAuthMethod:            refs.NewStringRef(c.GetTokenEndpointAuthMethod()),
+		Jwks:                               refs.NewStringRef(c.JWKS),
+		JwksURI:                            refs.NewStringRef(c.JWKSURI),
+		SubjectType:                        refs.NewStringRef(c.GetSubjectType()),
+		SectorIdentifierURI:                refs.NewStringRef(c.SectorIdentifierURI),
+		CreatedAt:                          refs.NewInt64Ref(c.CreatedAt),
+		UpdatedAt:                          refs.NewInt64Ref(c.UpdatedAt),
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// Identity model for db
+// It is the account of user at social login provider / upstream identity provider,
+// identified by provider & subject id of user at provider. User can have multiple identities.
+// RawProfile is the json encoded profile returned by provider
+type Identity struct {
+	Key            string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID             string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	UserID         string `gorm:"type:char(36);index" json:"user_id" bson:"user_id" cql:"user_id" dynamo:"user_id" index:"user_id,hash"`
+	Provider       string `gorm:"uniqueIndex:idx_identity_provider_user_id;type:varchar(256)" json:"provider" bson:"provider" cql:"provider" dynamo:"provider" index:"provider,hash"`
+	ProviderUserID string `gorm:"uniqueIndex:idx_identity_provider_user_id;type:varchar(256)" json:"provider_user_id" bson:"provider_user_id" cql:"provider_user_id" dynamo:"provider_user_id"`
+	Email          string `json:"email" bson:"email" cql:"email" dynamo:"email"`
+	RawProfile     string `gorm:"type:text" json:"raw_profile" bson:"raw_profile" cql:"raw_profile" dynamo:"raw_profile"`
+	CreatedAt      int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt      int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// AsAPIIdentity to return identity as graphql response object
+// raw profile is not returned as it can contain provider specific sensitive information
+func (i *Identity) AsAPIIdentity() *model.Identity {
+	id := i.ID
+	if strings.Contains(id, Collections.Identity+"/") {
+		id = strings.TrimPrefix(id, Collections.Identity+"/")
+	}
+	return &model.Identity{
+		ID:             id,
+		Provider:       i.Provider,
+		ProviderUserID: i.ProviderUserID,
+		Email:          refs.NewStringRef(i.Email),
+		CreatedAt:      refs.NewInt64Ref(i.CreatedAt),
+		UpdatedAt:      refs.NewInt64Ref(i.UpdatedAt),
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// IdentityProvider model for db, it is the upstream OIDC / OAuth2 provider managed by admin.
+// Endpoints are discovered from DiscoveryURL, or AuthorizationURL, TokenURL & UserInfoURL are used when it is not set.
+// Scopes are stored as comma separated values & ClaimMapping as json object of user field to claim name
+type IdentityProvider struct {
+	Key              string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID               string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	Name             string `gorm:"unique" json:"name" bson:"name" cql:"name" dynamo:"name" index:"name,hash"`
+	DiscoveryURL     string `json:"discovery_url" bson:"discovery_url" cql:"discovery_url" dynamo:"discovery_url"`
+	AuthorizationURL string `json:"authorization_url" bson:"authorization_url" cql:"authorization_url" dynamo:"authorization_url"`
+	TokenURL         string `json:"token_url" bson:"token_url" cql:"token_url" dynamo:"token_url"`
+	UserInfoURL      string `json:"userinfo_url" bson:"userinfo_url" cql:"userinfo_url" dynamo:"userinfo_url"`
+	ClientID         string `json:"client_id" bson:"client_id" cql:"client_id" dynamo:"client_id"`
+	// ClientSecret is stored encrypted, as it is used for authenticating with upstream provider
+	ClientSecret string `json:"client_secret" bson:"client_secret" cql:"client_secret" dynamo:"client_secret"`
+	Scopes       string `json:"scopes" bson:"scopes" cql:"scopes" dynamo:"scopes"`
+	ClaimMapping string `json:"claim_mapping" bson:"claim_mapping" cql:"claim_mapping" dynamo:"claim_mapping"`
+	CreatedAt    int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt    int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// IdentityProviderClaimMappingFields are the user fields which can be mapped from claims of upstream provider,
+// email_verified is the claim which should be true for the email returned by upstream provider to be trusted
+var IdentityProviderClaimMappingFields = []string{"email", "email_verified", "given_name", "family_name", "middle_name", "nickname", "picture", "gender", "birthdate", "phone_number", "roles"}
+
+// GetScopes returns the list of scopes requested from identity provider,
+// openid, profile & email are requested when scopes are not configured
+func (p *IdentityProvider) GetScopes() []string {
+	scopes := splitCommaSeparated(p.Scopes)
+	if len(scopes) == 0 {
+		return []string{"openid", "profile", "email"}
+	}
+	return scopes
+}
+
+// GetClaimMapping returns the claim name of upstream provider for each user field.
+// Standard claims are used for the fields which are not mapped, roles are only mapped when configured
+func (p *IdentityProvider) GetClaimMapping() map[string]string {
+	claimMapping := map[string]string{}
+	for _, field := range IdentityProviderClaimMappingFields {
+		if field != "roles" {
+			claimMapping[field] = field
+		}
+	}
+	if p.ClaimMapping != "" {
+		configuredMapping := map[string]string{}
+		json.Unmarshal([]byte(p.ClaimMapping), &configuredMapping)
+		for field, claim := range configuredMapping {
+			claimMapping[field] = claim
+		}
+	}
+	return claimMapping
+}
+
+// AsAPIIdentityProvider to return identity provider as graphql response object
+// Note: client secret is never returned
+func (p *IdentityProvider) AsAPIIdentityProvider() *model.IdentityProvider {
+	id := p.ID
+	if strings.Contains(id, Collections.IdentityProvider+"/") {
+		id = strings.TrimPrefix(id, Collections.IdentityProvider+"/")
+	}
+	claimMapping := map[string]interface{}{}
+	for field, claim := range p.GetClaimMapping() {
+		claimMapping[field] = claim
+	}
+	return &model.IdentityProvider{
+		ID:               id,
+		Name:             p.Name,
+		DiscoveryURL:     refs.NewStringRef(p.DiscoveryURL),
+		AuthorizationURL: refs.NewStringRef(p.AuthorizationURL),
+		TokenURL:         refs.NewStringRef(p.TokenURL),
+		UserinfoURL:      refs.NewStringRef(p.UserInfoURL),
+		ClientID:         p.ClientID,
+		Scopes:           p.GetScopes(),
+		ClaimMapping:     claimMapping,
+		CreatedAt:        refs.NewInt64Ref(p.CreatedAt),
+		UpdatedAt:        refs.NewInt64Ref(p.UpdatedAt),
+	}
+}
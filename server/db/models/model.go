@@ -12,6 +12,12 @@ type CollectionList struct {
 	OTP                    string
 	SMSVerificationRequest string
 	Authenticators         string
+	Client                 string
+	OAuthGrant             string
+	IdentityProvider       string
+	SAMLServiceProvider    string
+	SAMLIdentityProvider   string
+	Identity               string
 }
 
 var (
@@ -29,5 +35,11 @@ var (
 		OTP:                    Prefix + "otps",
 		SMSVerificationRequest: Prefix + "sms_verification_requests",
 		Authenticators:         Prefix + "authenticators",
+		Client:                 Prefix + "clients",
+		OAuthGrant:             Prefix + "oauth_grants",
+		IdentityProvider:       Prefix + "identity_providers",
+		SAMLServiceProvider:    Prefix + "saml_service_providers",
+		SAMLIdentityProvider:   Prefix + "saml_identity_providers",
+		Identity:               Prefix + "identities",
 	}
 )
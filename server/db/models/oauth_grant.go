@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// OAuthGrant model for db
+// It is the consent given by user to oauth client, there is a single grant per user & client.
+// Scopes are stored as comma separated values
+type OAuthGrant struct {
+	Key       string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID        string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	UserID    string `gorm:"type:char(36);index" json:"user_id" bson:"user_id" cql:"user_id" dynamo:"user_id" index:"user_id,hash"`
+	ClientID  string `json:"client_id" bson:"client_id" cql:"client_id" dynamo:"client_id"`
+	Scopes    string `json:"scopes" bson:"scopes" cql:"scopes" dynamo:"scopes"`
+	CreatedAt int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// GetScopes returns the list of scopes granted to client
+func (g *OAuthGrant) GetScopes() []string {
+	return splitCommaSeparated(g.Scopes)
+}
+
+// AsAPIOAuthGrant to return oauth grant as graphql response object
+func (g *OAuthGrant) AsAPIOAuthGrant() *model.OAuthGrant {
+	id := g.ID
+	if strings.Contains(id, Collections.OAuthGrant+"/") {
+		id = strings.TrimPrefix(id, Collections.OAuthGrant+"/")
+	}
+	return &model.OAuthGrant{
+		ID:        id,
+		ClientID:  g.ClientID,
+		Scopes:    g.GetScopes(),
+		CreatedAt: refs.NewInt64Ref(g.CreatedAt),
+		UpdatedAt: refs.NewInt64Ref(g.UpdatedAt),
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// SAMLIdentityProvider model for db, it is the upstream SAML identity provider (IdP) of enterprise managed by admin.
+// IdP metadata fetched from MetadataURL is pinned in MetadataXML when provider is added or updated,
+// so MetadataXML is always used for validating assertions.
+// AttributeMapping is stored as json object of user field to SAML attribute name.
+// AllowedEmailDomains are stored as comma separated values, existing users are matched by email only for these domains
+type SAMLIdentityProvider struct {
+	Key                 string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID                  string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	Name                string `gorm:"unique" json:"name" bson:"name" cql:"name" dynamo:"name" index:"name,hash"`
+	MetadataURL         string `json:"metadata_url" bson:"metadata_url" cql:"metadata_url" dynamo:"metadata_url"`
+	MetadataXML         string `json:"metadata_xml" bson:"metadata_xml" cql:"metadata_xml" dynamo:"metadata_xml"`
+	AttributeMapping    string `json:"attribute_mapping" bson:"attribute_mapping" cql:"attribute_mapping" dynamo:"attribute_mapping"`
+	AllowedEmailDomains string `json:"allowed_email_domains" bson:"allowed_email_domains" cql:"allowed_email_domains" dynamo:"allowed_email_domains"`
+	CreatedAt           int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt           int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// GetAttributeMapping returns the SAML attribute name for each user field.
+// Field names are used as attribute names for the fields which are not mapped, roles are only mapped when configured
+func (p *SAMLIdentityProvider) GetAttributeMapping() map[string]string {
+	attributeMapping := map[string]string{}
+	for _, field := range IdentityProviderClaimMappingFields {
+		if field != "roles" {
+			attributeMapping[field] = field
+		}
+	}
+	if p.AttributeMapping != "" {
+		configuredMapping := map[string]string{}
+		json.Unmarshal([]byte(p.AttributeMapping), &configuredMapping)
+		for field, attribute := range configuredMapping {
+			attributeMapping[field] = attribute
+		}
+	}
+	return attributeMapping
+}
+
+// GetAllowedEmailDomains returns the list of email domains for which existing users are matched by email
+func (p *SAMLIdentityProvider) GetAllowedEmailDomains() []string {
+	return splitCommaSeparated(p.AllowedEmailDomains)
+}
+
+// IsEmailMatchAllowed returns true when user authenticated by identity provider can be matched to existing user by email.
+// Enterprise identity providers can assert any email, hence it is allowed only for the domains owned by enterprise,
+// for rest of the emails user should link the identity explicitly
+func (p *SAMLIdentityProvider) IsEmailMatchAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowedDomain := range p.GetAllowedEmailDomains() {
+		if strings.ToLower(allowedDomain) == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// AsAPISAMLIdentityProvider to return saml identity provider as graphql response object
+func (p *SAMLIdentityProvider) AsAPISAMLIdentityProvider() *model.SAMLIdentityProvider {
+	id := p.ID
+	if strings.Contains(id, Collections.SAMLIdentityProvider+"/") {
+		id = strings.TrimPrefix(id, Collections.SAMLIdentityProvider+"/")
+	}
+	attributeMapping := map[string]interface{}{}
+	for field, attribute := range p.GetAttributeMapping() {
+		attributeMapping[field] = attribute
+	}
+	return &model.SAMLIdentityProvider{
+		ID:                  id,
+		Name:                p.Name,
+		MetadataURL:         refs.NewStringRef(p.MetadataURL),
+		MetadataXML:         refs.NewStringRef(p.MetadataXML),
+		AttributeMapping:    attributeMapping,
+		AllowedEmailDomains: p.GetAllowedEmailDomains(),
+		CreatedAt:           refs.NewInt64Ref(p.CreatedAt),
+		UpdatedAt:           refs.NewInt64Ref(p.UpdatedAt),
+	}
+}
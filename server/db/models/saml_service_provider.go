@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+)
+
+// Note: any change here should be reflected in providers/casandra/provider.go as it does not have model support in collection creation
+
+// SAMLServiceProvider model for db, it is the SAML service provider (SP) which uses authorizer as SAML identity provider.
+// Assertions are only sent to ACSURL registered for the EntityID of service provider.
+// AttributeMapping is stored as json object of SAML attribute name to user field
+type SAMLServiceProvider struct {
+	Key              string `json:"_key,omitempty" bson:"_key,omitempty" cql:"_key,omitempty" dynamo:"key,omitempty"` // for arangodb
+	ID               string `gorm:"primaryKey;type:char(36)" json:"_id" bson:"_id" cql:"id" dynamo:"id,hash"`
+	Name             string `json:"name" bson:"name" cql:"name" dynamo:"name"`
+	EntityID         string `gorm:"unique" json:"entity_id" bson:"entity_id" cql:"entity_id" dynamo:"entity_id" index:"entity_id,hash"`
+	ACSURL           string `json:"acs_url" bson:"acs_url" cql:"acs_url" dynamo:"acs_url"`
+	NameIDFormat     string `json:"name_id_format" bson:"name_id_format" cql:"name_id_format" dynamo:"name_id_format"`
+	AttributeMapping string `json:"attribute_mapping" bson:"attribute_mapping" cql:"attribute_mapping" dynamo:"attribute_mapping"`
+	CreatedAt        int64  `json:"created_at" bson:"created_at" cql:"created_at" dynamo:"created_at"`
+	UpdatedAt        int64  `json:"updated_at" bson:"updated_at" cql:"updated_at" dynamo:"updated_at"`
+}
+
+// SAMLAttributeMappingFields are the user fields which can be sent as attributes in SAML assertion
+var SAMLAttributeMappingFields = []string{"id", "email", "given_name", "family_name", "middle_name", "nickname", "picture", "gender", "birthdate", "phone_number", "roles"}
+
+// GetAttributeMapping returns the user field for each SAML attribute.
+// Email, given name, family name & roles are sent when attribute mapping is not configured
+func (p *SAMLServiceProvider) GetAttributeMapping() map[string]string {
+	attributeMapping := map[string]string{}
+	if p.AttributeMapping != "" {
+		json.Unmarshal([]byte(p.AttributeMapping), &attributeMapping)
+	}
+	if len(attributeMapping) == 0 {
+		attributeMapping = map[string]string{
+			"email":       "email",
+			"given_name":  "given_name",
+			"family_name": "family_name",
+			"roles":       "roles",
+		}
+	}
+	return attributeMapping
+}
+
+// AsAPISAMLServiceProvider to return saml service provider as graphql response object
+func (p *SAMLServiceProvider) AsAPISAMLServiceProvider() *model.SAMLServiceProvider {
+	id := p.ID
+	if strings.Contains(id, Collections.SAMLServiceProvider+"/") {
+		id = strings.TrimPrefix(id, Collections.SAMLServiceProvider+"/")
+	}
+	attributeMapping := map[string]interface{}{}
+	for attribute, field := range p.GetAttributeMapping() {
+		attributeMapping[attribute] = field
+	}
+	return &model.SAMLServiceProvider{
+		ID:               id,
+		Name:             p.Name,
+		EntityID:         p.EntityID,
+		AcsURL:           p.ACSURL,
+		NameIDFormat:     p.NameIDFormat,
+		AttributeMapping: attributeMapping,
+		CreatedAt:        refs.NewInt64Ref(p.CreatedAt),
+		UpdatedAt:        refs.NewInt64Ref(p.UpdatedAt),
+	}
+}
@@ -0,0 +1,138 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddClient to add oauth client
+func (p *provider) AddClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	if client.ID == "" {
+		client.ID = uuid.New().String()
+	}
+	client.Key = client.ID
+	client.CreatedAt = time.Now().Unix()
+	client.UpdatedAt = time.Now().Unix()
+	clientCollection, _ := p.db.Collection(ctx, models.Collections.Client)
+	meta, err := clientCollection.CreateDocument(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	client.Key = meta.Key
+	client.ID = meta.ID.String()
+	return client, nil
+}
+
+// UpdateClient to update oauth client
+func (p *provider) UpdateClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	client.UpdatedAt = time.Now().Unix()
+	clientCollection, _ := p.db.Collection(ctx, models.Collections.Client)
+	meta, err := clientCollection.UpdateDocument(ctx, client.Key, client)
+	if err != nil {
+		return nil, err
+	}
+	client.Key = meta.Key
+	client.ID = meta.ID.String()
+	return client, nil
+}
+
+// ListClients to list oauth clients
+func (p *provider) ListClients(ctx context.Context, pagination *model.Pagination) (*model.Clients, error) {
+	clients := []*model.Client{}
+	query := fmt.Sprintf("FOR d in %s SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.Client, pagination.Offset, pagination.Limit)
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var client *models.Client
+		meta, err := cursor.ReadDocument(ctx, &client)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			clients = append(clients, client.AsAPIClient())
+		}
+	}
+	return &model.Clients{
+		Pagination: paginationClone,
+		Clients:    clients,
+	}, nil
+}
+
+// GetClientByID to get oauth client by id
+func (p *provider) GetClientByID(ctx context.Context, id string) (*models.Client, error) {
+	var client *models.Client
+	query := fmt.Sprintf("FOR d in %s FILTER d._key == @id RETURN d", models.Collections.Client)
+	bindVars := map[string]interface{}{
+		"id": id,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if client == nil {
+				return nil, fmt.Errorf("client not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &client)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// GetClientByClientID to get oauth client by client_id
+func (p *provider) GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	var client *models.Client
+	query := fmt.Sprintf("FOR d in %s FILTER d.client_id == @client_id RETURN d", models.Collections.Client)
+	bindVars := map[string]interface{}{
+		"client_id": clientID,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if client == nil {
+				return nil, fmt.Errorf("client not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &client)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// DeleteClient to delete oauth client
+func (p *provider) DeleteClient(ctx context.Context, client *models.Client) error {
+	clientCollection, _ := p.db.Collection(ctx, models.Collections.Client)
+	_, err := clientCollection.RemoveDocument(ctx, client.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.Key = identity.ID
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	identityCollection, _ := p.db.Collection(ctx, models.Collections.Identity)
+	meta, err := identityCollection.CreateDocument(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Key = meta.Key
+	identity.ID = meta.ID.String()
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	identity.UpdatedAt = time.Now().Unix()
+	identityCollection, _ := p.db.Collection(ctx, models.Collections.Identity)
+	meta, err := identityCollection.UpdateDocument(ctx, identity.Key, identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Key = meta.Key
+	identity.ID = meta.ID.String()
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	identities := []*model.Identity{}
+	query := fmt.Sprintf("FOR d in %s FILTER d.user_id == @user_id SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.Identity, pagination.Offset, pagination.Limit)
+	bindVars := map[string]interface{}{
+		"user_id": userID,
+	}
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var identity *models.Identity
+		meta, err := cursor.ReadDocument(ctx, &identity)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			identities = append(identities, identity.AsAPIIdentity())
+		}
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: identities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	query := fmt.Sprintf("FOR d in %s FILTER d._key == @id RETURN d", models.Collections.Identity)
+	bindVars := map[string]interface{}{
+		"id": id,
+	}
+	return p.getIdentity(ctx, query, bindVars)
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	query := fmt.Sprintf("FOR d in %s FILTER d.provider == @provider AND d.provider_user_id == @provider_user_id RETURN d", models.Collections.Identity)
+	bindVars := map[string]interface{}{
+		"provider":         provider,
+		"provider_user_id": providerUserID,
+	}
+	return p.getIdentity(ctx, query, bindVars)
+}
+
+// getIdentity returns the identity matched by query
+func (p *provider) getIdentity(ctx context.Context, query string, bindVars map[string]interface{}) (*models.Identity, error) {
+	var identity *models.Identity
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if identity == nil {
+				return nil, fmt.Errorf("identity not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return identity, nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	identityCollection, _ := p.db.Collection(ctx, models.Collections.Identity)
+	_, err := identityCollection.RemoveDocument(ctx, identity.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
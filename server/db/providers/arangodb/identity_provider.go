@@ -0,0 +1,138 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.Key = identityProvider.ID
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	identityProviderCollection, _ := p.db.Collection(ctx, models.Collections.IdentityProvider)
+	meta, err := identityProviderCollection.CreateDocument(ctx, identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	identityProvider.Key = meta.Key
+	identityProvider.ID = meta.ID.String()
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	identityProvider.UpdatedAt = time.Now().Unix()
+	identityProviderCollection, _ := p.db.Collection(ctx, models.Collections.IdentityProvider)
+	meta, err := identityProviderCollection.UpdateDocument(ctx, identityProvider.Key, identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	identityProvider.Key = meta.Key
+	identityProvider.ID = meta.ID.String()
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	identityProviders := []*model.IdentityProvider{}
+	query := fmt.Sprintf("FOR d in %s SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.IdentityProvider, pagination.Offset, pagination.Limit)
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var identityProvider *models.IdentityProvider
+		meta, err := cursor.ReadDocument(ctx, &identityProvider)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			identityProviders = append(identityProviders, identityProvider.AsAPIIdentityProvider())
+		}
+	}
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: identityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d._key == @id RETURN d", models.Collections.IdentityProvider)
+	bindVars := map[string]interface{}{
+		"id": id,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if identityProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &identityProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d.name == @name RETURN d", models.Collections.IdentityProvider)
+	bindVars := map[string]interface{}{
+		"name": name,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if identityProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &identityProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return identityProvider, nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	identityProviderCollection, _ := p.db.Collection(ctx, models.Collections.IdentityProvider)
+	_, err := identityProviderCollection.RemoveDocument(ctx, identityProvider.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
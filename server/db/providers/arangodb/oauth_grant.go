@@ -0,0 +1,115 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.Key = oauthGrant.ID
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	oauthGrantCollection, _ := p.db.Collection(ctx, models.Collections.OAuthGrant)
+	meta, err := oauthGrantCollection.CreateDocument(ctx, oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	oauthGrant.Key = meta.Key
+	oauthGrant.ID = meta.ID.String()
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	oauthGrantCollection, _ := p.db.Collection(ctx, models.Collections.OAuthGrant)
+	meta, err := oauthGrantCollection.UpdateDocument(ctx, oauthGrant.Key, oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	oauthGrant.Key = meta.Key
+	oauthGrant.ID = meta.ID.String()
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	oauthGrants := []*model.OAuthGrant{}
+	query := fmt.Sprintf("FOR d in %s FILTER d.user_id == @user_id SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.OAuthGrant, pagination.Offset, pagination.Limit)
+	bindVars := map[string]interface{}{
+		"user_id": userID,
+	}
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var oauthGrant *models.OAuthGrant
+		meta, err := cursor.ReadDocument(ctx, &oauthGrant)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			oauthGrants = append(oauthGrants, oauthGrant.AsAPIOAuthGrant())
+		}
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: oauthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrant *models.OAuthGrant
+	query := fmt.Sprintf("FOR d in %s FILTER d.user_id == @user_id AND d.client_id == @client_id RETURN d", models.Collections.OAuthGrant)
+	bindVars := map[string]interface{}{
+		"user_id":   userID,
+		"client_id": clientID,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if oauthGrant == nil {
+				return nil, fmt.Errorf("oauth grant not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &oauthGrant)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return oauthGrant, nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	oauthGrantCollection, _ := p.db.Collection(ctx, models.Collections.OAuthGrant)
+	_, err := oauthGrantCollection.RemoveDocument(ctx, oauthGrant.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -244,6 +244,123 @@ func NewProvider() (*provider, error) {
 		Sparse: true,
 	})
 
+	clientCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.Client)
+	if err != nil {
+		return nil, err
+	}
+	if !clientCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.Client, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clientCollection, err := arangodb.Collection(ctx, models.Collections.Client)
+	if err != nil {
+		return nil, err
+	}
+	clientCollection.EnsureHashIndex(ctx, []string{"client_id"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+
+	oauthGrantCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.OAuthGrant)
+	if err != nil {
+		return nil, err
+	}
+	if !oauthGrantCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.OAuthGrant, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	oauthGrantCollection, err := arangodb.Collection(ctx, models.Collections.OAuthGrant)
+	if err != nil {
+		return nil, err
+	}
+	oauthGrantCollection.EnsureHashIndex(ctx, []string{"user_id", "client_id"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+
+	identityProviderCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.IdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	if !identityProviderCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.IdentityProvider, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	identityProviderCollection, err := arangodb.Collection(ctx, models.Collections.IdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	identityProviderCollection.EnsureHashIndex(ctx, []string{"name"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+
+	identityCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.Identity)
+	if err != nil {
+		return nil, err
+	}
+	if !identityCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.Identity, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	identityCollection, err := arangodb.Collection(ctx, models.Collections.Identity)
+	if err != nil {
+		return nil, err
+	}
+	identityCollection.EnsureHashIndex(ctx, []string{"provider", "provider_user_id"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+	identityCollection.EnsureHashIndex(ctx, []string{"user_id"}, &arangoDriver.EnsureHashIndexOptions{
+		Sparse: true,
+	})
+
+	samlServiceProviderCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.SAMLServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	if !samlServiceProviderCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.SAMLServiceProvider, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	samlServiceProviderCollection, err := arangodb.Collection(ctx, models.Collections.SAMLServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlServiceProviderCollection.EnsureHashIndex(ctx, []string{"entity_id"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+
+	samlIdentityProviderCollectionExists, err := arangodb.CollectionExists(ctx, models.Collections.SAMLIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	if !samlIdentityProviderCollectionExists {
+		_, err = arangodb.CreateCollection(ctx, models.Collections.SAMLIdentityProvider, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	samlIdentityProviderCollection, err := arangodb.Collection(ctx, models.Collections.SAMLIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlIdentityProviderCollection.EnsureHashIndex(ctx, []string{"name"}, &arangoDriver.EnsureHashIndexOptions{
+		Unique: true,
+		Sparse: true,
+	})
+
 	return &provider{
 		db: arangodb,
 	}, err
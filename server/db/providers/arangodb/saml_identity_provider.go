@@ -0,0 +1,138 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.Key = samlIdentityProvider.ID
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	samlIdentityProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLIdentityProvider)
+	meta, err := samlIdentityProviderCollection.CreateDocument(ctx, samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlIdentityProvider.Key = meta.Key
+	samlIdentityProvider.ID = meta.ID.String()
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	samlIdentityProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLIdentityProvider)
+	meta, err := samlIdentityProviderCollection.UpdateDocument(ctx, samlIdentityProvider.Key, samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlIdentityProvider.Key = meta.Key
+	samlIdentityProvider.ID = meta.ID.String()
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	samlIdentityProviders := []*model.SAMLIdentityProvider{}
+	query := fmt.Sprintf("FOR d in %s SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.SAMLIdentityProvider, pagination.Offset, pagination.Limit)
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var samlIdentityProvider *models.SAMLIdentityProvider
+		meta, err := cursor.ReadDocument(ctx, &samlIdentityProvider)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			samlIdentityProviders = append(samlIdentityProviders, samlIdentityProvider.AsAPISAMLIdentityProvider())
+		}
+	}
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: samlIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d._key == @id RETURN d", models.Collections.SAMLIdentityProvider)
+	bindVars := map[string]interface{}{
+		"id": id,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if samlIdentityProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &samlIdentityProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d.name == @name RETURN d", models.Collections.SAMLIdentityProvider)
+	bindVars := map[string]interface{}{
+		"name": name,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if samlIdentityProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &samlIdentityProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samlIdentityProvider, nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	samlIdentityProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLIdentityProvider)
+	_, err := samlIdentityProviderCollection.RemoveDocument(ctx, samlIdentityProvider.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package arangodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arangoDriver "github.com/arangodb/go-driver"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.Key = samlServiceProvider.ID
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	samlServiceProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLServiceProvider)
+	meta, err := samlServiceProviderCollection.CreateDocument(ctx, samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlServiceProvider.Key = meta.Key
+	samlServiceProvider.ID = meta.ID.String()
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	samlServiceProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLServiceProvider)
+	meta, err := samlServiceProviderCollection.UpdateDocument(ctx, samlServiceProvider.Key, samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	samlServiceProvider.Key = meta.Key
+	samlServiceProvider.ID = meta.ID.String()
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	samlServiceProviders := []*model.SAMLServiceProvider{}
+	query := fmt.Sprintf("FOR d in %s SORT d.created_at DESC LIMIT %d, %d RETURN d", models.Collections.SAMLServiceProvider, pagination.Offset, pagination.Limit)
+	sctx := arangoDriver.WithQueryFullCount(ctx)
+	cursor, err := p.db.Query(sctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	paginationClone := pagination
+	paginationClone.Total = cursor.Statistics().FullCount()
+	for {
+		var samlServiceProvider *models.SAMLServiceProvider
+		meta, err := cursor.ReadDocument(ctx, &samlServiceProvider)
+		if arangoDriver.IsNoMoreDocuments(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if meta.Key != "" {
+			samlServiceProviders = append(samlServiceProviders, samlServiceProvider.AsAPISAMLServiceProvider())
+		}
+	}
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: samlServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d._key == @id RETURN d", models.Collections.SAMLServiceProvider)
+	bindVars := map[string]interface{}{
+		"id": id,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if samlServiceProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &samlServiceProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	query := fmt.Sprintf("FOR d in %s FILTER d.entity_id == @entity_id RETURN d", models.Collections.SAMLServiceProvider)
+	bindVars := map[string]interface{}{
+		"entity_id": entityID,
+	}
+	cursor, err := p.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+	for {
+		if !cursor.HasMore() {
+			if samlServiceProvider == nil {
+				return nil, fmt.Errorf("identity provider not found")
+			}
+			break
+		}
+		_, err := cursor.ReadDocument(ctx, &samlServiceProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samlServiceProvider, nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	samlServiceProviderCollection, _ := p.db.Collection(ctx, models.Collections.SAMLServiceProvider)
+	_, err := samlServiceProviderCollection.RemoveDocument(ctx, samlServiceProvider.Key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -86,6 +86,14 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 		return err
 	}
 	defer cursor.Close()
+	oauthGrantQuery := fmt.Sprintf(`FOR d IN %s FILTER d.user_id == @user_id REMOVE { _key: d._key } IN %s`, models.Collections.OAuthGrant, models.Collections.OAuthGrant)
+	oauthGrantCursor, err := p.db.Query(ctx, oauthGrantQuery, map[string]interface{}{
+		"user_id": user.ID,
+	})
+	if err != nil {
+		return err
+	}
+	defer oauthGrantCursor.Close()
 	return nil
 }
 
@@ -0,0 +1,172 @@
+package cassandradb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const clientFields = "id, client_id, client_secret, name, redirect_uris, grant_types, scopes, access_token_expiry_time, refresh_token_expiry_time, registration_access_token, require_pushed_authorization_requests, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, token_exchange_audiences, token_endpoint_auth_method, jwks, jwks_uri, subject_type, sector_identifier_uri, require_signed_request_object, created_at, updated_at"
+
+// AddClient to add oauth client
+func (p *provider) AddClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	if client.ID == "" {
+		client.ID = uuid.New().String()
+	}
+	client.CreatedAt = time.Now().Unix()
+	client.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(client)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	clientMap := map[string]interface{}{}
+	err = decoder.Decode(&clientMap)
+	if err != nil {
+		return nil, err
+	}
+	fields := "("
+	values := "("
+	for key, value := range clientMap {
+		if value != nil {
+			if key == "_id" {
+				fields += "id,"
+			} else {
+				fields += key + ","
+			}
+			valueType := reflect.TypeOf(value)
+			if valueType.Name() == "string" {
+				values += fmt.Sprintf("'%s',", value.(string))
+			} else {
+				values += fmt.Sprintf("%v,", value)
+			}
+		}
+	}
+	fields = fields[:len(fields)-1] + ")"
+	values = values[:len(values)-1] + ")"
+	query := fmt.Sprintf("INSERT INTO %s %s VALUES %s IF NOT EXISTS", KeySpace+"."+models.Collections.Client, fields, values)
+	err = p.db.Query(query).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// UpdateClient to update oauth client
+func (p *provider) UpdateClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	client.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(client)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	clientMap := map[string]interface{}{}
+	err = decoder.Decode(&clientMap)
+	if err != nil {
+		return nil, err
+	}
+	updateFields := ""
+	for key, value := range clientMap {
+		if key == "_id" {
+			continue
+		}
+		if key == "_key" {
+			continue
+		}
+		if value == nil {
+			updateFields += fmt.Sprintf("%s = null, ", key)
+			continue
+		}
+		valueType := reflect.TypeOf(value)
+		if valueType.Name() == "string" {
+			updateFields += fmt.Sprintf("%s = '%s', ", key, value.(string))
+		} else {
+			updateFields += fmt.Sprintf("%s = %v, ", key, value)
+		}
+	}
+	updateFields = strings.Trim(updateFields, " ")
+	updateFields = strings.TrimSuffix(updateFields, ",")
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = '%s'", KeySpace+"."+models.Collections.Client, updateFields, client.ID)
+	err = p.db.Query(query).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ListClients to list oauth clients
+func (p *provider) ListClients(ctx context.Context, pagination *model.Pagination) (*model.Clients, error) {
+	clients := []*model.Client{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s`, KeySpace+"."+models.Collections.Client)
+	err := p.db.Query(totalCountQuery).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d", clientFields, KeySpace+"."+models.Collections.Client, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var client models.Client
+			err := scanner.Scan(&client.ID, &client.ClientID, &client.ClientSecret, &client.Name, &client.RedirectURIs, &client.GrantTypes, &client.Scopes, &client.AccessTokenExpiryTime, &client.RefreshTokenExpiryTime, &client.RegistrationAccessToken, &client.RequirePushedAuthorizationRequests, &client.PostLogoutRedirectURIs, &client.FrontchannelLogoutURI, &client.BackchannelLogoutURI, &client.TokenExchangeAudiences, &client.TokenEndpointAuthMethod, &client.JWKS, &client.JWKSURI, &client.SubjectType, &client.SectorIdentifierURI, &client.RequireSignedRequestObject, &client.CreatedAt, &client.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			clients = append(clients, client.AsAPIClient())
+		}
+		counter++
+	}
+	return &model.Clients{
+		Pagination: paginationClone,
+		Clients:    clients,
+	}, nil
+}
+
+// GetClientByID to get oauth client by id
+func (p *provider) GetClientByID(ctx context.Context, id string) (*models.Client, error) {
+	var client models.Client
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = '%s' LIMIT 1`, clientFields, KeySpace+"."+models.Collections.Client, id)
+	err := p.db.Query(query).Consistency(gocql.One).Scan(&client.ID, &client.ClientID, &client.ClientSecret, &client.Name, &client.RedirectURIs, &client.GrantTypes, &client.Scopes, &client.AccessTokenExpiryTime, &client.RefreshTokenExpiryTime, &client.RegistrationAccessToken, &client.RequirePushedAuthorizationRequests, &client.PostLogoutRedirectURIs, &client.FrontchannelLogoutURI, &client.BackchannelLogoutURI, &client.TokenExchangeAudiences, &client.TokenEndpointAuthMethod, &client.JWKS, &client.JWKSURI, &client.SubjectType, &client.SectorIdentifierURI, &client.RequireSignedRequestObject, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// GetClientByClientID to get oauth client by client_id
+func (p *provider) GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	var client models.Client
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE client_id = '%s' LIMIT 1 ALLOW FILTERING`, clientFields, KeySpace+"."+models.Collections.Client, clientID)
+	err := p.db.Query(query).Consistency(gocql.One).Scan(&client.ID, &client.ClientID, &client.ClientSecret, &client.Name, &client.RedirectURIs, &client.GrantTypes, &client.Scopes, &client.AccessTokenExpiryTime, &client.RefreshTokenExpiryTime, &client.RegistrationAccessToken, &client.RequirePushedAuthorizationRequests, &client.PostLogoutRedirectURIs, &client.FrontchannelLogoutURI, &client.BackchannelLogoutURI, &client.TokenExchangeAudiences, &client.TokenEndpointAuthMethod, &client.JWKS, &client.JWKSURI, &client.SubjectType, &client.SectorIdentifierURI, &client.RequireSignedRequestObject, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// DeleteClient to delete oauth client
+func (p *provider) DeleteClient(ctx context.Context, client *models.Client) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = '%s'", KeySpace+"."+models.Collections.Client, client.ID)
+	err := p.db.Query(query).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cassandradb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const identityFields = "id, user_id, provider, provider_user_id, email, raw_profile, created_at, updated_at"
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	// raw profile is returned by provider, hence values are bound instead of formatting them in query
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS", KeySpace+"."+models.Collections.Identity, identityFields)
+	err := p.db.Query(query, identity.ID, identity.UserID, identity.Provider, identity.ProviderUserID, identity.Email, identity.RawProfile, identity.CreatedAt, identity.UpdatedAt).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	identity.UpdatedAt = time.Now().Unix()
+	query := fmt.Sprintf("UPDATE %s SET user_id = ?, email = ?, raw_profile = ?, updated_at = ? WHERE id = ?", KeySpace+"."+models.Collections.Identity)
+	err := p.db.Query(query, identity.UserID, identity.Email, identity.RawProfile, identity.UpdatedAt, identity.ID).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	identities := []*model.Identity{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = ? ALLOW FILTERING`, KeySpace+"."+models.Collections.Identity)
+	err := p.db.Query(totalCountQuery, userID).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ? LIMIT %d ALLOW FILTERING", identityFields, KeySpace+"."+models.Collections.Identity, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query, userID).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var identity models.Identity
+			err := scanner.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.ProviderUserID, &identity.Email, &identity.RawProfile, &identity.CreatedAt, &identity.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			identities = append(identities, identity.AsAPIIdentity())
+		}
+		counter++
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: identities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	var identity models.Identity
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ? LIMIT 1`, identityFields, KeySpace+"."+models.Collections.Identity)
+	err := p.db.Query(query, id).Consistency(gocql.One).Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.ProviderUserID, &identity.Email, &identity.RawProfile, &identity.CreatedAt, &identity.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	var identity models.Identity
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE provider = ? AND provider_user_id = ? LIMIT 1 ALLOW FILTERING`, identityFields, KeySpace+"."+models.Collections.Identity)
+	err := p.db.Query(query, provider, providerUserID).Consistency(gocql.One).Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.ProviderUserID, &identity.Email, &identity.RawProfile, &identity.CreatedAt, &identity.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", KeySpace+"."+models.Collections.Identity)
+	err := p.db.Query(query, identity.ID).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
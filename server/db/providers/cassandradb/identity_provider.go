@@ -0,0 +1,106 @@
+package cassandradb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const identityProviderFields = "id, name, discovery_url, authorization_url, token_url, userinfo_url, client_id, client_secret, scopes, claim_mapping, created_at, updated_at"
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	// claim mapping is json, hence values are bound instead of formatting them in query
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS", KeySpace+"."+models.Collections.IdentityProvider, identityProviderFields)
+	err := p.db.Query(query, identityProvider.ID, identityProvider.Name, identityProvider.DiscoveryURL, identityProvider.AuthorizationURL, identityProvider.TokenURL, identityProvider.UserInfoURL, identityProvider.ClientID, identityProvider.ClientSecret, identityProvider.Scopes, identityProvider.ClaimMapping, identityProvider.CreatedAt, identityProvider.UpdatedAt).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	identityProvider.UpdatedAt = time.Now().Unix()
+	query := fmt.Sprintf("UPDATE %s SET name = ?, discovery_url = ?, authorization_url = ?, token_url = ?, userinfo_url = ?, client_id = ?, client_secret = ?, scopes = ?, claim_mapping = ?, updated_at = ? WHERE id = ?", KeySpace+"."+models.Collections.IdentityProvider)
+	err := p.db.Query(query, identityProvider.Name, identityProvider.DiscoveryURL, identityProvider.AuthorizationURL, identityProvider.TokenURL, identityProvider.UserInfoURL, identityProvider.ClientID, identityProvider.ClientSecret, identityProvider.Scopes, identityProvider.ClaimMapping, identityProvider.UpdatedAt, identityProvider.ID).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	identityProviders := []*model.IdentityProvider{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s`, KeySpace+"."+models.Collections.IdentityProvider)
+	err := p.db.Query(totalCountQuery).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d", identityProviderFields, KeySpace+"."+models.Collections.IdentityProvider, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var identityProvider models.IdentityProvider
+			err := scanner.Scan(&identityProvider.ID, &identityProvider.Name, &identityProvider.DiscoveryURL, &identityProvider.AuthorizationURL, &identityProvider.TokenURL, &identityProvider.UserInfoURL, &identityProvider.ClientID, &identityProvider.ClientSecret, &identityProvider.Scopes, &identityProvider.ClaimMapping, &identityProvider.CreatedAt, &identityProvider.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			identityProviders = append(identityProviders, identityProvider.AsAPIIdentityProvider())
+		}
+		counter++
+	}
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: identityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	var identityProvider models.IdentityProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ? LIMIT 1`, identityProviderFields, KeySpace+"."+models.Collections.IdentityProvider)
+	err := p.db.Query(query, id).Consistency(gocql.One).Scan(&identityProvider.ID, &identityProvider.Name, &identityProvider.DiscoveryURL, &identityProvider.AuthorizationURL, &identityProvider.TokenURL, &identityProvider.UserInfoURL, &identityProvider.ClientID, &identityProvider.ClientSecret, &identityProvider.Scopes, &identityProvider.ClaimMapping, &identityProvider.CreatedAt, &identityProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProvider models.IdentityProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE name = ? LIMIT 1 ALLOW FILTERING`, identityProviderFields, KeySpace+"."+models.Collections.IdentityProvider)
+	err := p.db.Query(query, name).Consistency(gocql.One).Scan(&identityProvider.ID, &identityProvider.Name, &identityProvider.DiscoveryURL, &identityProvider.AuthorizationURL, &identityProvider.TokenURL, &identityProvider.UserInfoURL, &identityProvider.ClientID, &identityProvider.ClientSecret, &identityProvider.Scopes, &identityProvider.ClaimMapping, &identityProvider.CreatedAt, &identityProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &identityProvider, nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", KeySpace+"."+models.Collections.IdentityProvider)
+	err := p.db.Query(query, identityProvider.ID).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
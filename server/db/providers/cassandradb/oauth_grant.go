@@ -0,0 +1,95 @@
+package cassandradb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const oauthGrantFields = "id, user_id, client_id, scopes, created_at, updated_at"
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	// scopes are requested by client, hence values are bound instead of formatting them in query
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?) IF NOT EXISTS", KeySpace+"."+models.Collections.OAuthGrant, oauthGrantFields)
+	err := p.db.Query(query, oauthGrant.ID, oauthGrant.UserID, oauthGrant.ClientID, oauthGrant.Scopes, oauthGrant.CreatedAt, oauthGrant.UpdatedAt).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	query := fmt.Sprintf("UPDATE %s SET scopes = ?, updated_at = ? WHERE id = ?", KeySpace+"."+models.Collections.OAuthGrant)
+	err := p.db.Query(query, oauthGrant.Scopes, oauthGrant.UpdatedAt, oauthGrant.ID).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	oauthGrants := []*model.OAuthGrant{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = '%s' ALLOW FILTERING`, KeySpace+"."+models.Collections.OAuthGrant, userID)
+	err := p.db.Query(totalCountQuery).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = '%s' LIMIT %d ALLOW FILTERING", oauthGrantFields, KeySpace+"."+models.Collections.OAuthGrant, userID, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var oauthGrant models.OAuthGrant
+			err := scanner.Scan(&oauthGrant.ID, &oauthGrant.UserID, &oauthGrant.ClientID, &oauthGrant.Scopes, &oauthGrant.CreatedAt, &oauthGrant.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			oauthGrants = append(oauthGrants, oauthGrant.AsAPIOAuthGrant())
+		}
+		counter++
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: oauthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrant models.OAuthGrant
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = '%s' AND client_id = '%s' LIMIT 1 ALLOW FILTERING`, oauthGrantFields, KeySpace+"."+models.Collections.OAuthGrant, userID, clientID)
+	err := p.db.Query(query).Consistency(gocql.One).Scan(&oauthGrant.ID, &oauthGrant.UserID, &oauthGrant.ClientID, &oauthGrant.Scopes, &oauthGrant.CreatedAt, &oauthGrant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthGrant, nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = '%s'", KeySpace+"."+models.Collections.OAuthGrant, oauthGrant.ID)
+	err := p.db.Query(query).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
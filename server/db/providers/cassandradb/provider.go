@@ -281,6 +281,132 @@ func NewProvider() (*provider, error) {
 		return nil, err
 	}
 
+	// add clients table
+	clientCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, client_id text, client_secret text, name text, redirect_uris text, grant_types text, scopes text, access_token_expiry_time text, refresh_token_expiry_time text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.Client)
+	err = session.Query(clientCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	clientIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_client_client_id ON %s.%s (client_id)", KeySpace, models.Collections.Client)
+	err = session.Query(clientIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	// Add registration_access_token column to clients table
+	clientAlterQuery := fmt.Sprintf(`ALTER TABLE %s.%s ADD (registration_access_token text);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as registration_access_token column exists: ", err)
+		// continue
+	}
+	// Add require_pushed_authorization_requests column to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (require_pushed_authorization_requests boolean);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as require_pushed_authorization_requests column exists: ", err)
+		// continue
+	}
+	// Add logout columns to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (post_logout_redirect_uris text, frontchannel_logout_uri text, backchannel_logout_uri text);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as logout columns exist: ", err)
+		// continue
+	}
+	// Add token_exchange_audiences column to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (token_exchange_audiences text);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as token_exchange_audiences column exists: ", err)
+		// continue
+	}
+	// Add client authentication columns to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (token_endpoint_auth_method text, jwks text, jwks_uri text);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as client authentication columns exist: ", err)
+		// continue
+	}
+	// Add subject type columns to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (subject_type text, sector_identifier_uri text);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as subject type columns exist: ", err)
+		// continue
+	}
+	// Add require_signed_request_object column to clients table
+	clientAlterQuery = fmt.Sprintf(`ALTER TABLE %s.%s ADD (require_signed_request_object boolean);`, KeySpace, models.Collections.Client)
+	err = session.Query(clientAlterQuery).Exec()
+	if err != nil {
+		log.Debug("Failed to alter clients table as require_signed_request_object column exists: ", err)
+		// continue
+	}
+
+	// add oauth grants table
+	oauthGrantCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, user_id text, client_id text, scopes text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.OAuthGrant)
+	err = session.Query(oauthGrantCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	oauthGrantIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_oauth_grant_user_id ON %s.%s (user_id)", KeySpace, models.Collections.OAuthGrant)
+	err = session.Query(oauthGrantIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// add identity providers table
+	identityProviderCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, name text, discovery_url text, authorization_url text, token_url text, userinfo_url text, client_id text, client_secret text, scopes text, claim_mapping text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.IdentityProvider)
+	err = session.Query(identityProviderCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	identityProviderIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_identity_provider_name ON %s.%s (name)", KeySpace, models.Collections.IdentityProvider)
+	err = session.Query(identityProviderIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// add identities table
+	identityCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, user_id text, provider text, provider_user_id text, email text, raw_profile text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.Identity)
+	err = session.Query(identityCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	identityUserIDIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_identity_user_id ON %s.%s (user_id)", KeySpace, models.Collections.Identity)
+	err = session.Query(identityUserIDIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	identityProviderUserIDIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_identity_provider_user_id ON %s.%s (provider_user_id)", KeySpace, models.Collections.Identity)
+	err = session.Query(identityProviderUserIDIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// add saml service providers table
+	samlServiceProviderCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, name text, entity_id text, acs_url text, name_id_format text, attribute_mapping text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.SAMLServiceProvider)
+	err = session.Query(samlServiceProviderCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	samlServiceProviderIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_saml_service_provider_entity_id ON %s.%s (entity_id)", KeySpace, models.Collections.SAMLServiceProvider)
+	err = session.Query(samlServiceProviderIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// add saml identity providers table
+	samlIdentityProviderCollectionQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (id text, name text, metadata_url text, metadata_xml text, attribute_mapping text, allowed_email_domains text, updated_at bigint, created_at bigint, PRIMARY KEY (id))", KeySpace, models.Collections.SAMLIdentityProvider)
+	err = session.Query(samlIdentityProviderCollectionQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+	samlIdentityProviderIndexQuery := fmt.Sprintf("CREATE INDEX IF NOT EXISTS authorizer_saml_identity_provider_name ON %s.%s (name)", KeySpace, models.Collections.SAMLIdentityProvider)
+	err = session.Query(samlIdentityProviderIndexQuery).Exec()
+	if err != nil {
+		return nil, err
+	}
+
 	return &provider{
 		db: session,
 	}, err
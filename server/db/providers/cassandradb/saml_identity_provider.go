@@ -0,0 +1,106 @@
+package cassandradb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const samlIdentityProviderFields = "id, name, metadata_url, metadata_xml, attribute_mapping, allowed_email_domains, created_at, updated_at"
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	// metadata xml & attribute mapping can contain quotes, hence values are bound instead of formatting them in query
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS", KeySpace+"."+models.Collections.SAMLIdentityProvider, samlIdentityProviderFields)
+	err := p.db.Query(query, samlIdentityProvider.ID, samlIdentityProvider.Name, samlIdentityProvider.MetadataURL, samlIdentityProvider.MetadataXML, samlIdentityProvider.AttributeMapping, samlIdentityProvider.AllowedEmailDomains, samlIdentityProvider.CreatedAt, samlIdentityProvider.UpdatedAt).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	query := fmt.Sprintf("UPDATE %s SET name = ?, metadata_url = ?, metadata_xml = ?, attribute_mapping = ?, allowed_email_domains = ?, updated_at = ? WHERE id = ?", KeySpace+"."+models.Collections.SAMLIdentityProvider)
+	err := p.db.Query(query, samlIdentityProvider.Name, samlIdentityProvider.MetadataURL, samlIdentityProvider.MetadataXML, samlIdentityProvider.AttributeMapping, samlIdentityProvider.AllowedEmailDomains, samlIdentityProvider.UpdatedAt, samlIdentityProvider.ID).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	samlIdentityProviders := []*model.SAMLIdentityProvider{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s`, KeySpace+"."+models.Collections.SAMLIdentityProvider)
+	err := p.db.Query(totalCountQuery).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d", samlIdentityProviderFields, KeySpace+"."+models.Collections.SAMLIdentityProvider, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var samlIdentityProvider models.SAMLIdentityProvider
+			err := scanner.Scan(&samlIdentityProvider.ID, &samlIdentityProvider.Name, &samlIdentityProvider.MetadataURL, &samlIdentityProvider.MetadataXML, &samlIdentityProvider.AttributeMapping, &samlIdentityProvider.AllowedEmailDomains, &samlIdentityProvider.CreatedAt, &samlIdentityProvider.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			samlIdentityProviders = append(samlIdentityProviders, samlIdentityProvider.AsAPISAMLIdentityProvider())
+		}
+		counter++
+	}
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: samlIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider models.SAMLIdentityProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ? LIMIT 1`, samlIdentityProviderFields, KeySpace+"."+models.Collections.SAMLIdentityProvider)
+	err := p.db.Query(query, id).Consistency(gocql.One).Scan(&samlIdentityProvider.ID, &samlIdentityProvider.Name, &samlIdentityProvider.MetadataURL, &samlIdentityProvider.MetadataXML, &samlIdentityProvider.AttributeMapping, &samlIdentityProvider.AllowedEmailDomains, &samlIdentityProvider.CreatedAt, &samlIdentityProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider models.SAMLIdentityProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE name = ? LIMIT 1 ALLOW FILTERING`, samlIdentityProviderFields, KeySpace+"."+models.Collections.SAMLIdentityProvider)
+	err := p.db.Query(query, name).Consistency(gocql.One).Scan(&samlIdentityProvider.ID, &samlIdentityProvider.Name, &samlIdentityProvider.MetadataURL, &samlIdentityProvider.MetadataXML, &samlIdentityProvider.AttributeMapping, &samlIdentityProvider.AllowedEmailDomains, &samlIdentityProvider.CreatedAt, &samlIdentityProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &samlIdentityProvider, nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", KeySpace+"."+models.Collections.SAMLIdentityProvider)
+	err := p.db.Query(query, samlIdentityProvider.ID).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cassandradb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const samlServiceProviderFields = "id, name, entity_id, acs_url, name_id_format, attribute_mapping, created_at, updated_at"
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	// attribute mapping is json, hence values are bound instead of formatting them in query
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS", KeySpace+"."+models.Collections.SAMLServiceProvider, samlServiceProviderFields)
+	err := p.db.Query(query, samlServiceProvider.ID, samlServiceProvider.Name, samlServiceProvider.EntityID, samlServiceProvider.ACSURL, samlServiceProvider.NameIDFormat, samlServiceProvider.AttributeMapping, samlServiceProvider.CreatedAt, samlServiceProvider.UpdatedAt).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	query := fmt.Sprintf("UPDATE %s SET name = ?, entity_id = ?, acs_url = ?, name_id_format = ?, attribute_mapping = ?, updated_at = ? WHERE id = ?", KeySpace+"."+models.Collections.SAMLServiceProvider)
+	err := p.db.Query(query, samlServiceProvider.Name, samlServiceProvider.EntityID, samlServiceProvider.ACSURL, samlServiceProvider.NameIDFormat, samlServiceProvider.AttributeMapping, samlServiceProvider.UpdatedAt, samlServiceProvider.ID).Exec()
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	samlServiceProviders := []*model.SAMLServiceProvider{}
+	paginationClone := pagination
+	totalCountQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s`, KeySpace+"."+models.Collections.SAMLServiceProvider)
+	err := p.db.Query(totalCountQuery).Consistency(gocql.One).Scan(&paginationClone.Total)
+	if err != nil {
+		return nil, err
+	}
+	// there is no offset in cassandra
+	// so we fetch till limit + offset
+	// and return the results from offset to limit
+	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d", samlServiceProviderFields, KeySpace+"."+models.Collections.SAMLServiceProvider, pagination.Limit+pagination.Offset)
+	scanner := p.db.Query(query).Iter().Scanner()
+	counter := int64(0)
+	for scanner.Next() {
+		if counter >= pagination.Offset {
+			var samlServiceProvider models.SAMLServiceProvider
+			err := scanner.Scan(&samlServiceProvider.ID, &samlServiceProvider.Name, &samlServiceProvider.EntityID, &samlServiceProvider.ACSURL, &samlServiceProvider.NameIDFormat, &samlServiceProvider.AttributeMapping, &samlServiceProvider.CreatedAt, &samlServiceProvider.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			samlServiceProviders = append(samlServiceProviders, samlServiceProvider.AsAPISAMLServiceProvider())
+		}
+		counter++
+	}
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: samlServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider models.SAMLServiceProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ? LIMIT 1`, samlServiceProviderFields, KeySpace+"."+models.Collections.SAMLServiceProvider)
+	err := p.db.Query(query, id).Consistency(gocql.One).Scan(&samlServiceProvider.ID, &samlServiceProvider.Name, &samlServiceProvider.EntityID, &samlServiceProvider.ACSURL, &samlServiceProvider.NameIDFormat, &samlServiceProvider.AttributeMapping, &samlServiceProvider.CreatedAt, &samlServiceProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider models.SAMLServiceProvider
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE entity_id = ? LIMIT 1 ALLOW FILTERING`, samlServiceProviderFields, KeySpace+"."+models.Collections.SAMLServiceProvider)
+	err := p.db.Query(query, entityID).Consistency(gocql.One).Scan(&samlServiceProvider.ID, &samlServiceProvider.Name, &samlServiceProvider.EntityID, &samlServiceProvider.ACSURL, &samlServiceProvider.NameIDFormat, &samlServiceProvider.AttributeMapping, &samlServiceProvider.CreatedAt, &samlServiceProvider.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &samlServiceProvider, nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", KeySpace+"."+models.Collections.SAMLServiceProvider)
+	err := p.db.Query(query, samlServiceProvider.ID).Exec()
+	if err != nil {
+		return err
+	}
+	return nil
+}
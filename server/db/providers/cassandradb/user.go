@@ -166,6 +166,26 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 		return err
 	}
 
+	getOAuthGrantsQuery := fmt.Sprintf("SELECT id FROM %s WHERE user_id = '%s' ALLOW FILTERING", KeySpace+"."+models.Collections.OAuthGrant, user.ID)
+	scanner = p.db.Query(getOAuthGrantsQuery).Iter().Scanner()
+	oauthGrantIDs := ""
+	for scanner.Next() {
+		var oauthGrantID string
+		err = scanner.Scan(&oauthGrantID)
+		if err != nil {
+			return err
+		}
+		oauthGrantIDs += fmt.Sprintf("'%s',", oauthGrantID)
+	}
+	oauthGrantIDs = strings.TrimSuffix(oauthGrantIDs, ",")
+	if oauthGrantIDs != "" {
+		deleteOAuthGrantQuery := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", KeySpace+"."+models.Collections.OAuthGrant, oauthGrantIDs)
+		err = p.db.Query(deleteOAuthGrantQuery).Exec()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,155 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const clientFields = "_id, client_id, client_secret, name, redirect_uris, grant_types, scopes, access_token_expiry_time, refresh_token_expiry_time, registration_access_token, require_pushed_authorization_requests, post_logout_redirect_uris, frontchannel_logout_uri, backchannel_logout_uri, token_exchange_audiences, token_endpoint_auth_method, jwks, jwks_uri, subject_type, sector_identifier_uri, require_signed_request_object, created_at, updated_at"
+
+// AddClient to add oauth client
+func (p *provider) AddClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	if client.ID == "" {
+		client.ID = uuid.New().String()
+	}
+	client.Key = client.ID
+	client.CreatedAt = time.Now().Unix()
+	client.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.Client).Insert(client.ID, client, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// UpdateClient to update oauth client
+func (p *provider) UpdateClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	client.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(client)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	clientMap := map[string]interface{}{}
+	err = decoder.Decode(&clientMap)
+	if err != nil {
+		return nil, err
+	}
+	updateFields, params := GetSetFields(clientMap)
+	query := fmt.Sprintf(`UPDATE %s.%s SET %s WHERE _id='%s'`, p.scopeName, models.Collections.Client, updateFields, client.ID)
+	_, err = p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ListClients to list oauth clients
+func (p *provider) ListClients(ctx context.Context, pagination *model.Pagination) (*model.Clients, error) {
+	clients := []*model.Client{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	total, err := p.GetTotalDocs(ctx, models.Collections.Client)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s ORDER BY created_at DESC OFFSET $offset LIMIT $limit", clientFields, p.scopeName, models.Collections.Client)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var client models.Client
+		err := queryResult.Row(&client)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client.AsAPIClient())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.Clients{
+		Pagination: paginationClone,
+		Clients:    clients,
+	}, nil
+}
+
+// GetClientByID to get oauth client by id
+func (p *provider) GetClientByID(ctx context.Context, id string) (*models.Client, error) {
+	var client *models.Client
+	params := make(map[string]interface{}, 1)
+	params["_id"] = id
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE _id=$_id LIMIT 1`, clientFields, p.scopeName, models.Collections.Client)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&client)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// GetClientByClientID to get oauth client by client_id
+func (p *provider) GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	var client *models.Client
+	params := make(map[string]interface{}, 1)
+	params["client_id"] = clientID
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE client_id=$client_id LIMIT 1`, clientFields, p.scopeName, models.Collections.Client)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&client)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// DeleteClient to delete oauth client
+func (p *provider) DeleteClient(ctx context.Context, client *models.Client) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.Client).Remove(client.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
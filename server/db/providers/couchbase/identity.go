@@ -0,0 +1,157 @@
+package couchbase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const identityFields = "_id, user_id, provider, provider_user_id, email, raw_profile, created_at, updated_at"
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.Key = identity.ID
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.Identity).Insert(identity.ID, identity, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	identity.UpdatedAt = time.Now().Unix()
+	params := make(map[string]interface{}, 1)
+	params["user_id"] = identity.UserID
+	params["email"] = identity.Email
+	params["raw_profile"] = identity.RawProfile
+	params["updated_at"] = identity.UpdatedAt
+	query := fmt.Sprintf(`UPDATE %s.%s SET user_id=$user_id, email=$email, raw_profile=$raw_profile, updated_at=$updated_at WHERE _id='%s'`, p.scopeName, models.Collections.Identity, identity.ID)
+	_, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	identities := []*model.Identity{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["user_id"] = userID
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	countQuery := fmt.Sprintf("SELECT COUNT(*) as Total FROM %s.%s WHERE user_id=$user_id", p.scopeName, models.Collections.Identity)
+	queryRes, err := p.db.Query(countQuery, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var totalDocs TotalDocs
+	err = queryRes.One(&totalDocs)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = totalDocs.Total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s WHERE user_id=$user_id ORDER BY created_at DESC OFFSET $offset LIMIT $limit", identityFields, p.scopeName, models.Collections.Identity)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var identity models.Identity
+		err := queryResult.Row(&identity)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity.AsAPIIdentity())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: identities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	var identity *models.Identity
+	params := make(map[string]interface{}, 1)
+	params["_id"] = id
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE _id=$_id LIMIT 1`, identityFields, p.scopeName, models.Collections.Identity)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	var identity *models.Identity
+	params := make(map[string]interface{}, 1)
+	params["provider"] = provider
+	params["provider_user_id"] = providerUserID
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE provider=$provider AND provider_user_id=$provider_user_id LIMIT 1`, identityFields, p.scopeName, models.Collections.Identity)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.Identity).Remove(identity.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
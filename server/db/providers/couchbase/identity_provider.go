@@ -0,0 +1,155 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const identityProviderFields = "_id, name, discovery_url, authorization_url, token_url, userinfo_url, client_id, client_secret, scopes, claim_mapping, created_at, updated_at"
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.Key = identityProvider.ID
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.IdentityProvider).Insert(identityProvider.ID, identityProvider, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	identityProvider.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	identityProviderMap := map[string]interface{}{}
+	err = decoder.Decode(&identityProviderMap)
+	if err != nil {
+		return nil, err
+	}
+	updateFields, params := GetSetFields(identityProviderMap)
+	query := fmt.Sprintf(`UPDATE %s.%s SET %s WHERE _id='%s'`, p.scopeName, models.Collections.IdentityProvider, updateFields, identityProvider.ID)
+	_, err = p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	identityProviders := []*model.IdentityProvider{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	total, err := p.GetTotalDocs(ctx, models.Collections.IdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s ORDER BY created_at DESC OFFSET $offset LIMIT $limit", identityProviderFields, p.scopeName, models.Collections.IdentityProvider)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var identityProvider models.IdentityProvider
+		err := queryResult.Row(&identityProvider)
+		if err != nil {
+			return nil, err
+		}
+		identityProviders = append(identityProviders, identityProvider.AsAPIIdentityProvider())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: identityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	params := make(map[string]interface{}, 1)
+	params["_id"] = id
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE _id=$_id LIMIT 1`, identityProviderFields, p.scopeName, models.Collections.IdentityProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	params := make(map[string]interface{}, 1)
+	params["name"] = name
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE name=$name LIMIT 1`, identityProviderFields, p.scopeName, models.Collections.IdentityProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.IdentityProvider).Remove(identityProvider.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
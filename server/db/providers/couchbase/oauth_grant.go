@@ -0,0 +1,134 @@
+package couchbase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const oauthGrantFields = "_id, user_id, client_id, scopes, created_at, updated_at"
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.Key = oauthGrant.ID
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.OAuthGrant).Insert(oauthGrant.ID, oauthGrant, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	params := make(map[string]interface{}, 1)
+	params["scopes"] = oauthGrant.Scopes
+	params["updated_at"] = oauthGrant.UpdatedAt
+	query := fmt.Sprintf(`UPDATE %s.%s SET scopes=$scopes, updated_at=$updated_at WHERE _id='%s'`, p.scopeName, models.Collections.OAuthGrant, oauthGrant.ID)
+	_, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	oauthGrants := []*model.OAuthGrant{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["user_id"] = userID
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	countQuery := fmt.Sprintf("SELECT COUNT(*) as Total FROM %s.%s WHERE user_id=$user_id", p.scopeName, models.Collections.OAuthGrant)
+	queryRes, err := p.db.Query(countQuery, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var totalDocs TotalDocs
+	err = queryRes.One(&totalDocs)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = totalDocs.Total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s WHERE user_id=$user_id ORDER BY created_at DESC OFFSET $offset LIMIT $limit", oauthGrantFields, p.scopeName, models.Collections.OAuthGrant)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var oauthGrant models.OAuthGrant
+		err := queryResult.Row(&oauthGrant)
+		if err != nil {
+			return nil, err
+		}
+		oauthGrants = append(oauthGrants, oauthGrant.AsAPIOAuthGrant())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: oauthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrant *models.OAuthGrant
+	params := make(map[string]interface{}, 1)
+	params["user_id"] = userID
+	params["client_id"] = clientID
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE user_id=$user_id AND client_id=$client_id LIMIT 1`, oauthGrantFields, p.scopeName, models.Collections.OAuthGrant)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.OAuthGrant).Remove(oauthGrant.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
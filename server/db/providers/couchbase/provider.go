@@ -170,5 +170,30 @@ func GetIndex(scopeName string) map[string][]string {
 	otpIndex2 := fmt.Sprintf("CREATE INDEX OTPPhoneNumberIndex ON %s.%s(phone_number)", scopeName, models.Collections.OTP)
 	indices[models.Collections.OTP] = []string{otpIndex2}
 
+	// Client index
+	clientIndex1 := fmt.Sprintf("CREATE INDEX ClientClientIDIndex ON %s.%s(client_id)", scopeName, models.Collections.Client)
+	indices[models.Collections.Client] = []string{clientIndex1}
+
+	// OAuthGrant index
+	oauthGrantIndex1 := fmt.Sprintf("CREATE INDEX OAuthGrantUserIDClientIDIndex ON %s.%s(user_id, client_id)", scopeName, models.Collections.OAuthGrant)
+	indices[models.Collections.OAuthGrant] = []string{oauthGrantIndex1}
+
+	// IdentityProvider index
+	identityProviderIndex1 := fmt.Sprintf("CREATE INDEX IdentityProviderNameIndex ON %s.%s(name)", scopeName, models.Collections.IdentityProvider)
+	indices[models.Collections.IdentityProvider] = []string{identityProviderIndex1}
+
+	// Identity index
+	identityIndex1 := fmt.Sprintf("CREATE INDEX IdentityProviderUserIDIndex ON %s.%s(provider, provider_user_id)", scopeName, models.Collections.Identity)
+	identityIndex2 := fmt.Sprintf("CREATE INDEX IdentityUserIDIndex ON %s.%s(user_id)", scopeName, models.Collections.Identity)
+	indices[models.Collections.Identity] = []string{identityIndex1, identityIndex2}
+
+	// SAMLServiceProvider index
+	samlServiceProviderIndex1 := fmt.Sprintf("CREATE INDEX SAMLServiceProviderEntityIDIndex ON %s.%s(entity_id)", scopeName, models.Collections.SAMLServiceProvider)
+	indices[models.Collections.SAMLServiceProvider] = []string{samlServiceProviderIndex1}
+
+	// SAMLIdentityProvider index
+	samlIdentityProviderIndex1 := fmt.Sprintf("CREATE INDEX SAMLIdentityProviderNameIndex ON %s.%s(name)", scopeName, models.Collections.SAMLIdentityProvider)
+	indices[models.Collections.SAMLIdentityProvider] = []string{samlIdentityProviderIndex1}
+
 	return indices
 }
@@ -0,0 +1,155 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const samlIdentityProviderFields = "_id, name, metadata_url, metadata_xml, attribute_mapping, allowed_email_domains, created_at, updated_at"
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.Key = samlIdentityProvider.ID
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.SAMLIdentityProvider).Insert(samlIdentityProvider.ID, samlIdentityProvider, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	samlIdentityProviderMap := map[string]interface{}{}
+	err = decoder.Decode(&samlIdentityProviderMap)
+	if err != nil {
+		return nil, err
+	}
+	updateFields, params := GetSetFields(samlIdentityProviderMap)
+	query := fmt.Sprintf(`UPDATE %s.%s SET %s WHERE _id='%s'`, p.scopeName, models.Collections.SAMLIdentityProvider, updateFields, samlIdentityProvider.ID)
+	_, err = p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	samlIdentityProviders := []*model.SAMLIdentityProvider{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	total, err := p.GetTotalDocs(ctx, models.Collections.SAMLIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s ORDER BY created_at DESC OFFSET $offset LIMIT $limit", samlIdentityProviderFields, p.scopeName, models.Collections.SAMLIdentityProvider)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var samlIdentityProvider models.SAMLIdentityProvider
+		err := queryResult.Row(&samlIdentityProvider)
+		if err != nil {
+			return nil, err
+		}
+		samlIdentityProviders = append(samlIdentityProviders, samlIdentityProvider.AsAPISAMLIdentityProvider())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: samlIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	params := make(map[string]interface{}, 1)
+	params["_id"] = id
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE _id=$_id LIMIT 1`, samlIdentityProviderFields, p.scopeName, models.Collections.SAMLIdentityProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	params := make(map[string]interface{}, 1)
+	params["name"] = name
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE name=$name LIMIT 1`, samlIdentityProviderFields, p.scopeName, models.Collections.SAMLIdentityProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.SAMLIdentityProvider).Remove(samlIdentityProvider.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package couchbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+const samlServiceProviderFields = "_id, name, entity_id, acs_url, name_id_format, attribute_mapping, created_at, updated_at"
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.Key = samlServiceProvider.ID
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	insertOpt := gocb.InsertOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.SAMLServiceProvider).Insert(samlServiceProvider.ID, samlServiceProvider, &insertOpt)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	bytes, err := json.Marshal(samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	// use decoder instead of json.Unmarshall, because it converts int64 -> float64 after unmarshalling
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	samlServiceProviderMap := map[string]interface{}{}
+	err = decoder.Decode(&samlServiceProviderMap)
+	if err != nil {
+		return nil, err
+	}
+	updateFields, params := GetSetFields(samlServiceProviderMap)
+	query := fmt.Sprintf(`UPDATE %s.%s SET %s WHERE _id='%s'`, p.scopeName, models.Collections.SAMLServiceProvider, updateFields, samlServiceProvider.ID)
+	_, err = p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	samlServiceProviders := []*model.SAMLServiceProvider{}
+	paginationClone := pagination
+	params := make(map[string]interface{}, 1)
+	params["offset"] = paginationClone.Offset
+	params["limit"] = paginationClone.Limit
+	total, err := p.GetTotalDocs(ctx, models.Collections.SAMLServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = total
+	query := fmt.Sprintf("SELECT %s FROM %s.%s ORDER BY created_at DESC OFFSET $offset LIMIT $limit", samlServiceProviderFields, p.scopeName, models.Collections.SAMLServiceProvider)
+	queryResult, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for queryResult.Next() {
+		var samlServiceProvider models.SAMLServiceProvider
+		err := queryResult.Row(&samlServiceProvider)
+		if err != nil {
+			return nil, err
+		}
+		samlServiceProviders = append(samlServiceProviders, samlServiceProvider.AsAPISAMLServiceProvider())
+	}
+	if err := queryResult.Err(); err != nil {
+		return nil, err
+	}
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: samlServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	params := make(map[string]interface{}, 1)
+	params["_id"] = id
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE _id=$_id LIMIT 1`, samlServiceProviderFields, p.scopeName, models.Collections.SAMLServiceProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	params := make(map[string]interface{}, 1)
+	params["entity_id"] = entityID
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE entity_id=$entity_id LIMIT 1`, samlServiceProviderFields, p.scopeName, models.Collections.SAMLServiceProvider)
+	q, err := p.db.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = q.One(&samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	removeOpt := gocb.RemoveOptions{
+		Context: ctx,
+	}
+	_, err := p.db.Collection(models.Collections.SAMLServiceProvider).Remove(samlServiceProvider.ID, &removeOpt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
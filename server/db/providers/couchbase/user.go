@@ -74,6 +74,17 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 	if err != nil {
 		return err
 	}
+	params := make(map[string]interface{}, 1)
+	params["user_id"] = user.ID
+	deleteOAuthGrantsQuery := fmt.Sprintf("DELETE FROM %s.%s WHERE user_id=$user_id", p.scopeName, models.Collections.OAuthGrant)
+	_, err = p.db.Query(deleteOAuthGrantsQuery, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: params,
+	})
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,113 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/dynamo"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddClient to add oauth client
+func (p *provider) AddClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	collection := p.db.Table(models.Collections.Client)
+	if client.ID == "" {
+		client.ID = uuid.New().String()
+	}
+	client.Key = client.ID
+	client.CreatedAt = time.Now().Unix()
+	client.UpdatedAt = time.Now().Unix()
+	err := collection.Put(client).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// UpdateClient to update oauth client
+func (p *provider) UpdateClient(ctx context.Context, client *models.Client) (*models.Client, error) {
+	collection := p.db.Table(models.Collections.Client)
+	client.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", client.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ListClients to list oauth clients
+func (p *provider) ListClients(ctx context.Context, pagination *model.Pagination) (*model.Clients, error) {
+	clients := []*model.Client{}
+	var client *models.Client
+	var lastEval dynamo.PagingKey
+	var iter dynamo.PagingIter
+	var iteration int64 = 0
+	collection := p.db.Table(models.Collections.Client)
+	paginationClone := pagination
+	scanner := collection.Scan()
+	count, err := scanner.Count()
+	if err != nil {
+		return nil, err
+	}
+	for (paginationClone.Offset + paginationClone.Limit) > iteration {
+		iter = scanner.StartFrom(lastEval).Limit(paginationClone.Limit).Iter()
+		for iter.NextWithContext(ctx, &client) {
+			if paginationClone.Offset == iteration {
+				clients = append(clients, client.AsAPIClient())
+			}
+		}
+		err = iter.Err()
+		if err != nil {
+			return nil, err
+		}
+		lastEval = iter.LastEvaluatedKey()
+		iteration += paginationClone.Limit
+	}
+	paginationClone.Total = count
+	return &model.Clients{
+		Pagination: paginationClone,
+		Clients:    clients,
+	}, nil
+}
+
+// GetClientByID to get oauth client by id
+func (p *provider) GetClientByID(ctx context.Context, id string) (*models.Client, error) {
+	collection := p.db.Table(models.Collections.Client)
+	var client *models.Client
+	err := collection.Get("id", id).OneWithContext(ctx, &client)
+	if err != nil {
+		return nil, err
+	}
+	if client.ID == "" {
+		return nil, errors.New("no documets found")
+	}
+	return client, nil
+}
+
+// GetClientByClientID to get oauth client by client_id
+func (p *provider) GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	var clients []*models.Client
+	collection := p.db.Table(models.Collections.Client)
+	err := collection.Scan().Index("client_id").Filter("'client_id' = ?", clientID).Limit(1).AllWithContext(ctx, &clients)
+	if err != nil {
+		return nil, err
+	}
+	if len(clients) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return clients[0], nil
+}
+
+// DeleteClient to delete oauth client
+func (p *provider) DeleteClient(ctx context.Context, client *models.Client) error {
+	collection := p.db.Table(models.Collections.Client)
+	err := collection.Delete("id", client.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	collection := p.db.Table(models.Collections.Identity)
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.Key = identity.ID
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	err := collection.Put(identity).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	collection := p.db.Table(models.Collections.Identity)
+	identity.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", identity.ID, identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+// user has few identities only, hence pagination is applied after filtering by user_id
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	identities := []*model.Identity{}
+	var userIdentities []*models.Identity
+	collection := p.db.Table(models.Collections.Identity)
+	err := collection.Scan().Index("user_id").Filter("'user_id' = ?", userID).AllWithContext(ctx, &userIdentities)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone := pagination
+	paginationClone.Total = int64(len(userIdentities))
+	for i, identity := range userIdentities {
+		if int64(i) >= pagination.Offset && int64(i) < pagination.Offset+pagination.Limit {
+			identities = append(identities, identity.AsAPIIdentity())
+		}
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: identities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	collection := p.db.Table(models.Collections.Identity)
+	var identity *models.Identity
+	err := collection.Get("id", id).OneWithContext(ctx, &identity)
+	if err != nil {
+		return nil, err
+	}
+	if identity.ID == "" {
+		return nil, errors.New("no documets found")
+	}
+	return identity, nil
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	var identities []*models.Identity
+	collection := p.db.Table(models.Collections.Identity)
+	err := collection.Scan().Index("provider").Filter("'provider' = ?", provider).Filter("'provider_user_id' = ?", providerUserID).Limit(1).AllWithContext(ctx, &identities)
+	if err != nil {
+		return nil, err
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return identities[0], nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	collection := p.db.Table(models.Collections.Identity)
+	err := collection.Delete("id", identity.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
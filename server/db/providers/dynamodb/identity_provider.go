@@ -0,0 +1,113 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/dynamo"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.Key = identityProvider.ID
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	err := collection.Put(identityProvider).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	identityProvider.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", identityProvider.ID, identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	identityProviders := []*model.IdentityProvider{}
+	var identityProvider *models.IdentityProvider
+	var lastEval dynamo.PagingKey
+	var iter dynamo.PagingIter
+	var iteration int64 = 0
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	paginationClone := pagination
+	scanner := collection.Scan()
+	count, err := scanner.Count()
+	if err != nil {
+		return nil, err
+	}
+	for (paginationClone.Offset + paginationClone.Limit) > iteration {
+		iter = scanner.StartFrom(lastEval).Limit(paginationClone.Limit).Iter()
+		for iter.NextWithContext(ctx, &identityProvider) {
+			if paginationClone.Offset == iteration {
+				identityProviders = append(identityProviders, identityProvider.AsAPIIdentityProvider())
+			}
+		}
+		err = iter.Err()
+		if err != nil {
+			return nil, err
+		}
+		lastEval = iter.LastEvaluatedKey()
+		iteration += paginationClone.Limit
+	}
+	paginationClone.Total = count
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: identityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	var identityProvider *models.IdentityProvider
+	err := collection.Get("id", id).OneWithContext(ctx, &identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	if identityProvider.ID == "" {
+		return nil, errors.New("no documets found")
+	}
+	return identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProviders []*models.IdentityProvider
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	err := collection.Scan().Index("name").Filter("'name' = ?", name).Limit(1).AllWithContext(ctx, &identityProviders)
+	if err != nil {
+		return nil, err
+	}
+	if len(identityProviders) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return identityProviders[0], nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	collection := p.db.Table(models.Collections.IdentityProvider)
+	err := collection.Delete("id", identityProvider.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	collection := p.db.Table(models.Collections.OAuthGrant)
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.Key = oauthGrant.ID
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	err := collection.Put(oauthGrant).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	collection := p.db.Table(models.Collections.OAuthGrant)
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", oauthGrant.ID, oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+// user has grants for few clients only, hence pagination is applied after filtering by user_id
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	oauthGrants := []*model.OAuthGrant{}
+	var userOAuthGrants []*models.OAuthGrant
+	collection := p.db.Table(models.Collections.OAuthGrant)
+	err := collection.Scan().Index("user_id").Filter("'user_id' = ?", userID).AllWithContext(ctx, &userOAuthGrants)
+	if err != nil {
+		return nil, err
+	}
+	paginationClone := pagination
+	paginationClone.Total = int64(len(userOAuthGrants))
+	for i, oauthGrant := range userOAuthGrants {
+		if int64(i) >= pagination.Offset && int64(i) < pagination.Offset+pagination.Limit {
+			oauthGrants = append(oauthGrants, oauthGrant.AsAPIOAuthGrant())
+		}
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: oauthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrants []*models.OAuthGrant
+	collection := p.db.Table(models.Collections.OAuthGrant)
+	err := collection.Scan().Index("user_id").Filter("'user_id' = ?", userID).Filter("'client_id' = ?", clientID).Limit(1).AllWithContext(ctx, &oauthGrants)
+	if err != nil {
+		return nil, err
+	}
+	if len(oauthGrants) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return oauthGrants[0], nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	collection := p.db.Table(models.Collections.OAuthGrant)
+	err := collection.Delete("id", oauthGrant.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -53,6 +53,12 @@ func NewProvider() (*provider, error) {
 	db.CreateTable(models.Collections.Webhook, models.Webhook{}).Wait()
 	db.CreateTable(models.Collections.WebhookLog, models.WebhookLog{}).Wait()
 	db.CreateTable(models.Collections.Authenticators, models.Authenticator{}).Wait()
+	db.CreateTable(models.Collections.Client, models.Client{}).Wait()
+	db.CreateTable(models.Collections.OAuthGrant, models.OAuthGrant{}).Wait()
+	db.CreateTable(models.Collections.IdentityProvider, models.IdentityProvider{}).Wait()
+	db.CreateTable(models.Collections.SAMLServiceProvider, models.SAMLServiceProvider{}).Wait()
+	db.CreateTable(models.Collections.SAMLIdentityProvider, models.SAMLIdentityProvider{}).Wait()
+	db.CreateTable(models.Collections.Identity, models.Identity{}).Wait()
 	return &provider{
 		db: db,
 	}, nil
@@ -0,0 +1,113 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/dynamo"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.Key = samlIdentityProvider.ID
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	err := collection.Put(samlIdentityProvider).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", samlIdentityProvider.ID, samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	samlIdentityProviders := []*model.SAMLIdentityProvider{}
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	var lastEval dynamo.PagingKey
+	var iter dynamo.PagingIter
+	var iteration int64 = 0
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	paginationClone := pagination
+	scanner := collection.Scan()
+	count, err := scanner.Count()
+	if err != nil {
+		return nil, err
+	}
+	for (paginationClone.Offset + paginationClone.Limit) > iteration {
+		iter = scanner.StartFrom(lastEval).Limit(paginationClone.Limit).Iter()
+		for iter.NextWithContext(ctx, &samlIdentityProvider) {
+			if paginationClone.Offset == iteration {
+				samlIdentityProviders = append(samlIdentityProviders, samlIdentityProvider.AsAPISAMLIdentityProvider())
+			}
+		}
+		err = iter.Err()
+		if err != nil {
+			return nil, err
+		}
+		lastEval = iter.LastEvaluatedKey()
+		iteration += paginationClone.Limit
+	}
+	paginationClone.Total = count
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: samlIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	err := collection.Get("id", id).OneWithContext(ctx, &samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	if samlIdentityProvider.ID == "" {
+		return nil, errors.New("no documets found")
+	}
+	return samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProviders []*models.SAMLIdentityProvider
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	err := collection.Scan().Index("name").Filter("'name' = ?", name).Limit(1).AllWithContext(ctx, &samlIdentityProviders)
+	if err != nil {
+		return nil, err
+	}
+	if len(samlIdentityProviders) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return samlIdentityProviders[0], nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	collection := p.db.Table(models.Collections.SAMLIdentityProvider)
+	err := collection.Delete("id", samlIdentityProvider.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/dynamo"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.Key = samlServiceProvider.ID
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	err := collection.Put(samlServiceProvider).RunWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	err := UpdateByHashKey(collection, "id", samlServiceProvider.ID, samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	samlServiceProviders := []*model.SAMLServiceProvider{}
+	var samlServiceProvider *models.SAMLServiceProvider
+	var lastEval dynamo.PagingKey
+	var iter dynamo.PagingIter
+	var iteration int64 = 0
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	paginationClone := pagination
+	scanner := collection.Scan()
+	count, err := scanner.Count()
+	if err != nil {
+		return nil, err
+	}
+	for (paginationClone.Offset + paginationClone.Limit) > iteration {
+		iter = scanner.StartFrom(lastEval).Limit(paginationClone.Limit).Iter()
+		for iter.NextWithContext(ctx, &samlServiceProvider) {
+			if paginationClone.Offset == iteration {
+				samlServiceProviders = append(samlServiceProviders, samlServiceProvider.AsAPISAMLServiceProvider())
+			}
+		}
+		err = iter.Err()
+		if err != nil {
+			return nil, err
+		}
+		lastEval = iter.LastEvaluatedKey()
+		iteration += paginationClone.Limit
+	}
+	paginationClone.Total = count
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: samlServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	var samlServiceProvider *models.SAMLServiceProvider
+	err := collection.Get("id", id).OneWithContext(ctx, &samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	if samlServiceProvider.ID == "" {
+		return nil, errors.New("no documets found")
+	}
+	return samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProviders []*models.SAMLServiceProvider
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	err := collection.Scan().Index("entity_id").Filter("'entity_id' = ?", entityID).Limit(1).AllWithContext(ctx, &samlServiceProviders)
+	if err != nil {
+		return nil, err
+	}
+	if len(samlServiceProviders) == 0 {
+		return nil, errors.New("no documets found")
+	}
+	return samlServiceProviders[0], nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	collection := p.db.Table(models.Collections.SAMLServiceProvider)
+	err := collection.Delete("id", samlServiceProvider.ID).RunWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -74,6 +74,18 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 		if err != nil {
 			return err
 		}
+		var oauthGrants []*models.OAuthGrant
+		oauthGrantCollection := p.db.Table(models.Collections.OAuthGrant)
+		err = oauthGrantCollection.Scan().Index("user_id").Filter("'user_id' = ?", user.ID).AllWithContext(ctx, &oauthGrants)
+		if err != nil {
+			return err
+		}
+		for _, oauthGrant := range oauthGrants {
+			err = oauthGrantCollection.Delete("id", oauthGrant.ID).RunWithContext(ctx)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.Key = identity.ID
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	_, err := identityCollection.InsertOne(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	identity.UpdatedAt = time.Now().Unix()
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	_, err := identityCollection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": identity.ID}}, bson.M{"$set": identity}, options.MergeUpdateOptions())
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	identities := []*model.Identity{}
+	opts := options.Find()
+	opts.SetLimit(pagination.Limit)
+	opts.SetSkip(pagination.Offset)
+	opts.SetSort(bson.M{"created_at": -1})
+	paginationClone := pagination
+	query := bson.M{"user_id": userID}
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	count, err := identityCollection.CountDocuments(ctx, query, options.Count())
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = count
+	cursor, err := identityCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var identity *models.Identity
+		err := cursor.Decode(&identity)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity.AsAPIIdentity())
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: identities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	var identity *models.Identity
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	err := identityCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	var identity *models.Identity
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	err := identityCollection.FindOne(ctx, bson.M{"provider": provider, "provider_user_id": providerUserID}).Decode(&identity)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	identityCollection := p.db.Collection(models.Collections.Identity, options.Collection())
+	_, err := identityCollection.DeleteOne(ctx, bson.M{"_id": identity.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.Key = identityProvider.ID
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	_, err := identityProviderCollection.InsertOne(ctx, identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	identityProvider.UpdatedAt = time.Now().Unix()
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	_, err := identityProviderCollection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": identityProvider.ID}}, bson.M{"$set": identityProvider}, options.MergeUpdateOptions())
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	identityProviders := []*model.IdentityProvider{}
+	opts := options.Find()
+	opts.SetLimit(pagination.Limit)
+	opts.SetSkip(pagination.Offset)
+	opts.SetSort(bson.M{"created_at": -1})
+	paginationClone := pagination
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	count, err := identityProviderCollection.CountDocuments(ctx, bson.M{}, options.Count())
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = count
+	cursor, err := identityProviderCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var identityProvider *models.IdentityProvider
+		err := cursor.Decode(&identityProvider)
+		if err != nil {
+			return nil, err
+		}
+		identityProviders = append(identityProviders, identityProvider.AsAPIIdentityProvider())
+	}
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: identityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	err := identityProviderCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	err := identityProviderCollection.FindOne(ctx, bson.M{"name": name}).Decode(&identityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return identityProvider, nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	identityProviderCollection := p.db.Collection(models.Collections.IdentityProvider, options.Collection())
+	_, err := identityProviderCollection.DeleteOne(ctx, bson.M{"_id": identityProvider.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
+	return nil
+}
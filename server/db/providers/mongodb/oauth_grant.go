@@ -0,0 +1,95 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.Key = oauthGrant.ID
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	_, err := oauthGrantCollection.InsertOne(ctx, oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	_, err := oauthGrantCollection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": oauthGrant.ID}}, bson.M{"$set": oauthGrant}, options.MergeUpdateOptions())
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	oauthGrants := []*model.OAuthGrant{}
+	opts := options.Find()
+	opts.SetLimit(pagination.Limit)
+	opts.SetSkip(pagination.Offset)
+	opts.SetSort(bson.M{"created_at": -1})
+	paginationClone := pagination
+	query := bson.M{"user_id": userID}
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	count, err := oauthGrantCollection.CountDocuments(ctx, query, options.Count())
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = count
+	cursor, err := oauthGrantCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var oauthGrant *models.OAuthGrant
+		err := cursor.Decode(&oauthGrant)
+		if err != nil {
+			return nil, err
+		}
+		oauthGrants = append(oauthGrants, oauthGrant.AsAPIOAuthGrant())
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: oauthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrant *models.OAuthGrant
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	err := oauthGrantCollection.FindOne(ctx, bson.M{"user_id": userID, "client_id": clientID}).Decode(&oauthGrant)
+	if err != nil {
+		return nil, err
+	}
+	return oauthGrant, nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	_, err := oauthGrantCollection.DeleteOne(ctx, bson.M{"_id": oauthGrant.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -131,6 +131,63 @@ func NewProvider() (*provider, error) {
 		},
 	}, options.CreateIndexes())
 
+	mongodb.CreateCollection(ctx, models.Collections.Client, options.CreateCollection())
+	clientCollection := mongodb.Collection(models.Collections.Client, options.Collection())
+	clientCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"client_id": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}, options.CreateIndexes())
+
+	mongodb.CreateCollection(ctx, models.Collections.OAuthGrant, options.CreateCollection())
+	oauthGrantCollection := mongodb.Collection(models.Collections.OAuthGrant, options.Collection())
+	oauthGrantCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"user_id": 1, "client_id": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}, options.CreateIndexes())
+
+	mongodb.CreateCollection(ctx, models.Collections.Identity, options.CreateCollection())
+	identityCollection := mongodb.Collection(models.Collections.Identity, options.Collection())
+	identityCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"provider": 1, "provider_user_id": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys: bson.M{"user_id": 1},
+		},
+	}, options.CreateIndexes())
+
+	mongodb.CreateCollection(ctx, models.Collections.IdentityProvider, options.CreateCollection())
+	identityProviderCollection := mongodb.Collection(models.Collections.IdentityProvider, options.Collection())
+	identityProviderCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"name": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}, options.CreateIndexes())
+
+	mongodb.CreateCollection(ctx, models.Collections.SAMLServiceProvider, options.CreateCollection())
+	samlServiceProviderCollection := mongodb.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	samlServiceProviderCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"entity_id": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}, options.CreateIndexes())
+
+	mongodb.CreateCollection(ctx, models.Collections.SAMLIdentityProvider, options.CreateCollection())
+	samlIdentityProviderCollection := mongodb.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	samlIdentityProviderCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.M{"name": 1},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+	}, options.CreateIndexes())
+
 	return &provider{
 		db: mongodb,
 	}, nil
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.Key = samlIdentityProvider.ID
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	_, err := samlIdentityProviderCollection.InsertOne(ctx, samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	_, err := samlIdentityProviderCollection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": samlIdentityProvider.ID}}, bson.M{"$set": samlIdentityProvider}, options.MergeUpdateOptions())
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	samlIdentityProviders := []*model.SAMLIdentityProvider{}
+	opts := options.Find()
+	opts.SetLimit(pagination.Limit)
+	opts.SetSkip(pagination.Offset)
+	opts.SetSort(bson.M{"created_at": -1})
+	paginationClone := pagination
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	count, err := samlIdentityProviderCollection.CountDocuments(ctx, bson.M{}, options.Count())
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = count
+	cursor, err := samlIdentityProviderCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var samlIdentityProvider *models.SAMLIdentityProvider
+		err := cursor.Decode(&samlIdentityProvider)
+		if err != nil {
+			return nil, err
+		}
+		samlIdentityProviders = append(samlIdentityProviders, samlIdentityProvider.AsAPISAMLIdentityProvider())
+	}
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: samlIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	err := samlIdentityProviderCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	err := samlIdentityProviderCollection.FindOne(ctx, bson.M{"name": name}).Decode(&samlIdentityProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlIdentityProvider, nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	samlIdentityProviderCollection := p.db.Collection(models.Collections.SAMLIdentityProvider, options.Collection())
+	_, err := samlIdentityProviderCollection.DeleteOne(ctx, bson.M{"_id": samlIdentityProvider.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
+	return nil
+}
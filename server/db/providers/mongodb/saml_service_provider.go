@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.Key = samlServiceProvider.ID
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	_, err := samlServiceProviderCollection.InsertOne(ctx, samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	_, err := samlServiceProviderCollection.UpdateOne(ctx, bson.M{"_id": bson.M{"$eq": samlServiceProvider.ID}}, bson.M{"$set": samlServiceProvider}, options.MergeUpdateOptions())
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	samlServiceProviders := []*model.SAMLServiceProvider{}
+	opts := options.Find()
+	opts.SetLimit(pagination.Limit)
+	opts.SetSkip(pagination.Offset)
+	opts.SetSort(bson.M{"created_at": -1})
+	paginationClone := pagination
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	count, err := samlServiceProviderCollection.CountDocuments(ctx, bson.M{}, options.Count())
+	if err != nil {
+		return nil, err
+	}
+	paginationClone.Total = count
+	cursor, err := samlServiceProviderCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var samlServiceProvider *models.SAMLServiceProvider
+		err := cursor.Decode(&samlServiceProvider)
+		if err != nil {
+			return nil, err
+		}
+		samlServiceProviders = append(samlServiceProviders, samlServiceProvider.AsAPISAMLServiceProvider())
+	}
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: samlServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	err := samlServiceProviderCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	err := samlServiceProviderCollection.FindOne(ctx, bson.M{"entity_id": entityID}).Decode(&samlServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	return samlServiceProvider, nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	samlServiceProviderCollection := p.db.Collection(models.Collections.SAMLServiceProvider, options.Collection())
+	_, err := samlServiceProviderCollection.DeleteOne(ctx, bson.M{"_id": samlServiceProvider.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
+	return nil
+}
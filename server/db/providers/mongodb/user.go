@@ -73,6 +73,11 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 	if err != nil {
 		return err
 	}
+	oauthGrantCollection := p.db.Collection(models.Collections.OAuthGrant, options.Collection())
+	_, err = oauthGrantCollection.DeleteMany(ctx, bson.M{"user_id": user.ID}, options.Delete())
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -12,7 +12,7 @@ type Provider interface {
 	AddUser(ctx context.Context, user *models.User) (*models.User, error)
 	// UpdateUser to update user information in database
 	UpdateUser(ctx context.Context, user *models.User) (*models.User, error)
-	// DeleteUser to delete user information from database
+	// DeleteUser to delete user information from database along with sessions & oauth grants of user
 	DeleteUser(ctx context.Context, user *models.User) error
 	// ListUsers to get list of users from database
 	ListUsers(ctx context.Context, pagination *model.Pagination) (*model.Users, error)
@@ -99,4 +99,80 @@ type Provider interface {
 	// GetAuthenticatorDetailsByUserId retrieves details of an authenticator document based on user ID and authenticator type.
 	// If found, the authenticator document is returned, or an error if not found or an error occurs during the retrieval.
 	GetAuthenticatorDetailsByUserId(ctx context.Context, userId string, authenticatorType string) (*models.Authenticator, error)
+
+	// AddClient to add oauth client
+	AddClient(ctx context.Context, client *models.Client) (*models.Client, error)
+	// UpdateClient to update oauth client
+	UpdateClient(ctx context.Context, client *models.Client) (*models.Client, error)
+	// ListClients to list oauth clients
+	ListClients(ctx context.Context, pagination *model.Pagination) (*model.Clients, error)
+	// GetClientByID to get oauth client by id
+	GetClientByID(ctx context.Context, id string) (*models.Client, error)
+	// GetClientByClientID to get oauth client by client_id
+	GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error)
+	// DeleteClient to delete oauth client
+	DeleteClient(ctx context.Context, client *models.Client) error
+
+	// AddOAuthGrant to add consent given by user to oauth client
+	AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error)
+	// UpdateOAuthGrant to update scopes granted by user to oauth client
+	UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error)
+	// ListOAuthGrants to list oauth grants given by user
+	ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error)
+	// GetOAuthGrant to get oauth grant given by user to oauth client
+	GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error)
+	// DeleteOAuthGrant to delete oauth grant
+	DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error
+
+	// AddIdentity to add identity of user at login provider
+	AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error)
+	// UpdateIdentity to update identity of user at login provider
+	UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error)
+	// ListIdentities to list identities of user
+	ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error)
+	// GetIdentityByID to get identity by id
+	GetIdentityByID(ctx context.Context, id string) (*models.Identity, error)
+	// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+	GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error)
+	// DeleteIdentity to delete identity
+	DeleteIdentity(ctx context.Context, identity *models.Identity) error
+
+	// AddIdentityProvider to add upstream identity provider
+	AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error)
+	// UpdateIdentityProvider to update upstream identity provider
+	UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error)
+	// ListIdentityProviders to list upstream identity providers
+	ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error)
+	// GetIdentityProviderByID to get upstream identity provider by id
+	GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error)
+	// GetIdentityProviderByName to get upstream identity provider by name
+	GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error)
+	// DeleteIdentityProvider to delete upstream identity provider
+	DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error
+
+	// AddSAMLServiceProvider to add saml service provider
+	AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error)
+	// UpdateSAMLServiceProvider to update saml service provider
+	UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error)
+	// ListSAMLServiceProviders to list saml service providers
+	ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error)
+	// GetSAMLServiceProviderByID to get saml service provider by id
+	GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error)
+	// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+	GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error)
+	// DeleteSAMLServiceProvider to delete saml service provider
+	DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error
+
+	// AddSAMLIdentityProvider to add saml identity provider
+	AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error)
+	// UpdateSAMLIdentityProvider to update saml identity provider
+	UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error)
+	// ListSAMLIdentityProviders to list saml identity providers
+	ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error)
+	// GetSAMLIdentityProviderByID to get saml identity provider by id
+	GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error)
+	// GetSAMLIdentityProviderByName to get saml identity provider by name
+	GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error)
+	// DeleteSAMLIdentityProvider to delete saml identity provider
+	DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error
 }
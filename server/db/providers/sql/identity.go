@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentity to add identity of user at login provider
+func (p *provider) AddIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	if identity.ID == "" {
+		identity.ID = uuid.New().String()
+	}
+	identity.Key = identity.ID
+	identity.CreatedAt = time.Now().Unix()
+	identity.UpdatedAt = time.Now().Unix()
+	res := p.db.Create(&identity)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return identity, nil
+}
+
+// UpdateIdentity to update identity of user at login provider
+func (p *provider) UpdateIdentity(ctx context.Context, identity *models.Identity) (*models.Identity, error) {
+	identity.UpdatedAt = time.Now().Unix()
+	result := p.db.Save(&identity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identity, nil
+}
+
+// ListIdentities to list identities of user
+func (p *provider) ListIdentities(ctx context.Context, pagination *model.Pagination, userID string) (*model.Identities, error) {
+	var identities []models.Identity
+	result := p.db.Where("user_id = ?", userID).Limit(int(pagination.Limit)).Offset(int(pagination.Offset)).Order("created_at DESC").Find(&identities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var total int64
+	totalRes := p.db.Model(&models.Identity{}).Where("user_id = ?", userID).Count(&total)
+	if totalRes.Error != nil {
+		return nil, totalRes.Error
+	}
+	paginationClone := pagination
+	paginationClone.Total = total
+	responseIdentities := []*model.Identity{}
+	for _, i := range identities {
+		responseIdentities = append(responseIdentities, i.AsAPIIdentity())
+	}
+	return &model.Identities{
+		Pagination: paginationClone,
+		Identities: responseIdentities,
+	}, nil
+}
+
+// GetIdentityByID to get identity by id
+func (p *provider) GetIdentityByID(ctx context.Context, id string) (*models.Identity, error) {
+	var identity *models.Identity
+	result := p.db.Where("id = ?", id).First(&identity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identity, nil
+}
+
+// GetIdentityByProviderUserID to get identity by provider & subject id of user at provider
+func (p *provider) GetIdentityByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.Identity, error) {
+	var identity *models.Identity
+	result := p.db.Where("provider = ? AND provider_user_id = ?", provider, providerUserID).First(&identity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identity, nil
+}
+
+// DeleteIdentity to delete identity
+func (p *provider) DeleteIdentity(ctx context.Context, identity *models.Identity) error {
+	result := p.db.Delete(&models.Identity{
+		ID: identity.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
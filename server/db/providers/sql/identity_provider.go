@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddIdentityProvider to add upstream identity provider
+func (p *provider) AddIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	if identityProvider.ID == "" {
+		identityProvider.ID = uuid.New().String()
+	}
+	identityProvider.Key = identityProvider.ID
+	identityProvider.CreatedAt = time.Now().Unix()
+	identityProvider.UpdatedAt = time.Now().Unix()
+	res := p.db.Create(&identityProvider)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return identityProvider, nil
+}
+
+// UpdateIdentityProvider to update upstream identity provider
+func (p *provider) UpdateIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) (*models.IdentityProvider, error) {
+	identityProvider.UpdatedAt = time.Now().Unix()
+	result := p.db.Save(&identityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identityProvider, nil
+}
+
+// ListIdentityProviders to list upstream identity providers
+func (p *provider) ListIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.IdentityProviders, error) {
+	var identityProviders []models.IdentityProvider
+	result := p.db.Limit(int(pagination.Limit)).Offset(int(pagination.Offset)).Order("created_at DESC").Find(&identityProviders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var total int64
+	totalRes := p.db.Model(&models.IdentityProvider{}).Count(&total)
+	if totalRes.Error != nil {
+		return nil, totalRes.Error
+	}
+	paginationClone := pagination
+	paginationClone.Total = total
+	responseIdentityProviders := []*model.IdentityProvider{}
+	for _, c := range identityProviders {
+		responseIdentityProviders = append(responseIdentityProviders, c.AsAPIIdentityProvider())
+	}
+	return &model.IdentityProviders{
+		Pagination:        paginationClone,
+		IdentityProviders: responseIdentityProviders,
+	}, nil
+}
+
+// GetIdentityProviderByID to get upstream identity provider by id
+func (p *provider) GetIdentityProviderByID(ctx context.Context, id string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	result := p.db.Where("id = ?", id).First(&identityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identityProvider, nil
+}
+
+// GetIdentityProviderByName to get upstream identity provider by name
+func (p *provider) GetIdentityProviderByName(ctx context.Context, name string) (*models.IdentityProvider, error) {
+	var identityProvider *models.IdentityProvider
+	result := p.db.Where("name = ?", name).First(&identityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return identityProvider, nil
+}
+
+// DeleteIdentityProvider to delete upstream identity provider
+func (p *provider) DeleteIdentityProvider(ctx context.Context, identityProvider *models.IdentityProvider) error {
+	result := p.db.Delete(&models.IdentityProvider{
+		ID: identityProvider.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
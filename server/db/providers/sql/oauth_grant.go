@@ -0,0 +1,81 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddOAuthGrant to add consent given by user to oauth client
+func (p *provider) AddOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	if oauthGrant.ID == "" {
+		oauthGrant.ID = uuid.New().String()
+	}
+	oauthGrant.Key = oauthGrant.ID
+	oauthGrant.CreatedAt = time.Now().Unix()
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	res := p.db.Create(&oauthGrant)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return oauthGrant, nil
+}
+
+// UpdateOAuthGrant to update scopes granted by user to oauth client
+func (p *provider) UpdateOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) (*models.OAuthGrant, error) {
+	oauthGrant.UpdatedAt = time.Now().Unix()
+	result := p.db.Save(&oauthGrant)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return oauthGrant, nil
+}
+
+// ListOAuthGrants to list oauth grants given by user
+func (p *provider) ListOAuthGrants(ctx context.Context, pagination *model.Pagination, userID string) (*model.OAuthGrants, error) {
+	var oauthGrants []models.OAuthGrant
+	result := p.db.Where("user_id = ?", userID).Limit(int(pagination.Limit)).Offset(int(pagination.Offset)).Order("created_at DESC").Find(&oauthGrants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var total int64
+	totalRes := p.db.Model(&models.OAuthGrant{}).Where("user_id = ?", userID).Count(&total)
+	if totalRes.Error != nil {
+		return nil, totalRes.Error
+	}
+	paginationClone := pagination
+	paginationClone.Total = total
+	responseOAuthGrants := []*model.OAuthGrant{}
+	for _, g := range oauthGrants {
+		responseOAuthGrants = append(responseOAuthGrants, g.AsAPIOAuthGrant())
+	}
+	return &model.OAuthGrants{
+		Pagination:  paginationClone,
+		OauthGrants: responseOAuthGrants,
+	}, nil
+}
+
+// GetOAuthGrant to get oauth grant given by user to oauth client
+func (p *provider) GetOAuthGrant(ctx context.Context, userID, clientID string) (*models.OAuthGrant, error) {
+	var oauthGrant *models.OAuthGrant
+	result := p.db.Where("user_id = ? AND client_id = ?", userID, clientID).First(&oauthGrant)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return oauthGrant, nil
+}
+
+// DeleteOAuthGrant to delete oauth grant
+func (p *provider) DeleteOAuthGrant(ctx context.Context, oauthGrant *models.OAuthGrant) error {
+	result := p.db.Delete(&models.OAuthGrant{
+		ID: oauthGrant.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLIdentityProvider to add saml identity provider
+func (p *provider) AddSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	if samlIdentityProvider.ID == "" {
+		samlIdentityProvider.ID = uuid.New().String()
+	}
+	samlIdentityProvider.Key = samlIdentityProvider.ID
+	samlIdentityProvider.CreatedAt = time.Now().Unix()
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	res := p.db.Create(&samlIdentityProvider)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return samlIdentityProvider, nil
+}
+
+// UpdateSAMLIdentityProvider to update saml identity provider
+func (p *provider) UpdateSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) (*models.SAMLIdentityProvider, error) {
+	samlIdentityProvider.UpdatedAt = time.Now().Unix()
+	result := p.db.Save(&samlIdentityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlIdentityProvider, nil
+}
+
+// ListSAMLIdentityProviders to list saml identity providers
+func (p *provider) ListSAMLIdentityProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLIdentityProviders, error) {
+	var samlIdentityProviders []models.SAMLIdentityProvider
+	result := p.db.Limit(int(pagination.Limit)).Offset(int(pagination.Offset)).Order("created_at DESC").Find(&samlIdentityProviders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var total int64
+	totalRes := p.db.Model(&models.SAMLIdentityProvider{}).Count(&total)
+	if totalRes.Error != nil {
+		return nil, totalRes.Error
+	}
+	paginationClone := pagination
+	paginationClone.Total = total
+	responseSAMLIdentityProviders := []*model.SAMLIdentityProvider{}
+	for _, c := range samlIdentityProviders {
+		responseSAMLIdentityProviders = append(responseSAMLIdentityProviders, c.AsAPISAMLIdentityProvider())
+	}
+	return &model.SAMLIdentityProviders{
+		Pagination:            paginationClone,
+		SamlIdentityProviders: responseSAMLIdentityProviders,
+	}, nil
+}
+
+// GetSAMLIdentityProviderByID to get saml identity provider by id
+func (p *provider) GetSAMLIdentityProviderByID(ctx context.Context, id string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	result := p.db.Where("id = ?", id).First(&samlIdentityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlIdentityProvider, nil
+}
+
+// GetSAMLIdentityProviderByName to get saml identity provider by name
+func (p *provider) GetSAMLIdentityProviderByName(ctx context.Context, name string) (*models.SAMLIdentityProvider, error) {
+	var samlIdentityProvider *models.SAMLIdentityProvider
+	result := p.db.Where("name = ?", name).First(&samlIdentityProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlIdentityProvider, nil
+}
+
+// DeleteSAMLIdentityProvider to delete saml identity provider
+func (p *provider) DeleteSAMLIdentityProvider(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	result := p.db.Delete(&models.SAMLIdentityProvider{
+		ID: samlIdentityProvider.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
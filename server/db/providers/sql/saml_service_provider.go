@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+)
+
+// AddSAMLServiceProvider to add saml service provider
+func (p *provider) AddSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	if samlServiceProvider.ID == "" {
+		samlServiceProvider.ID = uuid.New().String()
+	}
+	samlServiceProvider.Key = samlServiceProvider.ID
+	samlServiceProvider.CreatedAt = time.Now().Unix()
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	res := p.db.Create(&samlServiceProvider)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return samlServiceProvider, nil
+}
+
+// UpdateSAMLServiceProvider to update saml service provider
+func (p *provider) UpdateSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) (*models.SAMLServiceProvider, error) {
+	samlServiceProvider.UpdatedAt = time.Now().Unix()
+	result := p.db.Save(&samlServiceProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlServiceProvider, nil
+}
+
+// ListSAMLServiceProviders to list saml service providers
+func (p *provider) ListSAMLServiceProviders(ctx context.Context, pagination *model.Pagination) (*model.SAMLServiceProviders, error) {
+	var samlServiceProviders []models.SAMLServiceProvider
+	result := p.db.Limit(int(pagination.Limit)).Offset(int(pagination.Offset)).Order("created_at DESC").Find(&samlServiceProviders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var total int64
+	totalRes := p.db.Model(&models.SAMLServiceProvider{}).Count(&total)
+	if totalRes.Error != nil {
+		return nil, totalRes.Error
+	}
+	paginationClone := pagination
+	paginationClone.Total = total
+	responseSAMLServiceProviders := []*model.SAMLServiceProvider{}
+	for _, c := range samlServiceProviders {
+		responseSAMLServiceProviders = append(responseSAMLServiceProviders, c.AsAPISAMLServiceProvider())
+	}
+	return &model.SAMLServiceProviders{
+		Pagination:           paginationClone,
+		SamlServiceProviders: responseSAMLServiceProviders,
+	}, nil
+}
+
+// GetSAMLServiceProviderByID to get saml service provider by id
+func (p *provider) GetSAMLServiceProviderByID(ctx context.Context, id string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	result := p.db.Where("id = ?", id).First(&samlServiceProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlServiceProvider, nil
+}
+
+// GetSAMLServiceProviderByEntityID to get saml service provider by entity id
+func (p *provider) GetSAMLServiceProviderByEntityID(ctx context.Context, entityID string) (*models.SAMLServiceProvider, error) {
+	var samlServiceProvider *models.SAMLServiceProvider
+	result := p.db.Where("entity_id = ?", entityID).First(&samlServiceProvider)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return samlServiceProvider, nil
+}
+
+// DeleteSAMLServiceProvider to delete saml service provider
+func (p *provider) DeleteSAMLServiceProvider(ctx context.Context, samlServiceProvider *models.SAMLServiceProvider) error {
+	result := p.db.Delete(&models.SAMLServiceProvider{
+		ID: samlServiceProvider.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -71,6 +71,11 @@ func (p *provider) DeleteUser(ctx context.Context, user *models.User) error {
 		return result.Error
 	}
 
+	result = p.db.Where("user_id = ?", user.ID).Delete(&models.OAuthGrant{})
+	if result.Error != nil {
+		return result.Error
+	}
+
 	result = p.db.Delete(&user)
 	if result.Error != nil {
 		return result.Error
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -44,12 +45,54 @@ func InitAllEnv() error {
 		envData[constants.EnvKeyClientSecret] = uuid.New().String()
 	}
 
+	// unique salt for pairwise subject identifiers of each instance
+	if val, ok := envData[constants.EnvKeyPairwiseSubjectSalt]; !ok || val == "" {
+		envData[constants.EnvKeyPairwiseSubjectSalt] = os.Getenv(constants.EnvKeyPairwiseSubjectSalt)
+		if envData[constants.EnvKeyPairwiseSubjectSalt] == "" {
+			envData[constants.EnvKeyPairwiseSubjectSalt] = uuid.New().String()
+		}
+	}
+
+	// key & certificate for signing saml assertions of each instance,
+	// service providers trust the certificate published in saml metadata, hence it is generated only once
+	osSAMLIdPPrivateKey := os.Getenv(constants.EnvKeySAMLIdPPrivateKey)
+	osSAMLIdPCertificate := os.Getenv(constants.EnvKeySAMLIdPCertificate)
+	if osSAMLIdPPrivateKey != "" && osSAMLIdPCertificate != "" {
+		if _, err := crypto.ParseRsaPrivateKeyFromPemStr(osSAMLIdPPrivateKey); err != nil {
+			return err
+		}
+		if _, err := crypto.ParseCertificateFromPemStr(osSAMLIdPCertificate); err != nil {
+			return err
+		}
+		envData[constants.EnvKeySAMLIdPPrivateKey] = osSAMLIdPPrivateKey
+		envData[constants.EnvKeySAMLIdPCertificate] = osSAMLIdPCertificate
+	}
+	samlIdPPrivateKey, _ := envData[constants.EnvKeySAMLIdPPrivateKey].(string)
+	samlIdPCertificate, _ := envData[constants.EnvKeySAMLIdPCertificate].(string)
+	if samlIdPPrivateKey == "" || samlIdPCertificate == "" {
+		key, privateKey, _, _, err := crypto.NewRSAKey("RS256", clientID)
+		if err != nil {
+			return err
+		}
+		certificate, err := crypto.NewSelfSignedCertificate(key, "authorizer", time.Hour*24*365*10)
+		if err != nil {
+			return err
+		}
+		envData[constants.EnvKeySAMLIdPPrivateKey] = privateKey
+		envData[constants.EnvKeySAMLIdPCertificate] = certificate
+	}
+
 	// os string envs
 	osEnv := os.Getenv(constants.EnvKeyEnv)
 	osAppURL := os.Getenv(constants.EnvKeyAppURL)
 	osAuthorizerURL := os.Getenv(constants.EnvKeyAuthorizerURL)
 	osPort := os.Getenv(constants.EnvKeyPort)
 	osAccessTokenExpiryTime := os.Getenv(constants.EnvKeyAccessTokenExpiryTime)
+	osRefreshTokenExpiryTime := os.Getenv(constants.EnvKeyRefreshTokenExpiryTime)
+	osSessionExpiryTime := os.Getenv(constants.EnvKeySessionExpiryTime)
+	osSessionIdleTimeout := os.Getenv(constants.EnvKeySessionIdleTimeout)
+	osSessionMaxAge := os.Getenv(constants.EnvKeySessionMaxAge)
+	osOTPExpiryTime := os.Getenv(constants.EnvKeyOTPExpiryTime)
 	osAdminSecret := os.Getenv(constants.EnvKeyAdminSecret)
 	osSmtpHost := os.Getenv(constants.EnvKeySmtpHost)
 	osSmtpPort := os.Getenv(constants.EnvKeySmtpPort)
@@ -94,6 +137,7 @@ func InitAllEnv() error {
 	osCouchbaseBucketRAMQuotaMB := os.Getenv(constants.EnvCouchbaseBucketRAMQuotaMB)
 	osAuthorizeResponseType := os.Getenv(constants.EnvKeyDefaultAuthorizeResponseType)
 	osAuthorizeResponseMode := os.Getenv(constants.EnvKeyDefaultAuthorizeResponseMode)
+	osClientRegistrationInitialAccessToken := os.Getenv(constants.EnvKeyClientRegistrationInitialAccessToken)
 
 	// os bool vars
 	osAppCookieSecure := os.Getenv(constants.EnvKeyAppCookieSecure)
@@ -113,6 +157,7 @@ func InitAllEnv() error {
 	// phone verification var
 	osDisablePhoneVerification := os.Getenv(constants.EnvKeyDisablePhoneVerification)
 	osDisablePlayground := os.Getenv(constants.EnvKeyDisablePlayGround)
+	osDisableRefreshTokenReuseDetection := os.Getenv(constants.EnvKeyDisableRefreshTokenReuseDetection)
 
 	// twilio vars
 	osTwilioApiKey := os.Getenv(constants.EnvKeyTwilioAPIKey)
@@ -120,6 +165,16 @@ func InitAllEnv() error {
 	osTwilioAccountSid := os.Getenv(constants.EnvKeyTwilioAccountSID)
 	osTwilioSender := os.Getenv(constants.EnvKeyTwilioSender)
 
+	// ldap vars
+	osLDAPURL := os.Getenv(constants.EnvKeyLDAPURL)
+	osLDAPBindDN := os.Getenv(constants.EnvKeyLDAPBindDN)
+	osLDAPBindPassword := os.Getenv(constants.EnvKeyLDAPBindPassword)
+	osLDAPSearchBase := os.Getenv(constants.EnvKeyLDAPSearchBase)
+	osLDAPUserFilter := os.Getenv(constants.EnvKeyLDAPUserFilter)
+	osLDAPGroupRoleMapping := os.Getenv(constants.EnvKeyLDAPGroupRoleMapping)
+	osLDAPTLSCACertificate := os.Getenv(constants.EnvKeyLDAPTLSCACertificate)
+	osLDAPStartTLS := os.Getenv(constants.EnvKeyLDAPStartTLS)
+
 	// os slice vars
 	osAllowedOrigins := os.Getenv(constants.EnvKeyAllowedOrigins)
 	osRoles := os.Getenv(constants.EnvKeyRoles)
@@ -225,6 +280,50 @@ func InitAllEnv() error {
 		envData[constants.EnvKeyAccessTokenExpiryTime] = osAccessTokenExpiryTime
 	}
 
+	if val, ok := envData[constants.EnvKeyRefreshTokenExpiryTime]; !ok || val == "" {
+		envData[constants.EnvKeyRefreshTokenExpiryTime] = osRefreshTokenExpiryTime
+		if envData[constants.EnvKeyRefreshTokenExpiryTime] == "" {
+			envData[constants.EnvKeyRefreshTokenExpiryTime] = "8760h"
+		}
+	}
+	if osRefreshTokenExpiryTime != "" && envData[constants.EnvKeyRefreshTokenExpiryTime] != osRefreshTokenExpiryTime {
+		envData[constants.EnvKeyRefreshTokenExpiryTime] = osRefreshTokenExpiryTime
+	}
+
+	if val, ok := envData[constants.EnvKeySessionExpiryTime]; !ok || val == "" {
+		envData[constants.EnvKeySessionExpiryTime] = osSessionExpiryTime
+		if envData[constants.EnvKeySessionExpiryTime] == "" {
+			envData[constants.EnvKeySessionExpiryTime] = "8760h"
+		}
+	}
+	if osSessionExpiryTime != "" && envData[constants.EnvKeySessionExpiryTime] != osSessionExpiryTime {
+		envData[constants.EnvKeySessionExpiryTime] = osSessionExpiryTime
+	}
+
+	if val, ok := envData[constants.EnvKeySessionIdleTimeout]; !ok || val == "" {
+		envData[constants.EnvKeySessionIdleTimeout] = osSessionIdleTimeout
+	}
+	if osSessionIdleTimeout != "" && envData[constants.EnvKeySessionIdleTimeout] != osSessionIdleTimeout {
+		envData[constants.EnvKeySessionIdleTimeout] = osSessionIdleTimeout
+	}
+
+	if val, ok := envData[constants.EnvKeySessionMaxAge]; !ok || val == "" {
+		envData[constants.EnvKeySessionMaxAge] = osSessionMaxAge
+	}
+	if osSessionMaxAge != "" && envData[constants.EnvKeySessionMaxAge] != osSessionMaxAge {
+		envData[constants.EnvKeySessionMaxAge] = osSessionMaxAge
+	}
+
+	if val, ok := envData[constants.EnvKeyOTPExpiryTime]; !ok || val == "" {
+		envData[constants.EnvKeyOTPExpiryTime] = osOTPExpiryTime
+		if envData[constants.EnvKeyOTPExpiryTime] == "" {
+			envData[constants.EnvKeyOTPExpiryTime] = "1m"
+		}
+	}
+	if osOTPExpiryTime != "" && envData[constants.EnvKeyOTPExpiryTime] != osOTPExpiryTime {
+		envData[constants.EnvKeyOTPExpiryTime] = osOTPExpiryTime
+	}
+
 	if val, ok := envData[constants.EnvKeyAdminSecret]; !ok || val == "" {
 		envData[constants.EnvKeyAdminSecret] = osAdminSecret
 	}
@@ -804,6 +903,13 @@ func InitAllEnv() error {
 		envData[constants.EnvKeyDefaultAuthorizeResponseMode] = osAuthorizeResponseMode
 	}
 
+	if val, ok := envData[constants.EnvKeyClientRegistrationInitialAccessToken]; !ok || val == "" {
+		envData[constants.EnvKeyClientRegistrationInitialAccessToken] = osClientRegistrationInitialAccessToken
+	}
+	if osClientRegistrationInitialAccessToken != "" && envData[constants.EnvKeyClientRegistrationInitialAccessToken] != osClientRegistrationInitialAccessToken {
+		envData[constants.EnvKeyClientRegistrationInitialAccessToken] = osClientRegistrationInitialAccessToken
+	}
+
 	if val, ok := envData[constants.EnvKeyTwilioAPISecret]; !ok || val == "" {
 		envData[constants.EnvKeyTwilioAPISecret] = osTwilioApiSecret
 	}
@@ -832,6 +938,71 @@ func InitAllEnv() error {
 		envData[constants.EnvKeyTwilioSender] = osTwilioSender
 	}
 
+	if val, ok := envData[constants.EnvKeyLDAPURL]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPURL] = osLDAPURL
+	}
+	if osLDAPURL != "" && envData[constants.EnvKeyLDAPURL] != osLDAPURL {
+		envData[constants.EnvKeyLDAPURL] = osLDAPURL
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPBindDN]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPBindDN] = osLDAPBindDN
+	}
+	if osLDAPBindDN != "" && envData[constants.EnvKeyLDAPBindDN] != osLDAPBindDN {
+		envData[constants.EnvKeyLDAPBindDN] = osLDAPBindDN
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPBindPassword]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPBindPassword] = osLDAPBindPassword
+	}
+	if osLDAPBindPassword != "" && envData[constants.EnvKeyLDAPBindPassword] != osLDAPBindPassword {
+		envData[constants.EnvKeyLDAPBindPassword] = osLDAPBindPassword
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPSearchBase]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPSearchBase] = osLDAPSearchBase
+	}
+	if osLDAPSearchBase != "" && envData[constants.EnvKeyLDAPSearchBase] != osLDAPSearchBase {
+		envData[constants.EnvKeyLDAPSearchBase] = osLDAPSearchBase
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPUserFilter]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPUserFilter] = osLDAPUserFilter
+		if envData[constants.EnvKeyLDAPUserFilter] == "" {
+			envData[constants.EnvKeyLDAPUserFilter] = "(mail={email})"
+		}
+	}
+	if osLDAPUserFilter != "" && envData[constants.EnvKeyLDAPUserFilter] != osLDAPUserFilter {
+		envData[constants.EnvKeyLDAPUserFilter] = osLDAPUserFilter
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPGroupRoleMapping]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPGroupRoleMapping] = osLDAPGroupRoleMapping
+	}
+	if osLDAPGroupRoleMapping != "" && envData[constants.EnvKeyLDAPGroupRoleMapping] != osLDAPGroupRoleMapping {
+		envData[constants.EnvKeyLDAPGroupRoleMapping] = osLDAPGroupRoleMapping
+	}
+
+	if val, ok := envData[constants.EnvKeyLDAPTLSCACertificate]; !ok || val == "" {
+		envData[constants.EnvKeyLDAPTLSCACertificate] = osLDAPTLSCACertificate
+	}
+	if osLDAPTLSCACertificate != "" && envData[constants.EnvKeyLDAPTLSCACertificate] != osLDAPTLSCACertificate {
+		envData[constants.EnvKeyLDAPTLSCACertificate] = osLDAPTLSCACertificate
+	}
+
+	if _, ok := envData[constants.EnvKeyLDAPStartTLS]; !ok {
+		envData[constants.EnvKeyLDAPStartTLS] = osLDAPStartTLS == "true"
+	}
+	if osLDAPStartTLS != "" {
+		boolValue, err := strconv.ParseBool(osLDAPStartTLS)
+		if err != nil {
+			return err
+		}
+		if boolValue != envData[constants.EnvKeyLDAPStartTLS].(bool) {
+			envData[constants.EnvKeyLDAPStartTLS] = boolValue
+		}
+	}
+
 	if _, ok := envData[constants.EnvKeyDisablePhoneVerification]; !ok {
 		envData[constants.EnvKeyDisablePhoneVerification] = osDisablePhoneVerification == "false"
 	}
@@ -866,6 +1037,19 @@ func InitAllEnv() error {
 			envData[constants.EnvKeyDisablePlayGround] = boolValue
 		}
 	}
+
+	if _, ok := envData[constants.EnvKeyDisableRefreshTokenReuseDetection]; !ok {
+		envData[constants.EnvKeyDisableRefreshTokenReuseDetection] = osDisableRefreshTokenReuseDetection == "true"
+	}
+	if osDisableRefreshTokenReuseDetection != "" {
+		boolValue, err := strconv.ParseBool(osDisableRefreshTokenReuseDetection)
+		if err != nil {
+			return err
+		}
+		if boolValue != envData[constants.EnvKeyDisableRefreshTokenReuseDetection].(bool) {
+			envData[constants.EnvKeyDisableRefreshTokenReuseDetection] = boolValue
+		}
+	}
 	// TODO: remove after beta launch
 	envData[constants.EnvKeyDisableTOTPLogin] = true
 	if _, ok := envData[constants.EnvKeyDisableTOTPLogin]; !ok {
@@ -121,6 +121,11 @@ func PersistEnv() error {
 			return err
 		}
 		encodedHash := crypto.EncryptB64(hash)
+		// initialize keyring with key configured via env
+		if _, err := crypto.SyncKeyringWithEnv(crypto.DefaultRetiredSigningKeyTTL); err != nil {
+			log.Debug("Error while initializing jwt keyring: ", err)
+			return err
+		}
 		res, err := memorystore.Provider.GetEnvStore()
 		if err != nil {
 			log.Debug("Error while getting env store: ", err)
@@ -196,7 +201,7 @@ func PersistEnv() error {
 				envValue := strings.TrimSpace(os.Getenv(key))
 				if envValue != "" {
 					switch key {
-					case constants.EnvKeyIsProd, constants.EnvKeyDisableBasicAuthentication, constants.EnvKeyDisableMobileBasicAuthentication, constants.EnvKeyDisableEmailVerification, constants.EnvKeyDisableLoginPage, constants.EnvKeyDisableMagicLinkLogin, constants.EnvKeyDisableSignUp, constants.EnvKeyDisableRedisForEnv, constants.EnvKeyDisableStrongPassword, constants.EnvKeyIsEmailServiceEnabled, constants.EnvKeyIsSMSServiceEnabled, constants.EnvKeyEnforceMultiFactorAuthentication, constants.EnvKeyDisableMultiFactorAuthentication, constants.EnvKeyAdminCookieSecure, constants.EnvKeyAppCookieSecure, constants.EnvKeyDisablePhoneVerification, constants.EnvKeyDisablePlayGround, constants.EnvKeyDisableTOTPLogin, constants.EnvKeyDisableMailOTPLogin:
+					case constants.EnvKeyIsProd, constants.EnvKeyDisableBasicAuthentication, constants.EnvKeyDisableMobileBasicAuthentication, constants.EnvKeyDisableEmailVerification, constants.EnvKeyDisableLoginPage, constants.EnvKeyDisableMagicLinkLogin, constants.EnvKeyDisableSignUp, constants.EnvKeyDisableRedisForEnv, constants.EnvKeyDisableStrongPassword, constants.EnvKeyIsEmailServiceEnabled, constants.EnvKeyIsSMSServiceEnabled, constants.EnvKeyEnforceMultiFactorAuthentication, constants.EnvKeyDisableMultiFactorAuthentication, constants.EnvKeyAdminCookieSecure, constants.EnvKeyAppCookieSecure, constants.EnvKeyDisablePhoneVerification, constants.EnvKeyDisablePlayGround, constants.EnvKeyDisableTOTPLogin, constants.EnvKeyDisableMailOTPLogin, constants.EnvKeyDisableRefreshTokenReuseDetection, constants.EnvKeyLDAPStartTLS:
 						if envValueBool, err := strconv.ParseBool(envValue); err == nil {
 							if value.(bool) != envValueBool {
 								storeData[key] = envValueBool
@@ -213,6 +218,21 @@ func PersistEnv() error {
 			}
 		}
 
+		// salt is not part of env persisted by older versions,
+		// salt generated while initializing env is persisted so that pairwise subjects are stable
+		if val, ok := storeData[constants.EnvKeyPairwiseSubjectSalt]; !ok || val == nil || val == "" {
+			storeData[constants.EnvKeyPairwiseSubjectSalt], _ = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyPairwiseSubjectSalt)
+			hasChanged = true
+		}
+
+		// saml key & certificate are not part of env persisted by older versions,
+		// key generated while initializing env is persisted so that certificate in saml metadata is stable
+		if val, ok := storeData[constants.EnvKeySAMLIdPPrivateKey]; !ok || val == nil || val == "" {
+			storeData[constants.EnvKeySAMLIdPPrivateKey], _ = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPPrivateKey)
+			storeData[constants.EnvKeySAMLIdPCertificate], _ = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPCertificate)
+			hasChanged = true
+		}
+
 		// handle derivative cases like disabling email verification & magic login
 		// in case SMTP is off but env is set to true
 		if storeData[constants.EnvKeySmtpHost] == "" || storeData[constants.EnvKeySmtpUsername] == "" || storeData[constants.EnvKeySmtpPassword] == "" || storeData[constants.EnvKeySenderEmail] == "" && storeData[constants.EnvKeySmtpPort] == "" {
@@ -240,6 +260,17 @@ func PersistEnv() error {
 			return err
 		}
 
+		// if jwt keys are changed via env, previous key is retired
+		keyringChanged, err := crypto.SyncKeyringWithEnv(crypto.DefaultRetiredSigningKeyTTL)
+		if err != nil {
+			log.Debug("Error while syncing jwt keyring: ", err)
+			return err
+		}
+		if keyringChanged {
+			storeData[constants.EnvKeyJwtKeyring], _ = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtKeyring)
+			hasChanged = true
+		}
+
 		jwk, err := crypto.GenerateJWKBasedOnEnv()
 		if err != nil {
 			log.Debug("Error while generating JWK: ", err)
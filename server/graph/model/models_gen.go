@@ -2,6 +2,34 @@
 
 package model
 
+type AddClientRequest struct {
+	Name                               string   `json:"name"`
+	ClientID                           *string  `json:"client_id,omitempty"`
+	ClientSecret                       *string  `json:"client_secret,omitempty"`
+	RedirectUris                       []string `json:"redirect_uris,omitempty"`
+	GrantTypes                         []string `json:"grant_types,omitempty"`
+	Scopes                             []string `json:"scopes,omitempty"`
+	AccessTokenExpiryTime              *string  `json:"access_token_expiry_time,omitempty"`
+	RefreshTokenExpiryTime             *string  `json:"refresh_token_expiry_time,omitempty"`
+	RequirePushedAuthorizationRequests *bool    `json:"require_pushed_authorization_requests,omitempty"`
+	RequireSignedRequestObject         *bool    `json:"require_signed_request_object,omitempty"`
+	PostLogoutRedirectUris             []string `json:"post_logout_redirect_uris,omitempty"`
+	FrontchannelLogoutURI              *string  `json:"frontchannel_logout_uri,omitempty"`
+	BackchannelLogoutURI               *string  `json:"backchannel_logout_uri,omitempty"`
+	TokenExchangeAudiences             []string `json:"token_exchange_audiences,omitempty"`
+	TokenEndpointAuthMethod            *string  `json:"token_endpoint_auth_method,omitempty"`
+	Jwks                               *string  `json:"jwks,omitempty"`
+	JwksURI                            *string  `json:"jwks_uri,omitempty"`
+	SubjectType                        *string  `json:"subject_type,omitempty"`
+	SectorIdentifierURI                *string  `json:"sector_identifier_uri,omitempty"`
+}
+
+type AddClientResponse struct {
+	Message      string  `json:"message"`
+	Client       *Client `json:"client"`
+	ClientSecret string  `json:"client_secret"`
+}
+
 type AddEmailTemplateRequest struct {
 	EventName string  `json:"event_name"`
 	Subject   string  `json:"subject"`
@@ -9,6 +37,34 @@ type AddEmailTemplateRequest struct {
 	Design    *string `json:"design,omitempty"`
 }
 
+type AddIdentityProviderRequest struct {
+	Name             string                 `json:"name"`
+	DiscoveryURL     *string                `json:"discovery_url,omitempty"`
+	AuthorizationURL *string                `json:"authorization_url,omitempty"`
+	TokenURL         *string                `json:"token_url,omitempty"`
+	UserinfoURL      *string                `json:"userinfo_url,omitempty"`
+	ClientID         string                 `json:"client_id"`
+	ClientSecret     string                 `json:"client_secret"`
+	Scopes           []string               `json:"scopes,omitempty"`
+	ClaimMapping     map[string]interface{} `json:"claim_mapping,omitempty"`
+}
+
+type AddSAMLIdentityProviderRequest struct {
+	Name                string                 `json:"name"`
+	MetadataURL         *string                `json:"metadata_url,omitempty"`
+	MetadataXML         *string                `json:"metadata_xml,omitempty"`
+	AttributeMapping    map[string]interface{} `json:"attribute_mapping,omitempty"`
+	AllowedEmailDomains []string               `json:"allowed_email_domains,omitempty"`
+}
+
+type AddSAMLServiceProviderRequest struct {
+	Name             string                 `json:"name"`
+	EntityID         string                 `json:"entity_id"`
+	AcsURL           string                 `json:"acs_url"`
+	NameIDFormat     *string                `json:"name_id_format,omitempty"`
+	AttributeMapping map[string]interface{} `json:"attribute_mapping,omitempty"`
+}
+
 type AddWebhookRequest struct {
 	EventName        string                 `json:"event_name"`
 	EventDescription *string                `json:"event_description,omitempty"`
@@ -40,6 +96,44 @@ type AuthResponse struct {
 	AuthenticatorRecoveryCodes []*string `json:"authenticator_recovery_codes,omitempty"`
 }
 
+type AuthorizeDeviceRequest struct {
+	UserCode string `json:"user_code"`
+	Deny     *bool  `json:"deny,omitempty"`
+}
+
+type Client struct {
+	ID                                 string   `json:"id"`
+	ClientID                           string   `json:"client_id"`
+	Name                               *string  `json:"name,omitempty"`
+	RedirectUris                       []string `json:"redirect_uris,omitempty"`
+	GrantTypes                         []string `json:"grant_types,omitempty"`
+	Scopes                             []string `json:"scopes,omitempty"`
+	AccessTokenExpiryTime              *string  `json:"access_token_expiry_time,omitempty"`
+	RefreshTokenExpiryTime             *string  `json:"refresh_token_expiry_time,omitempty"`
+	RequirePushedAuthorizationRequests *bool    `json:"require_pushed_authorization_requests,omitempty"`
+	RequireSignedRequestObject         *bool    `json:"require_signed_request_object,omitempty"`
+	PostLogoutRedirectUris             []string `json:"post_logout_redirect_uris,omitempty"`
+	FrontchannelLogoutURI              *string  `json:"frontchannel_logout_uri,omitempty"`
+	BackchannelLogoutURI               *string  `json:"backchannel_logout_uri,omitempty"`
+	TokenExchangeAudiences             []string `json:"token_exchange_audiences,omitempty"`
+	TokenEndpointAuthMethod            *string  `json:"token_endpoint_auth_method,omitempty"`
+	Jwks                               *string  `json:"jwks,omitempty"`
+	JwksURI                            *string  `json:"jwks_uri,omitempty"`
+	SubjectType                        *string  `json:"subject_type,omitempty"`
+	SectorIdentifierURI                *string  `json:"sector_identifier_uri,omitempty"`
+	CreatedAt                          *int64   `json:"created_at,omitempty"`
+	UpdatedAt                          *int64   `json:"updated_at,omitempty"`
+}
+
+type ClientRequest struct {
+	ID string `json:"id"`
+}
+
+type Clients struct {
+	Pagination *Pagination `json:"pagination"`
+	Clients    []*Client   `json:"clients"`
+}
+
 type DeleteEmailTemplateRequest struct {
 	ID string `json:"id"`
 }
@@ -64,77 +158,84 @@ type EmailTemplates struct {
 }
 
 type Env struct {
-	AccessTokenExpiryTime            *string  `json:"ACCESS_TOKEN_EXPIRY_TIME,omitempty"`
-	AdminSecret                      *string  `json:"ADMIN_SECRET,omitempty"`
-	DatabaseName                     *string  `json:"DATABASE_NAME,omitempty"`
-	DatabaseURL                      *string  `json:"DATABASE_URL,omitempty"`
-	DatabaseType                     *string  `json:"DATABASE_TYPE,omitempty"`
-	DatabaseUsername                 *string  `json:"DATABASE_USERNAME,omitempty"`
-	DatabasePassword                 *string  `json:"DATABASE_PASSWORD,omitempty"`
-	DatabaseHost                     *string  `json:"DATABASE_HOST,omitempty"`
-	DatabasePort                     *string  `json:"DATABASE_PORT,omitempty"`
-	ClientID                         string   `json:"CLIENT_ID"`
-	ClientSecret                     string   `json:"CLIENT_SECRET"`
-	CustomAccessTokenScript          *string  `json:"CUSTOM_ACCESS_TOKEN_SCRIPT,omitempty"`
-	SMTPHost                         *string  `json:"SMTP_HOST,omitempty"`
-	SMTPPort                         *string  `json:"SMTP_PORT,omitempty"`
-	SMTPUsername                     *string  `json:"SMTP_USERNAME,omitempty"`
-	SMTPPassword                     *string  `json:"SMTP_PASSWORD,omitempty"`
-	SMTPLocalName                    *string  `json:"SMTP_LOCAL_NAME,omitempty"`
-	SenderEmail                      *string  `json:"SENDER_EMAIL,omitempty"`
-	SenderName                       *string  `json:"SENDER_NAME,omitempty"`
-	JwtType                          *string  `json:"JWT_TYPE,omitempty"`
-	JwtSecret                        *string  `json:"JWT_SECRET,omitempty"`
-	JwtPrivateKey                    *string  `json:"JWT_PRIVATE_KEY,omitempty"`
-	JwtPublicKey                     *string  `json:"JWT_PUBLIC_KEY,omitempty"`
-	AllowedOrigins                   []string `json:"ALLOWED_ORIGINS,omitempty"`
-	AppURL                           *string  `json:"APP_URL,omitempty"`
-	RedisURL                         *string  `json:"REDIS_URL,omitempty"`
-	ResetPasswordURL                 *string  `json:"RESET_PASSWORD_URL,omitempty"`
-	DisableEmailVerification         bool     `json:"DISABLE_EMAIL_VERIFICATION"`
-	DisableBasicAuthentication       bool     `json:"DISABLE_BASIC_AUTHENTICATION"`
-	DisableMobileBasicAuthentication bool     `json:"DISABLE_MOBILE_BASIC_AUTHENTICATION"`
-	DisableMagicLinkLogin            bool     `json:"DISABLE_MAGIC_LINK_LOGIN"`
-	DisableLoginPage                 bool     `json:"DISABLE_LOGIN_PAGE"`
-	DisableSignUp                    bool     `json:"DISABLE_SIGN_UP"`
-	DisableRedisForEnv               bool     `json:"DISABLE_REDIS_FOR_ENV"`
-	DisableStrongPassword            bool     `json:"DISABLE_STRONG_PASSWORD"`
-	DisableMultiFactorAuthentication bool     `json:"DISABLE_MULTI_FACTOR_AUTHENTICATION"`
-	EnforceMultiFactorAuthentication bool     `json:"ENFORCE_MULTI_FACTOR_AUTHENTICATION"`
-	Roles                            []string `json:"ROLES,omitempty"`
-	ProtectedRoles                   []string `json:"PROTECTED_ROLES,omitempty"`
-	DefaultRoles                     []string `json:"DEFAULT_ROLES,omitempty"`
-	JwtRoleClaim                     *string  `json:"JWT_ROLE_CLAIM,omitempty"`
-	GoogleClientID                   *string  `json:"GOOGLE_CLIENT_ID,omitempty"`
-	GoogleClientSecret               *string  `json:"GOOGLE_CLIENT_SECRET,omitempty"`
-	GithubClientID                   *string  `json:"GITHUB_CLIENT_ID,omitempty"`
-	GithubClientSecret               *string  `json:"GITHUB_CLIENT_SECRET,omitempty"`
-	FacebookClientID                 *string  `json:"FACEBOOK_CLIENT_ID,omitempty"`
-	FacebookClientSecret             *string  `json:"FACEBOOK_CLIENT_SECRET,omitempty"`
-	LinkedinClientID                 *string  `json:"LINKEDIN_CLIENT_ID,omitempty"`
-	LinkedinClientSecret             *string  `json:"LINKEDIN_CLIENT_SECRET,omitempty"`
-	AppleClientID                    *string  `json:"APPLE_CLIENT_ID,omitempty"`
-	AppleClientSecret                *string  `json:"APPLE_CLIENT_SECRET,omitempty"`
-	DiscordClientID                  *string  `json:"DISCORD_CLIENT_ID,omitempty"`
-	DiscordClientSecret              *string  `json:"DISCORD_CLIENT_SECRET,omitempty"`
-	TwitterClientID                  *string  `json:"TWITTER_CLIENT_ID,omitempty"`
-	TwitterClientSecret              *string  `json:"TWITTER_CLIENT_SECRET,omitempty"`
-	MicrosoftClientID                *string  `json:"MICROSOFT_CLIENT_ID,omitempty"`
-	MicrosoftClientSecret            *string  `json:"MICROSOFT_CLIENT_SECRET,omitempty"`
-	MicrosoftActiveDirectoryTenantID *string  `json:"MICROSOFT_ACTIVE_DIRECTORY_TENANT_ID,omitempty"`
-	TwitchClientID                   *string  `json:"TWITCH_CLIENT_ID,omitempty"`
-	TwitchClientSecret               *string  `json:"TWITCH_CLIENT_SECRET,omitempty"`
-	RobloxClientID                   *string  `json:"ROBLOX_CLIENT_ID,omitempty"`
-	RobloxClientSecret               *string  `json:"ROBLOX_CLIENT_SECRET,omitempty"`
-	OrganizationName                 *string  `json:"ORGANIZATION_NAME,omitempty"`
-	OrganizationLogo                 *string  `json:"ORGANIZATION_LOGO,omitempty"`
-	AppCookieSecure                  bool     `json:"APP_COOKIE_SECURE"`
-	AdminCookieSecure                bool     `json:"ADMIN_COOKIE_SECURE"`
-	DefaultAuthorizeResponseType     *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_TYPE,omitempty"`
-	DefaultAuthorizeResponseMode     *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_MODE,omitempty"`
-	DisablePlayground                bool     `json:"DISABLE_PLAYGROUND"`
-	DisableMailOtpLogin              bool     `json:"DISABLE_MAIL_OTP_LOGIN"`
-	DisableTotpLogin                 bool     `json:"DISABLE_TOTP_LOGIN"`
+	AccessTokenExpiryTime                *string  `json:"ACCESS_TOKEN_EXPIRY_TIME,omitempty"`
+	RefreshTokenExpiryTime               *string  `json:"REFRESH_TOKEN_EXPIRY_TIME,omitempty"`
+	SessionExpiryTime                    *string  `json:"SESSION_EXPIRY_TIME,omitempty"`
+	SessionIdleTimeout                   *string  `json:"SESSION_IDLE_TIMEOUT,omitempty"`
+	SessionMaxAge                        *string  `json:"SESSION_MAX_AGE,omitempty"`
+	OtpExpiryTime                        *string  `json:"OTP_EXPIRY_TIME,omitempty"`
+	AdminSecret                          *string  `json:"ADMIN_SECRET,omitempty"`
+	DatabaseName                         *string  `json:"DATABASE_NAME,omitempty"`
+	DatabaseURL                          *string  `json:"DATABASE_URL,omitempty"`
+	DatabaseType                         *string  `json:"DATABASE_TYPE,omitempty"`
+	DatabaseUsername                     *string  `json:"DATABASE_USERNAME,omitempty"`
+	DatabasePassword                     *string  `json:"DATABASE_PASSWORD,omitempty"`
+	DatabaseHost                         *string  `json:"DATABASE_HOST,omitempty"`
+	DatabasePort                         *string  `json:"DATABASE_PORT,omitempty"`
+	ClientID                             string   `json:"CLIENT_ID"`
+	ClientSecret                         string   `json:"CLIENT_SECRET"`
+	CustomAccessTokenScript              *string  `json:"CUSTOM_ACCESS_TOKEN_SCRIPT,omitempty"`
+	SMTPHost                             *string  `json:"SMTP_HOST,omitempty"`
+	SMTPPort                             *string  `json:"SMTP_PORT,omitempty"`
+	SMTPUsername                         *string  `json:"SMTP_USERNAME,omitempty"`
+	SMTPPassword                         *string  `json:"SMTP_PASSWORD,omitempty"`
+	SMTPLocalName                        *string  `json:"SMTP_LOCAL_NAME,omitempty"`
+	SenderEmail                          *string  `json:"SENDER_EMAIL,omitempty"`
+	SenderName                           *string  `json:"SENDER_NAME,omitempty"`
+	JwtType                              *string  `json:"JWT_TYPE,omitempty"`
+	JwtSecret                            *string  `json:"JWT_SECRET,omitempty"`
+	JwtPrivateKey                        *string  `json:"JWT_PRIVATE_KEY,omitempty"`
+	JwtPublicKey                         *string  `json:"JWT_PUBLIC_KEY,omitempty"`
+	AllowedOrigins                       []string `json:"ALLOWED_ORIGINS,omitempty"`
+	AppURL                               *string  `json:"APP_URL,omitempty"`
+	RedisURL                             *string  `json:"REDIS_URL,omitempty"`
+	ResetPasswordURL                     *string  `json:"RESET_PASSWORD_URL,omitempty"`
+	DisableEmailVerification             bool     `json:"DISABLE_EMAIL_VERIFICATION"`
+	DisableBasicAuthentication           bool     `json:"DISABLE_BASIC_AUTHENTICATION"`
+	DisableMobileBasicAuthentication     bool     `json:"DISABLE_MOBILE_BASIC_AUTHENTICATION"`
+	DisableMagicLinkLogin                bool     `json:"DISABLE_MAGIC_LINK_LOGIN"`
+	DisableLoginPage                     bool     `json:"DISABLE_LOGIN_PAGE"`
+	DisableSignUp                        bool     `json:"DISABLE_SIGN_UP"`
+	DisableRedisForEnv                   bool     `json:"DISABLE_REDIS_FOR_ENV"`
+	DisableStrongPassword                bool     `json:"DISABLE_STRONG_PASSWORD"`
+	DisableMultiFactorAuthentication     bool     `json:"DISABLE_MULTI_FACTOR_AUTHENTICATION"`
+	EnforceMultiFactorAuthentication     bool     `json:"ENFORCE_MULTI_FACTOR_AUTHENTICATION"`
+	Roles                                []string `json:"ROLES,omitempty"`
+	ProtectedRoles                       []string `json:"PROTECTED_ROLES,omitempty"`
+	DefaultRoles                         []string `json:"DEFAULT_ROLES,omitempty"`
+	JwtRoleClaim                         *string  `json:"JWT_ROLE_CLAIM,omitempty"`
+	GoogleClientID                       *string  `json:"GOOGLE_CLIENT_ID,omitempty"`
+	GoogleClientSecret                   *string  `json:"GOOGLE_CLIENT_SECRET,omitempty"`
+	GithubClientID                       *string  `json:"GITHUB_CLIENT_ID,omitempty"`
+	GithubClientSecret                   *string  `json:"GITHUB_CLIENT_SECRET,omitempty"`
+	FacebookClientID                     *string  `json:"FACEBOOK_CLIENT_ID,omitempty"`
+	FacebookClientSecret                 *string  `json:"FACEBOOK_CLIENT_SECRET,omitempty"`
+	LinkedinClientID                     *string  `json:"LINKEDIN_CLIENT_ID,omitempty"`
+	LinkedinClientSecret                 *string  `json:"LINKEDIN_CLIENT_SECRET,omitempty"`
+	AppleClientID                        *string  `json:"APPLE_CLIENT_ID,omitempty"`
+	AppleClientSecret                    *string  `json:"APPLE_CLIENT_SECRET,omitempty"`
+	DiscordClientID                      *string  `json:"DISCORD_CLIENT_ID,omitempty"`
+	DiscordClientSecret                  *string  `json:"DISCORD_CLIENT_SECRET,omitempty"`
+	TwitterClientID                      *string  `json:"TWITTER_CLIENT_ID,omitempty"`
+	TwitterClientSecret                  *string  `json:"TWITTER_CLIENT_SECRET,omitempty"`
+	MicrosoftClientID                    *string  `json:"MICROSOFT_CLIENT_ID,omitempty"`
+	MicrosoftClientSecret                *string  `json:"MICROSOFT_CLIENT_SECRET,omitempty"`
+	MicrosoftActiveDirectoryTenantID     *string  `json:"MICROSOFT_ACTIVE_DIRECTORY_TENANT_ID,omitempty"`
+	TwitchClientID                       *string  `json:"TWITCH_CLIENT_ID,omitempty"`
+	TwitchClientSecret                   *string  `json:"TWITCH_CLIENT_SECRET,omitempty"`
+	RobloxClientID                       *string  `json:"ROBLOX_CLIENT_ID,omitempty"`
+	RobloxClientSecret                   *string  `json:"ROBLOX_CLIENT_SECRET,omitempty"`
+	OrganizationName                     *string  `json:"ORGANIZATION_NAME,omitempty"`
+	OrganizationLogo                     *string  `json:"ORGANIZATION_LOGO,omitempty"`
+	AppCookieSecure                      bool     `json:"APP_COOKIE_SECURE"`
+	AdminCookieSecure                    bool     `json:"ADMIN_COOKIE_SECURE"`
+	DefaultAuthorizeResponseType         *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_TYPE,omitempty"`
+	DefaultAuthorizeResponseMode         *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_MODE,omitempty"`
+	ClientRegistrationInitialAccessToken *string  `json:"CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN,omitempty"`
+	DisablePlayground                    bool     `json:"DISABLE_PLAYGROUND"`
+	DisableRefreshTokenReuseDetection    bool     `json:"DISABLE_REFRESH_TOKEN_REUSE_DETECTION"`
+	DisableMailOtpLogin                  bool     `json:"DISABLE_MAIL_OTP_LOGIN"`
+	DisableTotpLogin                     bool     `json:"DISABLE_TOTP_LOGIN"`
 }
 
 type Error struct {
@@ -169,6 +270,43 @@ type GetUserRequest struct {
 	Email *string `json:"email,omitempty"`
 }
 
+type Identities struct {
+	Pagination *Pagination `json:"pagination"`
+	Identities []*Identity `json:"identities"`
+}
+
+type Identity struct {
+	ID             string  `json:"id"`
+	Provider       string  `json:"provider"`
+	ProviderUserID string  `json:"provider_user_id"`
+	Email          *string `json:"email,omitempty"`
+	CreatedAt      *int64  `json:"created_at,omitempty"`
+	UpdatedAt      *int64  `json:"updated_at,omitempty"`
+}
+
+type IdentityProvider struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	DiscoveryURL     *string                `json:"discovery_url,omitempty"`
+	AuthorizationURL *string                `json:"authorization_url,omitempty"`
+	TokenURL         *string                `json:"token_url,omitempty"`
+	UserinfoURL      *string                `json:"userinfo_url,omitempty"`
+	ClientID         string                 `json:"client_id"`
+	Scopes           []string               `json:"scopes,omitempty"`
+	ClaimMapping     map[string]interface{} `json:"claim_mapping,omitempty"`
+	CreatedAt        *int64                 `json:"created_at,omitempty"`
+	UpdatedAt        *int64                 `json:"updated_at,omitempty"`
+}
+
+type IdentityProviderRequest struct {
+	ID string `json:"id"`
+}
+
+type IdentityProviders struct {
+	Pagination        *Pagination         `json:"pagination"`
+	IdentityProviders []*IdentityProvider `json:"identity_providers"`
+}
+
 type InviteMemberInput struct {
 	Emails      []string `json:"emails"`
 	RedirectURI *string  `json:"redirect_uri,omitempty"`
@@ -179,6 +317,30 @@ type InviteMembersResponse struct {
 	Users   []*User `json:"Users"`
 }
 
+type JWTKey struct {
+	Kid         string `json:"kid"`
+	Algorithm   string `json:"algorithm"`
+	Status      string `json:"status"`
+	CreatedAt   *int64 `json:"created_at,omitempty"`
+	ActivatedAt *int64 `json:"activated_at,omitempty"`
+	RetiredAt   *int64 `json:"retired_at,omitempty"`
+	ExpiresAt   *int64 `json:"expires_at,omitempty"`
+}
+
+type JWTKeys struct {
+	Keys []*JWTKey `json:"keys"`
+}
+
+type LinkIdentityRequest struct {
+	Provider    string  `json:"provider"`
+	RedirectURI *string `json:"redirect_uri,omitempty"`
+}
+
+type LinkIdentityResponse struct {
+	Message          string `json:"message"`
+	AuthorizationURL string `json:"authorization_url"`
+}
+
 type ListWebhookLogRequest struct {
 	Pagination *PaginationInput `json:"pagination,omitempty"`
 	WebhookID  *string          `json:"webhook_id,omitempty"`
@@ -202,26 +364,27 @@ type MagicLinkLoginInput struct {
 }
 
 type Meta struct {
-	Version                            string `json:"version"`
-	ClientID                           string `json:"client_id"`
-	IsGoogleLoginEnabled               bool   `json:"is_google_login_enabled"`
-	IsFacebookLoginEnabled             bool   `json:"is_facebook_login_enabled"`
-	IsGithubLoginEnabled               bool   `json:"is_github_login_enabled"`
-	IsLinkedinLoginEnabled             bool   `json:"is_linkedin_login_enabled"`
-	IsAppleLoginEnabled                bool   `json:"is_apple_login_enabled"`
-	IsDiscordLoginEnabled              bool   `json:"is_discord_login_enabled"`
-	IsTwitterLoginEnabled              bool   `json:"is_twitter_login_enabled"`
-	IsMicrosoftLoginEnabled            bool   `json:"is_microsoft_login_enabled"`
-	IsTwitchLoginEnabled               bool   `json:"is_twitch_login_enabled"`
-	IsRobloxLoginEnabled               bool   `json:"is_roblox_login_enabled"`
-	IsEmailVerificationEnabled         bool   `json:"is_email_verification_enabled"`
-	IsBasicAuthenticationEnabled       bool   `json:"is_basic_authentication_enabled"`
-	IsMagicLinkLoginEnabled            bool   `json:"is_magic_link_login_enabled"`
-	IsSignUpEnabled                    bool   `json:"is_sign_up_enabled"`
-	IsStrongPasswordEnabled            bool   `json:"is_strong_password_enabled"`
-	IsMultiFactorAuthEnabled           bool   `json:"is_multi_factor_auth_enabled"`
-	IsMobileBasicAuthenticationEnabled bool   `json:"is_mobile_basic_authentication_enabled"`
-	IsPhoneVerificationEnabled         bool   `json:"is_phone_verification_enabled"`
+	Version                            string   `json:"version"`
+	ClientID                           string   `json:"client_id"`
+	IsGoogleLoginEnabled               bool     `json:"is_google_login_enabled"`
+	IsFacebookLoginEnabled             bool     `json:"is_facebook_login_enabled"`
+	IsGithubLoginEnabled               bool     `json:"is_github_login_enabled"`
+	IsLinkedinLoginEnabled             bool     `json:"is_linkedin_login_enabled"`
+	IsAppleLoginEnabled                bool     `json:"is_apple_login_enabled"`
+	IsDiscordLoginEnabled              bool     `json:"is_discord_login_enabled"`
+	IsTwitterLoginEnabled              bool     `json:"is_twitter_login_enabled"`
+	IsMicrosoftLoginEnabled            bool     `json:"is_microsoft_login_enabled"`
+	IsTwitchLoginEnabled               bool     `json:"is_twitch_login_enabled"`
+	IsRobloxLoginEnabled               bool     `json:"is_roblox_login_enabled"`
+	IsEmailVerificationEnabled         bool     `json:"is_email_verification_enabled"`
+	IsBasicAuthenticationEnabled       bool     `json:"is_basic_authentication_enabled"`
+	IsMagicLinkLoginEnabled            bool     `json:"is_magic_link_login_enabled"`
+	IsSignUpEnabled                    bool     `json:"is_sign_up_enabled"`
+	IsStrongPasswordEnabled            bool     `json:"is_strong_password_enabled"`
+	IsMultiFactorAuthEnabled           bool     `json:"is_multi_factor_auth_enabled"`
+	IsMobileBasicAuthenticationEnabled bool     `json:"is_mobile_basic_authentication_enabled"`
+	IsPhoneVerificationEnabled         bool     `json:"is_phone_verification_enabled"`
+	IdentityProviders                  []string `json:"identity_providers"`
 }
 
 type MobileLoginInput struct {
@@ -255,8 +418,24 @@ type MobileSignUpInput struct {
 type Mutation struct {
 }
 
+type OAuthGrant struct {
+	ID         string   `json:"id"`
+	ClientID   string   `json:"client_id"`
+	ClientName *string  `json:"client_name,omitempty"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  *int64   `json:"created_at,omitempty"`
+	UpdatedAt  *int64   `json:"updated_at,omitempty"`
+}
+
+type OAuthGrants struct {
+	Pagination  *Pagination   `json:"pagination"`
+	OauthGrants []*OAuthGrant `json:"oauth_grants"`
+}
+
 type OAuthRevokeInput struct {
-	RefreshToken string `json:"refresh_token"`
+	Token         *string `json:"token,omitempty"`
+	TokenTypeHint *string `json:"token_type_hint,omitempty"`
+	RefreshToken  *string `json:"refresh_token,omitempty"`
 }
 
 type PaginatedInput struct {
@@ -302,6 +481,61 @@ type Response struct {
 	Message string `json:"message"`
 }
 
+type RevokeOAuthGrantRequest struct {
+	ClientID string `json:"client_id"`
+}
+
+type RotateJWTKeysInput struct {
+	IfOlderThan          *string `json:"if_older_than,omitempty"`
+	RetiredKeyExpiryTime *string `json:"retired_key_expiry_time,omitempty"`
+}
+
+type RotateJWTKeysResponse struct {
+	Message string    `json:"message"`
+	Rotated bool      `json:"rotated"`
+	Keys    []*JWTKey `json:"keys"`
+}
+
+type SAMLIdentityProvider struct {
+	ID                  string                 `json:"id"`
+	Name                string                 `json:"name"`
+	MetadataURL         *string                `json:"metadata_url,omitempty"`
+	MetadataXML         *string                `json:"metadata_xml,omitempty"`
+	AttributeMapping    map[string]interface{} `json:"attribute_mapping,omitempty"`
+	AllowedEmailDomains []string               `json:"allowed_email_domains,omitempty"`
+	CreatedAt           *int64                 `json:"created_at,omitempty"`
+	UpdatedAt           *int64                 `json:"updated_at,omitempty"`
+}
+
+type SAMLIdentityProviderRequest struct {
+	ID string `json:"id"`
+}
+
+type SAMLIdentityProviders struct {
+	Pagination            *Pagination             `json:"pagination"`
+	SamlIdentityProviders []*SAMLIdentityProvider `json:"saml_identity_providers"`
+}
+
+type SAMLServiceProvider struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	EntityID         string                 `json:"entity_id"`
+	AcsURL           string                 `json:"acs_url"`
+	NameIDFormat     string                 `json:"name_id_format"`
+	AttributeMapping map[string]interface{} `json:"attribute_mapping,omitempty"`
+	CreatedAt        *int64                 `json:"created_at,omitempty"`
+	UpdatedAt        *int64                 `json:"updated_at,omitempty"`
+}
+
+type SAMLServiceProviderRequest struct {
+	ID string `json:"id"`
+}
+
+type SAMLServiceProviders struct {
+	Pagination           *Pagination            `json:"pagination"`
+	SamlServiceProviders []*SAMLServiceProvider `json:"saml_service_providers"`
+}
+
 type SMSVerificationRequests struct {
 	ID            string `json:"id"`
 	Code          string `json:"code"`
@@ -348,10 +582,36 @@ type TestEndpointResponse struct {
 	Response   *string `json:"response,omitempty"`
 }
 
+type UnlinkIdentityRequest struct {
+	ID string `json:"id"`
+}
+
 type UpdateAccessInput struct {
 	UserID string `json:"user_id"`
 }
 
+type UpdateClientRequest struct {
+	ID                                 string   `json:"id"`
+	Name                               *string  `json:"name,omitempty"`
+	ClientSecret                       *string  `json:"client_secret,omitempty"`
+	RedirectUris                       []string `json:"redirect_uris,omitempty"`
+	GrantTypes                         []string `json:"grant_types,omitempty"`
+	Scopes                             []string `json:"scopes,omitempty"`
+	AccessTokenExpiryTime              *string  `json:"access_token_expiry_time,omitempty"`
+	RefreshTokenExpiryTime             *string  `json:"refresh_token_expiry_time,omitempty"`
+	RequirePushedAuthorizationRequests *bool    `json:"require_pushed_authorization_requests,omitempty"`
+	RequireSignedRequestObject         *bool    `json:"require_signed_request_object,omitempty"`
+	PostLogoutRedirectUris             []string `json:"post_logout_redirect_uris,omitempty"`
+	FrontchannelLogoutURI              *string  `json:"frontchannel_logout_uri,omitempty"`
+	BackchannelLogoutURI               *string  `json:"backchannel_logout_uri,omitempty"`
+	TokenExchangeAudiences             []string `json:"token_exchange_audiences,omitempty"`
+	TokenEndpointAuthMethod            *string  `json:"token_endpoint_auth_method,omitempty"`
+	Jwks                               *string  `json:"jwks,omitempty"`
+	JwksURI                            *string  `json:"jwks_uri,omitempty"`
+	SubjectType                        *string  `json:"subject_type,omitempty"`
+	SectorIdentifierURI                *string  `json:"sector_identifier_uri,omitempty"`
+}
+
 type UpdateEmailTemplateRequest struct {
 	ID        string  `json:"id"`
 	EventName *string `json:"event_name,omitempty"`
@@ -361,68 +621,87 @@ type UpdateEmailTemplateRequest struct {
 }
 
 type UpdateEnvInput struct {
-	AccessTokenExpiryTime            *string  `json:"ACCESS_TOKEN_EXPIRY_TIME,omitempty"`
-	AdminSecret                      *string  `json:"ADMIN_SECRET,omitempty"`
-	CustomAccessTokenScript          *string  `json:"CUSTOM_ACCESS_TOKEN_SCRIPT,omitempty"`
-	OldAdminSecret                   *string  `json:"OLD_ADMIN_SECRET,omitempty"`
-	SMTPHost                         *string  `json:"SMTP_HOST,omitempty"`
-	SMTPPort                         *string  `json:"SMTP_PORT,omitempty"`
-	SMTPUsername                     *string  `json:"SMTP_USERNAME,omitempty"`
-	SMTPPassword                     *string  `json:"SMTP_PASSWORD,omitempty"`
-	SMTPLocalName                    *string  `json:"SMTP_LOCAL_NAME,omitempty"`
-	SenderEmail                      *string  `json:"SENDER_EMAIL,omitempty"`
-	SenderName                       *string  `json:"SENDER_NAME,omitempty"`
-	JwtType                          *string  `json:"JWT_TYPE,omitempty"`
-	JwtSecret                        *string  `json:"JWT_SECRET,omitempty"`
-	JwtPrivateKey                    *string  `json:"JWT_PRIVATE_KEY,omitempty"`
-	JwtPublicKey                     *string  `json:"JWT_PUBLIC_KEY,omitempty"`
-	AllowedOrigins                   []string `json:"ALLOWED_ORIGINS,omitempty"`
-	AppURL                           *string  `json:"APP_URL,omitempty"`
-	ResetPasswordURL                 *string  `json:"RESET_PASSWORD_URL,omitempty"`
-	AppCookieSecure                  *bool    `json:"APP_COOKIE_SECURE,omitempty"`
-	AdminCookieSecure                *bool    `json:"ADMIN_COOKIE_SECURE,omitempty"`
-	DisableEmailVerification         *bool    `json:"DISABLE_EMAIL_VERIFICATION,omitempty"`
-	DisableBasicAuthentication       *bool    `json:"DISABLE_BASIC_AUTHENTICATION,omitempty"`
-	DisableMobileBasicAuthentication *bool    `json:"DISABLE_MOBILE_BASIC_AUTHENTICATION,omitempty"`
-	DisableMagicLinkLogin            *bool    `json:"DISABLE_MAGIC_LINK_LOGIN,omitempty"`
-	DisableLoginPage                 *bool    `json:"DISABLE_LOGIN_PAGE,omitempty"`
-	DisableSignUp                    *bool    `json:"DISABLE_SIGN_UP,omitempty"`
-	DisableRedisForEnv               *bool    `json:"DISABLE_REDIS_FOR_ENV,omitempty"`
-	DisableStrongPassword            *bool    `json:"DISABLE_STRONG_PASSWORD,omitempty"`
-	DisableMultiFactorAuthentication *bool    `json:"DISABLE_MULTI_FACTOR_AUTHENTICATION,omitempty"`
-	EnforceMultiFactorAuthentication *bool    `json:"ENFORCE_MULTI_FACTOR_AUTHENTICATION,omitempty"`
-	Roles                            []string `json:"ROLES,omitempty"`
-	ProtectedRoles                   []string `json:"PROTECTED_ROLES,omitempty"`
-	DefaultRoles                     []string `json:"DEFAULT_ROLES,omitempty"`
-	JwtRoleClaim                     *string  `json:"JWT_ROLE_CLAIM,omitempty"`
-	GoogleClientID                   *string  `json:"GOOGLE_CLIENT_ID,omitempty"`
-	GoogleClientSecret               *string  `json:"GOOGLE_CLIENT_SECRET,omitempty"`
-	GithubClientID                   *string  `json:"GITHUB_CLIENT_ID,omitempty"`
-	GithubClientSecret               *string  `json:"GITHUB_CLIENT_SECRET,omitempty"`
-	FacebookClientID                 *string  `json:"FACEBOOK_CLIENT_ID,omitempty"`
-	FacebookClientSecret             *string  `json:"FACEBOOK_CLIENT_SECRET,omitempty"`
-	LinkedinClientID                 *string  `json:"LINKEDIN_CLIENT_ID,omitempty"`
-	LinkedinClientSecret             *string  `json:"LINKEDIN_CLIENT_SECRET,omitempty"`
-	AppleClientID                    *string  `json:"APPLE_CLIENT_ID,omitempty"`
-	AppleClientSecret                *string  `json:"APPLE_CLIENT_SECRET,omitempty"`
-	DiscordClientID                  *string  `json:"DISCORD_CLIENT_ID,omitempty"`
-	DiscordClientSecret              *string  `json:"DISCORD_CLIENT_SECRET,omitempty"`
-	TwitterClientID                  *string  `json:"TWITTER_CLIENT_ID,omitempty"`
-	TwitterClientSecret              *string  `json:"TWITTER_CLIENT_SECRET,omitempty"`
-	MicrosoftClientID                *string  `json:"MICROSOFT_CLIENT_ID,omitempty"`
-	MicrosoftClientSecret            *string  `json:"MICROSOFT_CLIENT_SECRET,omitempty"`
-	MicrosoftActiveDirectoryTenantID *string  `json:"MICROSOFT_ACTIVE_DIRECTORY_TENANT_ID,omitempty"`
-	TwitchClientID                   *string  `json:"TWITCH_CLIENT_ID,omitempty"`
-	TwitchClientSecret               *string  `json:"TWITCH_CLIENT_SECRET,omitempty"`
-	RobloxClientID                   *string  `json:"ROBLOX_CLIENT_ID,omitempty"`
-	RobloxClientSecret               *string  `json:"ROBLOX_CLIENT_SECRET,omitempty"`
-	OrganizationName                 *string  `json:"ORGANIZATION_NAME,omitempty"`
-	OrganizationLogo                 *string  `json:"ORGANIZATION_LOGO,omitempty"`
-	DefaultAuthorizeResponseType     *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_TYPE,omitempty"`
-	DefaultAuthorizeResponseMode     *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_MODE,omitempty"`
-	DisablePlayground                *bool    `json:"DISABLE_PLAYGROUND,omitempty"`
-	DisableMailOtpLogin              *bool    `json:"DISABLE_MAIL_OTP_LOGIN,omitempty"`
-	DisableTotpLogin                 *bool    `json:"DISABLE_TOTP_LOGIN,omitempty"`
+	AccessTokenExpiryTime                *string  `json:"ACCESS_TOKEN_EXPIRY_TIME,omitempty"`
+	RefreshTokenExpiryTime               *string  `json:"REFRESH_TOKEN_EXPIRY_TIME,omitempty"`
+	SessionExpiryTime                    *string  `json:"SESSION_EXPIRY_TIME,omitempty"`
+	SessionIdleTimeout                   *string  `json:"SESSION_IDLE_TIMEOUT,omitempty"`
+	SessionMaxAge                        *string  `json:"SESSION_MAX_AGE,omitempty"`
+	OtpExpiryTime                        *string  `json:"OTP_EXPIRY_TIME,omitempty"`
+	AdminSecret                          *string  `json:"ADMIN_SECRET,omitempty"`
+	CustomAccessTokenScript              *string  `json:"CUSTOM_ACCESS_TOKEN_SCRIPT,omitempty"`
+	OldAdminSecret                       *string  `json:"OLD_ADMIN_SECRET,omitempty"`
+	SMTPHost                             *string  `json:"SMTP_HOST,omitempty"`
+	SMTPPort                             *string  `json:"SMTP_PORT,omitempty"`
+	SMTPUsername                         *string  `json:"SMTP_USERNAME,omitempty"`
+	SMTPPassword                         *string  `json:"SMTP_PASSWORD,omitempty"`
+	SMTPLocalName                        *string  `json:"SMTP_LOCAL_NAME,omitempty"`
+	SenderEmail                          *string  `json:"SENDER_EMAIL,omitempty"`
+	SenderName                           *string  `json:"SENDER_NAME,omitempty"`
+	JwtType                              *string  `json:"JWT_TYPE,omitempty"`
+	JwtSecret                            *string  `json:"JWT_SECRET,omitempty"`
+	JwtPrivateKey                        *string  `json:"JWT_PRIVATE_KEY,omitempty"`
+	JwtPublicKey                         *string  `json:"JWT_PUBLIC_KEY,omitempty"`
+	AllowedOrigins                       []string `json:"ALLOWED_ORIGINS,omitempty"`
+	AppURL                               *string  `json:"APP_URL,omitempty"`
+	ResetPasswordURL                     *string  `json:"RESET_PASSWORD_URL,omitempty"`
+	AppCookieSecure                      *bool    `json:"APP_COOKIE_SECURE,omitempty"`
+	AdminCookieSecure                    *bool    `json:"ADMIN_COOKIE_SECURE,omitempty"`
+	DisableEmailVerification             *bool    `json:"DISABLE_EMAIL_VERIFICATION,omitempty"`
+	DisableBasicAuthentication           *bool    `json:"DISABLE_BASIC_AUTHENTICATION,omitempty"`
+	DisableMobileBasicAuthentication     *bool    `json:"DISABLE_MOBILE_BASIC_AUTHENTICATION,omitempty"`
+	DisableMagicLinkLogin                *bool    `json:"DISABLE_MAGIC_LINK_LOGIN,omitempty"`
+	DisableLoginPage                     *bool    `json:"DISABLE_LOGIN_PAGE,omitempty"`
+	DisableSignUp                        *bool    `json:"DISABLE_SIGN_UP,omitempty"`
+	DisableRedisForEnv                   *bool    `json:"DISABLE_REDIS_FOR_ENV,omitempty"`
+	DisableStrongPassword                *bool    `json:"DISABLE_STRONG_PASSWORD,omitempty"`
+	DisableMultiFactorAuthentication     *bool    `json:"DISABLE_MULTI_FACTOR_AUTHENTICATION,omitempty"`
+	EnforceMultiFactorAuthentication     *bool    `json:"ENFORCE_MULTI_FACTOR_AUTHENTICATION,omitempty"`
+	Roles                                []string `json:"ROLES,omitempty"`
+	ProtectedRoles                       []string `json:"PROTECTED_ROLES,omitempty"`
+	DefaultRoles                         []string `json:"DEFAULT_ROLES,omitempty"`
+	JwtRoleClaim                         *string  `json:"JWT_ROLE_CLAIM,omitempty"`
+	GoogleClientID                       *string  `json:"GOOGLE_CLIENT_ID,omitempty"`
+	GoogleClientSecret                   *string  `json:"GOOGLE_CLIENT_SECRET,omitempty"`
+	GithubClientID                       *string  `json:"GITHUB_CLIENT_ID,omitempty"`
+	GithubClientSecret                   *string  `json:"GITHUB_CLIENT_SECRET,omitempty"`
+	FacebookClientID                     *string  `json:"FACEBOOK_CLIENT_ID,omitempty"`
+	FacebookClientSecret                 *string  `json:"FACEBOOK_CLIENT_SECRET,omitempty"`
+	LinkedinClientID                     *string  `json:"LINKEDIN_CLIENT_ID,omitempty"`
+	LinkedinClientSecret                 *string  `json:"LINKEDIN_CLIENT_SECRET,omitempty"`
+	AppleClientID                        *string  `json:"APPLE_CLIENT_ID,omitempty"`
+	AppleClientSecret                    *string  `json:"APPLE_CLIENT_SECRET,omitempty"`
+	DiscordClientID                      *string  `json:"DISCORD_CLIENT_ID,omitempty"`
+	DiscordClientSecret                  *string  `json:"DISCORD_CLIENT_SECRET,omitempty"`
+	TwitterClientID                      *string  `json:"TWITTER_CLIENT_ID,omitempty"`
+	TwitterClientSecret                  *string  `json:"TWITTER_CLIENT_SECRET,omitempty"`
+	MicrosoftClientID                    *string  `json:"MICROSOFT_CLIENT_ID,omitempty"`
+	MicrosoftClientSecret                *string  `json:"MICROSOFT_CLIENT_SECRET,omitempty"`
+	MicrosoftActiveDirectoryTenantID     *string  `json:"MICROSOFT_ACTIVE_DIRECTORY_TENANT_ID,omitempty"`
+	TwitchClientID                       *string  `json:"TWITCH_CLIENT_ID,omitempty"`
+	TwitchClientSecret                   *string  `json:"TWITCH_CLIENT_SECRET,omitempty"`
+	RobloxClientID                       *string  `json:"ROBLOX_CLIENT_ID,omitempty"`
+	RobloxClientSecret                   *string  `json:"ROBLOX_CLIENT_SECRET,omitempty"`
+	OrganizationName                     *string  `json:"ORGANIZATION_NAME,omitempty"`
+	OrganizationLogo                     *string  `json:"ORGANIZATION_LOGO,omitempty"`
+	DefaultAuthorizeResponseType         *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_TYPE,omitempty"`
+	DefaultAuthorizeResponseMode         *string  `json:"DEFAULT_AUTHORIZE_RESPONSE_MODE,omitempty"`
+	ClientRegistrationInitialAccessToken *string  `json:"CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN,omitempty"`
+	DisablePlayground                    *bool    `json:"DISABLE_PLAYGROUND,omitempty"`
+	DisableRefreshTokenReuseDetection    *bool    `json:"DISABLE_REFRESH_TOKEN_REUSE_DETECTION,omitempty"`
+	DisableMailOtpLogin                  *bool    `json:"DISABLE_MAIL_OTP_LOGIN,omitempty"`
+	DisableTotpLogin                     *bool    `json:"DISABLE_TOTP_LOGIN,omitempty"`
+}
+
+type UpdateIdentityProviderRequest struct {
+	ID               string                 `json:"id"`
+	DiscoveryURL     *string                `json:"discovery_url,omitempty"`
+	AuthorizationURL *string                `json:"authorization_url,omitempty"`
+	TokenURL         *string                `json:"token_url,omitempty"`
+	UserinfoURL      *string                `json:"userinfo_url,omitempty"`
+	ClientID         *string                `json:"client_id,omitempty"`
+	ClientSecret     *string                `json:"client_secret,omitempty"`
+	Scopes           []string               `json:"scopes,omitempty"`
+	ClaimMapping     map[string]interface{} `json:"claim_mapping,omitempty"`
 }
 
 type UpdateProfileInput struct {
@@ -442,6 +721,23 @@ type UpdateProfileInput struct {
 	AppData                  map[string]interface{} `json:"app_data,omitempty"`
 }
 
+type UpdateSAMLIdentityProviderRequest struct {
+	ID                  string                 `json:"id"`
+	MetadataURL         *string                `json:"metadata_url,omitempty"`
+	MetadataXML         *string                `json:"metadata_xml,omitempty"`
+	AttributeMapping    map[string]interface{} `json:"attribute_mapping,omitempty"`
+	AllowedEmailDomains []string               `json:"allowed_email_domains,omitempty"`
+}
+
+type UpdateSAMLServiceProviderRequest struct {
+	ID               string                 `json:"id"`
+	Name             *string                `json:"name,omitempty"`
+	EntityID         *string                `json:"entity_id,omitempty"`
+	AcsURL           *string                `json:"acs_url,omitempty"`
+	NameIDFormat     *string                `json:"name_id_format,omitempty"`
+	AttributeMapping map[string]interface{} `json:"attribute_mapping,omitempty"`
+}
+
 type UpdateUserInput struct {
 	ID                       string                 `json:"id"`
 	Email                    *string                `json:"email,omitempty"`
@@ -87,6 +87,26 @@ func (r *mutationResolver) DeactivateAccount(ctx context.Context) (*model.Respon
 	return resolvers.DeactivateAccountResolver(ctx)
 }
 
+// AuthorizeDevice is the resolver for the authorize_device field.
+func (r *mutationResolver) AuthorizeDevice(ctx context.Context, params model.AuthorizeDeviceRequest) (*model.Response, error) {
+	return resolvers.AuthorizeDeviceResolver(ctx, params)
+}
+
+// RevokeOauthGrant is the resolver for the revoke_oauth_grant field.
+func (r *mutationResolver) RevokeOauthGrant(ctx context.Context, params model.RevokeOAuthGrantRequest) (*model.Response, error) {
+	return resolvers.RevokeOAuthGrantResolver(ctx, params)
+}
+
+// LinkIdentity is the resolver for the link_identity field.
+func (r *mutationResolver) LinkIdentity(ctx context.Context, params model.LinkIdentityRequest) (*model.LinkIdentityResponse, error) {
+	return resolvers.LinkIdentityResolver(ctx, params)
+}
+
+// UnlinkIdentity is the resolver for the unlink_identity field.
+func (r *mutationResolver) UnlinkIdentity(ctx context.Context, params model.UnlinkIdentityRequest) (*model.Response, error) {
+	return resolvers.UnlinkIdentityResolver(ctx, params)
+}
+
 // DeleteUser is the resolver for the _delete_user field.
 func (r *mutationResolver) DeleteUser(ctx context.Context, params model.DeleteUserInput) (*model.Response, error) {
 	return resolvers.DeleteUserResolver(ctx, params)
@@ -137,6 +157,11 @@ func (r *mutationResolver) GenerateJwtKeys(ctx context.Context, params model.Gen
 	return resolvers.GenerateJWTKeysResolver(ctx, params)
 }
 
+// RotateJwtKeys is the resolver for the _rotate_jwt_keys field.
+func (r *mutationResolver) RotateJwtKeys(ctx context.Context, params *model.RotateJWTKeysInput) (*model.RotateJWTKeysResponse, error) {
+	return resolvers.RotateJWTKeysResolver(ctx, params)
+}
+
 // AddWebhook is the resolver for the _add_webhook field.
 func (r *mutationResolver) AddWebhook(ctx context.Context, params model.AddWebhookRequest) (*model.Response, error) {
 	return resolvers.AddWebhookResolver(ctx, params)
@@ -172,6 +197,66 @@ func (r *mutationResolver) DeleteEmailTemplate(ctx context.Context, params model
 	return resolvers.DeleteEmailTemplateResolver(ctx, params)
 }
 
+// AddClient is the resolver for the _add_client field.
+func (r *mutationResolver) AddClient(ctx context.Context, params model.AddClientRequest) (*model.AddClientResponse, error) {
+	return resolvers.AddClientResolver(ctx, params)
+}
+
+// UpdateClient is the resolver for the _update_client field.
+func (r *mutationResolver) UpdateClient(ctx context.Context, params model.UpdateClientRequest) (*model.Response, error) {
+	return resolvers.UpdateClientResolver(ctx, params)
+}
+
+// DeleteClient is the resolver for the _delete_client field.
+func (r *mutationResolver) DeleteClient(ctx context.Context, params model.ClientRequest) (*model.Response, error) {
+	return resolvers.DeleteClientResolver(ctx, params)
+}
+
+// AddIdentityProvider is the resolver for the _add_identity_provider field.
+func (r *mutationResolver) AddIdentityProvider(ctx context.Context, params model.AddIdentityProviderRequest) (*model.Response, error) {
+	return resolvers.AddIdentityProviderResolver(ctx, params)
+}
+
+// UpdateIdentityProvider is the resolver for the _update_identity_provider field.
+func (r *mutationResolver) UpdateIdentityProvider(ctx context.Context, params model.UpdateIdentityProviderRequest) (*model.Response, error) {
+	return resolvers.UpdateIdentityProviderResolver(ctx, params)
+}
+
+// DeleteIdentityProvider is the resolver for the _delete_identity_provider field.
+func (r *mutationResolver) DeleteIdentityProvider(ctx context.Context, params model.IdentityProviderRequest) (*model.Response, error) {
+	return resolvers.DeleteIdentityProviderResolver(ctx, params)
+}
+
+// AddSamlServiceProvider is the resolver for the _add_saml_service_provider field.
+func (r *mutationResolver) AddSamlServiceProvider(ctx context.Context, params model.AddSAMLServiceProviderRequest) (*model.Response, error) {
+	return resolvers.AddSAMLServiceProviderResolver(ctx, params)
+}
+
+// UpdateSamlServiceProvider is the resolver for the _update_saml_service_provider field.
+func (r *mutationResolver) UpdateSamlServiceProvider(ctx context.Context, params model.UpdateSAMLServiceProviderRequest) (*model.Response, error) {
+	return resolvers.UpdateSAMLServiceProviderResolver(ctx, params)
+}
+
+// DeleteSamlServiceProvider is the resolver for the _delete_saml_service_provider field.
+func (r *mutationResolver) DeleteSamlServiceProvider(ctx context.Context, params model.SAMLServiceProviderRequest) (*model.Response, error) {
+	return resolvers.DeleteSAMLServiceProviderResolver(ctx, params)
+}
+
+// AddSamlIdentityProvider is the resolver for the _add_saml_identity_provider field.
+func (r *mutationResolver) AddSamlIdentityProvider(ctx context.Context, params model.AddSAMLIdentityProviderRequest) (*model.Response, error) {
+	return resolvers.AddSAMLIdentityProviderResolver(ctx, params)
+}
+
+// UpdateSamlIdentityProvider is the resolver for the _update_saml_identity_provider field.
+func (r *mutationResolver) UpdateSamlIdentityProvider(ctx context.Context, params model.UpdateSAMLIdentityProviderRequest) (*model.Response, error) {
+	return resolvers.UpdateSAMLIdentityProviderResolver(ctx, params)
+}
+
+// DeleteSamlIdentityProvider is the resolver for the _delete_saml_identity_provider field.
+func (r *mutationResolver) DeleteSamlIdentityProvider(ctx context.Context, params model.SAMLIdentityProviderRequest) (*model.Response, error) {
+	return resolvers.DeleteSAMLIdentityProviderResolver(ctx, params)
+}
+
 // Meta is the resolver for the meta field.
 func (r *queryResolver) Meta(ctx context.Context) (*model.Meta, error) {
 	return resolvers.MetaResolver(ctx)
@@ -197,6 +282,16 @@ func (r *queryResolver) ValidateSession(ctx context.Context, params *model.Valid
 	return resolvers.ValidateSessionResolver(ctx, params)
 }
 
+// OauthGrants is the resolver for the oauth_grants field.
+func (r *queryResolver) OauthGrants(ctx context.Context, params *model.PaginatedInput) (*model.OAuthGrants, error) {
+	return resolvers.OAuthGrantsResolver(ctx, params)
+}
+
+// Identities is the resolver for the identities field.
+func (r *queryResolver) Identities(ctx context.Context, params *model.PaginatedInput) (*model.Identities, error) {
+	return resolvers.IdentitiesResolver(ctx, params)
+}
+
 // Users is the resolver for the _users field.
 func (r *queryResolver) Users(ctx context.Context, params *model.PaginatedInput) (*model.Users, error) {
 	return resolvers.UsersResolver(ctx, params)
@@ -242,6 +337,51 @@ func (r *queryResolver) EmailTemplates(ctx context.Context, params *model.Pagina
 	return resolvers.EmailTemplatesResolver(ctx, params)
 }
 
+// Client is the resolver for the _client field.
+func (r *queryResolver) Client(ctx context.Context, params model.ClientRequest) (*model.Client, error) {
+	return resolvers.ClientResolver(ctx, params)
+}
+
+// Clients is the resolver for the _clients field.
+func (r *queryResolver) Clients(ctx context.Context, params *model.PaginatedInput) (*model.Clients, error) {
+	return resolvers.ClientsResolver(ctx, params)
+}
+
+// JwtKeys is the resolver for the _jwt_keys field.
+func (r *queryResolver) JwtKeys(ctx context.Context) (*model.JWTKeys, error) {
+	return resolvers.JWTKeysResolver(ctx)
+}
+
+// IdentityProvider is the resolver for the _identity_provider field.
+func (r *queryResolver) IdentityProvider(ctx context.Context, params model.IdentityProviderRequest) (*model.IdentityProvider, error) {
+	return resolvers.IdentityProviderResolver(ctx, params)
+}
+
+// IdentityProviders is the resolver for the _identity_providers field.
+func (r *queryResolver) IdentityProviders(ctx context.Context, params *model.PaginatedInput) (*model.IdentityProviders, error) {
+	return resolvers.IdentityProvidersResolver(ctx, params)
+}
+
+// SamlServiceProvider is the resolver for the _saml_service_provider field.
+func (r *queryResolver) SamlServiceProvider(ctx context.Context, params model.SAMLServiceProviderRequest) (*model.SAMLServiceProvider, error) {
+	return resolvers.SAMLServiceProviderResolver(ctx, params)
+}
+
+// SamlServiceProviders is the resolver for the _saml_service_providers field.
+func (r *queryResolver) SamlServiceProviders(ctx context.Context, params *model.PaginatedInput) (*model.SAMLServiceProviders, error) {
+	return resolvers.SAMLServiceProvidersResolver(ctx, params)
+}
+
+// SamlIdentityProvider is the resolver for the _saml_identity_provider field.
+func (r *queryResolver) SamlIdentityProvider(ctx context.Context, params model.SAMLIdentityProviderRequest) (*model.SAMLIdentityProvider, error) {
+	return resolvers.SAMLIdentityProviderResolver(ctx, params)
+}
+
+// SamlIdentityProviders is the resolver for the _saml_identity_providers field.
+func (r *queryResolver) SamlIdentityProviders(ctx context.Context, params *model.PaginatedInput) (*model.SAMLIdentityProviders, error) {
+	return resolvers.SAMLIdentityProvidersResolver(ctx, params)
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -32,10 +32,13 @@ jargons
 **/
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -44,8 +47,12 @@ import (
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/cookie"
 	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
 	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
 	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
 )
 
 // Check the flow for generating and verifying codes: https://developer.okta.com/blog/2019/08/22/okta-authjs-pkce#:~:text=PKCE%20works%20by%20having%20the,is%20called%20the%20Code%20Challenge.
@@ -55,6 +62,7 @@ import (
 const (
 	authorizeWebMessageTemplate = "authorize_web_message.tmpl"
 	authorizeFormPostTemplate   = "authorize_form_post.tmpl"
+	consentTemplate             = "consent.tmpl"
 	baseAppPath                 = "/app"
 	signupPath                  = "/app/signup"
 )
@@ -62,21 +70,109 @@ const (
 // AuthorizeHandler is the handler for the /authorize route
 // required params
 // ?redirect_uri = redirect url
-// ?response_mode = to decide if result should be html or re-direct
+// ?response_mode = to decide if result should be html or re-direct, jwt response modes wrap response in signed jwt (JARM)
 // state[recommended] = to prevent CSRF attack (for authorizer its compulsory)
 // code_challenge = to prevent CSRF attack
 // code_challenge_method = to prevent CSRF attack [only sh256 is supported]
+// request_uri = reference to params pushed via /oauth/par, all other params except client_id are ignored.
+// request objects passed by reference are not supported, so request_uri hosted by client is rejected
+// request = request object signed by client (RFC 9101), its params take precedence over query params
+// prompt = none for silent authentication, login to force re-authentication
+// max_age = allowed time in seconds since user was authenticated, user is asked to re-authenticate once it has elapsed
+// login_hint = email / phone number used to pre-fill login form
+// acr_values = voluntary request for authentication context class, it is not enforced but first value is reflected as acr claim of id token
+// claims = individual claims requested for userinfo & id token, in addition to the claims of requested scopes
+// consent page is shown to user for clients other than default client,
+// unless user has already granted all the requested scopes to client or prompt=consent is sent
 func AuthorizeHandler() gin.HandlerFunc {
 	return func(gc *gin.Context) {
-		redirectURI := strings.TrimSpace(gc.Query("redirect_uri"))
-		responseType := strings.TrimSpace(gc.Query("response_type"))
-		state := strings.TrimSpace(gc.Query("state"))
-		codeChallenge := strings.TrimSpace(gc.Query("code_challenge"))
-		scopeString := strings.TrimSpace(gc.Query("scope"))
 		clientID := strings.TrimSpace(gc.Query("client_id"))
-		responseMode := strings.TrimSpace(gc.Query("response_mode"))
-		nonce := strings.TrimSpace(gc.Query("nonce"))
-		screenHint := strings.TrimSpace(gc.Query("screen_hint"))
+		requestURI := strings.TrimSpace(gc.Query("request_uri"))
+		requestObject := strings.TrimSpace(gc.Query("request"))
+		getParam := func(key string) string {
+			return strings.TrimSpace(gc.Query(key))
+		}
+		if requestURI != "" && requestObject != "" {
+			log.Debug("both request and request_uri are present")
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "request and request_uri cannot be used together"})
+			return
+		}
+		isPushedAuthorizationRequest := strings.HasPrefix(requestURI, token.PushedAuthorizationRequestURIPrefix)
+		// minAuthTime is set for authorization request resumed after re-authentication,
+		// so that session authenticated before re-authentication was requested is not used
+		var minAuthTime int64
+		if requestURI != "" && !isPushedAuthorizationRequest {
+			log.Debug("request_uri is not of pushed authorization request: ", requestURI)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "request_uri_not_supported"})
+			return
+		} else if isPushedAuthorizationRequest {
+			pushedAuthorizationRequest, err := token.ConsumePushedAuthorizationRequest(requestURI)
+			if err != nil {
+				log.Debug("invalid request_uri: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid request_uri"})
+				return
+			}
+			if clientID != "" && clientID != pushedAuthorizationRequest.ClientID {
+				log.Debug("client_id does not match request_uri: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid request_uri"})
+				return
+			}
+			clientID = pushedAuthorizationRequest.ClientID
+			minAuthTime = pushedAuthorizationRequest.MinAuthTime
+			getParam = func(key string) string {
+				return pushedAuthorizationRequest.Params[key]
+			}
+		}
+		if requestObject != "" {
+			client, err := utils.GetClientByClientID(gc, clientID)
+			if err != nil || client == nil {
+				log.Debug("invalid client_id for request object: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid client_id " + clientID})
+				return
+			}
+			requestObjectParams, err := getRequestObjectParams(gc, client, requestObject, strings.TrimSpace(gc.Query("response_type")))
+			if err != nil {
+				log.Debug("invalid request object: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid request object"})
+				return
+			}
+			getParam = func(key string) string {
+				if value, ok := requestObjectParams[key]; ok {
+					return value
+				}
+				return strings.TrimSpace(gc.Query(key))
+			}
+		}
+		authorizeParams := map[string]string{}
+		for _, key := range pushedAuthorizationRequestParams {
+			if value := getParam(key); value != "" {
+				authorizeParams[key] = value
+			}
+		}
+
+		redirectURI := getParam("redirect_uri")
+		responseType := getParam("response_type")
+		state := getParam("state")
+		codeChallenge := getParam("code_challenge")
+		scopeString := getParam("scope")
+		responseMode := getParam("response_mode")
+		nonce := getParam("nonce")
+		screenHint := getParam("screen_hint")
+		prompt := getParam("prompt")
+		maxAge := getParam("max_age")
+		loginHint := getParam("login_hint")
+		claimsRequest, err := token.ParseClaimsRequest(getParam("claims"))
+		if err != nil {
+			log.Debug("invalid claims: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid claims"})
+			return
+		}
+		if acrValues := strings.Fields(getParam("acr_values")); len(acrValues) > 0 {
+			if claimsRequest == nil {
+				claimsRequest = &token.ClaimsRequest{}
+			}
+			claimsRequest.ACR = acrValues[0]
+		}
 
 		var scope []string
 		if scopeString == "" {
@@ -84,6 +180,12 @@ func AuthorizeHandler() gin.HandlerFunc {
 		} else {
 			scope = strings.Split(scopeString, " ")
 		}
+		// claims requested via claims request are released only when user consents to the scopes covering them
+		for _, s := range token.GetClaimsRequestScopes(claimsRequest) {
+			if !utils.StringSliceContains(scope, s) {
+				scope = append(scope, s)
+			}
+		}
 
 		if responseMode == "" {
 			if val, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyDefaultAuthorizeResponseMode); err == nil {
@@ -93,10 +195,6 @@ func AuthorizeHandler() gin.HandlerFunc {
 			}
 		}
 
-		if redirectURI == "" {
-			redirectURI = "/app"
-		}
-
 		if responseType == "" {
 			if val, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyDefaultAuthorizeResponseType); err == nil {
 				responseType = val
@@ -105,12 +203,84 @@ func AuthorizeHandler() gin.HandlerFunc {
 			}
 		}
 
-		if err := validateAuthorizeRequest(responseType, responseMode, clientID, state, codeChallenge); err != nil {
+		if responseMode == constants.ResponseModeJWT {
+			if responseType == constants.ResponseTypeCode {
+				responseMode = constants.ResponseModeQueryJWT
+			} else {
+				responseMode = constants.ResponseModeFragmentJWT
+			}
+		}
+
+		client, err := validateAuthorizeRequest(gc, responseType, responseMode, clientID, state, codeChallenge)
+		if err != nil {
 			log.Debug("invalid authorization request: ", err)
 			gc.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
+		if !isPushedAuthorizationRequest && client.RequirePushedAuthorizationRequests {
+			log.Debug("pushed authorization request is required for client: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "request_uri is required for client_id " + clientID})
+			return
+		}
+
+		// pushed authorization requests of such clients are verified while pushing
+		if !isPushedAuthorizationRequest && requestObject == "" && client.RequireSignedRequestObject {
+			log.Debug("signed request object is required for client: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "request object is required for client_id " + clientID})
+			return
+		}
+
+		// redirect uri is validated only when it is provided, as default is login page.
+		// Registered clients default to their only redirect uri, clients with multiple redirect uris should send it
+		shouldValidateRedirectURI := redirectURI != ""
+		if redirectURI == "" {
+			redirectURIs := client.GetRedirectURIs()
+			if !utils.IsDefaultClientID(client.ClientID) && len(redirectURIs) > 1 {
+				log.Debug("redirect uri is required for client: ", client.ClientID)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "redirect_uri is required for client_id " + client.ClientID})
+				return
+			}
+			redirectURI = "/app"
+			if len(redirectURIs) == 1 {
+				redirectURI = redirectURIs[0]
+			}
+		}
+		if shouldValidateRedirectURI && !validators.IsValidClientRedirectURI(client, redirectURI) {
+			log.Debug("invalid redirect uri: ", redirectURI)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid redirect_uri " + redirectURI})
+			return
+		}
+		// code is bound to client & redirect uri, so that it can be exchanged only by the client
+		// with the same redirect uri, which is required at token endpoint when it was sent here
+		codeClient := &token.AuthorizationCodeClient{
+			ClientID: client.ClientID,
+		}
+		if shouldValidateRedirectURI {
+			codeClient.RedirectURI = redirectURI
+		}
+
+		prompts := strings.Fields(prompt)
+		isPromptNone := utils.StringSliceContains(prompts, constants.PromptNone)
+		isPromptLogin := utils.StringSliceContains(prompts, constants.PromptLogin)
+		isPromptConsent := utils.StringSliceContains(prompts, constants.PromptConsent)
+		if isPromptNone && len(prompts) > 1 {
+			log.Debug("invalid prompt: ", prompt)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid prompt " + prompt + ". 'none' cannot be combined with other values"})
+			return
+		}
+
+		// -1 means max_age is not set
+		maxAgeSeconds := int64(-1)
+		if maxAge != "" {
+			maxAgeSeconds, err = strconv.ParseInt(maxAge, 10, 64)
+			if err != nil || maxAgeSeconds < 0 {
+				log.Debug("invalid max_age: ", maxAge)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_age " + maxAge})
+				return
+			}
+		}
+
 		code := uuid.New().String()
 		if nonce == "" {
 			nonce = uuid.New().String()
@@ -121,32 +291,61 @@ func AuthorizeHandler() gin.HandlerFunc {
 			"response_type": responseType,
 		})
 
-		// TODO add state with timeout
-		// used for response mode query or fragment
-		authState := "state=" + state + "&scope=" + scopeString + "&redirect_uri=" + redirectURI
-		if responseType == constants.ResponseTypeCode {
-			authState += "&code=" + code
-			if err := memorystore.Provider.SetState(state, code+"@@"+codeChallenge); err != nil {
-				log.Debug("Error setting temp code", err)
+		// user consent is not required for default client as it is the first party client
+		isConsentRequired := !utils.IsDefaultClientID(client.ClientID)
+		// login page cannot send jwt secured response, hence authorization request
+		// is resumed after login when consent is required or jwt response mode is used
+		isResumeRequired := isConsentRequired || isJWTResponseMode(responseMode)
+		// re-authentication is required for prompt=login & elapsed max_age,
+		// login page is shown even though user has a valid session
+		isReauthenticationRequired := false
+
+		getAuthURL := func(loginRedirectURI string) string {
+			// TODO add state with timeout
+			// used for response mode query or fragment
+			authState := "state=" + state + "&scope=" + scopeString + "&redirect_uri=" + loginRedirectURI
+			// code & nonce are not bound to state when authorization request is resumed after login
+			if !isResumeRequired && responseType == constants.ResponseTypeCode {
+				authState += "&code=" + code
+				if err := memorystore.Provider.SetState(state, code+"@@"+codeChallenge); err != nil {
+					log.Debug("Error setting temp code", err)
+				}
+				if err := token.SetAuthorizationCodeClient(code, codeClient); err != nil {
+					log.Debug("Error setting authorization code client", err)
+				}
+				if claimsRequest != nil {
+					if err := token.SetClaimsRequest(code, claimsRequest); err != nil {
+						log.Debug("Error setting claims request", err)
+					}
+				}
+			} else if !isResumeRequired {
+				authState += "&nonce=" + nonce
+				if err := memorystore.Provider.SetState(state, nonce); err != nil {
+					log.Debug("Error setting temp code", err)
+				}
 			}
-		} else {
-			authState += "&nonce=" + nonce
-			if err := memorystore.Provider.SetState(state, nonce); err != nil {
-				log.Debug("Error setting temp code", err)
+
+			if loginHint != "" {
+				authState += "&login_hint=" + url.QueryEscape(loginHint)
+			}
+			if isReauthenticationRequired {
+				authState += "&prompt=" + constants.PromptLogin
 			}
-		}
 
-		authURL := baseAppPath + "?" + authState
+			authURL := baseAppPath + "?" + authState
 
-		if screenHint == constants.ScreenHintSignUp {
-			authURL = signupPath + "?" + authState
-		}
+			if screenHint == constants.ScreenHintSignUp {
+				authURL = signupPath + "?" + authState
+			}
 
-		if responseMode == constants.ResponseModeFragment && screenHint == constants.ScreenHintSignUp {
-			authURL = signupPath + "#" + authState
-		} else if responseMode == constants.ResponseModeFragment {
-			authURL = baseAppPath + "#" + authState
+			if responseMode == constants.ResponseModeFragment && screenHint == constants.ScreenHintSignUp {
+				authURL = signupPath + "#" + authState
+			} else if responseMode == constants.ResponseModeFragment {
+				authURL = baseAppPath + "#" + authState
+			}
+			return authURL
 		}
+		authURL := getAuthURL(redirectURI)
 
 		if responseType == constants.ResponseTypeCode && codeChallenge == "" {
 			handleResponse(gc, responseMode, authURL, redirectURI, map[string]interface{}{
@@ -166,10 +365,35 @@ func AuthorizeHandler() gin.HandlerFunc {
 				"error_description": "Login is required",
 			},
 		}
+		// with prompt=none login page is never shown,
+		// instead login_required error is sent to redirect uri
+		handleLoginRequired := func() {
+			if isPromptNone {
+				handleAuthorizeError(gc, responseMode, redirectURI, client.ClientID, state, "login_required", "Login is required")
+				return
+			}
+			if isResumeRequired {
+				// user is redirected back to /authorize after login, so that consent can be asked
+				loginMinAuthTime := minAuthTime
+				if isReauthenticationRequired {
+					loginMinAuthTime = time.Now().Unix()
+				}
+				loginAuthorizationRequest, err := token.SetLoginAuthorizationRequest(client.ClientID, getResumeAuthorizeParams(authorizeParams, constants.PromptLogin), loginMinAuthTime)
+				if err != nil {
+					log.Debug("SetLoginAuthorizationRequest failed: ", err)
+					gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save authorization request"})
+					return
+				}
+				resumeURI := parsers.GetHost(gc) + "/authorize?client_id=" + url.QueryEscape(client.ClientID) + "&request_uri=" + url.QueryEscape(loginAuthorizationRequest.RequestURI)
+				handleResponse(gc, responseMode, getAuthURL(url.QueryEscape(resumeURI)), redirectURI, loginError, http.StatusOK)
+				return
+			}
+			handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
+		}
 		sessionToken, err := cookie.GetSession(gc)
 		if err != nil {
 			log.Debug("GetSession failed: ", err)
-			handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
+			handleLoginRequired()
 			return
 		}
 
@@ -177,7 +401,17 @@ func AuthorizeHandler() gin.HandlerFunc {
 		claims, err := token.ValidateBrowserSession(gc, sessionToken)
 		if err != nil {
 			log.Debug("ValidateBrowserSession failed: ", err)
-			handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
+			handleLoginRequired()
+			return
+		}
+
+		// prompt=login & elapsed max_age require user to re-authenticate.
+		// Current session is not ended, as re-authentication may never be completed
+		isMaxAgeElapsed := maxAgeSeconds >= 0 && time.Now().Unix()-claims.IssuedAt > maxAgeSeconds
+		if isPromptLogin || isMaxAgeElapsed || claims.IssuedAt < minAuthTime {
+			log.Debug("re-authentication is required, prompt: ", prompt, " max_age: ", maxAge)
+			isReauthenticationRequired = true
+			handleLoginRequired()
 			return
 		}
 
@@ -195,6 +429,51 @@ func AuthorizeHandler() gin.HandlerFunc {
 			return
 		}
 
+		if isConsentRequired {
+			isConsentGiven := false
+			oauthGrant, err := db.Provider.GetOAuthGrant(gc, user.ID, client.ClientID)
+			if err == nil && oauthGrant != nil && !isPromptConsent {
+				isConsentGiven = true
+				grantedScopes := oauthGrant.GetScopes()
+				for _, s := range scope {
+					if !utils.StringSliceContains(grantedScopes, s) {
+						isConsentGiven = false
+						break
+					}
+				}
+			}
+			if !isConsentGiven {
+				if isPromptNone {
+					handleAuthorizeError(gc, responseMode, redirectURI, client.ClientID, state, "consent_required", "Consent is required")
+					return
+				}
+				consentRequest, err := token.SetConsentRequest(&token.ConsentRequest{
+					UserID:       user.ID,
+					ClientID:     client.ClientID,
+					Scope:        scope,
+					RedirectURI:  redirectURI,
+					ResponseMode: responseMode,
+					State:        state,
+					Params:       getResumeAuthorizeParams(authorizeParams, constants.PromptLogin, constants.PromptConsent),
+				})
+				if err != nil {
+					log.Debug("SetConsentRequest failed: ", err)
+					gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save consent request"})
+					return
+				}
+				clientName := client.ClientID
+				if client.Name != "" {
+					clientName = client.Name
+				}
+				gc.HTML(http.StatusOK, consentTemplate, gin.H{
+					"client_name":       clientName,
+					"scopes":            scope,
+					"consent_challenge": consentRequest.ID,
+				})
+				return
+			}
+		}
+
 		sessionKey := user.ID
 		if claims.LoginMethod != "" {
 			sessionKey = claims.LoginMethod + ":" + user.ID
@@ -203,7 +482,7 @@ func AuthorizeHandler() gin.HandlerFunc {
 		// rollover the session for security
 		go memorystore.Provider.DeleteUserSession(sessionKey, claims.Nonce)
 		if responseType == constants.ResponseTypeCode {
-			newSessionTokenData, newSessionToken, newSessionExpiresAt, err := token.CreateSessionToken(user, nonce, claims.Roles, scope, claims.LoginMethod)
+			newSessionTokenData, newSessionToken, newSessionExpiresAt, err := token.CreateSessionToken(user, nonce, claims.Roles, scope, claims.LoginMethod, claims.IssuedAt, claims.GetSessionID())
 			if err != nil {
 				log.Debug("CreateSessionToken failed: ", err)
 				handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
@@ -223,6 +502,19 @@ func AuthorizeHandler() gin.HandlerFunc {
 				handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
 				return
 			}
+			if err := token.SetAuthorizationCodeClient(code, codeClient); err != nil {
+				log.Debug("SetAuthorizationCodeClient failed: ", err)
+				handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
+				return
+			}
+
+			if claimsRequest != nil {
+				if err := token.SetClaimsRequest(code, claimsRequest); err != nil {
+					log.Debug("SetClaimsRequest failed: ", err)
+					handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
+					return
+				}
+			}
 
 			if err := memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+newSessionTokenData.Nonce, newSessionToken, newSessionExpiresAt); err != nil {
 				log.Debug("SetUserSession failed: ", err)
@@ -232,6 +524,14 @@ func AuthorizeHandler() gin.HandlerFunc {
 
 			cookie.SetSession(gc, newSessionToken)
 
+			if isJWTResponseMode(responseMode) {
+				handleJWTResponse(gc, responseMode, redirectURI, client.ClientID, map[string]interface{}{
+					"code":  code,
+					"state": state,
+				})
+				return
+			}
+
 			// in case, response type is code and user is already logged in send the code and state
 			// and cookie session will already be rolled over and set
 			// gc.HTML(http.StatusOK, authorizeWebMessageTemplate, gin.H{
@@ -273,7 +573,7 @@ func AuthorizeHandler() gin.HandlerFunc {
 
 		if responseType == constants.ResponseTypeToken || responseType == constants.ResponseTypeIDToken {
 			// rollover the session for security
-			authToken, err := token.CreateAuthToken(gc, user, claims.Roles, scope, claims.LoginMethod, nonce, "")
+			authToken, err := token.CreateAuthTokenForClient(gc, client, user, claims.Roles, scope, claims.LoginMethod, nonce, "", claims.IssuedAt, claims.GetSessionID(), "", claimsRequest)
 			if err != nil {
 				log.Debug("CreateAuthToken failed: ", err)
 				handleResponse(gc, responseMode, authURL, redirectURI, loginError, http.StatusOK)
@@ -317,6 +617,11 @@ func AuthorizeHandler() gin.HandlerFunc {
 				memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeRefreshToken+"_"+authToken.FingerPrint, authToken.RefreshToken.Token, authToken.RefreshToken.ExpiresAt)
 			}
 
+			if isJWTResponseMode(responseMode) {
+				handleJWTResponse(gc, responseMode, redirectURI, client.ClientID, res)
+				return
+			}
+
 			if responseMode == constants.ResponseModeQuery {
 				if strings.Contains(redirectURI, "?") {
 					redirectURI = redirectURI + "&" + params
@@ -342,23 +647,28 @@ func AuthorizeHandler() gin.HandlerFunc {
 	}
 }
 
-func validateAuthorizeRequest(responseType, responseMode, clientID, state, codeChallenge string) error {
+func validateAuthorizeRequest(ctx context.Context, responseType, responseMode, clientID, state, codeChallenge string) (*models.Client, error) {
 	if strings.TrimSpace(state) == "" {
-		return fmt.Errorf("invalid state. state is required to prevent csrf attack")
+		return nil, fmt.Errorf("invalid state. state is required to prevent csrf attack")
 	}
 	if responseType != constants.ResponseTypeCode && responseType != constants.ResponseTypeToken && responseType != constants.ResponseTypeIDToken {
-		return fmt.Errorf("invalid response type %s. 'code' & 'token' are valid response_type", responseMode)
+		return nil, fmt.Errorf("invalid response type %s. 'code' & 'token' are valid response_type", responseMode)
+	}
+
+	if responseMode != constants.ResponseModeQuery && responseMode != constants.ResponseModeWebMessage && responseMode != constants.ResponseModeFragment && responseMode != constants.ResponseModeFormPost && !isJWTResponseMode(responseMode) {
+		return nil, fmt.Errorf("invalid response mode %s. 'query', 'fragment', 'form_post', 'web_message', 'query.jwt', 'fragment.jwt', 'form_post.jwt' and 'jwt' are valid response_mode", responseMode)
 	}
 
-	if responseMode != constants.ResponseModeQuery && responseMode != constants.ResponseModeWebMessage && responseMode != constants.ResponseModeFragment && responseMode != constants.ResponseModeFormPost {
-		return fmt.Errorf("invalid response mode %s. 'query', 'fragment', 'form_post' and 'web_message' are valid response_mode", responseMode)
+	client, err := utils.GetClientByClientID(ctx, clientID)
+	if err != nil || client == nil {
+		return nil, fmt.Errorf("invalid client_id %s", clientID)
 	}
 
-	if client, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID); client != clientID || err != nil {
-		return fmt.Errorf("invalid client_id %s", clientID)
+	if responseType == constants.ResponseTypeCode && !validators.IsValidClientGrantType(client, constants.GrantTypeAuthorizationCode) {
+		return nil, fmt.Errorf("response type %s is not allowed for client_id %s", responseType, clientID)
 	}
 
-	return nil
+	return client, nil
 }
 
 func handleResponse(gc *gin.Context, responseMode, authURI, redirectURI string, data map[string]interface{}, httpStatusCode int) {
@@ -390,3 +700,107 @@ func handleResponse(gc *gin.Context, responseMode, authURI, redirectURI string,
 		return
 	}
 }
+
+// getResumeAuthorizeParams returns the authorization request params used to resume
+// authorization request, given prompt values & max_age are removed as they are already satisfied
+func getResumeAuthorizeParams(params map[string]string, prompts ...string) map[string]string {
+	resumeParams := map[string]string{}
+	for key, value := range params {
+		resumeParams[key] = value
+	}
+	delete(resumeParams, "max_age")
+	if prompt, ok := resumeParams["prompt"]; ok {
+		resumePrompts := []string{}
+		for _, p := range strings.Fields(prompt) {
+			if !utils.StringSliceContains(prompts, p) {
+				resumePrompts = append(resumePrompts, p)
+			}
+		}
+		if len(resumePrompts) == 0 {
+			delete(resumeParams, "prompt")
+		} else {
+			resumeParams["prompt"] = strings.Join(resumePrompts, " ")
+		}
+	}
+	return resumeParams
+}
+
+// isJWTResponseMode returns true for response modes of jwt secured authorization response (JARM)
+func isJWTResponseMode(responseMode string) bool {
+	return responseMode == constants.ResponseModeQueryJWT || responseMode == constants.ResponseModeFragmentJWT || responseMode == constants.ResponseModeFormPostJWT
+}
+
+// handleJWTResponse sends the authorization response params to redirect uri
+// as jwt signed with instance key in response param, as per jwt response mode
+func handleJWTResponse(gc *gin.Context, responseMode, redirectURI, clientID string, res map[string]interface{}) {
+	responseToken, err := token.CreateAuthorizationResponseToken(clientID, parsers.GetHost(gc), res)
+	if err != nil {
+		log.Debug("CreateAuthorizationResponseToken failed: ", err)
+		gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create authorization response"})
+		return
+	}
+
+	if responseMode == constants.ResponseModeFormPostJWT {
+		gc.HTML(http.StatusOK, authorizeFormPostTemplate, gin.H{
+			"target_origin": redirectURI,
+			"authorization_response": map[string]interface{}{
+				"response": responseToken,
+			},
+		})
+		return
+	}
+
+	separator := "?"
+	if responseMode == constants.ResponseModeFragmentJWT {
+		separator = "#"
+	}
+	if strings.Contains(redirectURI, separator) {
+		redirectURI = redirectURI + "&response=" + responseToken
+	} else {
+		redirectURI = redirectURI + separator + "response=" + responseToken
+	}
+	gc.Redirect(http.StatusFound, redirectURI)
+}
+
+// handleAuthorizeError sends the error response to redirect uri as per response mode,
+// it is used when login page should not be shown to user e.g. prompt=none
+func handleAuthorizeError(gc *gin.Context, responseMode, redirectURI, clientID, state, errorCode, errorDescription string) {
+	res := map[string]interface{}{
+		"error":             errorCode,
+		"error_description": errorDescription,
+		"state":             state,
+	}
+
+	if isJWTResponseMode(responseMode) {
+		handleJWTResponse(gc, responseMode, redirectURI, clientID, res)
+		return
+	}
+
+	switch responseMode {
+	case constants.ResponseModeWebMessage:
+		gc.HTML(http.StatusOK, authorizeWebMessageTemplate, gin.H{
+			"target_origin": redirectURI,
+			"authorization_response": map[string]interface{}{
+				"type":     "authorization_response",
+				"response": res,
+			},
+		})
+	case constants.ResponseModeFormPost:
+		gc.HTML(http.StatusOK, authorizeFormPostTemplate, gin.H{
+			"target_origin":          redirectURI,
+			"authorization_response": res,
+		})
+	default:
+		params := "error=" + url.QueryEscape(errorCode) + "&error_description=" + url.QueryEscape(errorDescription) + "&state=" + url.QueryEscape(state)
+		separator := "?"
+		if responseMode == constants.ResponseModeFragment {
+			separator = "#"
+		}
+		if strings.Contains(redirectURI, separator) {
+			redirectURI = redirectURI + "&" + params
+		} else {
+			redirectURI = redirectURI + separator + params
+		}
+		gc.Redirect(http.StatusFound, redirectURI)
+	}
+}
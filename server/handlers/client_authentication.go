@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+var (
+	// errClientAuthenticationRequired is returned when confidential client does not send credentials
+	errClientAuthenticationRequired = errors.New("client authentication is required")
+	// errInvalidClientCredentials is returned when client credentials are invalid
+	errInvalidClientCredentials = errors.New("client authentication failed")
+)
+
+// authenticateClient authenticates client with client secret or client assertion (private_key_jwt or client_secret_jwt).
+// Public clients registered with token_endpoint_auth_method none are identified by client id only,
+// rest of the clients should always send valid credentials
+func authenticateClient(gc *gin.Context, client *models.Client, clientSecret, clientAssertion, clientAssertionType string) error {
+	if clientAssertion != "" {
+		if clientAssertionType != constants.ClientAssertionTypeJWTBearer || clientSecret != "" {
+			return errInvalidClientCredentials
+		}
+		if err := token.ValidateClientAssertion(gc, client, clientAssertion); err != nil {
+			return err
+		}
+		return nil
+	}
+	if clientSecret != "" {
+		if !validators.IsValidClientSecret(client, clientSecret) {
+			return errInvalidClientCredentials
+		}
+		return nil
+	}
+	if client.IsPublic() {
+		return nil
+	}
+	return errClientAuthenticationRequired
+}
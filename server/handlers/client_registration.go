@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// ClientMetadata is the client metadata as per RFC 7591
+type ClientMetadata struct {
+	ClientID                string   `json:"client_id"`
+	ClientSecret            string   `json:"client_secret"`
+	ClientName              string   `json:"client_name"`
+	RedirectURIs            []string `json:"redirect_uris"`
+	GrantTypes              []string `json:"grant_types"`
+	Scope                   string   `json:"scope"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+	// JWKS & JWKSURI are the public keys of client used for private_key_jwt client authentication
+	JWKS    json.RawMessage `json:"jwks"`
+	JWKSURI string          `json:"jwks_uri"`
+	// SubjectType & SectorIdentifierURI as per OIDC dynamic client registration spec
+	SubjectType         string `json:"subject_type"`
+	SectorIdentifierURI string `json:"sector_identifier_uri"`
+	// RequirePushedAuthorizationRequests as per RFC 9126
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
+	// RequireSignedRequestObject as per RFC 9101
+	RequireSignedRequestObject bool `json:"require_signed_request_object"`
+	// PostLogoutRedirectURIs, FrontchannelLogoutURI & BackchannelLogoutURI as per OIDC logout specs
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris"`
+	FrontchannelLogoutURI  string   `json:"frontchannel_logout_uri"`
+	BackchannelLogoutURI   string   `json:"backchannel_logout_uri"`
+}
+
+// ClientRegistrationHandler to handle dynamic client registration requests (RFC 7591)
+// POST /oauth/register
+// Registration is disabled unless CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN is set
+func ClientRegistrationHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		if !validators.IsClientRegistrationEnabled() {
+			log.Debug("Dynamic client registration is disabled")
+			gc.JSON(http.StatusForbidden, gin.H{
+				"error":             "access_denied",
+				"error_description": "Dynamic client registration is disabled",
+			})
+			return
+		}
+		initialAccessToken, _ := token.GetAccessToken(gc)
+		if !validators.IsValidClientRegistrationInitialAccessToken(initialAccessToken) {
+			log.Debug("Invalid initial access token")
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_token",
+				"error_description": "The initial access token is invalid",
+			})
+			return
+		}
+
+		var metadata ClientMetadata
+		if err := gc.ShouldBindJSON(&metadata); err != nil {
+			log.Debug("Error binding JSON: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_client_metadata",
+				"error_description": err.Error(),
+			})
+			return
+		}
+
+		client := &models.Client{
+			ClientID: uuid.New().String(),
+		}
+		if !applyClientMetadata(gc, client, &metadata) {
+			return
+		}
+
+		clientSecret := uuid.New().String()
+		hashedClientSecret, err := crypto.EncryptClientSecret(client.TokenEndpointAuthMethod, clientSecret)
+		if err != nil {
+			log.Debug("Error encrypting client secret: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to register client",
+			})
+			return
+		}
+		client.ClientSecret = hashedClientSecret
+
+		registrationAccessToken := uuid.New().String()
+		hashedRegistrationAccessToken, err := crypto.EncryptPassword(registrationAccessToken)
+		if err != nil {
+			log.Debug("Error encrypting registration access token: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to register client",
+			})
+			return
+		}
+		client.RegistrationAccessToken = hashedRegistrationAccessToken
+
+		client, err = db.Provider.AddClient(gc, client)
+		if err != nil {
+			log.Debug("Error adding client: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to register client",
+			})
+			return
+		}
+
+		res := clientInformationResponse(gc, client, registrationAccessToken)
+		// client secret is not issued for clients authenticating with private key jwt and public clients
+		if client.GetTokenEndpointAuthMethod() != constants.TokenEndpointAuthMethodPrivateKeyJWT && !client.IsPublic() {
+			res["client_secret"] = clientSecret
+			res["client_secret_expires_at"] = 0
+		}
+		gc.JSON(http.StatusCreated, res)
+	}
+}
+
+// ClientConfigurationHandler to handle read, update and delete requests
+// for dynamically registered clients (RFC 7592)
+// GET, PUT & DELETE /oauth/register/:client_id
+func ClientConfigurationHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		clientID := gc.Param("client_id")
+		registrationAccessToken, err := token.GetAccessToken(gc)
+		if err != nil {
+			log.Debug("Error getting registration access token: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_token",
+				"error_description": "The registration access token is required",
+			})
+			return
+		}
+
+		// Note: as per RFC 7592, invalid client id should respond with 401
+		// so that the existence of client is not disclosed
+		client, err := db.Provider.GetClientByClientID(gc, clientID)
+		if err != nil || !validators.IsValidClientRegistrationAccessToken(client, registrationAccessToken) {
+			log.Debug("Invalid registration access token for client: ", clientID)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_token",
+				"error_description": "The registration access token is invalid",
+			})
+			return
+		}
+
+		switch gc.Request.Method {
+		case http.MethodGet:
+			gc.JSON(http.StatusOK, clientInformationResponse(gc, client, registrationAccessToken))
+		case http.MethodPut:
+			var metadata ClientMetadata
+			if err := gc.ShouldBindJSON(&metadata); err != nil {
+				log.Debug("Error binding JSON: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_client_metadata",
+					"error_description": err.Error(),
+				})
+				return
+			}
+			if metadata.ClientID != client.ClientID {
+				log.Debug("Client ID mismatch: ", metadata.ClientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_client_metadata",
+					"error_description": "The client_id does not match",
+				})
+				return
+			}
+			if metadata.ClientSecret != "" && !validators.IsMatchingClientSecret(client, metadata.ClientSecret) {
+				log.Debug("Client secret mismatch: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_client_metadata",
+					"error_description": "The client_secret does not match",
+				})
+				return
+			}
+			if !applyClientMetadata(gc, client, &metadata) {
+				return
+			}
+			client, err = db.Provider.UpdateClient(gc, client)
+			if err != nil {
+				log.Debug("Error updating client: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{
+					"error":             "server_error",
+					"error_description": "Failed to update client",
+				})
+				return
+			}
+			gc.JSON(http.StatusOK, clientInformationResponse(gc, client, registrationAccessToken))
+		case http.MethodDelete:
+			if err := db.Provider.DeleteClient(gc, client); err != nil {
+				log.Debug("Error deleting client: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{
+					"error":             "server_error",
+					"error_description": "Failed to delete client",
+				})
+				return
+			}
+			token.InvalidateClientAudience(client.ClientID)
+			gc.Status(http.StatusNoContent)
+		default:
+			gc.JSON(http.StatusMethodNotAllowed, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The method is not allowed",
+			})
+		}
+	}
+}
+
+// applyClientMetadata validates the client metadata and sets it on client.
+// It writes error response and returns false if metadata is invalid
+func applyClientMetadata(gc *gin.Context, client *models.Client, metadata *ClientMetadata) bool {
+	if len(metadata.GrantTypes) == 0 {
+		metadata.GrantTypes = []string{constants.GrantTypeAuthorizationCode}
+	}
+	for _, grantType := range metadata.GrantTypes {
+		if !validators.IsValidGrantType(grantType) {
+			log.Debug("Invalid grant type: ", grantType)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_client_metadata",
+				"error_description": "The grant type " + grantType + " is not supported",
+			})
+			return false
+		}
+	}
+	if metadata.TokenEndpointAuthMethod == "" {
+		metadata.TokenEndpointAuthMethod = constants.TokenEndpointAuthMethodClientSecretBasic
+	}
+	if !validators.IsValidTokenEndpointAuthMethod(metadata.TokenEndpointAuthMethod) {
+		log.Debug("Invalid token endpoint auth method: ", metadata.TokenEndpointAuthMethod)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The token_endpoint_auth_method is not supported",
+		})
+		return false
+	}
+	// secret of client using client_secret_jwt is stored encrypted instead of hashed,
+	// so existing secret cannot be used once client is switched to or from client_secret_jwt
+	if client.ClientSecret != "" && (client.GetTokenEndpointAuthMethod() == constants.TokenEndpointAuthMethodClientSecretJWT) != (metadata.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodClientSecretJWT) {
+		log.Debug("Token endpoint auth method cannot be changed to or from client_secret_jwt")
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The token_endpoint_auth_method cannot be changed to or from client_secret_jwt",
+		})
+		return false
+	}
+	jwks := ""
+	if len(metadata.JWKS) > 0 && string(metadata.JWKS) != "null" {
+		jwks = string(metadata.JWKS)
+	}
+	if (jwks != "" && !validators.IsValidJWKS(jwks)) || (metadata.JWKSURI != "" && !validators.IsValidJWKSURI(metadata.JWKSURI)) || (jwks != "" && metadata.JWKSURI != "") {
+		log.Debug("Invalid jwks or jwks uri")
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The jwks or jwks_uri is invalid",
+		})
+		return false
+	}
+	if metadata.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodPrivateKeyJWT && jwks == "" && metadata.JWKSURI == "" {
+		log.Debug("Jwks or jwks uri is required for private_key_jwt")
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The jwks or jwks_uri is required for private_key_jwt",
+		})
+		return false
+	}
+	if metadata.RequireSignedRequestObject && jwks == "" && metadata.JWKSURI == "" {
+		log.Debug("Jwks or jwks uri is required for signed request object")
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The jwks or jwks_uri is required for signed request object",
+		})
+		return false
+	}
+	if utils.StringSliceContains(metadata.GrantTypes, constants.GrantTypeAuthorizationCode) && len(metadata.RedirectURIs) == 0 {
+		log.Debug("Redirect uris are required for authorization_code grant")
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_redirect_uri",
+			"error_description": "The redirect_uris are required",
+		})
+		return false
+	}
+	for _, redirectURI := range metadata.RedirectURIs {
+		if !validators.IsValidRedirectURI(redirectURI) {
+			log.Debug("Invalid redirect uri: ", redirectURI)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_redirect_uri",
+				"error_description": "The redirect uri " + redirectURI + " is invalid",
+			})
+			return false
+		}
+	}
+
+	logoutURIs := append([]string{}, metadata.PostLogoutRedirectURIs...)
+	if metadata.FrontchannelLogoutURI != "" {
+		logoutURIs = append(logoutURIs, metadata.FrontchannelLogoutURI)
+	}
+	if metadata.BackchannelLogoutURI != "" {
+		logoutURIs = append(logoutURIs, metadata.BackchannelLogoutURI)
+	}
+	for _, logoutURI := range logoutURIs {
+		if !validators.IsValidRedirectURI(logoutURI) {
+			log.Debug("Invalid logout uri: ", logoutURI)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_client_metadata",
+				"error_description": "The logout uri " + logoutURI + " is invalid",
+			})
+			return false
+		}
+	}
+
+	client.Name = strings.TrimSpace(metadata.ClientName)
+	if client.Name == "" {
+		client.Name = client.ClientID
+	}
+	client.RedirectURIs = strings.Join(metadata.RedirectURIs, ",")
+	client.GrantTypes = strings.Join(metadata.GrantTypes, ",")
+	client.Scopes = strings.Join(strings.Fields(metadata.Scope), ",")
+	client.RequirePushedAuthorizationRequests = metadata.RequirePushedAuthorizationRequests
+	client.RequireSignedRequestObject = metadata.RequireSignedRequestObject
+	client.PostLogoutRedirectURIs = strings.Join(metadata.PostLogoutRedirectURIs, ",")
+	client.FrontchannelLogoutURI = metadata.FrontchannelLogoutURI
+	client.BackchannelLogoutURI = metadata.BackchannelLogoutURI
+	client.TokenEndpointAuthMethod = metadata.TokenEndpointAuthMethod
+	client.JWKS = jwks
+	client.JWKSURI = metadata.JWKSURI
+
+	if metadata.SubjectType == "" {
+		metadata.SubjectType = constants.SubjectTypePublic
+	}
+	if !validators.IsValidSubjectType(metadata.SubjectType) {
+		log.Debug("Invalid subject type: ", metadata.SubjectType)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The subject_type is not supported",
+		})
+		return false
+	}
+	client.SubjectType = metadata.SubjectType
+	client.SectorIdentifierURI = metadata.SectorIdentifierURI
+	if !validators.IsValidClientSectorIdentifier(client) {
+		log.Debug("Invalid sector identifier uri: ", metadata.SectorIdentifierURI)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_client_metadata",
+			"error_description": "The sector_identifier_uri is invalid or redirect uris do not share the same host",
+		})
+		return false
+	}
+	return true
+}
+
+// clientInformationResponse returns the client information response as per RFC 7591 & RFC 7592
+func clientInformationResponse(gc *gin.Context, client *models.Client, registrationAccessToken string) gin.H {
+	responseTypes := []string{}
+	if utils.StringSliceContains(client.GetGrantTypes(), constants.GrantTypeAuthorizationCode) {
+		responseTypes = append(responseTypes, "code")
+	}
+	createdAt := client.CreatedAt
+	if createdAt == 0 {
+		createdAt = time.Now().Unix()
+	}
+	res := gin.H{
+		"client_id":                             client.ClientID,
+		"client_id_issued_at":                   createdAt,
+		"client_name":                           client.Name,
+		"redirect_uris":                         client.GetRedirectURIs(),
+		"grant_types":                           client.GetGrantTypes(),
+		"response_types":                        responseTypes,
+		"scope":                                 strings.Join(client.GetScopes(), " "),
+		"token_endpoint_auth_method":            client.GetTokenEndpointAuthMethod(),
+		"require_pushed_authorization_requests": client.RequirePushedAuthorizationRequests,
+		"require_signed_request_object":         client.RequireSignedRequestObject,
+		"post_logout_redirect_uris":             client.GetPostLogoutRedirectURIs(),
+		"frontchannel_logout_uri":               client.FrontchannelLogoutURI,
+		"backchannel_logout_uri":                client.BackchannelLogoutURI,
+		"registration_access_token":             registrationAccessToken,
+		"registration_client_uri":               parsers.GetHost(gc) + "/oauth/register/" + client.ClientID,
+	}
+	if client.JWKS != "" {
+		res["jwks"] = json.RawMessage(client.JWKS)
+	}
+	if client.JWKSURI != "" {
+		res["jwks_uri"] = client.JWKSURI
+	}
+	res["subject_type"] = client.GetSubjectType()
+	if client.SectorIdentifierURI != "" {
+		res["sector_identifier_uri"] = client.SectorIdentifierURI
+	}
+	return res
+}
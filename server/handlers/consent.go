@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/cookie"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// ConsentHandler to handle the response of user on consent page
+// POST /oauth/consent
+// consent_challenge = id of consent request rendered by /authorize
+// action = allow / deny
+// On allow, scopes are saved as oauth grant and authorization request is resumed,
+// on deny, access_denied error is sent to redirect uri
+func ConsentHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		consentChallenge := strings.TrimSpace(gc.Request.FormValue("consent_challenge"))
+		action := strings.TrimSpace(gc.Request.FormValue("action"))
+		if action != "allow" && action != "deny" {
+			log.Debug("Invalid consent action: ", action)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The action must be allow or deny",
+			})
+			return
+		}
+
+		consentRequest, err := token.GetConsentRequest(consentChallenge)
+		if err != nil {
+			log.Debug("Invalid consent challenge: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The consent_challenge is invalid or has expired",
+			})
+			return
+		}
+
+		// consent can only be given by the user for whom consent page was rendered,
+		// consent challenge is consumed only after session is verified
+		sessionToken, err := cookie.GetSession(gc)
+		if err != nil {
+			log.Debug("GetSession failed: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "login_required",
+				"error_description": "Login is required",
+			})
+			return
+		}
+		claims, err := token.ValidateBrowserSession(gc, sessionToken)
+		if err != nil || claims.Subject != consentRequest.UserID {
+			log.Debug("Invalid session for consent request: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "login_required",
+				"error_description": "Login is required",
+			})
+			return
+		}
+		if _, err := token.ConsumeConsentRequest(consentChallenge); err != nil {
+			log.Debug("Failed to consume consent challenge: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The consent_challenge is invalid or has expired",
+			})
+			return
+		}
+
+		if action == "deny" {
+			handleAuthorizeError(gc, consentRequest.ResponseMode, consentRequest.RedirectURI, consentRequest.ClientID, consentRequest.State, "access_denied", "User denied the request")
+			return
+		}
+
+		oauthGrant, err := db.Provider.GetOAuthGrant(gc, consentRequest.UserID, consentRequest.ClientID)
+		if err != nil || oauthGrant == nil {
+			_, err = db.Provider.AddOAuthGrant(gc, &models.OAuthGrant{
+				UserID:   consentRequest.UserID,
+				ClientID: consentRequest.ClientID,
+				Scopes:   strings.Join(consentRequest.Scope, ","),
+			})
+		} else {
+			// scopes granted earlier are retained
+			scopes := oauthGrant.GetScopes()
+			for _, scope := range consentRequest.Scope {
+				if !utils.StringSliceContains(scopes, scope) {
+					scopes = append(scopes, scope)
+				}
+			}
+			oauthGrant.Scopes = strings.Join(scopes, ",")
+			_, err = db.Provider.UpdateOAuthGrant(gc, oauthGrant)
+		}
+		if err != nil {
+			log.Debug("Failed to save oauth grant: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to save consent",
+			})
+			return
+		}
+
+		authorizationRequest, err := token.SetPushedAuthorizationRequest(consentRequest.ClientID, consentRequest.Params)
+		if err != nil {
+			log.Debug("Failed to save authorization request: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to save authorization request",
+			})
+			return
+		}
+		gc.Redirect(http.StatusFound, "/authorize?client_id="+url.QueryEscape(consentRequest.ClientID)+"&request_uri="+url.QueryEscape(authorizationRequest.RequestURI))
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// DeviceCodeRequestBody is the request body for device authorization request
+type DeviceCodeRequestBody struct {
+	ClientID     string `form:"client_id" json:"client_id"`
+	ClientSecret string `form:"client_secret" json:"client_secret"`
+	Scope        string `form:"scope" json:"scope"`
+	// client assertion params for private_key_jwt client authentication as per RFC 7523
+	ClientAssertion     string `form:"client_assertion" json:"client_assertion"`
+	ClientAssertionType string `form:"client_assertion_type" json:"client_assertion_type"`
+}
+
+// DeviceCodeHandler to handle device authorization requests (RFC 8628)
+// POST /oauth/device/code
+func DeviceCodeHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		var reqBody DeviceCodeRequestBody
+		if err := gc.Bind(&reqBody); err != nil {
+			log.Debug("Error binding request: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": err.Error(),
+			})
+			return
+		}
+
+		clientID := strings.TrimSpace(reqBody.ClientID)
+		clientSecret := strings.TrimSpace(reqBody.ClientSecret)
+		// check if clientID & clientSecret are present as part of
+		// authorization header with basic auth
+		if clientID == "" && clientSecret == "" {
+			clientID, clientSecret, _ = gc.Request.BasicAuth()
+		}
+		// client_id is optional with client assertion as client is identified by sub claim
+		if clientID == "" && reqBody.ClientAssertion != "" {
+			clientID, _ = token.GetClientAssertionSubject(strings.TrimSpace(reqBody.ClientAssertion))
+		}
+		if clientID == "" {
+			log.Debug("Client ID is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "client_id_required",
+				"error_description": "The client id is required",
+			})
+			return
+		}
+		client, err := utils.GetClientByClientID(gc, clientID)
+		if err != nil || client == nil {
+			log.Debug("Client ID is invalid: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client id is invalid",
+			})
+			return
+		}
+		// device clients are usually public clients,
+		// confidential clients should authenticate as they would at token endpoint
+		if err := authenticateClient(gc, client, clientSecret, strings.TrimSpace(reqBody.ClientAssertion), reqBody.ClientAssertionType); err != nil {
+			log.Debug("Client authentication failed: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client authentication failed",
+			})
+			return
+		}
+		if !validators.IsValidClientGrantType(client, constants.GrantTypeDeviceCode) {
+			log.Debug("Device code grant not allowed for client: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "unauthorized_client",
+				"error_description": "The grant type is not allowed for client",
+			})
+			return
+		}
+
+		scope := strings.Fields(reqBody.Scope)
+		if len(scope) == 0 {
+			scope = []string{"openid", "profile", "email"}
+		}
+		if !validators.IsValidClientScope(client, scope) {
+			log.Debug("Scope not allowed for client: ", scope)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_scope",
+				"error_description": "The requested scope is not allowed for client",
+			})
+			return
+		}
+
+		userCode, err := token.NewUserCode()
+		if err != nil {
+			log.Debug("Error generating user code: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to generate user code",
+			})
+			return
+		}
+		deviceAuthorization := &token.DeviceAuthorization{
+			DeviceCode: uuid.New().String(),
+			UserCode:   userCode,
+			ClientID:   client.ClientID,
+			Scope:      scope,
+			Status:     token.DeviceAuthorizationStatusPending,
+			Interval:   token.DeviceCodePollingInterval,
+			ExpiresAt:  time.Now().Unix() + token.DeviceCodeExpiresIn,
+		}
+		if err := token.SetDeviceAuthorization(deviceAuthorization); err != nil {
+			log.Debug("Error saving device authorization: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to save device authorization",
+			})
+			return
+		}
+
+		verificationURI := parsers.GetHost(gc) + "/app/device"
+		gc.JSON(http.StatusOK, gin.H{
+			"device_code":               deviceAuthorization.DeviceCode,
+			"user_code":                 deviceAuthorization.UserCode,
+			"verification_uri":          verificationURI,
+			"verification_uri_complete": verificationURI + "?user_code=" + url.QueryEscape(deviceAuthorization.UserCode),
+			"expires_in":                token.DeviceCodeExpiresIn,
+			"interval":                  deviceAuthorization.Interval,
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/cookie"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+const (
+	endSessionTemplate        = "end_session.tmpl"
+	endSessionConfirmTemplate = "end_session_confirm.tmpl"
+)
+
+// EndSessionHandler to handle OIDC RP-initiated logout requests
+// GET/POST /oauth/logout
+// id_token_hint = id token previously issued to client, used to identify the user & client
+// post_logout_redirect_uri = uri registered for client, where user is redirected after logout
+// state = opaque value passed back to post_logout_redirect_uri
+// client_id = required when post_logout_redirect_uri is sent without id_token_hint
+// logout_challenge = id of logout request rendered by confirmation page.
+// Logout request without id_token_hint can be sent by any site, hence user is asked to confirm it
+func EndSessionHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		idTokenHint := strings.TrimSpace(gc.Request.FormValue("id_token_hint"))
+		postLogoutRedirectURI := strings.TrimSpace(gc.Request.FormValue("post_logout_redirect_uri"))
+		state := strings.TrimSpace(gc.Request.FormValue("state"))
+		clientID := strings.TrimSpace(gc.Request.FormValue("client_id"))
+		logoutChallenge := strings.TrimSpace(gc.Request.PostFormValue("logout_challenge"))
+		hostname := parsers.GetHost(gc)
+
+		userID := ""
+		sessionID := ""
+		loginMethod := ""
+		// nonce is set when session is identified by browser session
+		nonce := ""
+		if idTokenHint != "" {
+			claims, err := token.ParseIDTokenHint(gc, idTokenHint)
+			if err != nil {
+				log.Debug("Invalid id_token_hint: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The id_token_hint is invalid",
+				})
+				return
+			}
+			aud, _ := claims["aud"].(string)
+			if clientID != "" && clientID != aud {
+				log.Debug("client_id does not match id_token_hint: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The client_id does not match id_token_hint",
+				})
+				return
+			}
+			clientID = aud
+			subject, _ := claims["sub"].(string)
+			userID = token.GetUserIDFromSubject(subject)
+			sessionID, _ = claims["sid"].(string)
+			loginMethod, _ = claims["login_method"].(string)
+		}
+
+		if idTokenHint == "" && logoutChallenge != "" {
+			logoutRequest, err := token.ConsumeLogoutRequest(logoutChallenge)
+			if err != nil {
+				log.Debug("Invalid logout challenge: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The logout_challenge is invalid or has expired",
+				})
+				return
+			}
+			// post logout redirect uri is validated before logout request is saved
+			postLogoutRedirectURI = logoutRequest.PostLogoutRedirectURI
+		} else if postLogoutRedirectURI != "" {
+			client, err := utils.GetClientByClientID(gc, clientID)
+			if err != nil || client == nil {
+				log.Debug("Invalid client_id for post logout redirect uri: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The client_id or id_token_hint is required with post_logout_redirect_uri",
+				})
+				return
+			}
+			if !validators.IsValidClientPostLogoutRedirectURI(client, postLogoutRedirectURI) {
+				log.Debug("Invalid post logout redirect uri: ", postLogoutRedirectURI)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The post_logout_redirect_uri is invalid",
+				})
+				return
+			}
+			if state != "" {
+				if strings.Contains(postLogoutRedirectURI, "?") {
+					postLogoutRedirectURI += "&state=" + url.QueryEscape(state)
+				} else {
+					postLogoutRedirectURI += "?state=" + url.QueryEscape(state)
+				}
+			}
+		}
+
+		if idTokenHint == "" && logoutChallenge == "" {
+			logoutRequest, err := token.SetLogoutRequest(postLogoutRedirectURI)
+			if err != nil {
+				log.Debug("Failed to save logout request: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{
+					"error":             "server_error",
+					"error_description": "Failed to save logout request",
+				})
+				return
+			}
+			gc.HTML(http.StatusOK, endSessionConfirmTemplate, gin.H{
+				"logout_challenge": logoutRequest.ID,
+			})
+			return
+		}
+
+		// browser session takes precedence over id_token_hint
+		// as it identifies the session which is being terminated
+		if fingerprintHash, err := cookie.GetSession(gc); err == nil {
+			var sessionData token.SessionData
+			decryptedFingerPrint, err := crypto.DecryptAES(fingerprintHash)
+			if err == nil {
+				err = json.Unmarshal([]byte(decryptedFingerPrint), &sessionData)
+			}
+			if err != nil {
+				log.Debug("Failed to decrypt session: ", err)
+			} else if userID != "" && userID != sessionData.Subject {
+				log.Debug("id_token_hint does not match logged in user")
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The id_token_hint does not match the logged in user",
+				})
+				return
+			} else {
+				userID = sessionData.Subject
+				sessionID = sessionData.GetSessionID()
+				loginMethod = sessionData.LoginMethod
+				nonce = sessionData.Nonce
+			}
+		}
+
+		frontchannelLogoutURIs := []string{}
+		if userID != "" && sessionID != "" {
+			sessionKey := userID
+			if loginMethod != "" {
+				sessionKey = loginMethod + ":" + userID
+			}
+			if nonce != "" {
+				memorystore.Provider.DeleteUserSession(sessionKey, nonce)
+			}
+			// only the clients which participated in session are notified
+			clients := token.EndSession(gc, sessionKey, sessionID)
+			go token.NotifyBackchannelLogout(hostname, userID, sessionID, clients)
+			frontchannelLogoutURIs = token.GetFrontchannelLogoutURIs(hostname, sessionID, clients)
+		}
+		cookie.DeleteSession(gc)
+
+		if len(frontchannelLogoutURIs) == 0 {
+			if postLogoutRedirectURI != "" {
+				gc.Redirect(http.StatusFound, postLogoutRedirectURI)
+				return
+			}
+			gc.JSON(http.StatusOK, gin.H{
+				"message": "Logged out successfully",
+			})
+			return
+		}
+
+		gc.HTML(http.StatusOK, endSessionTemplate, gin.H{
+			"frontchannel_logout_uris": frontchannelLogoutURIs,
+			"redirect_uri":             postLogoutRedirectURI,
+		})
+	}
+}
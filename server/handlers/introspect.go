@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IntrospectRequestBody is the request body for token introspection
+type IntrospectRequestBody struct {
+	Token         string `form:"token" json:"token"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+	ClientID      string `form:"client_id" json:"client_id"`
+	ClientSecret  string `form:"client_secret" json:"client_secret"`
+	// client assertion params for private_key_jwt client authentication as per RFC 7523
+	ClientAssertion     string `form:"client_assertion" json:"client_assertion"`
+	ClientAssertionType string `form:"client_assertion_type" json:"client_assertion_type"`
+}
+
+// IntrospectHandler to handle token introspection requests (RFC 7662)
+// POST /oauth/introspect
+func IntrospectHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		var reqBody IntrospectRequestBody
+		if err := gc.Bind(&reqBody); err != nil {
+			log.Debug("Error binding request: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": err.Error(),
+			})
+			return
+		}
+
+		clientID := strings.TrimSpace(reqBody.ClientID)
+		clientSecret := strings.TrimSpace(reqBody.ClientSecret)
+		// check if clientID & clientSecret are present as part of
+		// authorization header with basic auth
+		if clientID == "" && clientSecret == "" {
+			clientID, clientSecret, _ = gc.Request.BasicAuth()
+		}
+		// client_id is optional with client assertion as client is identified by sub claim
+		clientAssertion := strings.TrimSpace(reqBody.ClientAssertion)
+		if clientID == "" && clientAssertion != "" {
+			clientID, _ = token.GetClientAssertionSubject(clientAssertion)
+		}
+		// public clients cannot introspect tokens, as they cannot authenticate
+		client, err := utils.GetClientByClientID(gc, clientID)
+		if clientID == "" || err != nil || client == nil || client.IsPublic() || authenticateClient(gc, client, clientSecret, clientAssertion, reqBody.ClientAssertionType) != nil {
+			log.Debug("Invalid client credentials: ", clientID)
+			gc.Header("WWW-Authenticate", `Basic realm="authorizer"`)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client authentication failed",
+			})
+			return
+		}
+
+		tokenString := strings.TrimSpace(reqBody.Token)
+		if tokenString == "" {
+			log.Debug("Token is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The token is required",
+			})
+			return
+		}
+
+		// token_type_hint only decides the order of lookup,
+		// as per RFC 7662 other token types should be checked as well
+		tokenValidators := []func(*gin.Context, string) (map[string]interface{}, error){
+			token.ValidateAccessTokenWithoutDPoPProof,
+			token.ValidateClientAccessToken,
+			token.ValidateRefreshToken,
+		}
+		if reqBody.TokenTypeHint == constants.TokenTypeRefreshToken {
+			tokenValidators = []func(*gin.Context, string) (map[string]interface{}, error){
+				token.ValidateRefreshToken,
+				token.ValidateAccessTokenWithoutDPoPProof,
+				token.ValidateClientAccessToken,
+			}
+		}
+
+		for _, validate := range tokenValidators {
+			claims, err := validate(gc, tokenString)
+			if err != nil {
+				continue
+			}
+			if _, ok := claims["sub"]; ok {
+				claims["sub"] = token.GetTokenSubject(gc, claims)
+			}
+			gc.JSON(http.StatusOK, introspectionResponse(claims))
+			return
+		}
+
+		log.Debug("Token is not active")
+		gc.JSON(http.StatusOK, gin.H{
+			"active": false,
+		})
+	}
+}
+
+// introspectionResponse returns the introspection response for active token claims
+func introspectionResponse(claims map[string]interface{}) gin.H {
+	res := gin.H{
+		"active":     true,
+		"token_type": claims["token_type"],
+		"exp":        claims["exp"],
+		"iat":        claims["iat"],
+		"iss":        claims["iss"],
+		"aud":        claims["aud"],
+		"client_id":  claims["aud"],
+	}
+	if clientID, ok := claims["client_id"]; ok {
+		res["client_id"] = clientID
+	}
+	if sub, ok := claims["sub"]; ok {
+		res["sub"] = sub
+	}
+	// cnf claim is set for tokens bound to DPoP key
+	if cnf, ok := claims["cnf"]; ok {
+		res["cnf"] = cnf
+	}
+	// act claim is set for tokens issued via token exchange on behalf of user
+	if act, ok := claims["act"]; ok {
+		res["act"] = act
+	}
+	scopes := []string{}
+	if scopeList, ok := claims["scope"].([]interface{}); ok {
+		for _, s := range scopeList {
+			if scope, ok := s.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	res["scope"] = strings.Join(scopes, " ")
+	return res
+}
@@ -7,11 +7,46 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 )
 
+// JWKsHandler publishes the keys of keyring which can be used for verifying tokens.
+// It falls back to JWK generated from env, if keyring is not initialized.
+// Symmetric keys are never published, as JWK of HMAC key contains the secret
 func JWKsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		jwks, err := crypto.GetJWKs()
+		if err != nil {
+			log.Debug("Error getting JWKs from keyring: ", err)
+			c.JSON(500, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if len(jwks) > 0 {
+			c.JSON(200, gin.H{
+				"keys": jwks,
+			})
+			return
+		}
+
+		keys := []map[string]string{}
+		jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+		if err != nil {
+			log.Debug("Error getting JWT type from memorystore: ", err)
+			c.JSON(500, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if crypto.IsHMACA(jwtType) {
+			c.JSON(200, gin.H{
+				"keys": keys,
+			})
+			return
+		}
+
 		var data map[string]string
 		jwk, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJWK)
 		if err != nil {
@@ -29,10 +64,11 @@ func JWKsHandler() gin.HandlerFunc {
 			})
 			return
 		}
+		if data["kty"] != "oct" {
+			keys = append(keys, data)
+		}
 		c.JSON(200, gin.H{
-			"keys": []map[string]string{
-				data,
-			},
+			"keys": keys,
 		})
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/authorizerdev/authorizer/server/cookie"
 	"github.com/authorizerdev/authorizer/server/crypto"
 	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
 	"github.com/authorizerdev/authorizer/server/token"
 )
 
@@ -56,6 +57,9 @@ func LogoutHandler() gin.HandlerFunc {
 
 		memorystore.Provider.DeleteUserSession(sessionToken, sessionData.Nonce)
 		cookie.DeleteSession(gc)
+		// clients which participated in session are notified
+		clients := token.EndSession(gc, sessionToken, sessionData.GetSessionID())
+		go token.NotifyBackchannelLogout(parsers.GetHost(gc), userID, sessionData.GetSessionID(), clients)
 
 		if redirectURL != "" {
 			gc.Redirect(http.StatusFound, redirectURL)
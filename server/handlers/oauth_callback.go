@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -24,6 +25,7 @@ import (
 	"github.com/authorizerdev/authorizer/server/db/models"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/oauth"
+	"github.com/authorizerdev/authorizer/server/parsers"
 	"github.com/authorizerdev/authorizer/server/refs"
 	"github.com/authorizerdev/authorizer/server/token"
 	"github.com/authorizerdev/authorizer/server/utils"
@@ -72,6 +74,7 @@ func OAuthCallbackHandler() gin.HandlerFunc {
 			}
 		}
 		var user *models.User
+		var identity *models.Identity
 		oauthCode := ctx.Request.FormValue("code")
 		if oauthCode == "" {
 			log.Debug("Invalid oauth code: ", oauthCode)
@@ -80,31 +83,34 @@ func OAuthCallbackHandler() gin.HandlerFunc {
 		}
 		switch provider {
 		case constants.AuthRecipeMethodGoogle:
-			user, err = processGoogleUserInfo(ctx, oauthCode)
+			user, identity, err = processGoogleUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodGithub:
-			user, err = processGithubUserInfo(ctx, oauthCode)
+			user, identity, err = processGithubUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodFacebook:
-			user, err = processFacebookUserInfo(ctx, oauthCode)
+			user, identity, err = processFacebookUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodLinkedIn:
-			user, err = processLinkedInUserInfo(ctx, oauthCode)
+			user, identity, err = processLinkedInUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodApple:
 			user_ := AppleUserInfo{}
 			userRaw := ctx.Request.FormValue("user")
 			err = json.Unmarshal([]byte(userRaw), &user_)
-			user, err = processAppleUserInfo(ctx, oauthCode, &user_)
+			user, identity, err = processAppleUserInfo(ctx, oauthCode, &user_)
 		case constants.AuthRecipeMethodDiscord:
-			user, err = processDiscordUserInfo(ctx, oauthCode)
+			user, identity, err = processDiscordUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodTwitter:
-			user, err = processTwitterUserInfo(ctx, oauthCode, sessionState)
+			user, identity, err = processTwitterUserInfo(ctx, oauthCode, sessionState)
 		case constants.AuthRecipeMethodMicrosoft:
-			user, err = processMicrosoftUserInfo(ctx, oauthCode)
+			user, identity, err = processMicrosoftUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodTwitch:
-			user, err = processTwitchUserInfo(ctx, oauthCode)
+			user, identity, err = processTwitchUserInfo(ctx, oauthCode)
 		case constants.AuthRecipeMethodRoblox:
-			user, err = processRobloxUserInfo(ctx, oauthCode, sessionState)
+			user, identity, err = processRobloxUserInfo(ctx, oauthCode, sessionState)
 		default:
-			log.Info("Invalid oauth provider")
-			err = fmt.Errorf(`invalid oauth provider`)
+			user, identity, err = processIdentityProviderUserInfo(ctx, provider, oauthCode, sessionState)
+			// roles mapped from claims of identity provider are used instead of requested roles
+			if err == nil && user.Roles != "" {
+				inputRoles = strings.Split(user.Roles, ",")
+			}
 		}
 
 		if err != nil {
@@ -120,27 +126,145 @@ func OAuthCallbackHandler() gin.HandlerFunc {
 			)
 			return
 		}
-		existingUser, err := db.Provider.GetUserByEmail(ctx, refs.StringValue(user.Email))
-		log := log.WithField("user", user.Email)
-		isSignUp := false
+		// identity is linked to logged in user when oauth_login is initiated with link token
+		if len(sessionSplit) > 4 {
+			processUpstreamIdentityLink(ctx, sessionSplit[4], provider, identity, redirectURL)
+			return
+		}
+		processUpstreamUserLogin(ctx, provider, user, identity, inputRoles, scopes, stateValue, redirectURL, isUpstreamEmailVerified(provider, identity))
+	}
+}
+
+// isUpstreamEmailVerified returns true when built-in provider guarantees that email of user is verified.
+// Existing user is matched by email only in such case, otherwise identity should be linked explicitly
+func isUpstreamEmailVerified(provider string, identity *models.Identity) bool {
+	if identity == nil || identity.Email == "" {
+		return false
+	}
+	switch provider {
+	case constants.AuthRecipeMethodGithub:
+		// email of github user is picked only from verified primary email
+		return true
+	case constants.AuthRecipeMethodGoogle, constants.AuthRecipeMethodApple, constants.AuthRecipeMethodTwitch:
+		claims := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(identity.RawProfile), &claims); err != nil {
+			return false
+		}
+		switch emailVerified := claims["email_verified"].(type) {
+		case bool:
+			return emailVerified
+		case string:
+			return strings.EqualFold(emailVerified, "true")
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+}
+
+// processUpstreamUserLogin signs up or logs in the user authenticated by upstream provider,
+// creates the session & tokens and redirects to redirect url with state & code of authorize request.
+// User is looked up by identity of provider first & then by email, identity is saved once user is signed up or logged in.
+// When isEmailMatchAllowed is false existing user is not matched by email, user should link the identity explicitly.
+// Roles are only assigned on signup or when they are not protected roles
+func processUpstreamUserLogin(ctx *gin.Context, provider string, user *models.User, identity *models.Identity, inputRoles, scopes []string, stateValue, redirectURL string, isEmailMatchAllowed bool) {
+	email := refs.StringValue(user.Email)
+	if !isEmailMatchAllowed {
+		email = ""
+	}
+	existingUser, err := getUpstreamUser(ctx, identity, email)
+	log := log.WithField("user", user.Email)
+	isSignUp := false
+
+	if err != nil && !isEmailMatchAllowed {
+		if userWithEmail, err := db.Provider.GetUserByEmail(ctx, refs.StringValue(user.Email)); err == nil && userWithEmail != nil {
+			log.Debug("User with email exists, identity should be linked explicitly")
+			ctx.JSON(400, gin.H{"error": "user with this email already exists, login and link " + provider + " identity to your account"})
+			return
+		}
+	}
 
+	if err != nil {
+		isSignupDisabled, err := memorystore.Provider.GetBoolStoreEnvVariable(constants.EnvKeyDisableSignUp)
 		if err != nil {
-			isSignupDisabled, err := memorystore.Provider.GetBoolStoreEnvVariable(constants.EnvKeyDisableSignUp)
+			log.Debug("Failed to get signup disabled env variable: ", err)
+			ctx.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if isSignupDisabled {
+			log.Debug("Failed to signup as disabled")
+			ctx.JSON(400, gin.H{"error": "signup is disabled for this instance"})
+			return
+		}
+		// user not registered, register user and generate session token
+		user.SignupMethods = provider
+		// make sure inputRoles don't include protected roles
+		hasProtectedRole := false
+		for _, ir := range inputRoles {
+			protectedRolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyProtectedRoles)
+			protectedRoles := []string{}
 			if err != nil {
-				log.Debug("Failed to get signup disabled env variable: ", err)
-				ctx.JSON(400, gin.H{"error": err.Error()})
-				return
+				log.Debug("Failed to get protected roles: ", err)
+				protectedRolesString = ""
+			} else {
+				protectedRoles = strings.Split(protectedRolesString, ",")
 			}
-			if isSignupDisabled {
-				log.Debug("Failed to signup as disabled")
-				ctx.JSON(400, gin.H{"error": "signup is disabled for this instance"})
-				return
+			if utils.StringSliceContains(protectedRoles, ir) {
+				hasProtectedRole = true
+			}
+		}
+
+		if hasProtectedRole {
+			log.Debug("Signup is not allowed with protected roles:", inputRoles)
+			ctx.JSON(400, gin.H{"error": "invalid role"})
+			return
+		}
+
+		user.Roles = strings.Join(inputRoles, ",")
+		now := time.Now().Unix()
+		user.EmailVerifiedAt = &now
+		user, _ = db.Provider.AddUser(ctx, user)
+		isSignUp = true
+	} else {
+		user = existingUser
+		if user.RevokedTimestamp != nil {
+			log.Debug("User access revoked at: ", user.RevokedTimestamp)
+			ctx.JSON(400, gin.H{"error": "user access has been revoked"})
+			return
+		}
+
+		// user exists in db, check if method was google
+		// if not append google to existing signup method and save it
+		signupMethod := existingUser.SignupMethods
+		if !strings.Contains(signupMethod, provider) {
+			signupMethod = signupMethod + "," + provider
+		}
+		user.SignupMethods = signupMethod
+
+		if user.EmailVerifiedAt == nil {
+			now := time.Now().Unix()
+			user.EmailVerifiedAt = &now
+		}
+
+		// There multiple scenarios with roles here in social login
+		// 1. user has access to protected roles + roles and trying to login
+		// 2. user has not signed up for one of the available role but trying to signup.
+		// 		Need to modify roles in this case
+
+		// find the unassigned roles
+		existingRoles := strings.Split(existingUser.Roles, ",")
+		unasignedRoles := []string{}
+		for _, ir := range inputRoles {
+			if !utils.StringSliceContains(existingRoles, ir) {
+				unasignedRoles = append(unasignedRoles, ir)
 			}
-			// user not registered, register user and generate session token
-			user.SignupMethods = provider
-			// make sure inputRoles don't include protected roles
+		}
+
+		if len(unasignedRoles) > 0 {
+			// check if it contains protected unassigned role
 			hasProtectedRole := false
-			for _, ir := range inputRoles {
+			for _, ur := range unasignedRoles {
 				protectedRolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyProtectedRoles)
 				protectedRoles := []string{}
 				if err != nil {
@@ -149,181 +273,232 @@ func OAuthCallbackHandler() gin.HandlerFunc {
 				} else {
 					protectedRoles = strings.Split(protectedRolesString, ",")
 				}
-				if utils.StringSliceContains(protectedRoles, ir) {
+				if utils.StringSliceContains(protectedRoles, ur) {
 					hasProtectedRole = true
 				}
 			}
 
 			if hasProtectedRole {
-				log.Debug("Signup is not allowed with protected roles:", inputRoles)
+				log.Debug("Invalid role. User is using protected unassigned role")
 				ctx.JSON(400, gin.H{"error": "invalid role"})
 				return
+			} else {
+				user.Roles = existingUser.Roles + "," + strings.Join(unasignedRoles, ",")
 			}
-
-			user.Roles = strings.Join(inputRoles, ",")
-			now := time.Now().Unix()
-			user.EmailVerifiedAt = &now
-			user, _ = db.Provider.AddUser(ctx, user)
-			isSignUp = true
 		} else {
-			user = existingUser
-			if user.RevokedTimestamp != nil {
-				log.Debug("User access revoked at: ", user.RevokedTimestamp)
-				ctx.JSON(400, gin.H{"error": "user access has been revoked"})
-				return
-			}
-
-			// user exists in db, check if method was google
-			// if not append google to existing signup method and save it
-			signupMethod := existingUser.SignupMethods
-			if !strings.Contains(signupMethod, provider) {
-				signupMethod = signupMethod + "," + provider
-			}
-			user.SignupMethods = signupMethod
-
-			if user.EmailVerifiedAt == nil {
-				now := time.Now().Unix()
-				user.EmailVerifiedAt = &now
-			}
+			user.Roles = existingUser.Roles
+		}
 
-			// There multiple scenarios with roles here in social login
-			// 1. user has access to protected roles + roles and trying to login
-			// 2. user has not signed up for one of the available role but trying to signup.
-			// 		Need to modify roles in this case
-
-			// find the unassigned roles
-			existingRoles := strings.Split(existingUser.Roles, ",")
-			unasignedRoles := []string{}
-			for _, ir := range inputRoles {
-				if !utils.StringSliceContains(existingRoles, ir) {
-					unasignedRoles = append(unasignedRoles, ir)
-				}
-			}
+		user, err = db.Provider.UpdateUser(ctx, user)
+		if err != nil {
+			log.Debug("Failed to update user: ", err)
+			ctx.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
-			if len(unasignedRoles) > 0 {
-				// check if it contains protected unassigned role
-				hasProtectedRole := false
-				for _, ur := range unasignedRoles {
-					protectedRolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyProtectedRoles)
-					protectedRoles := []string{}
-					if err != nil {
-						log.Debug("Failed to get protected roles: ", err)
-						protectedRolesString = ""
-					} else {
-						protectedRoles = strings.Split(protectedRolesString, ",")
-					}
-					if utils.StringSliceContains(protectedRoles, ur) {
-						hasProtectedRole = true
-					}
-				}
+	if identity != nil {
+		if err := saveUpstreamIdentity(ctx, user.ID, identity); err != nil {
+			log.Debug("Failed to save identity: ", err)
+		}
+	}
 
-				if hasProtectedRole {
-					log.Debug("Invalid role. User is using protected unassigned role")
-					ctx.JSON(400, gin.H{"error": "invalid role"})
-					return
-				} else {
-					user.Roles = existingUser.Roles + "," + strings.Join(unasignedRoles, ",")
-				}
+	// TODO
+	// use stateValue to get code / nonce
+	// add code / nonce to id_token
+	code := ""
+	codeChallenge := ""
+	nonce := ""
+	if stateValue != "" {
+		// Get state from store
+		authorizeState, _ := memorystore.Provider.GetState(stateValue)
+		if authorizeState != "" {
+			authorizeStateSplit := strings.Split(authorizeState, "@@")
+			if len(authorizeStateSplit) > 1 {
+				code = authorizeStateSplit[0]
+				codeChallenge = authorizeStateSplit[1]
 			} else {
-				user.Roles = existingUser.Roles
-			}
-
-			user, err = db.Provider.UpdateUser(ctx, user)
-			if err != nil {
-				log.Debug("Failed to update user: ", err)
-				ctx.JSON(500, gin.H{"error": err.Error()})
-				return
+				nonce = authorizeState
 			}
+			go memorystore.Provider.RemoveState(stateValue)
 		}
+	}
+	if nonce == "" {
+		nonce = uuid.New().String()
+	}
+	authToken, err := token.CreateAuthToken(ctx, user, inputRoles, scopes, provider, nonce, code)
+	if err != nil {
+		log.Debug("Failed to create auth token: ", err)
+		ctx.JSON(500, gin.H{"error": err.Error()})
+	}
 
-		// TODO
-		// use stateValue to get code / nonce
-		// add code / nonce to id_token
-		code := ""
-		codeChallenge := ""
-		nonce := ""
-		if stateValue != "" {
-			// Get state from store
-			authorizeState, _ := memorystore.Provider.GetState(stateValue)
-			if authorizeState != "" {
-				authorizeStateSplit := strings.Split(authorizeState, "@@")
-				if len(authorizeStateSplit) > 1 {
-					code = authorizeStateSplit[0]
-					codeChallenge = authorizeStateSplit[1]
-				} else {
-					nonce = authorizeState
-				}
-				go memorystore.Provider.RemoveState(stateValue)
-			}
-		}
-		if nonce == "" {
-			nonce = uuid.New().String()
-		}
-		authToken, err := token.CreateAuthToken(ctx, user, inputRoles, scopes, provider, nonce, code)
-		if err != nil {
-			log.Debug("Failed to create auth token: ", err)
+	// Code challenge could be optional if PKCE flow is not used
+	if code != "" {
+		if err := memorystore.Provider.SetState(code, codeChallenge+"@@"+authToken.FingerPrintHash); err != nil {
+			log.Debug("SetState failed: ", err)
 			ctx.JSON(500, gin.H{"error": err.Error()})
 		}
+	}
 
-		// Code challenge could be optional if PKCE flow is not used
-		if code != "" {
-			if err := memorystore.Provider.SetState(code, codeChallenge+"@@"+authToken.FingerPrintHash); err != nil {
-				log.Debug("SetState failed: ", err)
-				ctx.JSON(500, gin.H{"error": err.Error()})
-			}
-		}
+	expiresIn := authToken.AccessToken.ExpiresAt - time.Now().Unix()
+	if expiresIn <= 0 {
+		expiresIn = 1
+	}
 
-		expiresIn := authToken.AccessToken.ExpiresAt - time.Now().Unix()
-		if expiresIn <= 0 {
-			expiresIn = 1
-		}
+	// params := "access_token=" + authToken.AccessToken.Token + "&token_type=bearer&expires_in=" + strconv.FormatInt(expiresIn, 10) + "&state=" + stateValue + "&id_token=" + authToken.IDToken.Token + "&nonce=" + nonce
+	// Note: If OIDC breaks in the future, use the above params
+	params := "state=" + stateValue + "&nonce=" + nonce
+	if code != "" {
+		params += "&code=" + code
+	}
 
-		// params := "access_token=" + authToken.AccessToken.Token + "&token_type=bearer&expires_in=" + strconv.FormatInt(expiresIn, 10) + "&state=" + stateValue + "&id_token=" + authToken.IDToken.Token + "&nonce=" + nonce
-		// Note: If OIDC breaks in the future, use the above params
-		params := "state=" + stateValue + "&nonce=" + nonce
-		if code != "" {
-			params += "&code=" + code
+	sessionKey := provider + ":" + user.ID
+	cookie.SetSession(ctx, authToken.FingerPrintHash)
+	memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+authToken.FingerPrint, authToken.FingerPrintHash, authToken.SessionTokenExpiresAt)
+	memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+authToken.FingerPrint, authToken.AccessToken.Token, authToken.AccessToken.ExpiresAt)
+
+	if authToken.RefreshToken != nil {
+		params += `&refresh_token=` + authToken.RefreshToken.Token
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeRefreshToken+"_"+authToken.FingerPrint, authToken.RefreshToken.Token, authToken.RefreshToken.ExpiresAt)
+	}
+
+	go func() {
+		if isSignUp {
+			utils.RegisterEvent(ctx, constants.UserSignUpWebhookEvent, provider, user)
+			// User is also logged in with signup
+			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, provider, user)
+		} else {
+			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, provider, user)
 		}
+		db.Provider.AddSession(ctx, &models.Session{
+			UserID:    user.ID,
+			UserAgent: utils.GetUserAgent(ctx.Request),
+			IP:        utils.GetIP(ctx.Request),
+		})
+	}()
+	if strings.Contains(redirectURL, "?") {
+		redirectURL = redirectURL + "&" + params
+	} else {
+		redirectURL = redirectURL + "?" + strings.TrimPrefix(params, "&")
+	}
 
-		sessionKey := provider + ":" + user.ID
-		cookie.SetSession(ctx, authToken.FingerPrintHash)
-		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+authToken.FingerPrint, authToken.FingerPrintHash, authToken.SessionTokenExpiresAt)
-		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+authToken.FingerPrint, authToken.AccessToken.Token, authToken.AccessToken.ExpiresAt)
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
 
-		if authToken.RefreshToken != nil {
-			params += `&refresh_token=` + authToken.RefreshToken.Token
-			memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeRefreshToken+"_"+authToken.FingerPrint, authToken.RefreshToken.Token, authToken.RefreshToken.ExpiresAt)
+// processUpstreamIdentityLink links the identity returned by upstream provider to the user who requested the link
+// and redirects to redirect url, no session is created as user is already logged in.
+// Identity which belongs to another user can not be linked,
+// and link is accepted only within browser session of the user who requested it
+func processUpstreamIdentityLink(ctx *gin.Context, linkToken, provider string, identity *models.Identity, redirectURL string) {
+	linkRequest, err := token.ConsumeIdentityLinkRequest(linkToken)
+	if err != nil || linkRequest.Provider != provider {
+		log.Debug("Invalid link token: ", err)
+		ctx.JSON(400, gin.H{"error": "invalid link token"})
+		return
+	}
+	log := log.WithFields(log.Fields{
+		"user_id":  linkRequest.UserID,
+		"provider": provider,
+	})
+	// link token is only usable in the browser session of user who requested the link
+	if err := token.ValidateIdentityLinkSession(ctx, linkRequest); err != nil {
+		log.Debug("Invalid session for identity link: ", err)
+		ctx.JSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	if identity == nil {
+		log.Debug("User id is not returned by provider")
+		ctx.JSON(400, gin.H{"error": "unable to get user id from " + provider})
+		return
+	}
+	user, err := db.Provider.GetUserByID(ctx, linkRequest.UserID)
+	if err != nil {
+		log.Debug("Failed to get user: ", err)
+		ctx.JSON(400, gin.H{"error": "user not found"})
+		return
+	}
+	existingUser, err := getUpstreamUser(ctx, identity, "")
+	if err == nil && existingUser != nil && existingUser.ID != user.ID {
+		log.Debug("Identity is linked to another user: ", existingUser.ID)
+		ctx.JSON(400, gin.H{"error": "identity is already linked to another user"})
+		return
+	}
+	if err := saveUpstreamIdentity(ctx, user.ID, identity); err != nil {
+		log.Debug("Failed to save identity: ", err)
+		ctx.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if !utils.StringSliceContains(strings.Split(user.SignupMethods, ","), provider) {
+		user.SignupMethods = user.SignupMethods + "," + provider
+		if _, err := db.Provider.UpdateUser(ctx, user); err != nil {
+			log.Debug("Failed to update user: ", err)
+			ctx.JSON(500, gin.H{"error": err.Error()})
+			return
 		}
+	}
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
 
-		go func() {
-			if isSignUp {
-				utils.RegisterEvent(ctx, constants.UserSignUpWebhookEvent, provider, user)
-				// User is also logged in with signup
-				utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, provider, user)
-			} else {
-				utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, provider, user)
+// getUpstreamUser returns the user to whom identity of upstream provider belongs,
+// users who have logged in before identity was saved are matched by email.
+// Identity of deleted user is removed
+func getUpstreamUser(ctx context.Context, identity *models.Identity, email string) (*models.User, error) {
+	if identity != nil {
+		existingIdentity, err := db.Provider.GetIdentityByProviderUserID(ctx, identity.Provider, identity.ProviderUserID)
+		if err == nil && existingIdentity != nil {
+			user, err := db.Provider.GetUserByID(ctx, existingIdentity.UserID)
+			if err == nil && user != nil {
+				return user, nil
 			}
-			db.Provider.AddSession(ctx, &models.Session{
-				UserID:    user.ID,
-				UserAgent: utils.GetUserAgent(ctx.Request),
-				IP:        utils.GetIP(ctx.Request),
-			})
-		}()
-		if strings.Contains(redirectURL, "?") {
-			redirectURL = redirectURL + "&" + params
-		} else {
-			redirectURL = redirectURL + "?" + strings.TrimPrefix(params, "&")
+			log.Debug("Failed to get user of identity: ", err)
+			db.Provider.DeleteIdentity(ctx, existingIdentity)
 		}
+	}
+	if email == "" {
+		return nil, fmt.Errorf("user not found")
+	}
+	return db.Provider.GetUserByEmail(ctx, email)
+}
 
-		ctx.Redirect(http.StatusFound, redirectURL)
+// saveUpstreamIdentity adds the identity to user,
+// email & raw profile of identity are updated if it is already saved
+func saveUpstreamIdentity(ctx context.Context, userID string, identity *models.Identity) error {
+	existingIdentity, err := db.Provider.GetIdentityByProviderUserID(ctx, identity.Provider, identity.ProviderUserID)
+	if err != nil || existingIdentity == nil {
+		identity.UserID = userID
+		_, err = db.Provider.AddIdentity(ctx, identity)
+		return err
+	}
+	existingIdentity.UserID = userID
+	existingIdentity.Email = identity.Email
+	existingIdentity.RawProfile = identity.RawProfile
+	_, err = db.Provider.UpdateIdentity(ctx, existingIdentity)
+	return err
+}
+
+// newUpstreamIdentity returns the identity of user at upstream provider with json encoded profile,
+// nil is returned if subject id of user is not returned by provider
+func newUpstreamIdentity(provider string, providerUserID interface{}, email *string, profile interface{}) *models.Identity {
+	if providerUserID == nil || fmt.Sprintf("%v", providerUserID) == "" {
+		return nil
+	}
+	rawProfile, err := json.Marshal(profile)
+	if err != nil {
+		log.Debug("Failed to encode profile: ", err)
+	}
+	return &models.Identity{
+		Provider:       provider,
+		ProviderUserID: fmt.Sprintf("%v", providerUserID),
+		Email:          refs.StringValue(email),
+		RawProfile:     string(rawProfile),
 	}
 }
 
-func processGoogleUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processGoogleUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.GoogleConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid google exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid google exchange code: %s", err.Error())
 	}
 	verifier := oauth.OIDCProviders.GoogleOIDC.Verifier(&oidc.Config{ClientID: oauth.OAuthProviders.GoogleConfig.ClientID})
 
@@ -331,35 +506,37 @@ func processGoogleUserInfo(ctx context.Context, code string) (*models.User, erro
 	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
 	if !ok {
 		log.Debug("Failed to extract ID Token from OAuth2 token")
-		return nil, fmt.Errorf("unable to extract id_token")
+		return nil, nil, fmt.Errorf("unable to extract id_token")
 	}
 
 	// Parse and verify ID Token payload.
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		log.Debug("Failed to verify ID Token: ", err)
-		return nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
+		return nil, nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
 	}
 	user := &models.User{}
 	if err := idToken.Claims(&user); err != nil {
 		log.Debug("Failed to parse ID Token claims: ", err)
-		return nil, fmt.Errorf("unable to extract claims")
+		return nil, nil, fmt.Errorf("unable to extract claims")
 	}
+	claims := map[string]interface{}{}
+	idToken.Claims(&claims)
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodGoogle, idToken.Subject, user.Email, claims), nil
 }
 
-func processGithubUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processGithubUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.GithubConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid github exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid github exchange code: %s", err.Error())
 	}
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.GithubUserInfoURL, nil)
 	if err != nil {
 		log.Debug("Failed to create github user info request: ", err)
-		return nil, fmt.Errorf("error creating github user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating github user info request: %s", err.Error())
 	}
 	req.Header.Set(
 		"Authorization", fmt.Sprintf("token %s", oauth2Token.AccessToken),
@@ -368,22 +545,27 @@ func processGithubUserInfo(ctx context.Context, code string) (*models.User, erro
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request github user info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read github user info response body: ", err)
-		return nil, fmt.Errorf("failed to read github response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read github response body: %s", err.Error())
 	}
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request github user info: ", string(body))
-		return nil, fmt.Errorf("failed to request github user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request github user info: %s", string(body))
 	}
 
 	userRawData := make(map[string]string)
 	json.Unmarshal(body, &userRawData)
+	// id of github user is a number, hence it is decoded as json number to keep its exact value
+	profile := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	decoder.Decode(&profile)
 
 	name := strings.Split(userRawData["name"], " ")
 	firstName := ""
@@ -396,53 +578,51 @@ func processGithubUserInfo(ctx context.Context, code string) (*models.User, erro
 	}
 
 	picture := userRawData["avatar_url"]
-	email := userRawData["email"]
-
-	if email == "" {
-		type GithubUserEmails struct {
-			Email   string `json:"email"`
-			Primary bool   `json:"primary"`
-		}
-
-		// fetch using /users/email endpoint
-		req, err := http.NewRequest(http.MethodGet, constants.GithubUserEmails, nil)
-		if err != nil {
-			log.Debug("Failed to create github emails request: ", err)
-			return nil, fmt.Errorf("error creating github user info request: %s", err.Error())
-		}
-		req.Header.Set(
-			"Authorization", fmt.Sprintf("token %s", oauth2Token.AccessToken),
-		)
+	// email of profile is not used as it is not known if it is verified,
+	// only verified primary email is picked using /user/emails endpoint
+	type GithubUserEmails struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	req, err = http.NewRequest(http.MethodGet, constants.GithubUserEmails, nil)
+	if err != nil {
+		log.Debug("Failed to create github emails request: ", err)
+		return nil, nil, fmt.Errorf("error creating github user info request: %s", err.Error())
+	}
+	req.Header.Set(
+		"Authorization", fmt.Sprintf("token %s", oauth2Token.AccessToken),
+	)
 
-		response, err := client.Do(req)
-		if err != nil {
-			log.Debug("Failed to request github user email: ", err)
-			return nil, err
-		}
+	response, err = client.Do(req)
+	if err != nil {
+		log.Debug("Failed to request github user email: ", err)
+		return nil, nil, err
+	}
 
-		defer response.Body.Close()
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			log.Debug("Failed to read github user email response body: ", err)
-			return nil, fmt.Errorf("failed to read github response body: %s", err.Error())
-		}
-		if response.StatusCode >= 400 {
-			log.Debug("Failed to request github user email: ", string(body))
-			return nil, fmt.Errorf("failed to request github user info: %s", string(body))
-		}
+	defer response.Body.Close()
+	body, err = io.ReadAll(response.Body)
+	if err != nil {
+		log.Debug("Failed to read github user email response body: ", err)
+		return nil, nil, fmt.Errorf("failed to read github response body: %s", err.Error())
+	}
+	if response.StatusCode >= 400 {
+		log.Debug("Failed to request github user email: ", string(body))
+		return nil, nil, fmt.Errorf("failed to request github user info: %s", string(body))
+	}
 
-		emailData := []GithubUserEmails{}
-		err = json.Unmarshal(body, &emailData)
-		if err != nil {
-			log.Debug("Failed to parse github user email: ", err)
-			return nil, fmt.Errorf("failed to parse github user email: %s", err.Error())
-		}
+	emailData := []GithubUserEmails{}
+	err = json.Unmarshal(body, &emailData)
+	if err != nil {
+		log.Debug("Failed to parse github user email: ", err)
+		return nil, nil, fmt.Errorf("failed to parse github user email: %s", err.Error())
+	}
 
-		for _, userEmail := range emailData {
+	email := ""
+	for _, userEmail := range emailData {
+		if userEmail.Verified && userEmail.Primary {
 			email = userEmail.Email
-			if userEmail.Primary {
-				break
-			}
+			break
 		}
 	}
 
@@ -453,37 +633,37 @@ func processGithubUserInfo(ctx context.Context, code string) (*models.User, erro
 		Email:      &email,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodGithub, profile["id"], user.Email, profile), nil
 }
 
-func processFacebookUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processFacebookUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.FacebookConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Invalid facebook exchange code: ", err)
-		return nil, fmt.Errorf("invalid facebook exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid facebook exchange code: %s", err.Error())
 	}
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.FacebookUserInfoURL+oauth2Token.AccessToken, nil)
 	if err != nil {
 		log.Debug("Error creating facebook user info request: ", err)
-		return nil, fmt.Errorf("error creating facebook user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating facebook user info request: %s", err.Error())
 	}
 
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to process facebook user: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read facebook response: ", err)
-		return nil, fmt.Errorf("failed to read facebook response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read facebook response body: %s", err.Error())
 	}
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request facebook user info: ", string(body))
-		return nil, fmt.Errorf("failed to request facebook user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request facebook user info: %s", string(body))
 	}
 	userRawData := make(map[string]interface{})
 	json.Unmarshal(body, &userRawData)
@@ -503,21 +683,21 @@ func processFacebookUserInfo(ctx context.Context, code string) (*models.User, er
 		Email:      &email,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodFacebook, userRawData["id"], user.Email, userRawData), nil
 }
 
-func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.LinkedInConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid linkedin exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid linkedin exchange code: %s", err.Error())
 	}
 
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.LinkedInUserInfoURL, nil)
 	if err != nil {
 		log.Debug("Failed to create linkedin user info request: ", err)
-		return nil, fmt.Errorf("error creating linkedin user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating linkedin user info request: %s", err.Error())
 	}
 	req.Header = http.Header{
 		"Authorization": []string{fmt.Sprintf("Bearer %s", oauth2Token.AccessToken)},
@@ -526,19 +706,19 @@ func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, er
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request linkedin user info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read linkedin user info response body: ", err)
-		return nil, fmt.Errorf("failed to read linkedin response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read linkedin response body: %s", err.Error())
 	}
 
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request linkedin user info: ", string(body))
-		return nil, fmt.Errorf("failed to request linkedin user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request linkedin user info: %s", string(body))
 	}
 
 	userRawData := make(map[string]interface{})
@@ -547,7 +727,7 @@ func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, er
 	req, err = http.NewRequest("GET", constants.LinkedInEmailURL, nil)
 	if err != nil {
 		log.Debug("Failed to create linkedin email info request: ", err)
-		return nil, fmt.Errorf("error creating linkedin user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating linkedin user info request: %s", err.Error())
 	}
 	req.Header = http.Header{
 		"Authorization": []string{fmt.Sprintf("Bearer %s", oauth2Token.AccessToken)},
@@ -556,18 +736,18 @@ func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, er
 	response, err = client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request linkedin email info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err = io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read linkedin email info response body: ", err)
-		return nil, fmt.Errorf("failed to read linkedin email response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read linkedin email response body: %s", err.Error())
 	}
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request linkedin user info: ", string(body))
-		return nil, fmt.Errorf("failed to request linkedin user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request linkedin user info: %s", string(body))
 	}
 	emailRawData := make(map[string]interface{})
 	json.Unmarshal(body, &emailRawData)
@@ -584,22 +764,22 @@ func processLinkedInUserInfo(ctx context.Context, code string) (*models.User, er
 		Email:      &emailAddress,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodLinkedIn, userRawData["id"], user.Email, userRawData), nil
 }
 
-func processAppleUserInfo(ctx context.Context, code string, user_ *AppleUserInfo) (*models.User, error) {
+func processAppleUserInfo(ctx context.Context, code string, user_ *AppleUserInfo) (*models.User, *models.Identity, error) {
 	var user = &models.User{}
 	oauth2Token, err := oauth.OAuthProviders.AppleConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return user, fmt.Errorf("invalid apple exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid apple exchange code: %s", err.Error())
 	}
 
 	// Extract the ID Token from OAuth2 token.
 	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
 	if !ok {
 		log.Debug("Failed to extract ID Token from OAuth2 token")
-		return user, fmt.Errorf("unable to extract id_token")
+		return nil, nil, fmt.Errorf("unable to extract id_token")
 	}
 
 	tokenSplit := strings.Split(rawIDToken, ".")
@@ -607,18 +787,18 @@ func processAppleUserInfo(ctx context.Context, code string, user_ *AppleUserInfo
 	decodedClaimsData, err := base64.RawURLEncoding.DecodeString(claimsData)
 	if err != nil {
 		log.Debugf("Failed to decrypt claims %s: %s", claimsData, err.Error())
-		return user, fmt.Errorf("failed to decrypt claims data: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to decrypt claims data: %s", err.Error())
 	}
 
 	claims := make(map[string]interface{})
 	err = json.Unmarshal(decodedClaimsData, &claims)
 	if err != nil {
 		log.Debug("Failed to unmarshal claims data: ", err)
-		return user, fmt.Errorf("failed to unmarshal claims data: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to unmarshal claims data: %s", err.Error())
 	}
 	if val, ok := claims["email"]; !ok || val == nil {
 		log.Debug("Failed to extract email from claims.")
-		return user, fmt.Errorf("unable to extract email, please check the scopes enabled for your app. It needs `email`, `name` scopes")
+		return nil, nil, fmt.Errorf("unable to extract email, please check the scopes enabled for your app. It needs `email`, `name` scopes")
 	} else {
 		email := val.(string)
 		user.Email = &email
@@ -639,21 +819,21 @@ func processAppleUserInfo(ctx context.Context, code string, user_ *AppleUserInfo
 	user.GivenName = &user_.Name.FirstName
 	user.FamilyName = &user_.Name.LastName
 
-	return user, err
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodApple, claims["sub"], user.Email, claims), err
 }
 
-func processDiscordUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processDiscordUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.DiscordConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid discord exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid discord exchange code: %s", err.Error())
 	}
 
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.DiscordUserInfoURL, nil)
 	if err != nil {
 		log.Debug("Failed to create Discord user info request: ", err)
-		return nil, fmt.Errorf("error creating Discord user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating Discord user info request: %s", err.Error())
 	}
 	req.Header = http.Header{
 		"Authorization": []string{fmt.Sprintf("Bearer %s", oauth2Token.AccessToken)},
@@ -662,40 +842,40 @@ func processDiscordUserInfo(ctx context.Context, code string) (*models.User, err
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request Discord user info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read Discord user info response body: ", err)
-		return nil, fmt.Errorf("failed to read Discord response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read Discord response body: %s", err.Error())
 	}
 
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request Discord user info: ", string(body))
-		return nil, fmt.Errorf("failed to request Discord user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request Discord user info: %s", string(body))
 	}
 
 	// Unmarshal the response body into a map
 	responseRawData := make(map[string]interface{})
 	if err := json.Unmarshal(body, &responseRawData); err != nil {
 		log.Debug("Failed to unmarshal Discord response: ", err)
-		return nil, fmt.Errorf("failed to unmarshal Discord response: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to unmarshal Discord response: %s", err.Error())
 	}
 
 	// Safely extract the user data
 	userRawData, ok := responseRawData["user"].(map[string]interface{})
 	if !ok {
 		log.Debug("User data is not in expected format or missing in response")
-		return nil, fmt.Errorf("user data is not in expected format or missing in response")
+		return nil, nil, fmt.Errorf("user data is not in expected format or missing in response")
 	}
 
 	// Extract the username
 	firstName, ok := userRawData["username"].(string)
 	if !ok {
 		log.Debug("Username is not in expected format or missing in user data")
-		return nil, fmt.Errorf("username is not in expected format or missing in user data")
+		return nil, nil, fmt.Errorf("username is not in expected format or missing in user data")
 	}
 	profilePicture := fmt.Sprintf("https://cdn.discordapp.com/avatars/%s/%s.png", userRawData["id"].(string), userRawData["avatar"].(string))
 
@@ -704,21 +884,21 @@ func processDiscordUserInfo(ctx context.Context, code string) (*models.User, err
 		Picture:   &profilePicture,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodDiscord, userRawData["id"], user.Email, userRawData), nil
 }
 
-func processTwitterUserInfo(ctx context.Context, code, verifier string) (*models.User, error) {
+func processTwitterUserInfo(ctx context.Context, code, verifier string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.TwitterConfig.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid twitter exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid twitter exchange code: %s", err.Error())
 	}
 
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.TwitterUserInfoURL, nil)
 	if err != nil {
 		log.Debug("Failed to create Twitter user info request: ", err)
-		return nil, fmt.Errorf("error creating Twitter user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating Twitter user info request: %s", err.Error())
 	}
 	req.Header = http.Header{
 		"Authorization": []string{fmt.Sprintf("Bearer %s", oauth2Token.AccessToken)},
@@ -727,19 +907,19 @@ func processTwitterUserInfo(ctx context.Context, code, verifier string) (*models
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request Twitter user info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read Twitter user info response body: ", err)
-		return nil, fmt.Errorf("failed to read Twitter response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read Twitter response body: %s", err.Error())
 	}
 
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request Twitter user info: ", string(body))
-		return nil, fmt.Errorf("failed to request Twitter user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request Twitter user info: %s", string(body))
 	}
 
 	responseRawData := make(map[string]interface{})
@@ -770,15 +950,15 @@ func processTwitterUserInfo(ctx context.Context, code, verifier string) (*models
 		Nickname:   &nickname,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodTwitter, userRawData["id"], user.Email, userRawData), nil
 }
 
 // process microsoft user information
-func processMicrosoftUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processMicrosoftUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.MicrosoftConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid microsoft exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid microsoft exchange code: %s", err.Error())
 	}
 	// we need to skip issuer check because for common tenant it will return internal issuer which does not match
 	verifier := oauth.OIDCProviders.MicrosoftOIDC.Verifier(&oidc.Config{
@@ -789,36 +969,38 @@ func processMicrosoftUserInfo(ctx context.Context, code string) (*models.User, e
 	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
 	if !ok {
 		log.Debug("Failed to extract ID Token from OAuth2 token")
-		return nil, fmt.Errorf("unable to extract id_token")
+		return nil, nil, fmt.Errorf("unable to extract id_token")
 	}
 	// Parse and verify ID Token payload.
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		log.Debug("Failed to verify ID Token: ", err)
-		return nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
+		return nil, nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
 	}
 	user := &models.User{}
 	if err := idToken.Claims(&user); err != nil {
 		log.Debug("Failed to parse ID Token claims: ", err)
-		return nil, fmt.Errorf("unable to extract claims")
+		return nil, nil, fmt.Errorf("unable to extract claims")
 	}
+	claims := map[string]interface{}{}
+	idToken.Claims(&claims)
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodMicrosoft, idToken.Subject, user.Email, claims), nil
 }
 
 // process twitch user information
-func processTwitchUserInfo(ctx context.Context, code string) (*models.User, error) {
+func processTwitchUserInfo(ctx context.Context, code string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.TwitchConfig.Exchange(ctx, code)
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid twitch exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid twitch exchange code: %s", err.Error())
 	}
 
 	// Extract the ID Token from OAuth2 token.
 	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
 	if !ok {
 		log.Debug("Failed to extract ID Token from OAuth2 token")
-		return nil, fmt.Errorf("unable to extract id_token")
+		return nil, nil, fmt.Errorf("unable to extract id_token")
 	}
 	verifier := oauth.OIDCProviders.TwitchOIDC.Verifier(&oidc.Config{
 		ClientID:        oauth.OAuthProviders.TwitchConfig.ClientID,
@@ -829,31 +1011,33 @@ func processTwitchUserInfo(ctx context.Context, code string) (*models.User, erro
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		log.Debug("Failed to verify ID Token: ", err)
-		return nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
+		return nil, nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
 	}
 
 	user := &models.User{}
 	if err := idToken.Claims(&user); err != nil {
 		log.Debug("Failed to parse ID Token claims: ", err)
-		return nil, fmt.Errorf("unable to extract claims")
+		return nil, nil, fmt.Errorf("unable to extract claims")
 	}
+	claims := map[string]interface{}{}
+	idToken.Claims(&claims)
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodTwitch, idToken.Subject, user.Email, claims), nil
 }
 
 // process roblox user information
-func processRobloxUserInfo(ctx context.Context, code, verifier string) (*models.User, error) {
+func processRobloxUserInfo(ctx context.Context, code, verifier string) (*models.User, *models.Identity, error) {
 	oauth2Token, err := oauth.OAuthProviders.RobloxConfig.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
 	if err != nil {
 		log.Debug("Failed to exchange code for token: ", err)
-		return nil, fmt.Errorf("invalid roblox exchange code: %s", err.Error())
+		return nil, nil, fmt.Errorf("invalid roblox exchange code: %s", err.Error())
 	}
 
 	client := http.Client{}
 	req, err := http.NewRequest("GET", constants.RobloxUserInfoURL, nil)
 	if err != nil {
 		log.Debug("Failed to create roblox user info request: ", err)
-		return nil, fmt.Errorf("error creating roblox user info request: %s", err.Error())
+		return nil, nil, fmt.Errorf("error creating roblox user info request: %s", err.Error())
 	}
 	req.Header = http.Header{
 		"Authorization": []string{fmt.Sprintf("Bearer %s", oauth2Token.AccessToken)},
@@ -862,19 +1046,19 @@ func processRobloxUserInfo(ctx context.Context, code, verifier string) (*models.
 	response, err := client.Do(req)
 	if err != nil {
 		log.Debug("Failed to request roblox user info: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	defer response.Body.Close()
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		log.Debug("Failed to read roblox user info response body: ", err)
-		return nil, fmt.Errorf("failed to read roblox response body: %s", err.Error())
+		return nil, nil, fmt.Errorf("failed to read roblox response body: %s", err.Error())
 	}
 
 	if response.StatusCode >= 400 {
 		log.Debug("Failed to request roblox user info: ", string(body))
-		return nil, fmt.Errorf("failed to request roblox user info: %s", string(body))
+		return nil, nil, fmt.Errorf("failed to request roblox user info: %s", string(body))
 	}
 
 	userRawData := make(map[string]interface{})
@@ -903,5 +1087,85 @@ func processRobloxUserInfo(ctx context.Context, code, verifier string) (*models.
 		Email:      &email,
 	}
 
-	return user, nil
+	return user, newUpstreamIdentity(constants.AuthRecipeMethodRoblox, userRawData["sub"], user.Email, userRawData), nil
+}
+
+// process user information of upstream identity provider managed by admin
+func processIdentityProviderUserInfo(ctx *gin.Context, provider, code, verifier string) (*models.User, *models.Identity, error) {
+	identityProvider, err := db.Provider.GetIdentityProviderByName(ctx, provider)
+	if err != nil || identityProvider == nil {
+		log.Debug("Invalid oauth provider: ", provider)
+		return nil, nil, fmt.Errorf(`invalid oauth provider`)
+	}
+	identityProviderConfig, err := oauth.GetIdentityProviderConfig(ctx, identityProvider, parsers.GetHost(ctx)+"/oauth_callback/"+provider)
+	if err != nil {
+		log.Debug("Failed to get identity provider config: ", err)
+		return nil, nil, err
+	}
+	oauth2Token, err := identityProviderConfig.OAuthConfig.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		log.Debug("Failed to exchange code for token: ", err)
+		return nil, nil, fmt.Errorf("invalid %s exchange code: %s", provider, err.Error())
+	}
+
+	claims := map[string]interface{}{}
+	if rawIDToken, ok := oauth2Token.Extra("id_token").(string); ok && identityProviderConfig.IDTokenVerifier != nil {
+		idToken, err := identityProviderConfig.IDTokenVerifier.Verify(ctx, rawIDToken)
+		if err != nil {
+			log.Debug("Failed to verify ID Token: ", err)
+			return nil, nil, fmt.Errorf("unable to verify id_token: %s", err.Error())
+		}
+		if err := idToken.Claims(&claims); err != nil {
+			log.Debug("Failed to parse ID Token claims: ", err)
+			return nil, nil, fmt.Errorf("unable to extract claims")
+		}
+	}
+
+	if identityProviderConfig.UserInfoURL != "" {
+		response, err := identityProviderConfig.OAuthConfig.Client(ctx, oauth2Token).Get(identityProviderConfig.UserInfoURL)
+		if err != nil {
+			log.Debug("Failed to request user info: ", err)
+			return nil, nil, err
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			log.Debug("Failed to read user info response body: ", err)
+			return nil, nil, fmt.Errorf("failed to read %s response body: %s", provider, err.Error())
+		}
+		if response.StatusCode >= 400 {
+			log.Debug("Failed to request user info: ", string(body))
+			return nil, nil, fmt.Errorf("failed to request %s user info: %s", provider, string(body))
+		}
+		userInfoClaims := map[string]interface{}{}
+		if err := json.Unmarshal(body, &userInfoClaims); err != nil {
+			log.Debug("Failed to parse user info: ", err)
+			return nil, nil, fmt.Errorf("failed to parse %s user info", provider)
+		}
+		// userinfo should belong to the same user as id token
+		if sub, ok := claims["sub"]; ok && userInfoClaims["sub"] != nil && userInfoClaims["sub"] != sub {
+			log.Debug("User info sub does not match id token sub")
+			return nil, nil, fmt.Errorf("invalid %s user info", provider)
+		}
+		for key, value := range userInfoClaims {
+			claims[key] = value
+		}
+	}
+
+	// users are matched by email, hence email should be verified by identity provider
+	if !oauth.IsIdentityProviderEmailVerified(identityProvider, claims) {
+		log.Debug("Email is not verified by identity provider")
+		return nil, nil, fmt.Errorf("email is not verified by %s", provider)
+	}
+	user := oauth.GetIdentityProviderUser(identityProvider, claims)
+	if refs.StringValue(user.Email) == "" {
+		log.Debug("Email is not returned by identity provider")
+		return nil, nil, fmt.Errorf("unable to get email from %s", provider)
+	}
+	// userinfo of oauth2 providers which do not support openid connect can have id instead of sub
+	providerUserID := claims["sub"]
+	if providerUserID == nil {
+		providerUserID = claims["id"]
+	}
+	return user, newUpstreamIdentity(provider, providerUserID, user.Email, claims), nil
 }
@@ -11,9 +11,11 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/oauth"
 	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
 	"github.com/authorizerdev/authorizer/server/utils"
 	"github.com/authorizerdev/authorizer/server/validators"
 )
@@ -98,6 +100,20 @@ func OAuthLoginHandler() gin.HandlerFunc {
 		oauthStateString := state + "___" + redirectURI + "___" + roles + "___" + strings.Join(scope, " ")
 
 		provider := c.Param("oauth_provider")
+		// link token is sent when logged in user links identity of provider,
+		// it is part of state so identity is linked to the user in oauth_callback instead of login
+		linkToken := strings.TrimSpace(c.Query("link_token"))
+		if linkToken != "" {
+			linkRequest, err := token.GetIdentityLinkRequest(linkToken)
+			if err != nil || linkRequest.Provider != provider {
+				log.Debug("Invalid link token: ", err)
+				c.JSON(400, gin.H{
+					"error": "invalid link token",
+				})
+				return
+			}
+			oauthStateString += "___" + linkToken
+		}
 		isProviderConfigured := true
 		switch provider {
 		case constants.AuthRecipeMethodGoogle:
@@ -281,10 +297,39 @@ func OAuthLoginHandler() gin.HandlerFunc {
 			url := oauth.OAuthProviders.RobloxConfig.AuthCodeURL(oauthStateString)
 			c.Redirect(http.StatusTemporaryRedirect, url)
 		default:
-			log.Debug("Invalid oauth provider: ", provider)
-			c.JSON(422, gin.H{
-				"message": "Invalid oauth provider",
-			})
+			// upstream identity providers managed by admin
+			identityProvider, err := db.Provider.GetIdentityProviderByName(c, provider)
+			if err != nil || identityProvider == nil {
+				// saml identity providers managed by admin share the login url
+				if samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(c, provider); err == nil && samlIdentityProvider != nil {
+					processSAMLIdentityProviderLogin(c, samlIdentityProvider, oauthStateString)
+					return
+				}
+				log.Debug("Invalid oauth provider: ", provider)
+				c.JSON(422, gin.H{
+					"message": "Invalid oauth provider",
+				})
+				return
+			}
+			identityProviderConfig, err := oauth.GetIdentityProviderConfig(c, identityProvider, hostname+"/oauth_callback/"+provider)
+			if err != nil {
+				log.Debug("Failed to get identity provider config: ", err)
+				c.JSON(500, gin.H{
+					"error": "internal server error",
+				})
+				return
+			}
+			verifier, challenge := utils.GenerateCodeChallenge()
+			err = memorystore.Provider.SetState(oauthStateString, verifier)
+			if err != nil {
+				log.Debug("Error setting state: ", err)
+				c.JSON(500, gin.H{
+					"error": "internal server error",
+				})
+				return
+			}
+			url := identityProviderConfig.OAuthConfig.AuthCodeURL(oauthStateString, oauth2.SetAuthURLParam("code_challenge", challenge), oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+			c.Redirect(http.StatusTemporaryRedirect, url)
 		}
 
 		if !isProviderConfigured {
@@ -6,6 +6,8 @@ import (
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/validators"
 )
 
 // OpenIDConfigurationHandler handler for open-id configurations
@@ -14,19 +16,43 @@ func OpenIDConfigurationHandler() gin.HandlerFunc {
 		issuer := parsers.GetHost(c)
 		jwtType, _ := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
 
-		c.JSON(200, gin.H{
-			"issuer":                                issuer,
-			"authorization_endpoint":                issuer + "/authorize",
-			"token_endpoint":                        issuer + "/oauth/token",
-			"userinfo_endpoint":                     issuer + "/userinfo",
-			"jwks_uri":                              issuer + "/.well-known/jwks.json",
-			"registration_endpoint":                 issuer + "/app",
-			"response_types_supported":              []string{"code", "token", "id_token"},
-			"scopes_supported":                      []string{"openid", "email", "profile"},
-			"response_modes_supported":              []string{"query", "fragment", "form_post", "web_message"},
-			"subject_types_supported":               []string{"public"},
-			"id_token_signing_alg_values_supported": []string{jwtType},
-			"claims_supported":                      []string{"aud", "exp", "iss", "iat", "sub", "given_name", "family_name", "middle_name", "nickname", "preferred_username", "picture", "email", "email_verified", "roles", "role", "gender", "birthdate", "phone_number", "phone_number_verified", "nonce", "updated_at", "created_at", "revoked_timestamp", "login_method", "signup_methods", "token_type"},
-		})
+		config := gin.H{
+			"issuer":                                           issuer,
+			"authorization_endpoint":                           issuer + "/authorize",
+			"token_endpoint":                                   issuer + "/oauth/token",
+			"userinfo_endpoint":                                issuer + "/userinfo",
+			"jwks_uri":                                         issuer + "/.well-known/jwks.json",
+			"introspection_endpoint":                           issuer + "/oauth/introspect",
+			"revocation_endpoint":                              issuer + "/oauth/revoke",
+			"device_authorization_endpoint":                    issuer + "/oauth/device/code",
+			"pushed_authorization_request_endpoint":            issuer + "/oauth/par",
+			"require_pushed_authorization_requests":            false,
+			"request_parameter_supported":                      true,
+			"request_uri_parameter_supported":                  false,
+			"request_object_signing_alg_values_supported":      token.ClientAssertionSigningAlgorithms,
+			"end_session_endpoint":                             issuer + "/oauth/logout",
+			"frontchannel_logout_supported":                    true,
+			"frontchannel_logout_session_supported":            true,
+			"backchannel_logout_supported":                     true,
+			"backchannel_logout_session_supported":             true,
+			"response_types_supported":                         []string{"code", "token", "id_token"},
+			"scopes_supported":                                 []string{"openid", "email", "profile", "phone", "address"},
+			"response_modes_supported":                         []string{"query", "fragment", "form_post", "web_message", "query.jwt", "fragment.jwt", "form_post.jwt", "jwt"},
+			"authorization_signing_alg_values_supported":       []string{jwtType},
+			"grant_types_supported":                            []string{constants.GrantTypeAuthorizationCode, constants.GrantTypeRefreshToken, constants.GrantTypeClientCredentials, constants.GrantTypeDeviceCode, constants.GrantTypeTokenExchange},
+			"token_endpoint_auth_methods_supported":            []string{constants.TokenEndpointAuthMethodClientSecretBasic, constants.TokenEndpointAuthMethodClientSecretPost, constants.TokenEndpointAuthMethodClientSecretJWT, constants.TokenEndpointAuthMethodPrivateKeyJWT, constants.TokenEndpointAuthMethodNone},
+			"token_endpoint_auth_signing_alg_values_supported": append(append([]string{}, token.ClientAssertionSigningAlgorithms...), token.ClientSecretAssertionSigningAlgorithms...),
+			"revocation_endpoint_auth_methods_supported":       []string{constants.TokenEndpointAuthMethodClientSecretBasic, constants.TokenEndpointAuthMethodClientSecretPost, constants.TokenEndpointAuthMethodClientSecretJWT, constants.TokenEndpointAuthMethodPrivateKeyJWT, constants.TokenEndpointAuthMethodNone},
+			"introspection_endpoint_auth_methods_supported":    []string{constants.TokenEndpointAuthMethodClientSecretBasic, constants.TokenEndpointAuthMethodClientSecretPost, constants.TokenEndpointAuthMethodClientSecretJWT, constants.TokenEndpointAuthMethodPrivateKeyJWT},
+			"subject_types_supported":                          []string{constants.SubjectTypePublic, constants.SubjectTypePairwise},
+			"id_token_signing_alg_values_supported":            []string{jwtType},
+			"dpop_signing_alg_values_supported":                token.DPoPSigningAlgorithms,
+			"claims_supported":                                 []string{"aud", "exp", "iss", "iat", "sub", "given_name", "family_name", "middle_name", "nickname", "preferred_username", "picture", "email", "email_verified", "roles", "role", "gender", "birthdate", "phone_number", "phone_number_verified", "nonce", "updated_at", "login_method", "token_type", "sid", "auth_time", "acr"},
+			"claims_parameter_supported":                       true,
+		}
+		if validators.IsClientRegistrationEnabled() {
+			config["registration_endpoint"] = issuer + "/oauth/register"
+		}
+		c.JSON(200, config)
 	}
 }
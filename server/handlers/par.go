@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// pushedAuthorizationRequestParams are the authorize request params
+// which can be pushed by client
+var pushedAuthorizationRequestParams = []string{
+	"redirect_uri",
+	"response_type",
+	"response_mode",
+	"state",
+	"code_challenge",
+	"code_challenge_method",
+	"scope",
+	"nonce",
+	"screen_hint",
+	"prompt",
+	"max_age",
+	"login_hint",
+	"acr_values",
+	"claims",
+}
+
+// getRequestObjectParams validates the request object signed by client and returns the authorization request params in it.
+// response_type sent along with request object should be same as the one in request object
+func getRequestObjectParams(gc *gin.Context, client *models.Client, requestObject, responseType string) (map[string]string, error) {
+	requestObjectParams, err := token.ValidateRequestObject(gc, client, requestObject)
+	if err != nil {
+		return nil, err
+	}
+	if responseType != "" && requestObjectParams["response_type"] != "" && responseType != requestObjectParams["response_type"] {
+		return nil, fmt.Errorf("response_type does not match request object")
+	}
+	params := map[string]string{}
+	for _, key := range pushedAuthorizationRequestParams {
+		if value := strings.TrimSpace(requestObjectParams[key]); value != "" {
+			params[key] = value
+		}
+	}
+	return params, nil
+}
+
+// PushedAuthorizationRequestHandler to handle pushed authorization requests (RFC 9126)
+// POST /oauth/par
+func PushedAuthorizationRequestHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		if err := gc.Request.ParseForm(); err != nil {
+			log.Debug("Error parsing form: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": err.Error(),
+			})
+			return
+		}
+
+		clientID := strings.TrimSpace(gc.Request.PostForm.Get("client_id"))
+		clientSecret := strings.TrimSpace(gc.Request.PostForm.Get("client_secret"))
+		// check if clientID & clientSecret are present as part of
+		// authorization header with basic auth
+		if clientID == "" && clientSecret == "" {
+			clientID, clientSecret, _ = gc.Request.BasicAuth()
+		}
+		// client_id is optional with client assertion as client is identified by sub claim
+		clientAssertion := strings.TrimSpace(gc.Request.PostForm.Get("client_assertion"))
+		if clientID == "" && clientAssertion != "" {
+			clientID, _ = token.GetClientAssertionSubject(clientAssertion)
+		}
+		if clientID == "" {
+			log.Debug("Client ID is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "client_id_required",
+				"error_description": "The client id is required",
+			})
+			return
+		}
+		client, err := utils.GetClientByClientID(gc, clientID)
+		if err != nil || client == nil {
+			log.Debug("Client ID is invalid: ", clientID)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client id is invalid",
+			})
+			return
+		}
+		// clients authenticate as they would at token endpoint
+		if err := authenticateClient(gc, client, clientSecret, clientAssertion, gc.Request.PostForm.Get("client_assertion_type")); err != nil {
+			log.Debug("Client authentication failed: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client authentication failed",
+			})
+			return
+		}
+
+		// request_uri cannot be nested in pushed authorization request
+		if gc.Request.PostForm.Get("request_uri") != "" {
+			log.Debug("request_uri is not allowed in pushed authorization request")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The request_uri parameter is not allowed",
+			})
+			return
+		}
+
+		params := map[string]string{}
+		for _, key := range pushedAuthorizationRequestParams {
+			if value := strings.TrimSpace(gc.Request.PostForm.Get(key)); value != "" {
+				params[key] = value
+			}
+		}
+		// params of request object take precedence over form params
+		if requestObject := strings.TrimSpace(gc.Request.PostForm.Get("request")); requestObject != "" {
+			requestObjectParams, err := getRequestObjectParams(gc, client, requestObject, params["response_type"])
+			if err != nil {
+				log.Debug("Invalid request object: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request_object",
+					"error_description": "The request object is invalid",
+				})
+				return
+			}
+			for key, value := range requestObjectParams {
+				params[key] = value
+			}
+		} else if client.RequireSignedRequestObject {
+			log.Debug("Signed request object is required for client: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The request object is required",
+			})
+			return
+		}
+
+		if params["state"] == "" {
+			log.Debug("State is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The state is required to prevent csrf attack",
+			})
+			return
+		}
+		if params["redirect_uri"] == "" && !utils.IsDefaultClientID(client.ClientID) && len(client.GetRedirectURIs()) > 1 {
+			log.Debug("Redirect uri is required for client: ", clientID)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The redirect uri is required",
+			})
+			return
+		}
+		if redirectURI := params["redirect_uri"]; redirectURI != "" && !validators.IsValidClientRedirectURI(client, redirectURI) {
+			log.Debug("Invalid redirect uri: ", redirectURI)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The redirect uri " + redirectURI + " is invalid",
+			})
+			return
+		}
+		if scope := params["scope"]; scope != "" && !validators.IsValidClientScope(client, strings.Fields(scope)) {
+			log.Debug("Scope not allowed for client: ", scope)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_scope",
+				"error_description": "The requested scope is not allowed for client",
+			})
+			return
+		}
+
+		pushedAuthorizationRequest, err := token.SetPushedAuthorizationRequest(client.ClientID, params)
+		if err != nil {
+			log.Debug("Error saving pushed authorization request: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"error":             "server_error",
+				"error_description": "Failed to save authorization request",
+			})
+			return
+		}
+
+		gc.JSON(http.StatusCreated, gin.H{
+			"request_uri": pushedAuthorizationRequest.RequestURI,
+			"expires_in":  token.PushedAuthorizationRequestExpiresIn,
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// RevokeRequestBody is the request body for token revocation
+type RevokeRequestBody struct {
+	Token         string `form:"token" json:"token"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+	ClientID      string `form:"client_id" json:"client_id"`
+	ClientSecret  string `form:"client_secret" json:"client_secret"`
+	// client assertion params for private_key_jwt client authentication as per RFC 7523
+	ClientAssertion     string `form:"client_assertion" json:"client_assertion"`
+	ClientAssertionType string `form:"client_assertion_type" json:"client_assertion_type"`
+	// RefreshToken is kept for backward compatibility, token should be used instead
+	RefreshToken string `form:"refresh_token" json:"refresh_token"`
+}
+
+// RevokeTokenHandler handler to revoke access token & refresh token (RFC 7009)
+// POST /oauth/revoke
+// Confidential clients authenticate with client secret or client assertion,
+// public clients are identified by client id and can only revoke tokens issued to them
+func RevokeTokenHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		var reqBody RevokeRequestBody
+		if err := gc.Bind(&reqBody); err != nil {
+			log.Debug("Error binding request: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": err.Error(),
+			})
+			return
+		}
+
+		clientID := strings.TrimSpace(reqBody.ClientID)
+		clientSecret := strings.TrimSpace(reqBody.ClientSecret)
+		clientAssertion := strings.TrimSpace(reqBody.ClientAssertion)
+		// check if clientID & clientSecret are present as part of
+		// authorization header with basic auth
+		if clientID == "" && clientSecret == "" {
+			if basicClientID, basicClientSecret, ok := gc.Request.BasicAuth(); ok {
+				clientID, clientSecret = basicClientID, basicClientSecret
+			}
+		}
+		// client_id is optional with client assertion as client is identified by sub claim
+		if clientID == "" && clientAssertion != "" {
+			clientID, _ = token.GetClientAssertionSubject(clientAssertion)
+		}
+		// kept for backward compatibility
+		if clientID == "" {
+			clientID = gc.Request.Header.Get("x-authorizer-client-id")
+		}
+		if clientID == "" {
+			log.Debug("Client ID is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The client id is required",
+			})
+			return
+		}
+
+		client, err := utils.GetClientByClientID(gc, clientID)
+		if err != nil || client == nil {
+			log.Debug("Client ID is invalid: ", clientID)
+			gc.Header("WWW-Authenticate", `Basic realm="authorizer"`)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client authentication failed",
+			})
+			return
+		}
+
+		// confidential clients should always authenticate, public clients are identified by client id.
+		// Default client is the first party client used by browser apps,
+		// hence it authenticates only when credentials are sent
+		isAuthenticationRequired := clientSecret != "" || clientAssertion != "" || !utils.IsDefaultClientID(client.ClientID)
+		if isAuthenticationRequired && authenticateClient(gc, client, clientSecret, clientAssertion, reqBody.ClientAssertionType) != nil {
+			log.Debug("Invalid client credentials: ", clientID)
+			gc.Header("WWW-Authenticate", `Basic realm="authorizer"`)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client authentication failed",
+			})
+			return
+		}
+
+		tokenString := strings.TrimSpace(reqBody.Token)
+		if tokenString == "" {
+			tokenString = strings.TrimSpace(reqBody.RefreshToken)
+		}
+		if tokenString == "" {
+			log.Debug("Token is empty")
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_request",
+				"error_description": "The token is required",
+			})
+			return
+		}
+
+		// token_type_hint is not required for lookup as type of token is part of its claims
+		if err := token.RevokeToken(tokenString, client.ClientID); err != nil {
+			if errors.Is(err, token.ErrTokenClientMismatch) {
+				log.Debug("Token was not issued to client: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "unauthorized_client",
+					"error_description": "The token was not issued to the client",
+				})
+				return
+			}
+			if errors.Is(err, token.ErrUnsupportedTokenType) {
+				log.Debug("Unsupported token type for revocation")
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "unsupported_token_type",
+					"error_description": "Only access token and refresh token can be revoked",
+				})
+				return
+			}
+			// as per RFC 7009 invalid tokens do not cause an error response,
+			// as client cannot handle such an error in a reasonable way
+			log.Debug("Failed to revoke token: ", err)
+		}
+
+		gc.JSON(http.StatusOK, gin.H{
+			"message": "Token revoked successfully",
+		})
+	}
+}
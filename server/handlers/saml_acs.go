@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/saml"
+)
+
+// SAMLACSHandler is the assertion consumer service for upstream saml identity providers.
+// Assertion is accepted only in response to authn request sent via oauth_login,
+// user is then signed up or logged in same as the users of oauth providers
+func SAMLACSHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider := ctx.Param("saml_provider")
+		relayState := ctx.Request.FormValue("RelayState")
+		samlState, err := memorystore.Provider.GetState(relayState)
+		if relayState == "" || samlState == "" || err != nil {
+			log.Debug("Invalid saml relay state: ", relayState)
+			ctx.JSON(400, gin.H{"error": "invalid saml relay state"})
+			return
+		}
+		// remove state from store
+		go memorystore.Provider.RemoveState(relayState)
+		// contains authn request id, random token, redirect url, role & scope
+		stateSplit := strings.Split(samlState, "___")
+		if len(stateSplit) < 5 {
+			log.Debug("Unable to get redirect url from state: ", samlState)
+			ctx.JSON(400, gin.H{"error": "invalid redirect url"})
+			return
+		}
+		authnRequestID := stateSplit[0]
+		stateValue := stateSplit[1]
+		redirectURL := stateSplit[2]
+		inputRoles := strings.Split(stateSplit[3], ",")
+		scopes := []string{}
+		if stateSplit[4] != "" {
+			scopes = strings.Split(stateSplit[4], " ")
+		}
+
+		samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(ctx, provider)
+		if err != nil || samlIdentityProvider == nil {
+			log.Debug("Invalid saml provider: ", provider)
+			ctx.JSON(400, gin.H{"error": "invalid saml provider"})
+			return
+		}
+		serviceProvider, err := saml.GetServiceProvider(samlIdentityProvider, parsers.GetHost(ctx))
+		if err != nil {
+			log.Debug("Failed to get saml service provider: ", err)
+			ctx.JSON(500, gin.H{"error": "internal server error"})
+			return
+		}
+		// signature, audience, recipient & validity of assertion are verified
+		assertion, err := serviceProvider.ParseResponse(ctx.Request, []string{authnRequestID})
+		if err != nil {
+			if invalidResponseErr, ok := err.(*gosaml.InvalidResponseError); ok {
+				err = invalidResponseErr.PrivateErr
+			}
+			log.Debug("Invalid saml response: ", err)
+			ctx.JSON(400, gin.H{"error": "invalid saml response"})
+			return
+		}
+		user := saml.GetSAMLIdentityProviderUser(samlIdentityProvider, assertion)
+		if user.Email == nil {
+			log.Debug("Email not found in saml assertion")
+			ctx.JSON(400, gin.H{"error": "email not found in saml assertion"})
+			return
+		}
+		// roles mapped from attributes of identity provider are used instead of requested roles
+		if user.Roles != "" {
+			inputRoles = strings.Split(user.Roles, ",")
+		}
+		// transient name id changes on every login, hence it can not identify the user
+		var identity *models.Identity
+		if assertion.Subject != nil && assertion.Subject.NameID != nil && assertion.Subject.NameID.Format != string(gosaml.TransientNameIDFormat) {
+			identity = newUpstreamIdentity(provider, strings.TrimSpace(assertion.Subject.NameID.Value), user.Email, saml.GetSAMLAssertionAttributes(assertion))
+		}
+		// identity is linked to logged in user when oauth_login is initiated with link token
+		if len(stateSplit) > 5 {
+			processUpstreamIdentityLink(ctx, stateSplit[5], provider, identity, redirectURL)
+			return
+		}
+		// enterprise identity provider can assert any email, hence existing users are matched by email only for its domains
+		processUpstreamUserLogin(ctx, provider, user, identity, inputRoles, scopes, stateValue, redirectURL, samlIdentityProvider.IsEmailMatchAllowed(refs.StringValue(user.Email)))
+	}
+}
+
+// processSAMLIdentityProviderLogin sends authn request to saml identity provider, redirect binding is preferred.
+// Relay state refers the stored oauth state as relay state is limited to 80 bytes
+func processSAMLIdentityProviderLogin(c *gin.Context, samlIdentityProvider *models.SAMLIdentityProvider, oauthStateString string) {
+	serviceProvider, err := saml.GetServiceProvider(samlIdentityProvider, parsers.GetHost(c))
+	if err != nil {
+		log.Debug("Failed to get saml service provider: ", err)
+		c.JSON(500, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+	binding := gosaml.HTTPRedirectBinding
+	ssoURL := serviceProvider.GetSSOBindingLocation(binding)
+	if ssoURL == "" {
+		binding = gosaml.HTTPPostBinding
+		ssoURL = serviceProvider.GetSSOBindingLocation(binding)
+	}
+	authnRequest, err := serviceProvider.MakeAuthenticationRequest(ssoURL, binding, gosaml.HTTPPostBinding)
+	if err != nil {
+		log.Debug("Failed to make saml authn request: ", err)
+		c.JSON(500, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+	relayState := uuid.New().String()
+	err = memorystore.Provider.SetState(relayState, authnRequest.ID+"___"+oauthStateString)
+	if err != nil {
+		log.Debug("Error setting state: ", err)
+		c.JSON(500, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+	if binding == gosaml.HTTPPostBinding {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", authnRequest.Post(relayState))
+		return
+	}
+	url, err := authnRequest.Redirect(relayState, serviceProvider)
+	if err != nil {
+		log.Debug("Failed to make saml authn request url: ", err)
+		c.JSON(500, gin.H{
+			"error": "internal server error",
+		})
+		return
+	}
+	c.Redirect(http.StatusTemporaryRedirect, url.String())
+}
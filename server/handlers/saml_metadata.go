@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/saml"
+)
+
+// SAMLMetadataHandler publishes the saml identity provider metadata,
+// service providers use it for sso url & certificate used for signing assertions
+func SAMLMetadataHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		identityProvider, err := saml.GetIdentityProvider(parsers.GetHost(gc))
+		if err != nil {
+			log.Debug("Failed to get saml identity provider: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get saml identity provider"})
+			return
+		}
+		identityProvider.ServeMetadata(gc.Writer, gc.Request)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/saml"
+)
+
+// SAMLServiceProviderMetadataHandler publishes the saml service provider metadata for upstream saml identity provider,
+// it has the entity id, acs url & certificate required for configuring authorizer in identity provider
+func SAMLServiceProviderMetadataHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		provider := gc.Param("saml_provider")
+		samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(gc, provider)
+		if err != nil || samlIdentityProvider == nil {
+			log.Debug("Invalid saml provider: ", provider)
+			gc.JSON(http.StatusNotFound, gin.H{"error": "invalid saml provider"})
+			return
+		}
+		metadata, err := saml.GetServiceProviderMetadata(samlIdentityProvider, parsers.GetHost(gc))
+		if err != nil {
+			log.Debug("Failed to get saml service provider metadata: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get saml service provider metadata"})
+			return
+		}
+		buf, err := xml.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			log.Debug("Failed to marshal saml service provider metadata: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get saml service provider metadata"})
+			return
+		}
+		gc.Data(http.StatusOK, "application/samlmetadata+xml", buf)
+	}
+}
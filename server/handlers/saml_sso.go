@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/cookie"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/saml"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+// SAMLSSOHandler handles SP initiated saml authentication requests of redirect & post bindings.
+// Signed assertion is sent to acs url of service provider for the user of browser session,
+// if session is not present user is redirected to login page and request is resumed after login
+func SAMLSSOHandler() gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		hostname := parsers.GetHost(gc)
+		identityProvider, err := saml.GetIdentityProvider(hostname)
+		if err != nil {
+			log.Debug("Failed to get saml identity provider: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get saml identity provider"})
+			return
+		}
+
+		var req *gosaml.IdpAuthnRequest
+		authnRequestID := gc.Query("authn_request")
+		if authnRequestID != "" {
+			authnRequest, err := saml.ConsumeAuthnRequest(authnRequestID)
+			if err != nil {
+				log.Debug("Failed to consume saml authn request: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			// request is validated against the time it was received, as user might take a while to login
+			req = &gosaml.IdpAuthnRequest{
+				IDP:           identityProvider,
+				HTTPRequest:   gc.Request,
+				RequestBuffer: authnRequest.Request,
+				RelayState:    authnRequest.RelayState,
+				Now:           time.Unix(authnRequest.ReceivedAt, 0),
+			}
+		} else {
+			req, err = gosaml.NewIdpAuthnRequest(identityProvider, gc.Request)
+			if err != nil {
+				log.Debug("Failed to parse saml authn request: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid saml request"})
+				return
+			}
+		}
+		if err := req.Validate(); err != nil {
+			log.Debug("Failed to validate saml authn request: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid saml request"})
+			return
+		}
+		receivedAt := req.Now
+		if authnRequestID != "" {
+			// validity of assertion starts when request is resumed
+			req.Now = gosaml.TimeNow()
+			req.Request.IssueInstant = req.Now
+		}
+
+		handleLoginRequired := func() {
+			authnRequest, err := saml.SetAuthnRequest(req)
+			if err != nil {
+				log.Debug("Failed to set saml authn request: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save saml request"})
+				return
+			}
+			resumeURI := hostname + "/saml/sso?authn_request=" + url.QueryEscape(authnRequest.ID)
+			gc.Redirect(http.StatusFound, baseAppPath+"?state="+uuid.New().String()+"&redirect_uri="+url.QueryEscape(resumeURI))
+		}
+		sessionToken, err := cookie.GetSession(gc)
+		if err != nil {
+			log.Debug("GetSession failed: ", err)
+			handleLoginRequired()
+			return
+		}
+		sessionData, err := token.ValidateBrowserSession(gc, sessionToken)
+		if err != nil {
+			log.Debug("ValidateBrowserSession failed: ", err)
+			handleLoginRequired()
+			return
+		}
+		// ForceAuthn requires user to login after the request was received,
+		// hence current session is ended so that login page is shown
+		if req.Request.ForceAuthn != nil && *req.Request.ForceAuthn && sessionData.IssuedAt < receivedAt.Unix() {
+			log.Debug("re-authentication is required by saml service provider")
+			sessionKey := sessionData.Subject
+			if sessionData.LoginMethod != "" {
+				sessionKey = sessionData.LoginMethod + ":" + sessionData.Subject
+			}
+			go memorystore.Provider.DeleteUserSession(sessionKey, sessionData.Nonce)
+			cookie.DeleteSession(gc)
+			handleLoginRequired()
+			return
+		}
+
+		user, err := db.Provider.GetUserByID(gc, sessionData.Subject)
+		if err != nil {
+			log.Debug("Failed to get user: ", err)
+			gc.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByEntityID(gc, req.ServiceProviderMetadata.EntityID)
+		if err != nil {
+			log.Debug("Failed to get saml service provider: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{"error": "invalid saml request"})
+			return
+		}
+		if err := (gosaml.DefaultAssertionMaker{}).MakeAssertion(req, saml.NewSession(user, samlServiceProvider, sessionData)); err != nil {
+			log.Debug("Failed to make saml assertion: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create saml assertion"})
+			return
+		}
+		// response is sent to acs url via auto submitted form
+		gc.Header("Content-Type", "text/html; charset=utf-8")
+		if err := req.WriteResponse(gc.Writer); err != nil {
+			log.Debug("Failed to write saml response: ", err)
+			gc.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create saml response"})
+			return
+		}
+	}
+}
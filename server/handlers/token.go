@@ -15,9 +15,16 @@ import (
 	"github.com/authorizerdev/authorizer/server/cookie"
 	"github.com/authorizerdev/authorizer/server/db"
 	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
 	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
 )
 
+// userScopes are the scopes which require user to be present
+// and are not allowed with client_credentials grant
+var userScopes = []string{"openid", "profile", "email", "phone", "address", "offline_access"}
+
 type RequestBody struct {
 	CodeVerifier string `form:"code_verifier" json:"code_verifier"`
 	Code         string `form:"code" json:"code"`
@@ -26,6 +33,18 @@ type RequestBody struct {
 	GrantType    string `form:"grant_type" json:"grant_type"`
 	RefreshToken string `form:"refresh_token" json:"refresh_token"`
 	RedirectURI  string `form:"redirect_uri" json:"redirect_uri"`
+	Scope        string `form:"scope" json:"scope"`
+	DeviceCode   string `form:"device_code" json:"device_code"`
+	// token exchange params as per RFC 8693
+	SubjectToken       string `form:"subject_token" json:"subject_token"`
+	SubjectTokenType   string `form:"subject_token_type" json:"subject_token_type"`
+	ActorToken         string `form:"actor_token" json:"actor_token"`
+	ActorTokenType     string `form:"actor_token_type" json:"actor_token_type"`
+	Audience           string `form:"audience" json:"audience"`
+	RequestedTokenType string `form:"requested_token_type" json:"requested_token_type"`
+	// client assertion params for private_key_jwt client authentication as per RFC 7523
+	ClientAssertion     string `form:"client_assertion" json:"client_assertion"`
+	ClientAssertionType string `form:"client_assertion_type" json:"client_assertion_type"`
 }
 
 // TokenHandler to handle /oauth/token requests
@@ -48,20 +67,50 @@ func TokenHandler() gin.HandlerFunc {
 		grantType := strings.TrimSpace(reqBody.GrantType)
 		refreshToken := strings.TrimSpace(reqBody.RefreshToken)
 		clientSecret := strings.TrimSpace(reqBody.ClientSecret)
+		clientAssertion := strings.TrimSpace(reqBody.ClientAssertion)
 
 		if grantType == "" {
-			grantType = "authorization_code"
+			grantType = constants.GrantTypeAuthorizationCode
 		}
 
-		isRefreshTokenGrant := grantType == "refresh_token"
-		isAuthorizationCodeGrant := grantType == "authorization_code"
+		isRefreshTokenGrant := grantType == constants.GrantTypeRefreshToken
+		isAuthorizationCodeGrant := grantType == constants.GrantTypeAuthorizationCode
+		isClientCredentialsGrant := grantType == constants.GrantTypeClientCredentials
+		isDeviceCodeGrant := grantType == constants.GrantTypeDeviceCode
+		isTokenExchangeGrant := grantType == constants.GrantTypeTokenExchange
 
-		if !isRefreshTokenGrant && !isAuthorizationCodeGrant {
+		if !isRefreshTokenGrant && !isAuthorizationCodeGrant && !isClientCredentialsGrant && !isDeviceCodeGrant && !isTokenExchangeGrant {
 			log.Debug("Invalid grant type: ", grantType)
 			gc.JSON(http.StatusBadRequest, gin.H{
 				"error":             "invalid_grant_type",
 				"error_description": "grant_type is invalid",
 			})
+			return
+		}
+
+		if clientAssertion != "" {
+			if reqBody.ClientAssertionType != constants.ClientAssertionTypeJWTBearer {
+				log.Debug("Invalid client assertion type: ", reqBody.ClientAssertionType)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The client_assertion_type must be " + constants.ClientAssertionTypeJWTBearer,
+				})
+				return
+			}
+			// client should use only one authentication method
+			_, _, hasBasicAuth := gc.Request.BasicAuth()
+			if clientSecret != "" || hasBasicAuth {
+				log.Debug("Client secret is sent with client assertion")
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_request",
+					"error_description": "The client secret cannot be used with client assertion",
+				})
+				return
+			}
+			// client_id is optional with client assertion as client is identified by sub claim
+			if clientID == "" {
+				clientID, _ = token.GetClientAssertionSubject(clientAssertion)
+			}
 		}
 
 		// check if clientID & clientSecret are present as part of
@@ -79,7 +128,8 @@ func TokenHandler() gin.HandlerFunc {
 			return
 		}
 
-		if client, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID); clientID != client || err != nil {
+		client, err := utils.GetClientByClientID(gc, clientID)
+		if err != nil || client == nil {
 			log.Debug("Client ID is invalid: ", clientID)
 			gc.JSON(http.StatusBadRequest, gin.H{
 				"error":             "invalid_client_id",
@@ -88,10 +138,127 @@ func TokenHandler() gin.HandlerFunc {
 			return
 		}
 
+		if !validators.IsValidClientGrantType(client, grantType) {
+			log.Debug("Grant type not allowed for client: ", grantType)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "unauthorized_client",
+				"error_description": "The grant type is not allowed for client",
+			})
+			return
+		}
+
+		// isClientAssertionValid is set when client is authenticated with client assertion,
+		// clients registered with private_key_jwt or client_secret_jwt cannot authenticate with client secret
+		isClientAssertionValid := false
+		if clientAssertion != "" {
+			if err := token.ValidateClientAssertion(gc, client, clientAssertion); err != nil {
+				log.Debug("Invalid client assertion: ", err)
+				gc.JSON(http.StatusUnauthorized, gin.H{
+					"error":             "invalid_client",
+					"error_description": err.Error(),
+				})
+				return
+			}
+			isClientAssertionValid = true
+		} else if client.IsClientAssertionRequired() {
+			log.Debug("Client assertion is required for client: ", clientID)
+			gc.JSON(http.StatusUnauthorized, gin.H{
+				"error":             "invalid_client",
+				"error_description": "The client assertion is required",
+			})
+			return
+		}
+
+		// tokens are bound to the key of DPoP proof when it is sent (RFC 9449)
+		dpopJKT := ""
+		tokenType := "Bearer"
+		if dpopProof := gc.GetHeader(token.DPoPHeader); dpopProof != "" {
+			dpopJKT, err = token.ValidateDPoPProof(gc, dpopProof, "")
+			if err != nil {
+				log.Debug("Invalid DPoP proof: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_dpop_proof",
+					"error_description": err.Error(),
+				})
+				return
+			}
+			tokenType = token.DPoPTokenType
+		}
+
+		if isTokenExchangeGrant {
+			isClientAuthenticated := isClientAssertionValid || validators.IsValidClientSecret(client, clientSecret)
+			handleTokenExchange(gc, client, isClientAuthenticated, reqBody, dpopJKT)
+			return
+		}
+
+		if isClientCredentialsGrant {
+			if !isClientAssertionValid && !validators.IsValidClientSecret(client, clientSecret) {
+				log.Debug("Client Secret is invalid: ", clientID)
+				gc.JSON(http.StatusUnauthorized, gin.H{
+					"error":             "invalid_client",
+					"error_description": "The client secret is invalid",
+				})
+				return
+			}
+
+			// user specific scopes cannot be granted as there is no user involved
+			scope := []string{}
+			for _, s := range strings.Fields(reqBody.Scope) {
+				if !utils.StringSliceContains(userScopes, s) {
+					scope = append(scope, s)
+				}
+			}
+			if !validators.IsValidClientScope(client, scope) {
+				log.Debug("Scope not allowed for client: ", scope)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_scope",
+					"error_description": "The requested scope is not allowed for client",
+				})
+				return
+			}
+
+			nonce := uuid.New().String()
+			accessToken, expiresAt, err := token.CreateClientAccessToken(client, scope, parsers.GetHost(gc), nonce, dpopJKT)
+			if err != nil {
+				log.Debug("Error creating client access token: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{
+					"error":             "server_error",
+					"error_description": "Failed to create access token",
+				})
+				return
+			}
+			memorystore.Provider.SetUserSession(constants.GrantTypeClientCredentials+":"+clientID, constants.TokenTypeAccessToken+"_"+nonce, accessToken, expiresAt)
+
+			expiresIn := expiresAt - time.Now().Unix()
+			if expiresIn <= 0 {
+				expiresIn = 1
+			}
+			gc.JSON(http.StatusOK, gin.H{
+				"access_token": accessToken,
+				"token_type":   tokenType,
+				"scope":        strings.Join(scope, " "),
+				"expires_in":   expiresIn,
+			})
+			return
+		}
+
 		var userID string
 		var roles, scope []string
 		loginMethod := ""
 		sessionKey := ""
+		// authTime is the time when user was authenticated, 0 means user is authenticated now
+		var authTime int64
+		// sessionID is the id of session to which tokens are issued, empty means new session is created
+		sessionID := ""
+		// claimsRequest is the claims requested with authorization request, nil if claims were not requested
+		var claimsRequest *token.ClaimsRequest
+		// nonce of tokens to be issued, rotated refresh token is linked to it before tokens are issued
+		nonce := uuid.New().String() + "@@" + code
+		isRefreshTokenReuseDetectionDisabled, err := memorystore.Provider.GetBoolStoreEnvVariable(constants.EnvKeyDisableRefreshTokenReuseDetection)
+		if err != nil {
+			log.Debug("Error getting refresh token reuse detection env: ", err)
+			isRefreshTokenReuseDetectionDisabled = false
+		}
 
 		if isAuthorizationCodeGrant {
 			if code == "" {
@@ -103,10 +270,15 @@ func TokenHandler() gin.HandlerFunc {
 				return
 			}
 
-			if codeVerifier == "" && clientSecret == "" {
-				gc.JSON(http.StatusBadRequest, gin.H{
-					"error":             "invalid_data",
-					"error_description": "The code verifier or client secret is required",
+			// confidential clients should authenticate even when PKCE is used.
+			// Default client is the first party client used by browser apps via PKCE,
+			// hence it authenticates only when client secret is sent
+			isSecretRequired := !client.IsPublic() && (clientSecret != "" || !utils.IsDefaultClientID(client.ClientID))
+			if !isClientAssertionValid && isSecretRequired && !validators.IsValidClientSecret(client, clientSecret) {
+				log.Debug("Client Secret is invalid: ", clientID)
+				gc.JSON(http.StatusUnauthorized, gin.H{
+					"error":             "invalid_client",
+					"error_description": "The client secret is invalid",
 				})
 				return
 			}
@@ -126,30 +298,34 @@ func TokenHandler() gin.HandlerFunc {
 			sessionDataSplit := strings.Split(sessionData, "@@")
 
 			go memorystore.Provider.RemoveState(code)
+			claimsRequest = token.ConsumeClaimsRequest(code)
+
+			// code can be exchanged only by the client to which it was issued,
+			// with the redirect uri of authorization request
+			codeClient, err := token.ConsumeAuthorizationCodeClient(code)
+			if err != nil || codeClient.ClientID != client.ClientID || (codeClient.RedirectURI != "" && codeClient.RedirectURI != strings.TrimSpace(reqBody.RedirectURI)) {
+				log.Debug("Code is not issued to client: ", clientID)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_grant",
+					"error_description": "The code was not issued to the client or redirect uri",
+				})
+				return
+			}
 
-			if codeVerifier != "" {
+			// code verifier is required for codes issued with code challenge
+			if sessionDataSplit[0] != "" || codeVerifier != "" {
 				hash := sha256.New()
 				hash.Write([]byte(codeVerifier))
 				encryptedCode := strings.ReplaceAll(base64.RawURLEncoding.EncodeToString(hash.Sum(nil)), "+", "-")
 				encryptedCode = strings.ReplaceAll(encryptedCode, "/", "_")
 				encryptedCode = strings.ReplaceAll(encryptedCode, "=", "")
-				if encryptedCode != sessionDataSplit[0] {
+				if codeVerifier == "" || encryptedCode != sessionDataSplit[0] {
 					gc.JSON(http.StatusBadRequest, gin.H{
 						"error":             "invalid_code_verifier",
 						"error_description": "The code verifier is invalid",
 					})
 					return
 				}
-
-			} else {
-				if clientHash, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientSecret); clientSecret != clientHash || err != nil {
-					log.Debug("Client Secret is invalid: ", clientID)
-					gc.JSON(http.StatusBadRequest, gin.H{
-						"error":             "invalid_client_secret",
-						"error_description": "The client secret is invalid",
-					})
-					return
-				}
 			}
 
 			// validate session
@@ -167,6 +343,8 @@ func TokenHandler() gin.HandlerFunc {
 			roles = claims.Roles
 			scope = claims.Scope
 			loginMethod = claims.LoginMethod
+			authTime = claims.IssuedAt
+			sessionID = claims.GetSessionID()
 
 			// rollover the session for security
 			sessionKey = userID
@@ -174,8 +352,84 @@ func TokenHandler() gin.HandlerFunc {
 				sessionKey = loginMethod + ":" + userID
 			}
 
-			go memorystore.Provider.DeleteUserSession(sessionKey, claims.Nonce)
+			memorystore.Provider.DeleteUserSession(sessionKey, claims.Nonce)
+
+		} else if isDeviceCodeGrant {
+			// device clients are usually public clients,
+			// confidential clients should authenticate with client secret or client assertion
+			if !isClientAssertionValid && (clientSecret != "" || !client.IsPublic()) && !validators.IsValidClientSecret(client, clientSecret) {
+				log.Debug("Client Secret is invalid: ", clientID)
+				gc.JSON(http.StatusUnauthorized, gin.H{
+					"error":             "invalid_client",
+					"error_description": "The client secret is invalid",
+				})
+				return
+			}
+
+			deviceAuthorization, err := token.GetDeviceAuthorization(strings.TrimSpace(reqBody.DeviceCode))
+			if err != nil || deviceAuthorization.ClientID != client.ClientID {
+				log.Debug("Device code is invalid: ", err)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_grant",
+					"error_description": "The device code is invalid",
+				})
+				return
+			}
+
+			if deviceAuthorization.IsExpired() {
+				log.Debug("Device code is expired")
+				token.RemoveDeviceAuthorization(deviceAuthorization)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "expired_token",
+					"error_description": "The device code has expired",
+				})
+				return
+			}
+
+			switch deviceAuthorization.Status {
+			case token.DeviceAuthorizationStatusDenied:
+				log.Debug("Device authorization is denied")
+				token.RemoveDeviceAuthorization(deviceAuthorization)
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "access_denied",
+					"error_description": "The device authorization was denied",
+				})
+				return
+			case token.DeviceAuthorizationStatusPending:
+				now := time.Now().Unix()
+				isSlowDown := now-deviceAuthorization.LastPolledAt < deviceAuthorization.Interval
+				if isSlowDown {
+					// as per RFC 8628 interval should be increased by 5 seconds
+					deviceAuthorization.Interval += token.DeviceCodePollingInterval
+				}
+				deviceAuthorization.LastPolledAt = now
+				token.SetDeviceAuthorization(deviceAuthorization)
+				if isSlowDown {
+					log.Debug("Device is polling too frequently")
+					gc.JSON(http.StatusBadRequest, gin.H{
+						"error":             "slow_down",
+						"error_description": "The device is polling too frequently",
+						"interval":          deviceAuthorization.Interval,
+					})
+					return
+				}
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "authorization_pending",
+					"error_description": "The device authorization is pending",
+				})
+				return
+			}
+
+			// device code can be exchanged only once
+			token.RemoveDeviceAuthorization(deviceAuthorization)
 
+			userID = deviceAuthorization.UserID
+			scope = deviceAuthorization.Scope
+			loginMethod = deviceAuthorization.LoginMethod
+			sessionKey = userID
+			if loginMethod != "" {
+				sessionKey = loginMethod + ":" + userID
+			}
 		} else {
 			// validate refresh token
 			if refreshToken == "" {
@@ -190,12 +444,33 @@ func TokenHandler() gin.HandlerFunc {
 			claims, err := token.ValidateRefreshToken(gc, refreshToken)
 			if err != nil {
 				log.Debug("Error validating refresh token: ", err)
+				if !isRefreshTokenReuseDetectionDisabled {
+					detectRefreshTokenReuse(gc, refreshToken)
+				}
 				gc.JSON(http.StatusUnauthorized, gin.H{
 					"error":             "unauthorized",
 					"error_description": err.Error(),
 				})
 				return
 			}
+			// refresh token can only be used by the client it was issued to
+			if claims["aud"] != client.ClientID {
+				log.Debug("Refresh token was not issued to client: ", clientID)
+				gc.JSON(http.StatusUnauthorized, gin.H{
+					"error":             "unauthorized",
+					"error_description": "The refresh token was not issued to client",
+				})
+				return
+			}
+			// refresh token bound to DPoP key can only be used with proof of same key
+			if jkt := token.GetDPoPJKT(claims); jkt != "" && jkt != dpopJKT {
+				log.Debug("DPoP proof does not match refresh token")
+				gc.JSON(http.StatusBadRequest, gin.H{
+					"error":             "invalid_dpop_proof",
+					"error_description": "The DPoP proof does not match the key to which refresh token is bound",
+				})
+				return
+			}
 			userID = claims["sub"].(string)
 			claimLoginMethod := claims["login_method"]
 			rolesInterface := claims["roles"].([]interface{})
@@ -206,6 +481,14 @@ func TokenHandler() gin.HandlerFunc {
 			for _, v := range scopeInterface {
 				scope = append(scope, v.(string))
 			}
+			if claimAuthTime, ok := claims["auth_time"].(float64); ok {
+				authTime = int64(claimAuthTime)
+			}
+			// refresh tokens issued before session id was introduced are identified by nonce
+			sessionID, _ = claims["sid"].(string)
+			if sessionID == "" {
+				sessionID, _ = claims["nonce"].(string)
+			}
 
 			sessionKey = userID
 			if claimLoginMethod != nil && claimLoginMethod != "" {
@@ -213,8 +496,27 @@ func TokenHandler() gin.HandlerFunc {
 				loginMethod = claimLoginMethod.(string)
 			}
 
-			// remove older refresh token and rotate it for security
-			go memorystore.Provider.DeleteUserSession(sessionKey, claims["nonce"].(string))
+			// remove older refresh token and rotate it for security.
+			// Rotated refresh token is marked before issuing new tokens,
+			// so that concurrent request with same refresh token is detected as reuse
+			claimsRequest = token.GetClaimsRequest(claims)
+			rotatedRefreshTokenNonce := claims["nonce"].(string)
+			if !isRefreshTokenReuseDetectionDisabled {
+				if token.IsRefreshTokenRotated(sessionKey, rotatedRefreshTokenNonce) {
+					log.Debug("Refresh token is already rotated")
+					detectRefreshTokenReuse(gc, refreshToken)
+					gc.JSON(http.StatusUnauthorized, gin.H{
+						"error":             "unauthorized",
+						"error_description": "The refresh token is already used",
+					})
+					return
+				}
+				rotatedRefreshTokenExpiresAt, _ := claims["exp"].(int64)
+				if err := token.SetRotatedRefreshToken(sessionKey, rotatedRefreshTokenNonce, nonce, rotatedRefreshTokenExpiresAt); err != nil {
+					log.Debug("Error setting rotated refresh token: ", err)
+				}
+			}
+			memorystore.Provider.DeleteUserSession(sessionKey, rotatedRefreshTokenNonce)
 		}
 
 		if sessionKey == "" {
@@ -236,8 +538,13 @@ func TokenHandler() gin.HandlerFunc {
 			return
 		}
 
-		nonce := uuid.New().String() + "@@" + code
-		authToken, err := token.CreateAuthToken(gc, user, roles, scope, loginMethod, nonce, code)
+		// roles are not part of device authorization request,
+		// hence user roles are granted
+		if isDeviceCodeGrant {
+			roles = strings.Split(user.Roles, ",")
+		}
+
+		authToken, err := token.CreateAuthTokenForClient(gc, client, user, roles, scope, loginMethod, nonce, code, authTime, sessionID, dpopJKT, claimsRequest)
 		if err != nil {
 			log.Debug("Error creating auth token: ", err)
 			gc.JSON(http.StatusUnauthorized, gin.H{
@@ -259,6 +566,7 @@ func TokenHandler() gin.HandlerFunc {
 		res := map[string]interface{}{
 			"access_token": authToken.AccessToken.Token,
 			"id_token":     authToken.IDToken.Token,
+			"token_type":   tokenType,
 			"scope":        strings.Join(scope, " "),
 			"roles":        roles,
 			"expires_in":   expiresIn,
@@ -270,3 +578,34 @@ func TokenHandler() gin.HandlerFunc {
 		gc.JSON(http.StatusOK, res)
 	}
 }
+
+// detectRefreshTokenReuse revokes the refresh token family, clears the session cookie
+// and triggers webhook event if the given refresh token was already rotated
+func detectRefreshTokenReuse(gc *gin.Context, refreshToken string) {
+	claims, err := token.ParseJWTToken(refreshToken)
+	if err != nil || claims["token_type"] != constants.TokenTypeRefreshToken {
+		return
+	}
+	subject, _ := claims["sub"].(string)
+	nonce, _ := claims["nonce"].(string)
+	if subject == "" || nonce == "" {
+		return
+	}
+	userID := token.GetUserIDFromSubject(subject)
+	loginMethod, _ := claims["login_method"].(string)
+	sessionKey := userID
+	if loginMethod != "" {
+		sessionKey = loginMethod + ":" + userID
+	}
+	if !token.RevokeRefreshTokenFamily(sessionKey, nonce) {
+		return
+	}
+	cookie.DeleteSession(gc)
+	log.Warn("Rotated refresh token reused, revoked refresh token family for user: ", userID)
+	user, err := db.Provider.GetUserByID(gc, userID)
+	if err != nil {
+		log.Debug("Error getting user: ", err)
+		return
+	}
+	go utils.RegisterEvent(gc, constants.UserRefreshTokenReusedWebhookEvent, loginMethod, user)
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// handleTokenExchange handles token exchange grant (RFC 8693)
+// subject_token = access token of user on whose behalf token is requested
+// actor_token = access token of user / client acting on behalf of subject, it is set as act claim
+// audience = target service of token, it should be allowed by token exchange audiences of client
+// scope = requested scopes, it should be subset of scopes of subject token
+// isClientAuthenticated is set when client is authenticated with client secret or client assertion
+// dpopJKT is the thumbprint of DPoP key to which issued token is bound
+func handleTokenExchange(gc *gin.Context, client *models.Client, isClientAuthenticated bool, reqBody RequestBody, dpopJKT string) {
+	if !isClientAuthenticated {
+		log.Debug("Client authentication failed: ", client.ClientID)
+		gc.JSON(http.StatusUnauthorized, gin.H{
+			"error":             "invalid_client",
+			"error_description": "The client authentication failed",
+		})
+		return
+	}
+
+	subjectToken := strings.TrimSpace(reqBody.SubjectToken)
+	actorToken := strings.TrimSpace(reqBody.ActorToken)
+	if subjectToken == "" || reqBody.SubjectTokenType != constants.TokenTypeIdentifierAccessToken {
+		log.Debug("Invalid subject token type: ", reqBody.SubjectTokenType)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "The subject_token of type " + constants.TokenTypeIdentifierAccessToken + " is required",
+		})
+		return
+	}
+	if actorToken != "" && reqBody.ActorTokenType != constants.TokenTypeIdentifierAccessToken {
+		log.Debug("Invalid actor token type: ", reqBody.ActorTokenType)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "The actor_token_type must be " + constants.TokenTypeIdentifierAccessToken,
+		})
+		return
+	}
+	if reqBody.RequestedTokenType != "" && reqBody.RequestedTokenType != constants.TokenTypeIdentifierAccessToken {
+		log.Debug("Invalid requested token type: ", reqBody.RequestedTokenType)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_request",
+			"error_description": "Only " + constants.TokenTypeIdentifierAccessToken + " can be requested",
+		})
+		return
+	}
+
+	audience := strings.TrimSpace(reqBody.Audience)
+	if audience == "" {
+		audience = client.ClientID
+	}
+	if !validators.IsValidClientTokenExchangeAudience(client, audience) {
+		log.Debug("Audience not allowed for client: ", audience)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_target",
+			"error_description": "The audience is not allowed for client",
+		})
+		return
+	}
+
+	subjectClaims, err := token.ValidateAccessTokenWithoutDPoPProof(gc, subjectToken)
+	if err != nil {
+		log.Debug("Invalid subject token: ", err)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_grant",
+			"error_description": "The subject_token is invalid",
+		})
+		return
+	}
+
+	subjectScope := []string{}
+	if scopeList, ok := subjectClaims["scope"].([]interface{}); ok {
+		for _, s := range scopeList {
+			subjectScope = append(subjectScope, s.(string))
+		}
+	}
+	roles := []string{}
+	if roleList, ok := subjectClaims["roles"].([]interface{}); ok {
+		for _, r := range roleList {
+			roles = append(roles, r.(string))
+		}
+	}
+	// token can only be downscoped
+	scope := strings.Fields(reqBody.Scope)
+	if len(scope) == 0 {
+		scope = subjectScope
+	}
+	for _, s := range scope {
+		if !utils.StringSliceContains(subjectScope, s) {
+			log.Debug("Scope not granted to subject token: ", s)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_scope",
+				"error_description": "The requested scope exceeds the scope of subject_token",
+			})
+			return
+		}
+	}
+	if !validators.IsValidClientScope(client, scope) {
+		log.Debug("Scope not allowed for client: ", scope)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_scope",
+			"error_description": "The requested scope is not allowed for client",
+		})
+		return
+	}
+
+	// act claim of subject token is retained, so that chain of delegation is preserved
+	act, _ := subjectClaims["act"].(map[string]interface{})
+	if actorToken != "" {
+		actor := map[string]interface{}{}
+		if actorClaims, err := token.ValidateAccessTokenWithoutDPoPProof(gc, actorToken); err == nil {
+			// actor user is identified with subject of the client to which token is issued
+			actorUserID, _ := actorClaims["sub"].(string)
+			actorSubject, err := token.GetSubject(client, actorUserID)
+			if err != nil {
+				log.Debug("Failed to get actor subject: ", err)
+				gc.JSON(http.StatusInternalServerError, gin.H{
+					"error":             "server_error",
+					"error_description": "Failed to get actor subject",
+				})
+				return
+			}
+			actor["sub"] = actorSubject
+		} else if actorClaims, err := token.ValidateClientAccessToken(gc, actorToken); err == nil {
+			actor["sub"] = actorClaims["client_id"]
+		} else {
+			log.Debug("Invalid actor token: ", err)
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"error":             "invalid_grant",
+				"error_description": "The actor_token is invalid",
+			})
+			return
+		}
+		if act != nil {
+			actor["act"] = act
+		}
+		act = actor
+	}
+
+	userID := subjectClaims["sub"].(string)
+	user, err := db.Provider.GetUserByID(gc, userID)
+	if err != nil || user.RevokedTimestamp != nil {
+		log.Debug("User not found or revoked: ", userID)
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"error":             "invalid_grant",
+			"error_description": "The subject_token is invalid",
+		})
+		return
+	}
+	loginMethod, _ := subjectClaims["login_method"].(string)
+	sessionKey := userID
+	if loginMethod != "" {
+		sessionKey = loginMethod + ":" + userID
+	}
+
+	nonce := uuid.New().String()
+	accessToken, expiresAt, err := token.CreateExchangedAccessToken(client, user, roles, scope, audience, parsers.GetHost(gc), nonce, loginMethod, act, dpopJKT)
+	if err != nil {
+		log.Debug("Error creating exchanged access token: ", err)
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"error":             "server_error",
+			"error_description": "Failed to create access token",
+		})
+		return
+	}
+	// token is part of user session, so that it is revoked when user logs out
+	memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+nonce, accessToken, expiresAt)
+
+	expiresIn := expiresAt - time.Now().Unix()
+	if expiresIn <= 0 {
+		expiresIn = 1
+	}
+	tokenType := "Bearer"
+	if dpopJKT != "" {
+		tokenType = token.DPoPTokenType
+	}
+	gc.JSON(http.StatusOK, gin.H{
+		"access_token":      accessToken,
+		"issued_token_type": constants.TokenTypeIdentifierAccessToken,
+		"token_type":        tokenType,
+		"scope":             strings.Join(scope, " "),
+		"expires_in":        expiresIn,
+	})
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -38,27 +37,20 @@ func UserInfoHandler() gin.HandlerFunc {
 			})
 			return
 		}
-		apiUser := user.AsAPIUser()
-		userBytes, err := json.Marshal(apiUser)
-		if err != nil {
-			log.Debug("Error marshalling user: ", err)
-			gc.JSON(http.StatusUnauthorized, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
-		res := map[string]interface{}{}
-		err = json.Unmarshal(userBytes, &res)
-		if err != nil {
-			log.Debug("Error un-marshalling user: ", err)
-			gc.JSON(http.StatusUnauthorized, gin.H{
-				"error": err.Error(),
-			})
-			return
+		// user claims are released as per granted scopes
+		scope := []string{}
+		if scopeList, ok := claims["scope"].([]interface{}); ok {
+			for _, s := range scopeList {
+				if v, ok := s.(string); ok {
+					scope = append(scope, v)
+				}
+			}
 		}
+		res := token.GetUserClaims(user, scope)
 		// add sub field to user as per openid standards
 		// https://github.com/authorizerdev/authorizer/issues/327
-		res["sub"] = userID
+		// sub should be same as that of id token, which is pairwise for pairwise clients
+		res["sub"] = token.GetTokenSubject(gc, claims)
 		gc.JSON(http.StatusOK, res)
 	}
 }
@@ -53,7 +53,7 @@ func VerifyEmailHandler() gin.HandlerFunc {
 			return
 		}
 
-		if ok, err := token.ValidateJWTClaims(claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
+		if ok, err := token.ValidateJWTClaims(c, claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
 			log.Debug("Error validating jwt claims: ", err)
 			errorRes["error"] = err.Error()
 			utils.HandleRedirectORJsonResponse(c, http.StatusBadRequest, errorRes, generateRedirectURL(redirectURL, errorRes))
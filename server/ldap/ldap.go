@@ -0,0 +1,218 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// ErrInvalidCredentials is returned when user is not found in directory or password is not correct
+var ErrInvalidCredentials = errors.New("invalid ldap credentials")
+
+// timeout for connecting & each request to ldap server
+const timeout = 10 * time.Second
+
+// attributes of directory entry which are mapped to user
+var userAttributes = []string{"mail", "givenName", "sn", "memberOf"}
+
+// IsEnabled returns true when ldap server & search base are configured
+func IsEnabled() bool {
+	ldapURL, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPURL)
+	if err != nil || ldapURL == "" {
+		return false
+	}
+	searchBase, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPSearchBase)
+	if err != nil || searchBase == "" {
+		return false
+	}
+	return true
+}
+
+// Authenticate authenticates the user by ldap bind and returns the user with fields mapped from directory entry.
+// User is searched with LDAP_USER_FILTER using the service account, then dn of user is bound with password.
+// Roles of user are mapped from groups using LDAP_GROUP_ROLE_MAPPING
+func Authenticate(email, password string) (*models.User, error) {
+	if strings.TrimSpace(email) == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	searchBase, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPSearchBase)
+	if err != nil {
+		return nil, err
+	}
+	userFilter, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPUserFilter)
+	if err != nil || userFilter == "" {
+		userFilter = "(mail={email})"
+	}
+	bindDN, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPBindDN)
+	if err != nil {
+		return nil, err
+	}
+	bindPassword, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPBindPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if bindDN != "" {
+		err = conn.Bind(bindDN, bindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind ldap service account: %s", err.Error())
+	}
+
+	searchRes, err := conn.Search(goldap.NewSearchRequest(
+		searchBase,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		0,
+		int(timeout.Seconds()),
+		false,
+		strings.ReplaceAll(userFilter, "{email}", goldap.EscapeFilter(email)),
+		userAttributes,
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search ldap user: %s", err.Error())
+	}
+	// filter matching multiple entries is ambiguous, hence it is treated same as user not found
+	if len(searchRes.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := searchRes.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	mail := strings.TrimSpace(entry.GetAttributeValue("mail"))
+	if mail == "" {
+		return nil, fmt.Errorf("mail attribute of ldap user not found")
+	}
+	user := &models.User{
+		Email: refs.NewStringRef(mail),
+	}
+	if givenName := strings.TrimSpace(entry.GetAttributeValue("givenName")); givenName != "" {
+		user.GivenName = refs.NewStringRef(givenName)
+	}
+	if familyName := strings.TrimSpace(entry.GetAttributeValue("sn")); familyName != "" {
+		user.FamilyName = refs.NewStringRef(familyName)
+	}
+	roles, err := GetGroupRoles(entry.GetAttributeValues("memberOf"))
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = strings.Join(roles, ",")
+	return user, nil
+}
+
+// GetGroupRoles returns the roles mapped from group dns using LDAP_GROUP_ROLE_MAPPING,
+// group dns are compared case insensitively and only the roles configured for instance are returned
+func GetGroupRoles(groups []string) ([]string, error) {
+	groupRoleMappingString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPGroupRoleMapping)
+	if err != nil || groupRoleMappingString == "" {
+		return []string{}, nil
+	}
+	groupRoleMapping := map[string]string{}
+	if err := json.Unmarshal([]byte(groupRoleMappingString), &groupRoleMapping); err != nil {
+		return nil, fmt.Errorf("invalid ldap group role mapping: %s", err.Error())
+	}
+	rolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyRoles)
+	if err != nil {
+		rolesString = ""
+	}
+	allowedRoles := strings.Split(rolesString, ",")
+	roles := []string{}
+	for mappedGroup, role := range groupRoleMapping {
+		if !utils.StringSliceContains(allowedRoles, role) || utils.StringSliceContains(roles, role) {
+			continue
+		}
+		for _, group := range groups {
+			if isSameDN(mappedGroup, group) {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+	return roles, nil
+}
+
+// isSameDN compares the dns ignoring case & spaces between the attributes
+func isSameDN(a, b string) bool {
+	aDN, err := goldap.ParseDN(a)
+	if err != nil {
+		return strings.EqualFold(a, b)
+	}
+	bDN, err := goldap.ParseDN(b)
+	if err != nil {
+		return strings.EqualFold(a, b)
+	}
+	return aDN.EqualFold(bDN)
+}
+
+// dial connects to LDAP_URL, ldap:// connection is upgraded to TLS when LDAP_START_TLS is enabled.
+// Passwords are sent with bind, hence ldap:// is refused without LDAP_START_TLS
+func dial() (*goldap.Conn, error) {
+	ldapURL, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPURL)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(ldapURL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") {
+		return nil, fmt.Errorf("invalid ldap url")
+	}
+	isStartTLS, err := memorystore.Provider.GetBoolStoreEnvVariable(constants.EnvKeyLDAPStartTLS)
+	if err != nil {
+		isStartTLS = false
+	}
+	if u.Scheme == "ldap" && !isStartTLS {
+		return nil, fmt.Errorf("ldap:// url requires LDAP_START_TLS, use ldaps:// or enable LDAP_START_TLS")
+	}
+	tlsConfig := &tls.Config{
+		ServerName: u.Hostname(),
+		MinVersion: tls.VersionTLS12,
+	}
+	caCertificate, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPTLSCACertificate)
+	if err == nil && caCertificate != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(caCertificate)) {
+			return nil, fmt.Errorf("invalid ldap tls ca certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	conn, err := goldap.DialURL(ldapURL, goldap.DialWithDialer(&net.Dialer{Timeout: timeout}), goldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect ldap server: %s", err.Error())
+	}
+	conn.SetTimeout(timeout)
+	if u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls with ldap server: %s", err.Error())
+		}
+	}
+	return conn, nil
+}
@@ -25,22 +25,24 @@ func InitMemStore() error {
 		constants.EnvKeyOrganizationLogo: "https://www.authorizer.dev/images/logo.png",
 
 		// boolean envs
-		constants.EnvKeyDisableBasicAuthentication:       false,
-		constants.EnvKeyDisableMobileBasicAuthentication: false,
-		constants.EnvKeyDisableMagicLinkLogin:            false,
-		constants.EnvKeyDisableEmailVerification:         false,
-		constants.EnvKeyDisableLoginPage:                 false,
-		constants.EnvKeyDisableSignUp:                    false,
-		constants.EnvKeyDisableStrongPassword:            false,
-		constants.EnvKeyIsEmailServiceEnabled:            false,
-		constants.EnvKeyIsSMSServiceEnabled:              false,
-		constants.EnvKeyEnforceMultiFactorAuthentication: false,
-		constants.EnvKeyDisableMultiFactorAuthentication: false,
-		constants.EnvKeyDisableTOTPLogin:                 false,
-		constants.EnvKeyAppCookieSecure:                  true,
-		constants.EnvKeyAdminCookieSecure:                true,
-		constants.EnvKeyDisablePlayGround:                true,
-		constants.EnvKeyDisableMailOTPLogin:              true,
+		constants.EnvKeyDisableBasicAuthentication:        false,
+		constants.EnvKeyDisableMobileBasicAuthentication:  false,
+		constants.EnvKeyDisableMagicLinkLogin:             false,
+		constants.EnvKeyDisableEmailVerification:          false,
+		constants.EnvKeyDisableLoginPage:                  false,
+		constants.EnvKeyDisableSignUp:                     false,
+		constants.EnvKeyDisableStrongPassword:             false,
+		constants.EnvKeyIsEmailServiceEnabled:             false,
+		constants.EnvKeyIsSMSServiceEnabled:               false,
+		constants.EnvKeyEnforceMultiFactorAuthentication:  false,
+		constants.EnvKeyDisableMultiFactorAuthentication:  false,
+		constants.EnvKeyDisableTOTPLogin:                  false,
+		constants.EnvKeyAppCookieSecure:                   true,
+		constants.EnvKeyAdminCookieSecure:                 true,
+		constants.EnvKeyDisablePlayGround:                 true,
+		constants.EnvKeyDisableMailOTPLogin:               true,
+		constants.EnvKeyDisableRefreshTokenReuseDetection: false,
+		constants.EnvKeyLDAPStartTLS:                      false,
 	}
 
 	requiredEnvs := RequiredEnvStoreObj.GetRequiredEnv()
@@ -74,6 +74,12 @@ func (c *provider) SetState(key, state string) error {
 	return nil
 }
 
+// SetStateWithExpiration sets the state in the in-memory store till given expiration.
+func (c *provider) SetStateWithExpiration(key, state string, expiration int64) error {
+	c.stateStore.SetWithExpiration(key, state, expiration)
+	return nil
+}
+
 // GetState gets the state from the in-memory store.
 func (c *provider) GetState(key string) (string, error) {
 	return c.stateStore.Get(key), nil
@@ -2,19 +2,55 @@ package stores
 
 import (
 	"sync"
+	"time"
 )
 
 // StateStore struct to store the env variables
 type StateStore struct {
+	wg    sync.WaitGroup
 	mutex sync.Mutex
 	store map[string]string
+	// expiresAt stores expiry time of the keys set with expiration,
+	// expired keys are removed when they are accessed or periodically by clean
+	expiresAt map[string]int64
+	stop      chan struct{}
 }
 
 // NewStateStore create a new state store
 func NewStateStore() *StateStore {
-	return &StateStore{
-		mutex: sync.Mutex{},
-		store: make(map[string]string),
+	store := &StateStore{
+		mutex:     sync.Mutex{},
+		store:     make(map[string]string),
+		expiresAt: make(map[string]int64),
+		stop:      make(chan struct{}),
+	}
+	store.wg.Add(1)
+	go func() {
+		defer store.wg.Done()
+		store.clean()
+	}()
+	return store
+}
+
+// clean removes expired keys periodically, so that store does not grow with keys which are never accessed again
+func (s *StateStore) clean() {
+	t := time.NewTicker(clearInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			s.mutex.Lock()
+			currentTime := time.Now().Unix()
+			for k, expiresAt := range s.expiresAt {
+				if expiresAt <= currentTime {
+					delete(s.store, k)
+					delete(s.expiresAt, k)
+				}
+			}
+			s.mutex.Unlock()
+		}
 	}
 }
 
@@ -22,6 +58,11 @@ func NewStateStore() *StateStore {
 func (s *StateStore) Get(key string) string {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	if expiresAt, ok := s.expiresAt[key]; ok && expiresAt <= time.Now().Unix() {
+		delete(s.store, key)
+		delete(s.expiresAt, key)
+		return ""
+	}
 	return s.store[key]
 }
 
@@ -31,6 +72,16 @@ func (s *StateStore) Set(key string, value string) {
 	defer s.mutex.Unlock()
 
 	s.store[key] = value
+	delete(s.expiresAt, key)
+}
+
+// SetWithExpiration sets the value of the key in state store till given expiration time (unix timestamp)
+func (s *StateStore) SetWithExpiration(key string, value string, expiration int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.store[key] = value
+	s.expiresAt[key] = expiration
 }
 
 // Remove removes the key from state store
@@ -39,4 +90,5 @@ func (s *StateStore) Remove(key string) {
 	defer s.mutex.Unlock()
 
 	delete(s.store, key)
+	delete(s.expiresAt, key)
 }
@@ -123,4 +123,13 @@ func ProviderTests(t *testing.T, p Provider) {
 	key, err = p.GetMfaSession("auth_provider:123", "session123")
 	assert.Error(t, err)
 	assert.Empty(t, key)
+
+	err = p.SetStateWithExpiration("state_key", "state_value", time.Now().Add(2*time.Second).Unix())
+	assert.NoError(t, err)
+	key, err = p.GetState("state_key")
+	assert.NoError(t, err)
+	assert.Equal(t, "state_value", key)
+	time.Sleep(3 * time.Second)
+	key, _ = p.GetState("state_key")
+	assert.Empty(t, key)
 }
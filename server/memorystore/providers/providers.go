@@ -21,6 +21,9 @@ type Provider interface {
 
 	// SetState sets the login state (key, value form) in the session store
 	SetState(key, state string) error
+	// SetStateWithExpiration sets the state (key, value form) in the session store till given expiration (unix timestamp).
+	// Unlike sessions, states are never evicted before they expire
+	SetStateWithExpiration(key, state string, expiration int64) error
 	// GetState returns the state from the session store
 	GetState(key string) (string, error)
 	// RemoveState removes the social login state from the session store
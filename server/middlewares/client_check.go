@@ -1,12 +1,14 @@
 package middlewares
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 )
 
@@ -15,7 +17,7 @@ import (
 func ClientCheckMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		clientID := c.Request.Header.Get("X-Authorizer-Client-ID")
-		if client, _ := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID); clientID != "" && client != "" && client != clientID {
+		if client, _ := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID); clientID != "" && client != "" && client != clientID && !isRegisteredClient(c, clientID) {
 			log.Debug("Client ID is invalid: ", clientID)
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":             "invalid_client_id",
@@ -27,3 +29,9 @@ func ClientCheckMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// isRegisteredClient checks if client id is registered in client registry
+func isRegisteredClient(ctx context.Context, clientID string) bool {
+	client, err := db.Provider.GetClientByClientID(ctx, clientID)
+	return err == nil && client != nil
+}
@@ -0,0 +1,155 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IdentityProviderConfig is the configuration of upstream identity provider managed by admin
+type IdentityProviderConfig struct {
+	OAuthConfig *oauth2.Config
+	UserInfoURL string
+	// IDTokenVerifier is only set for providers configured with discovery url,
+	// id token of other providers is ignored and user details are fetched from userinfo url
+	IDTokenVerifier *oidc.IDTokenVerifier
+}
+
+// GetIdentityProviderConfig returns the oauth config of upstream identity provider,
+// endpoints are fetched from discovery document when discovery url is configured
+func GetIdentityProviderConfig(ctx context.Context, identityProvider *models.IdentityProvider, redirectURL string) (*IdentityProviderConfig, error) {
+	clientSecret, err := crypto.DecryptAES(identityProvider.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	config := &IdentityProviderConfig{
+		OAuthConfig: &oauth2.Config{
+			ClientID:     identityProvider.ClientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  identityProvider.AuthorizationURL,
+				TokenURL: identityProvider.TokenURL,
+			},
+			Scopes: identityProvider.GetScopes(),
+		},
+		UserInfoURL: identityProvider.UserInfoURL,
+	}
+	if identityProvider.DiscoveryURL != "" {
+		issuer := strings.TrimSuffix(identityProvider.DiscoveryURL, "/.well-known/openid-configuration")
+		p, err := oidc.NewProvider(ctx, issuer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover identity provider: %s", err.Error())
+		}
+		config.OAuthConfig.Endpoint = p.Endpoint()
+		config.UserInfoURL = p.UserInfoEndpoint()
+		config.IDTokenVerifier = p.Verifier(&oidc.Config{ClientID: identityProvider.ClientID})
+	}
+	return config, nil
+}
+
+// GetIdentityProviderUser returns the user with fields mapped from claims of upstream identity provider.
+// Claim names can refer nested claims using ".", e.g. realm_access.roles.
+// Only the roles configured for instance are assigned, protected roles are never assigned via claims
+func GetIdentityProviderUser(identityProvider *models.IdentityProvider, claims map[string]interface{}) *models.User {
+	return GetMappedUser(identityProvider.GetClaimMapping(), claims)
+}
+
+// IsIdentityProviderEmailVerified returns true when upstream identity provider has verified the email of user,
+// i.e. the claim mapped to email_verified is true. Missing claim is treated as unverified,
+// providers using other claim for verified email can map it via claim mapping
+func IsIdentityProviderEmailVerified(identityProvider *models.IdentityProvider, claims map[string]interface{}) bool {
+	switch emailVerified := getClaim(claims, identityProvider.GetClaimMapping()["email_verified"]).(type) {
+	case bool:
+		return emailVerified
+	case string:
+		return strings.EqualFold(emailVerified, "true")
+	default:
+		return false
+	}
+}
+
+// GetMappedUser returns the user with fields mapped from claims using claim mapping of user field to claim name,
+// it is shared by upstream providers which do not use standard claims e.g. attributes of saml assertion
+func GetMappedUser(claimMapping map[string]string, claims map[string]interface{}) *models.User {
+	user := &models.User{}
+	fields := map[string]**string{
+		"email":        &user.Email,
+		"given_name":   &user.GivenName,
+		"family_name":  &user.FamilyName,
+		"middle_name":  &user.MiddleName,
+		"nickname":     &user.Nickname,
+		"picture":      &user.Picture,
+		"gender":       &user.Gender,
+		"birthdate":    &user.Birthdate,
+		"phone_number": &user.PhoneNumber,
+	}
+	for field, value := range fields {
+		if claim, ok := getClaim(claims, claimMapping[field]).(string); ok && strings.TrimSpace(claim) != "" {
+			*value = refs.NewStringRef(strings.TrimSpace(claim))
+		}
+	}
+
+	if rolesClaim, ok := claimMapping["roles"]; ok {
+		rolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyRoles)
+		if err != nil {
+			rolesString = ""
+		}
+		allowedRoles := strings.Split(rolesString, ",")
+		roles := []string{}
+		for _, role := range getClaimValues(getClaim(claims, rolesClaim)) {
+			if utils.StringSliceContains(allowedRoles, role) && !utils.StringSliceContains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+		user.Roles = strings.Join(roles, ",")
+	}
+	return user
+}
+
+// getClaim returns the value of claim, nested claims are separated by "."
+func getClaim(claims map[string]interface{}, name string) interface{} {
+	if name == "" {
+		return nil
+	}
+	if value, ok := claims[name]; ok {
+		return value
+	}
+	var value interface{} = claims
+	for _, key := range strings.Split(name, ".") {
+		nestedClaims, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = nestedClaims[key]
+	}
+	return value
+}
+
+// getClaimValues returns the values of claim which is either list or comma / space separated string
+func getClaimValues(claim interface{}) []string {
+	values := []string{}
+	switch v := claim.(type) {
+	case string:
+		values = strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	case []interface{}:
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
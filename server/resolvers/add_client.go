@@ -0,0 +1,188 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// AddClientResolver resolver for add oauth client mutation
+func AddClientResolver(ctx context.Context, params model.AddClientRequest) (*model.AddClientResponse, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		log.Debug("empty name not allowed")
+		return nil, fmt.Errorf("empty name not allowed")
+	}
+	clientID := strings.TrimSpace(refs.StringValue(params.ClientID))
+	if clientID == "" {
+		clientID = uuid.New().String()
+	}
+	if utils.IsDefaultClientID(clientID) {
+		log.Debug("client id is reserved: ", clientID)
+		return nil, fmt.Errorf("client with client_id %s already exists", clientID)
+	}
+	if existingClient, err := db.Provider.GetClientByClientID(ctx, clientID); err == nil && existingClient != nil {
+		log.Debug("client already exists: ", clientID)
+		return nil, fmt.Errorf("client with client_id %s already exists", clientID)
+	}
+	clientSecret := strings.TrimSpace(refs.StringValue(params.ClientSecret))
+	if clientSecret == "" {
+		clientSecret = uuid.New().String()
+	}
+	grantTypes := params.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{constants.GrantTypeAuthorizationCode, constants.GrantTypeRefreshToken}
+	}
+	if err := validateClientParams(params.RedirectUris, grantTypes, params.AccessTokenExpiryTime, params.RefreshTokenExpiryTime); err != nil {
+		log.Debug("Invalid client params: ", err)
+		return nil, err
+	}
+	if err := validateClientLogoutParams(params.PostLogoutRedirectUris, params.FrontchannelLogoutURI, params.BackchannelLogoutURI); err != nil {
+		log.Debug("Invalid client logout params: ", err)
+		return nil, err
+	}
+	tokenEndpointAuthMethod := strings.TrimSpace(refs.StringValue(params.TokenEndpointAuthMethod))
+	jwks := strings.TrimSpace(refs.StringValue(params.Jwks))
+	jwksURI := strings.TrimSpace(refs.StringValue(params.JwksURI))
+	if err := validateClientAuthenticationParams(tokenEndpointAuthMethod, jwks, jwksURI, refs.BoolValue(params.RequireSignedRequestObject)); err != nil {
+		log.Debug("Invalid client authentication params: ", err)
+		return nil, err
+	}
+	hashedClientSecret, err := crypto.EncryptClientSecret(tokenEndpointAuthMethod, clientSecret)
+	if err != nil {
+		log.Debug("Failed to encrypt client secret: ", err)
+		return nil, err
+	}
+	client := &models.Client{
+		ClientID:                           clientID,
+		ClientSecret:                       hashedClientSecret,
+		Name:                               strings.TrimSpace(params.Name),
+		RedirectURIs:                       strings.Join(params.RedirectUris, ","),
+		GrantTypes:                         strings.Join(grantTypes, ","),
+		Scopes:                             strings.Join(params.Scopes, ","),
+		AccessTokenExpiryTime:              refs.StringValue(params.AccessTokenExpiryTime),
+		RefreshTokenExpiryTime:             refs.StringValue(params.RefreshTokenExpiryTime),
+		RequirePushedAuthorizationRequests: refs.BoolValue(params.RequirePushedAuthorizationRequests),
+		RequireSignedRequestObject:         refs.BoolValue(params.RequireSignedRequestObject),
+		PostLogoutRedirectURIs:             strings.Join(params.PostLogoutRedirectUris, ","),
+		FrontchannelLogoutURI:              refs.StringValue(params.FrontchannelLogoutURI),
+		BackchannelLogoutURI:               refs.StringValue(params.BackchannelLogoutURI),
+		TokenExchangeAudiences:             strings.Join(params.TokenExchangeAudiences, ","),
+		TokenEndpointAuthMethod:            tokenEndpointAuthMethod,
+		JWKS:                               jwks,
+		JWKSURI:                            jwksURI,
+		SubjectType:                        strings.TrimSpace(refs.StringValue(params.SubjectType)),
+		SectorIdentifierURI:                strings.TrimSpace(refs.StringValue(params.SectorIdentifierURI)),
+	}
+	if err := validateClientSubjectParams(client); err != nil {
+		log.Debug("Invalid client subject params: ", err)
+		return nil, err
+	}
+	client, err = db.Provider.AddClient(ctx, client)
+	if err != nil {
+		log.Debug("Failed to add client: ", err)
+		return nil, err
+	}
+	return &model.AddClientResponse{
+		Message:      `Client added successfully`,
+		Client:       client.AsAPIClient(),
+		ClientSecret: clientSecret,
+	}, nil
+}
+
+// validateClientParams validates redirect uris, grant types and token lifetimes of client
+func validateClientParams(redirectURIs, grantTypes []string, accessTokenExpiryTime, refreshTokenExpiryTime *string) error {
+	for _, redirectURI := range redirectURIs {
+		if !validators.IsValidRedirectURI(redirectURI) {
+			return fmt.Errorf("invalid redirect uri %s", redirectURI)
+		}
+	}
+	for _, grantType := range grantTypes {
+		if !validators.IsValidGrantType(grantType) {
+			return fmt.Errorf("invalid grant type %s", grantType)
+		}
+	}
+	if refs.StringValue(accessTokenExpiryTime) != "" {
+		if _, err := utils.ParseDurationInSeconds(refs.StringValue(accessTokenExpiryTime)); err != nil {
+			return fmt.Errorf("invalid access token expiry time: %s", err.Error())
+		}
+	}
+	if refs.StringValue(refreshTokenExpiryTime) != "" {
+		if _, err := utils.ParseDurationInSeconds(refs.StringValue(refreshTokenExpiryTime)); err != nil {
+			return fmt.Errorf("invalid refresh token expiry time: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// validateClientLogoutParams validates post logout redirect uris and logout uris of client
+func validateClientLogoutParams(postLogoutRedirectURIs []string, frontchannelLogoutURI, backchannelLogoutURI *string) error {
+	for _, redirectURI := range postLogoutRedirectURIs {
+		if !validators.IsValidRedirectURI(redirectURI) {
+			return fmt.Errorf("invalid post logout redirect uri %s", redirectURI)
+		}
+	}
+	if refs.StringValue(frontchannelLogoutURI) != "" && !validators.IsValidRedirectURI(refs.StringValue(frontchannelLogoutURI)) {
+		return fmt.Errorf("invalid frontchannel logout uri %s", refs.StringValue(frontchannelLogoutURI))
+	}
+	if refs.StringValue(backchannelLogoutURI) != "" && !validators.IsValidRedirectURI(refs.StringValue(backchannelLogoutURI)) {
+		return fmt.Errorf("invalid backchannel logout uri %s", refs.StringValue(backchannelLogoutURI))
+	}
+	return nil
+}
+
+// validateClientAuthenticationParams validates token endpoint auth method and keys of client.
+// Clients using private_key_jwt or signed request object should have jwks or jwks uri for validating signatures
+func validateClientAuthenticationParams(tokenEndpointAuthMethod, jwks, jwksURI string, requireSignedRequestObject bool) error {
+	if tokenEndpointAuthMethod != "" && !validators.IsValidTokenEndpointAuthMethod(tokenEndpointAuthMethod) {
+		return fmt.Errorf("invalid token endpoint auth method %s", tokenEndpointAuthMethod)
+	}
+	if jwks != "" && !validators.IsValidJWKS(jwks) {
+		return fmt.Errorf("invalid jwks")
+	}
+	if jwksURI != "" && !validators.IsValidJWKSURI(jwksURI) {
+		return fmt.Errorf("invalid jwks uri %s", jwksURI)
+	}
+	if jwks != "" && jwksURI != "" {
+		return fmt.Errorf("only one of jwks and jwks uri can be set")
+	}
+	if tokenEndpointAuthMethod == constants.TokenEndpointAuthMethodPrivateKeyJWT && jwks == "" && jwksURI == "" {
+		return fmt.Errorf("jwks or jwks uri is required for %s", constants.TokenEndpointAuthMethodPrivateKeyJWT)
+	}
+	if requireSignedRequestObject && jwks == "" && jwksURI == "" {
+		return fmt.Errorf("jwks or jwks uri is required for signed request object")
+	}
+	return nil
+}
+
+// validateClientSubjectParams validates subject type and sector identifier of client
+func validateClientSubjectParams(client *models.Client) error {
+	if client.SubjectType != "" && !validators.IsValidSubjectType(client.SubjectType) {
+		return fmt.Errorf("invalid subject type %s", client.SubjectType)
+	}
+	if !validators.IsValidClientSectorIdentifier(client) {
+		return fmt.Errorf("invalid sector identifier, sector identifier uri should list all the redirect uris")
+	}
+	return nil
+}
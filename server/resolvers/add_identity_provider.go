@@ -0,0 +1,111 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// AddIdentityProviderResolver resolver for add upstream identity provider mutation
+func AddIdentityProviderResolver(ctx context.Context, params model.AddIdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	name := strings.TrimSpace(params.Name)
+	if !validators.IsValidIdentityProviderName(name) {
+		log.Debug("Invalid identity provider name: ", name)
+		return nil, fmt.Errorf("invalid name %s, only lower case letters, digits, _ and - are allowed", name)
+	}
+	if existingIdentityProvider, err := db.Provider.GetIdentityProviderByName(ctx, name); err == nil && existingIdentityProvider != nil {
+		log.Debug("Identity provider already exists: ", name)
+		return nil, fmt.Errorf("identity provider with name %s already exists", name)
+	}
+	// oauth & saml identity providers share the login url, hence name should be unique across them
+	if existingSAMLIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(ctx, name); err == nil && existingSAMLIdentityProvider != nil {
+		log.Debug("SAML identity provider already exists: ", name)
+		return nil, fmt.Errorf("identity provider with name %s already exists", name)
+	}
+	clientSecret := strings.TrimSpace(params.ClientSecret)
+	if strings.TrimSpace(params.ClientID) == "" || clientSecret == "" {
+		log.Debug("Client id and client secret are required")
+		return nil, fmt.Errorf("client id and client secret are required")
+	}
+	encryptedClientSecret, err := crypto.EncryptAES(clientSecret)
+	if err != nil {
+		log.Debug("Failed to encrypt client secret: ", err)
+		return nil, err
+	}
+	identityProvider := &models.IdentityProvider{
+		Name:             name,
+		DiscoveryURL:     strings.TrimSpace(refs.StringValue(params.DiscoveryURL)),
+		AuthorizationURL: strings.TrimSpace(refs.StringValue(params.AuthorizationURL)),
+		TokenURL:         strings.TrimSpace(refs.StringValue(params.TokenURL)),
+		UserInfoURL:      strings.TrimSpace(refs.StringValue(params.UserinfoURL)),
+		ClientID:         strings.TrimSpace(params.ClientID),
+		ClientSecret:     encryptedClientSecret,
+		Scopes:           strings.Join(params.Scopes, ","),
+	}
+	if params.ClaimMapping != nil {
+		identityProvider.ClaimMapping, err = getIdentityProviderClaimMapping(params.ClaimMapping)
+		if err != nil {
+			log.Debug("Invalid claim mapping: ", err)
+			return nil, err
+		}
+	}
+	if err := validateIdentityProviderEndpoints(identityProvider); err != nil {
+		log.Debug("Invalid identity provider endpoints: ", err)
+		return nil, err
+	}
+	if _, err := db.Provider.AddIdentityProvider(ctx, identityProvider); err != nil {
+		log.Debug("Failed to add identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `Identity provider added successfully`,
+	}, nil
+}
+
+// validateIdentityProviderEndpoints validates that identity provider either has discovery url
+// or authorization, token & userinfo urls for obtaining the user details
+func validateIdentityProviderEndpoints(identityProvider *models.IdentityProvider) error {
+	endpoints := []string{identityProvider.DiscoveryURL, identityProvider.AuthorizationURL, identityProvider.TokenURL, identityProvider.UserInfoURL}
+	for _, endpoint := range endpoints {
+		if endpoint != "" && !validators.IsValidIdentityProviderURL(endpoint) {
+			return fmt.Errorf("invalid url %s", endpoint)
+		}
+	}
+	if identityProvider.DiscoveryURL == "" && (identityProvider.AuthorizationURL == "" || identityProvider.TokenURL == "" || identityProvider.UserInfoURL == "") {
+		return fmt.Errorf("discovery url or authorization, token and userinfo urls are required")
+	}
+	return nil
+}
+
+// getIdentityProviderClaimMapping validates claim mapping and returns it as json
+func getIdentityProviderClaimMapping(claimMapping map[string]interface{}) (string, error) {
+	if !validators.IsValidIdentityProviderClaimMapping(claimMapping) {
+		return "", fmt.Errorf("invalid claim mapping, supported fields are %s", strings.Join(models.IdentityProviderClaimMappingFields, ", "))
+	}
+	data, err := json.Marshal(claimMapping)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
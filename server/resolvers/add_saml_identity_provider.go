@@ -0,0 +1,107 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/saml"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// AddSAMLIdentityProviderResolver resolver for add saml identity provider mutation
+func AddSAMLIdentityProviderResolver(ctx context.Context, params model.AddSAMLIdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	name := strings.TrimSpace(params.Name)
+	if !validators.IsValidIdentityProviderName(name) {
+		log.Debug("Invalid saml identity provider name: ", name)
+		return nil, fmt.Errorf("invalid name %s, only lower case letters, digits, _ and - are allowed", name)
+	}
+	// oauth & saml identity providers share the login url, hence name should be unique across them
+	if existingSAMLIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(ctx, name); err == nil && existingSAMLIdentityProvider != nil {
+		log.Debug("SAML identity provider already exists: ", name)
+		return nil, fmt.Errorf("identity provider with name %s already exists", name)
+	}
+	if existingIdentityProvider, err := db.Provider.GetIdentityProviderByName(ctx, name); err == nil && existingIdentityProvider != nil {
+		log.Debug("Identity provider already exists: ", name)
+		return nil, fmt.Errorf("identity provider with name %s already exists", name)
+	}
+	samlIdentityProvider := &models.SAMLIdentityProvider{
+		Name:        name,
+		MetadataURL: strings.TrimSpace(refs.StringValue(params.MetadataURL)),
+		MetadataXML: strings.TrimSpace(refs.StringValue(params.MetadataXML)),
+	}
+	if params.AttributeMapping != nil {
+		samlIdentityProvider.AttributeMapping, err = getIdentityProviderClaimMapping(params.AttributeMapping)
+		if err != nil {
+			log.Debug("Invalid attribute mapping: ", err)
+			return nil, err
+		}
+	}
+	if params.AllowedEmailDomains != nil {
+		samlIdentityProvider.AllowedEmailDomains, err = getAllowedEmailDomains(params.AllowedEmailDomains)
+		if err != nil {
+			log.Debug("Invalid allowed email domains: ", err)
+			return nil, err
+		}
+	}
+	if err := validateSAMLIdentityProviderMetadata(ctx, samlIdentityProvider); err != nil {
+		log.Debug("Invalid saml identity provider metadata: ", err)
+		return nil, err
+	}
+	if _, err := db.Provider.AddSAMLIdentityProvider(ctx, samlIdentityProvider); err != nil {
+		log.Debug("Failed to add saml identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `SAML identity provider added successfully`,
+	}, nil
+}
+
+// validateSAMLIdentityProviderMetadata validates that saml identity provider either has metadata url
+// or metadata xml having single sign on service of identity provider.
+// Metadata fetched from metadata url is pinned in metadata xml, so that it is not fetched on every login
+func validateSAMLIdentityProviderMetadata(ctx context.Context, samlIdentityProvider *models.SAMLIdentityProvider) error {
+	if samlIdentityProvider.MetadataURL != "" {
+		metadata, err := saml.FetchIdentityProviderMetadata(ctx, samlIdentityProvider.MetadataURL)
+		if err != nil {
+			return err
+		}
+		samlIdentityProvider.MetadataXML = string(metadata)
+		return nil
+	}
+	if samlIdentityProvider.MetadataXML == "" {
+		return fmt.Errorf("metadata url or metadata xml is required")
+	}
+	_, err := saml.ParseIdentityProviderMetadata([]byte(samlIdentityProvider.MetadataXML))
+	return err
+}
+
+// getAllowedEmailDomains validates email domains and returns them as comma separated values
+func getAllowedEmailDomains(allowedEmailDomains []string) (string, error) {
+	domains := []string{}
+	for _, domain := range allowedEmailDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.Contains(domain, "@") || !validators.IsValidEmail("user@"+domain) {
+			return "", fmt.Errorf("invalid email domain %s", domain)
+		}
+		domains = append(domains, domain)
+	}
+	return strings.Join(domains, ","), nil
+}
@@ -0,0 +1,91 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// AddSAMLServiceProviderResolver resolver for add saml service provider mutation
+func AddSAMLServiceProviderResolver(ctx context.Context, params model.AddSAMLServiceProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		log.Debug("Name is required")
+		return nil, fmt.Errorf("name is required")
+	}
+	entityID := strings.TrimSpace(params.EntityID)
+	if entityID == "" {
+		log.Debug("Entity id is required")
+		return nil, fmt.Errorf("entity id is required")
+	}
+	if existingSAMLServiceProvider, err := db.Provider.GetSAMLServiceProviderByEntityID(ctx, entityID); err == nil && existingSAMLServiceProvider != nil {
+		log.Debug("SAML service provider already exists: ", entityID)
+		return nil, fmt.Errorf("saml service provider with entity id %s already exists", entityID)
+	}
+	acsURL := strings.TrimSpace(params.AcsURL)
+	if !validators.IsValidSAMLACSURL(acsURL) {
+		log.Debug("Invalid acs url: ", acsURL)
+		return nil, fmt.Errorf("invalid acs url %s", acsURL)
+	}
+	nameIDFormat := strings.TrimSpace(refs.StringValue(params.NameIDFormat))
+	if nameIDFormat == "" {
+		nameIDFormat = constants.SAMLNameIDFormatEmail
+	}
+	if !validators.IsValidSAMLNameIDFormat(nameIDFormat) {
+		log.Debug("Invalid name id format: ", nameIDFormat)
+		return nil, fmt.Errorf("invalid name id format %s, supported formats are %s and %s", nameIDFormat, constants.SAMLNameIDFormatEmail, constants.SAMLNameIDFormatPersistent)
+	}
+	samlServiceProvider := &models.SAMLServiceProvider{
+		Name:         name,
+		EntityID:     entityID,
+		ACSURL:       acsURL,
+		NameIDFormat: nameIDFormat,
+	}
+	if params.AttributeMapping != nil {
+		samlServiceProvider.AttributeMapping, err = getSAMLAttributeMapping(params.AttributeMapping)
+		if err != nil {
+			log.Debug("Invalid attribute mapping: ", err)
+			return nil, err
+		}
+	}
+	if _, err := db.Provider.AddSAMLServiceProvider(ctx, samlServiceProvider); err != nil {
+		log.Debug("Failed to add saml service provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `SAML service provider added successfully`,
+	}, nil
+}
+
+// getSAMLAttributeMapping validates attribute mapping and returns it as json
+func getSAMLAttributeMapping(attributeMapping map[string]interface{}) (string, error) {
+	if !validators.IsValidSAMLAttributeMapping(attributeMapping) {
+		return "", fmt.Errorf("invalid attribute mapping, supported fields are %s", strings.Join(models.SAMLAttributeMappingFields, ", "))
+	}
+	data, err := json.Marshal(attributeMapping)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// AuthorizeDeviceResolver is a resolver for authorize device mutation.
+// It is used by logged in user to approve or deny device authorization request
+func AuthorizeDeviceResolver(ctx context.Context, params model.AuthorizeDeviceRequest) (*model.Response, error) {
+	var res *model.Response
+
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return res, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return res, err
+	}
+	log := log.WithFields(log.Fields{
+		"user_id": tokenData.UserID,
+	})
+	user, err := db.Provider.GetUserByID(ctx, tokenData.UserID)
+	if err != nil {
+		log.Debug("Failed to get user: ", err)
+		return res, err
+	}
+	if user.RevokedTimestamp != nil {
+		log.Debug("User access is revoked")
+		return res, fmt.Errorf(`user access has been revoked`)
+	}
+
+	deviceAuthorization, err := token.GetDeviceAuthorizationByUserCode(params.UserCode)
+	if err != nil {
+		log.Debug("Failed to get device authorization: ", err)
+		return res, fmt.Errorf(`invalid user code`)
+	}
+	if deviceAuthorization.IsExpired() {
+		log.Debug("Device code is expired")
+		token.RemoveDeviceAuthorization(deviceAuthorization)
+		return res, fmt.Errorf(`user code has expired`)
+	}
+	if deviceAuthorization.Status != token.DeviceAuthorizationStatusPending {
+		log.Debug("Device authorization is already processed")
+		return res, fmt.Errorf(`invalid user code`)
+	}
+
+	message := `Device authorized successfully`
+	deviceAuthorization.Status = token.DeviceAuthorizationStatusApproved
+	deviceAuthorization.UserID = user.ID
+	deviceAuthorization.LoginMethod = tokenData.LoginMethod
+	if refs.BoolValue(params.Deny) {
+		message = `Device authorization denied`
+		deviceAuthorization.Status = token.DeviceAuthorizationStatusDenied
+	}
+	if err := token.SetDeviceAuthorization(deviceAuthorization); err != nil {
+		log.Debug("Failed to save device authorization: ", err)
+		return res, err
+	}
+
+	res = &model.Response{
+		Message: message,
+	}
+	return res, nil
+}
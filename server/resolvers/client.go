@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// ClientResolver resolver for getting oauth client by identifier
+func ClientResolver(ctx context.Context, params model.ClientRequest) (*model.Client, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	client, err := db.Provider.GetClientByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("error getting client: ", err)
+		return nil, err
+	}
+	return client.AsAPIClient(), nil
+}
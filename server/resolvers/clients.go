@@ -0,0 +1,33 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// ClientsResolver resolver for getting the list of oauth clients based on pagination
+func ClientsResolver(ctx context.Context, params *model.PaginatedInput) (*model.Clients, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	pagination := utils.GetPagination(params)
+	clients, err := db.Provider.ListClients(ctx, pagination)
+	if err != nil {
+		log.Debug("failed to get clients: ", err)
+		return nil, err
+	}
+	return clients, nil
+}
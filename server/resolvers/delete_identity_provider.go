@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// DeleteIdentityProviderResolver resolver to delete upstream identity provider
+func DeleteIdentityProviderResolver(ctx context.Context, params model.IdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if params.ID == "" {
+		log.Debug("identity provider id is required")
+		return nil, fmt.Errorf("identity provider ID required")
+	}
+	log := log.WithField("id", params.ID)
+	identityProvider, err := db.Provider.GetIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get identity provider: ", err)
+		return nil, err
+	}
+	if err := db.Provider.DeleteIdentityProvider(ctx, identityProvider); err != nil {
+		log.Debug("failed to delete identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: "Identity provider deleted successfully",
+	}, nil
+}
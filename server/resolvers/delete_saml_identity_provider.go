@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// DeleteSAMLIdentityProviderResolver resolver to delete saml identity provider
+func DeleteSAMLIdentityProviderResolver(ctx context.Context, params model.SAMLIdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if params.ID == "" {
+		log.Debug("saml identity provider id is required")
+		return nil, fmt.Errorf("saml identity provider ID required")
+	}
+	log := log.WithField("id", params.ID)
+	samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get saml identity provider: ", err)
+		return nil, err
+	}
+	if err := db.Provider.DeleteSAMLIdentityProvider(ctx, samlIdentityProvider); err != nil {
+		log.Debug("failed to delete saml identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: "SAML identity provider deleted successfully",
+	}, nil
+}
@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// DeleteSAMLServiceProviderResolver resolver to delete saml service provider
+func DeleteSAMLServiceProviderResolver(ctx context.Context, params model.SAMLServiceProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if params.ID == "" {
+		log.Debug("saml service provider id is required")
+		return nil, fmt.Errorf("saml service provider ID required")
+	}
+	log := log.WithField("id", params.ID)
+	samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get saml service provider: ", err)
+		return nil, err
+	}
+	if err := db.Provider.DeleteSAMLServiceProvider(ctx, samlServiceProvider); err != nil {
+		log.Debug("failed to delete saml service provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: "SAML service provider deleted successfully",
+	}, nil
+}
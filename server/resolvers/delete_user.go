@@ -8,6 +8,7 @@ import (
 
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
 	"github.com/authorizerdev/authorizer/server/graph/model"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/refs"
@@ -92,6 +93,26 @@ func DeleteUserResolver(ctx context.Context, params model.DeleteUserInput) (*mod
 			}
 		}
 
+		// delete identities of user
+		identities, err := db.Provider.ListIdentities(ctx, &model.Pagination{
+			Limit: 100,
+		}, user.ID)
+		if err != nil {
+			log.Debugf("Failed to get identities of user (%s): %v", user.ID, err)
+			// continue
+		} else {
+			for _, identity := range identities.Identities {
+				err := db.Provider.DeleteIdentity(ctx, &models.Identity{
+					Key: identity.ID,
+					ID:  identity.ID,
+				})
+				if err != nil {
+					log.Debugf("Failed to delete identity (%s): %v", identity.ID, err)
+					// continue
+				}
+			}
+		}
+
 		memorystore.Provider.DeleteAllUserSessions(user.ID)
 		utils.RegisterEvent(ctx, constants.UserDeletedWebhookEvent, "", user)
 	}()
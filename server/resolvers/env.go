@@ -41,6 +41,21 @@ func EnvResolver(ctx context.Context) (*model.Env, error) {
 	if val, ok := store[constants.EnvKeyAccessTokenExpiryTime]; ok {
 		res.AccessTokenExpiryTime = refs.NewStringRef(val.(string))
 	}
+	if val, ok := store[constants.EnvKeyRefreshTokenExpiryTime]; ok {
+		res.RefreshTokenExpiryTime = refs.NewStringRef(val.(string))
+	}
+	if val, ok := store[constants.EnvKeySessionExpiryTime]; ok {
+		res.SessionExpiryTime = refs.NewStringRef(val.(string))
+	}
+	if val, ok := store[constants.EnvKeySessionIdleTimeout]; ok {
+		res.SessionIdleTimeout = refs.NewStringRef(val.(string))
+	}
+	if val, ok := store[constants.EnvKeySessionMaxAge]; ok {
+		res.SessionMaxAge = refs.NewStringRef(val.(string))
+	}
+	if val, ok := store[constants.EnvKeyOTPExpiryTime]; ok {
+		res.OtpExpiryTime = refs.NewStringRef(val.(string))
+	}
 	if val, ok := store[constants.EnvKeyAdminSecret]; ok {
 		res.AdminSecret = refs.NewStringRef(val.(string))
 	}
@@ -194,6 +209,9 @@ func EnvResolver(ctx context.Context) (*model.Env, error) {
 	if val, ok := store[constants.EnvKeyDefaultAuthorizeResponseMode]; ok {
 		res.DefaultAuthorizeResponseMode = refs.NewStringRef(val.(string))
 	}
+	if val, ok := store[constants.EnvKeyClientRegistrationInitialAccessToken]; ok {
+		res.ClientRegistrationInitialAccessToken = refs.NewStringRef(val.(string))
+	}
 
 	// string slice vars
 	res.AllowedOrigins = strings.Split(store[constants.EnvKeyAllowedOrigins].(string), ",")
@@ -221,6 +239,7 @@ func EnvResolver(ctx context.Context) (*model.Env, error) {
 	res.AdminCookieSecure = store[constants.EnvKeyAdminCookieSecure].(bool)
 	res.AppCookieSecure = store[constants.EnvKeyAppCookieSecure].(bool)
 	res.DisablePlayground = store[constants.EnvKeyDisablePlayGround].(bool)
+	res.DisableRefreshTokenReuseDetection = store[constants.EnvKeyDisableRefreshTokenReuseDetection].(bool)
 	res.DisableMailOtpLogin = store[constants.EnvKeyDisableMailOTPLogin].(bool)
 	res.DisableTotpLogin = store[constants.EnvKeyDisableTOTPLogin].(bool)
 
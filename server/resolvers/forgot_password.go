@@ -134,7 +134,7 @@ func ForgotPasswordResolver(ctx context.Context, params model.ForgotPasswordInpu
 		}, nil
 	}
 	if isMobileLogin {
-		expiresAt := time.Now().Add(1 * time.Minute).Unix()
+		expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 		otp := utils.GenerateOTP()
 		otpData, err := db.Provider.UpsertOTP(ctx, &models.OTP{
 			Email:       refs.StringValue(user.Email),
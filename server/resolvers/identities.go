@@ -0,0 +1,37 @@
+package resolvers
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IdentitiesResolver is a resolver for identities query.
+// It returns the identities of login providers linked to logged in user
+func IdentitiesResolver(ctx context.Context, params *model.PaginatedInput) (*model.Identities, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return nil, err
+	}
+	log := log.WithFields(log.Fields{
+		"user_id": tokenData.UserID,
+	})
+	pagination := utils.GetPagination(params)
+	identities, err := db.Provider.ListIdentities(ctx, pagination, tokenData.UserID)
+	if err != nil {
+		log.Debug("Failed to get identities: ", err)
+		return nil, err
+	}
+	return identities, nil
+}
@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IdentityProviderResolver resolver for getting upstream identity provider by identifier
+func IdentityProviderResolver(ctx context.Context, params model.IdentityProviderRequest) (*model.IdentityProvider, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	identityProvider, err := db.Provider.GetIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("error getting identity provider: ", err)
+		return nil, err
+	}
+	return identityProvider.AsAPIIdentityProvider(), nil
+}
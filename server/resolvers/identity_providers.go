@@ -0,0 +1,33 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IdentityProvidersResolver resolver for getting the list of upstream identity providers based on pagination
+func IdentityProvidersResolver(ctx context.Context, params *model.PaginatedInput) (*model.IdentityProviders, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	pagination := utils.GetPagination(params)
+	identityProviders, err := db.Provider.ListIdentityProviders(ctx, pagination)
+	if err != nil {
+		log.Debug("failed to get identity providers: ", err)
+		return nil, err
+	}
+	return identityProviders, nil
+}
@@ -0,0 +1,56 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// JWTKeysResolver resolver for jwt keys query
+// Only key metadata is returned, key material is never exposed
+func JWTKeysResolver(ctx context.Context) (*model.JWTKeys, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	keyring, err := crypto.GetKeyring()
+	if err != nil {
+		log.Debug("Failed to get jwt keyring: ", err)
+		return nil, err
+	}
+	return &model.JWTKeys{
+		Keys: asAPIJWTKeys(keyring),
+	}, nil
+}
+
+// asAPIJWTKeys returns keyring as graphql response object
+func asAPIJWTKeys(keyring []*crypto.SigningKey) []*model.JWTKey {
+	keys := []*model.JWTKey{}
+	for _, key := range keyring {
+		if key.IsExpired() {
+			continue
+		}
+		keys = append(keys, &model.JWTKey{
+			Kid:         key.KeyID,
+			Algorithm:   key.Algorithm,
+			Status:      key.Status,
+			CreatedAt:   refs.NewInt64Ref(key.CreatedAt),
+			ActivatedAt: refs.NewInt64Ref(key.ActivatedAt),
+			RetiredAt:   refs.NewInt64Ref(key.RetiredAt),
+			ExpiresAt:   refs.NewInt64Ref(key.ExpiresAt),
+		})
+	}
+	return keys
+}
@@ -0,0 +1,70 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// LinkIdentityResolver is a resolver for link identity mutation.
+// It returns the oauth_login url with one time link token,
+// identity returned by provider on login is linked to logged in user instead of login
+func LinkIdentityResolver(ctx context.Context, params model.LinkIdentityRequest) (*model.LinkIdentityResponse, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return nil, err
+	}
+	provider := strings.TrimSpace(params.Provider)
+	log := log.WithFields(log.Fields{
+		"user_id":  tokenData.UserID,
+		"provider": provider,
+	})
+	if provider == "" {
+		log.Debug("Provider is empty")
+		return nil, fmt.Errorf("provider is required")
+	}
+	redirectURI := parsers.GetAppURL(gc)
+	if strings.TrimSpace(refs.StringValue(params.RedirectURI)) != "" {
+		redirectURI = strings.TrimSpace(refs.StringValue(params.RedirectURI))
+		if !validators.IsValidOrigin(redirectURI) {
+			log.Debug("Invalid redirect uri: ", redirectURI)
+			return nil, fmt.Errorf("invalid redirect uri")
+		}
+	}
+	if _, err := db.Provider.GetUserByID(ctx, tokenData.UserID); err != nil {
+		log.Debug("Failed to get user: ", err)
+		return nil, err
+	}
+	linkRequest, err := token.SetIdentityLinkRequest(&token.IdentityLinkRequest{
+		UserID:   tokenData.UserID,
+		Provider: provider,
+	})
+	if err != nil {
+		log.Debug("Failed to set identity link request: ", err)
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("redirect_uri", redirectURI)
+	query.Set("link_token", linkRequest.ID)
+	return &model.LinkIdentityResponse{
+		Message:          `Login with provider to link identity`,
+		AuthorizationURL: parsers.GetHost(gc) + "/oauth_login/" + url.PathEscape(provider) + "?" + query.Encode(),
+	}, nil
+}
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/authorizerdev/authorizer/server/db/models"
 	mailService "github.com/authorizerdev/authorizer/server/email"
 	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/ldap"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/refs"
 	"github.com/authorizerdev/authorizer/server/smsproviders"
@@ -75,10 +77,26 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 	} else {
 		user, err = db.Provider.GetUserByPhoneNumber(ctx, phoneNumber)
 	}
+	// users of ldap directory are authenticated with ldap bind instead of password stored in db
+	isLDAPLogin := isEmailLogin && ldap.IsEnabled() && (err != nil || strings.Contains(user.SignupMethods, constants.AuthRecipeMethodLDAP))
+	if isLDAPLogin {
+		user, err = getLDAPUser(ctx, email, params.Password)
+		if err != nil {
+			log.Debug("Failed to authenticate ldap user: ", err)
+			if errors.Is(err, ldap.ErrInvalidCredentials) {
+				return res, fmt.Errorf(`bad user credentials`)
+			}
+			return res, err
+		}
+	}
 	if err != nil {
 		log.Debug("Failed to get user: ", err)
 		return res, fmt.Errorf(`user not found`)
 	}
+	loginMethod := constants.AuthRecipeMethodBasicAuth
+	if isLDAPLogin {
+		loginMethod = constants.AuthRecipeMethodLDAP
+	}
 	if user.RevokedTimestamp != nil {
 		log.Debug("User access is revoked")
 		return res, fmt.Errorf(`user access has been revoked`)
@@ -116,7 +134,8 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 		cookie.SetMfaSession(gc, mfaSession)
 		return nil
 	}
-	if isEmailLogin {
+	// email of ldap user is verified by directory, hence only basic auth users are checked
+	if isEmailLogin && !isLDAPLogin {
 		if !strings.Contains(user.SignupMethods, constants.AuthRecipeMethodBasicAuth) {
 			log.Debug("User signup method is not basic auth")
 			return res, fmt.Errorf(`user has not signed up email & password`)
@@ -142,7 +161,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 						return res, fmt.Errorf(`email verification pending`)
 					}
 				}
-				expiresAt := time.Now().Add(1 * time.Minute).Unix()
+				expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 				otpData, err := generateOTP(expiresAt)
 				if err != nil {
 					log.Debug("Failed to generate otp: ", err)
@@ -169,7 +188,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 				}, nil
 			}
 		}
-	} else {
+	} else if !isEmailLogin {
 		if !strings.Contains(user.SignupMethods, constants.AuthRecipeMethodMobileBasicAuth) {
 			log.Debug("User signup method is not mobile basic auth")
 			return res, fmt.Errorf(`user has not signed up with phone number & password`)
@@ -180,7 +199,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 				log.Debug("User phone number is not verified")
 				return res, fmt.Errorf(`phone number is not verified and sms service is not enabled`)
 			} else {
-				expiresAt := time.Now().Add(1 * time.Minute).Unix()
+				expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 				otpData, err := generateOTP(expiresAt)
 				if err != nil {
 					log.Debug("Failed to generate otp: ", err)
@@ -206,10 +225,12 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 			}
 		}
 	}
-	err = bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(params.Password))
-	if err != nil {
-		log.Debug("Failed to compare password: ", err)
-		return res, fmt.Errorf(`bad user credentials`)
+	if !isLDAPLogin {
+		err = bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(params.Password))
+		if err != nil {
+			log.Debug("Failed to compare password: ", err)
+			return res, fmt.Errorf(`bad user credentials`)
+		}
 	}
 	defaultRolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyDefaultRoles)
 	roles := []string{}
@@ -220,6 +241,10 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 		roles = strings.Split(defaultRolesString, ",")
 	}
 	currentRoles := strings.Split(user.Roles, ",")
+	// roles of ldap user are mapped from groups of directory
+	if isLDAPLogin {
+		roles = currentRoles
+	}
 	if len(params.Roles) > 0 {
 		if !validators.IsValidRoles(params.Roles, currentRoles) {
 			log.Debug("Invalid roles: ", params.Roles)
@@ -254,7 +279,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 
 	// If multi factor authentication is enabled and is email based login and email otp is enabled
 	if refs.BoolValue(user.IsMultiFactorAuthEnabled) && !isMFADisabled && !isMailOTPDisabled && isEmailServiceEnabled && isEmailLogin {
-		expiresAt := time.Now().Add(1 * time.Minute).Unix()
+		expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 		otpData, err := generateOTP(expiresAt)
 		if err != nil {
 			log.Debug("Failed to generate otp: ", err)
@@ -273,7 +298,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 			}); err != nil {
 				log.Debug("Failed to send otp email: ", err)
 			}
-			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, constants.AuthRecipeMethodBasicAuth, user)
+			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, loginMethod, user)
 		}()
 		return &model.AuthResponse{
 			Message:                  "Please check email inbox for the OTP",
@@ -282,7 +307,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 	}
 	// If multi factor authentication is enabled and is sms based login and sms otp is enabled
 	if refs.BoolValue(user.IsMultiFactorAuthEnabled) && !isMFADisabled && !isSMSOTPDisabled && isSMSServiceEnabled && isMobileLogin {
-		expiresAt := time.Now().Add(1 * time.Minute).Unix()
+		expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 		otpData, err := generateOTP(expiresAt)
 		if err != nil {
 			log.Debug("Failed to generate otp: ", err)
@@ -366,7 +391,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 	if nonce == "" {
 		nonce = uuid.New().String()
 	}
-	authToken, err := token.CreateAuthToken(gc, user, roles, scope, constants.AuthRecipeMethodBasicAuth, nonce, code)
+	authToken, err := token.CreateAuthToken(gc, user, roles, scope, loginMethod, nonce, code)
 	if err != nil {
 		log.Debug("Failed to create auth token", err)
 		return res, err
@@ -395,7 +420,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 	}
 
 	cookie.SetSession(gc, authToken.FingerPrintHash)
-	sessionStoreKey := constants.AuthRecipeMethodBasicAuth + ":" + user.ID
+	sessionStoreKey := loginMethod + ":" + user.ID
 	memorystore.Provider.SetUserSession(sessionStoreKey, constants.TokenTypeSessionToken+"_"+authToken.FingerPrint, authToken.FingerPrintHash, authToken.SessionTokenExpiresAt)
 	memorystore.Provider.SetUserSession(sessionStoreKey, constants.TokenTypeAccessToken+"_"+authToken.FingerPrint, authToken.AccessToken.Token, authToken.AccessToken.ExpiresAt)
 
@@ -407,7 +432,7 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 	go func() {
 		// Register event
 		if isEmailLogin {
-			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, constants.AuthRecipeMethodBasicAuth, user)
+			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, loginMethod, user)
 		} else {
 			utils.RegisterEvent(ctx, constants.UserLoginWebhookEvent, constants.AuthRecipeMethodMobileBasicAuth, user)
 		}
@@ -421,3 +446,60 @@ func LoginResolver(ctx context.Context, params model.LoginInput) (*model.AuthRes
 
 	return res, nil
 }
+
+// getLDAPUser authenticates the user with ldap directory and returns the shadow user of directory entry.
+// Shadow user is created on first login and its name & roles are updated from directory on each login.
+// Existing users who have not signed up via ldap are not linked to directory entry with the same email,
+// as directory entry does not prove the ownership of existing account
+func getLDAPUser(ctx context.Context, email, password string) (*models.User, error) {
+	ldapUser, err := ldap.Authenticate(email, password)
+	if err != nil {
+		return nil, err
+	}
+	// roles are not updated from directory when group role mapping is not configured
+	groupRoleMapping, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyLDAPGroupRoleMapping)
+	if err != nil {
+		groupRoleMapping = ""
+	}
+	if ldapUser.Roles == "" && groupRoleMapping != "" {
+		defaultRoles, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyDefaultRoles)
+		if err != nil {
+			return nil, err
+		}
+		ldapUser.Roles = defaultRoles
+	}
+
+	user, err := db.Provider.GetUserByEmail(ctx, refs.StringValue(ldapUser.Email))
+	if err != nil {
+		isSignupDisabled, err := memorystore.Provider.GetBoolStoreEnvVariable(constants.EnvKeyDisableSignUp)
+		if err != nil {
+			return nil, err
+		}
+		if isSignupDisabled {
+			return nil, fmt.Errorf(`signup is disabled for this instance`)
+		}
+		ldapUser.SignupMethods = constants.AuthRecipeMethodLDAP
+		now := time.Now().Unix()
+		ldapUser.EmailVerifiedAt = &now
+		user, err = db.Provider.AddUser(ctx, ldapUser)
+		if err != nil {
+			return nil, err
+		}
+		go utils.RegisterEvent(ctx, constants.UserSignUpWebhookEvent, constants.AuthRecipeMethodLDAP, user)
+		return user, nil
+	}
+
+	if !strings.Contains(user.SignupMethods, constants.AuthRecipeMethodLDAP) {
+		return nil, fmt.Errorf(`user with this email has not signed up via ldap`)
+	}
+	if ldapUser.GivenName != nil {
+		user.GivenName = ldapUser.GivenName
+	}
+	if ldapUser.FamilyName != nil {
+		user.FamilyName = ldapUser.FamilyName
+	}
+	if groupRoleMapping != "" {
+		user.Roles = ldapUser.Roles
+	}
+	return db.Provider.UpdateUser(ctx, user)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/authorizerdev/authorizer/server/cookie"
 	"github.com/authorizerdev/authorizer/server/graph/model"
 	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
 	"github.com/authorizerdev/authorizer/server/token"
 	"github.com/authorizerdev/authorizer/server/utils"
 )
@@ -33,6 +34,9 @@ func LogoutResolver(ctx context.Context) (*model.Response, error) {
 
 	memorystore.Provider.DeleteUserSession(sessionKey, tokenData.Nonce)
 	cookie.DeleteSession(gc)
+	// clients which participated in session are notified
+	clients := token.EndSession(gc, sessionKey, tokenData.SessionID)
+	go token.NotifyBackchannelLogout(parsers.GetHost(gc), tokenData.UserID, tokenData.SessionID, clients)
 
 	res := &model.Response{
 		Message: "Logged out successfully",
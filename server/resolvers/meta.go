@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
 	"github.com/authorizerdev/authorizer/server/graph/model"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 )
@@ -171,6 +172,29 @@ func MetaResolver(ctx context.Context) (*model.Meta, error) {
 		isSignUpDisabled = true
 	}
 
+	identityProviders := []string{}
+	identityProvidersRes, err := db.Provider.ListIdentityProviders(ctx, &model.Pagination{
+		Limit: 100,
+	})
+	if err != nil {
+		log.Debug("Failed to get identity providers: ", err)
+	} else {
+		for _, identityProvider := range identityProvidersRes.IdentityProviders {
+			identityProviders = append(identityProviders, identityProvider.Name)
+		}
+	}
+	// saml identity providers are also logged in via oauth_login url
+	samlIdentityProvidersRes, err := db.Provider.ListSAMLIdentityProviders(ctx, &model.Pagination{
+		Limit: 100,
+	})
+	if err != nil {
+		log.Debug("Failed to get saml identity providers: ", err)
+	} else {
+		for _, samlIdentityProvider := range samlIdentityProvidersRes.SamlIdentityProviders {
+			identityProviders = append(identityProviders, samlIdentityProvider.Name)
+		}
+	}
+
 	metaInfo := model.Meta{
 		Version:                            constants.VERSION,
 		ClientID:                           clientID,
@@ -191,6 +215,7 @@ func MetaResolver(ctx context.Context) (*model.Meta, error) {
 		IsPhoneVerificationEnabled:         !isMobileVerificationDisabled,
 		IsTwitchLoginEnabled:               twitchClientID != "" && twitchClientSecret != "",
 		IsRobloxLoginEnabled:               robloxClientID != "" && robloxClientSecret != "",
+		IdentityProviders:                  identityProviders,
 	}
 	return &metaInfo, nil
 }
@@ -0,0 +1,48 @@
+package resolvers
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// OAuthGrantsResolver is a resolver for oauth grants query.
+// It returns the oauth clients to which logged in user has given consent
+func OAuthGrantsResolver(ctx context.Context, params *model.PaginatedInput) (*model.OAuthGrants, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return nil, err
+	}
+	log := log.WithFields(log.Fields{
+		"user_id": tokenData.UserID,
+	})
+	pagination := utils.GetPagination(params)
+	oauthGrants, err := db.Provider.ListOAuthGrants(ctx, pagination, tokenData.UserID)
+	if err != nil {
+		log.Debug("Failed to get oauth grants: ", err)
+		return nil, err
+	}
+	for _, oauthGrant := range oauthGrants.OauthGrants {
+		client, err := db.Provider.GetClientByClientID(ctx, oauthGrant.ClientID)
+		if err != nil {
+			log.Debug("Failed to get client of oauth grant: ", err)
+			continue
+		}
+		if client.Name != "" {
+			oauthGrant.ClientName = refs.NewStringRef(client.Name)
+		}
+	}
+	return oauthGrants, nil
+}
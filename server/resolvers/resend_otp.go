@@ -122,7 +122,7 @@ func ResendOTPResolver(ctx context.Context, params model.ResendOTPRequest) (*mod
 		cookie.SetMfaSession(gc, mfaSession)
 		return nil
 	}
-	expiresAt := time.Now().Add(1 * time.Minute).Unix()
+	expiresAt := time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeyOTPExpiryTime, time.Minute)).Unix()
 	otpData, err = generateOTP(expiresAt)
 	if err != nil {
 		log.Debug("Failed to generate otp: ", err)
@@ -80,7 +80,7 @@ func ResetPasswordResolver(ctx context.Context, params model.ResetPasswordInput)
 			return res, fmt.Errorf(`invalid token`)
 		}
 
-		if ok, err := token.ValidateJWTClaims(claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
+		if ok, err := token.ValidateJWTClaims(ctx, claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
 			log.Debug("Failed to validate jwt claims: ", err)
 			return res, fmt.Errorf(`invalid token`)
 		}
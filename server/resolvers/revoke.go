@@ -2,14 +2,35 @@ package resolvers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/authorizerdev/authorizer/server/graph/model"
-	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
 )
 
-// RevokeResolver resolver to revoke refresh token
+// RevokeResolver resolver to revoke access token or refresh token
 func RevokeResolver(ctx context.Context, params model.OAuthRevokeInput) (*model.Response, error) {
-	memorystore.Provider.RemoveState(params.RefreshToken)
+	tokenString := strings.TrimSpace(refs.StringValue(params.Token))
+	if tokenString == "" {
+		tokenString = strings.TrimSpace(refs.StringValue(params.RefreshToken))
+	}
+	if tokenString == "" {
+		log.Debug("Token is empty")
+		return nil, fmt.Errorf("token is required")
+	}
+	if err := token.RevokeToken(tokenString, ""); err != nil {
+		if errors.Is(err, token.ErrUnsupportedTokenType) {
+			log.Debug("Unsupported token type for revocation")
+			return nil, err
+		}
+		// invalid & expired tokens are treated as revoked
+		log.Debug("Failed to revoke token: ", err)
+	}
 	return &model.Response{
 		Message: "Token revoked",
 	}, nil
@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// RevokeOAuthGrantResolver is a resolver for revoke oauth grant mutation.
+// It removes the consent given by logged in user to oauth client & revokes the tokens issued to client,
+// so user is asked for consent on next authorization request of client
+func RevokeOAuthGrantResolver(ctx context.Context, params model.RevokeOAuthGrantRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return nil, err
+	}
+	clientID := strings.TrimSpace(params.ClientID)
+	log := log.WithFields(log.Fields{
+		"user_id":   tokenData.UserID,
+		"client_id": clientID,
+	})
+	oauthGrant, err := db.Provider.GetOAuthGrant(ctx, tokenData.UserID, clientID)
+	if err != nil || oauthGrant == nil {
+		log.Debug("Failed to get oauth grant: ", err)
+		return nil, fmt.Errorf("oauth grant not found for client_id %s", clientID)
+	}
+	err = db.Provider.DeleteOAuthGrant(ctx, oauthGrant)
+	if err != nil {
+		log.Debug("Failed to delete oauth grant: ", err)
+		return nil, err
+	}
+	// tokens of deleted client are revoked as per default token lifetime
+	client, err := utils.GetClientByClientID(ctx, clientID)
+	if err != nil || client == nil {
+		log.Debug("Failed to get client: ", err)
+		client = &models.Client{ClientID: clientID}
+	}
+	err = token.RevokeOAuthGrantTokens(client, tokenData.UserID)
+	if err != nil {
+		log.Debug("Failed to revoke tokens of oauth grant: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `OAuth grant revoked successfully`,
+	}, nil
+}
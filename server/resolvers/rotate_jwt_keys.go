@@ -0,0 +1,118 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// RotateJWTKeysResolver mutation to rotate jwt signing keys.
+// Next key becomes active, active key is retired and new next key is generated
+func RotateJWTKeysResolver(ctx context.Context, params *model.RotateJWTKeysInput) (*model.RotateJWTKeysResponse, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if params == nil {
+		params = &model.RotateJWTKeysInput{}
+	}
+
+	retiredKeyTTL := crypto.DefaultRetiredSigningKeyTTL
+	if refs.StringValue(params.RetiredKeyExpiryTime) != "" {
+		retiredKeyTTL, err = utils.ParseDurationInSeconds(refs.StringValue(params.RetiredKeyExpiryTime))
+		if err != nil {
+			log.Debug("Invalid retired key expiry time: ", err)
+			return nil, fmt.Errorf("invalid retired key expiry time: %s", err.Error())
+		}
+	}
+
+	// make sure keyring is initialized with key configured via env
+	if _, err := crypto.SyncKeyringWithEnv(retiredKeyTTL); err != nil {
+		log.Debug("Failed to sync jwt keyring: ", err)
+		return nil, err
+	}
+
+	if refs.StringValue(params.IfOlderThan) != "" {
+		minKeyAge, err := utils.ParseDurationInSeconds(refs.StringValue(params.IfOlderThan))
+		if err != nil {
+			log.Debug("Invalid if_older_than: ", err)
+			return nil, fmt.Errorf("invalid if_older_than: %s", err.Error())
+		}
+		activeKey, err := crypto.GetActiveSigningKey()
+		if err != nil {
+			log.Debug("Failed to get active signing key: ", err)
+			return nil, err
+		}
+		if time.Unix(activeKey.ActivatedAt, 0).Add(minKeyAge).After(time.Now()) {
+			keyring, err := crypto.GetKeyring()
+			if err != nil {
+				log.Debug("Failed to get jwt keyring: ", err)
+				return nil, err
+			}
+			return &model.RotateJWTKeysResponse{
+				Message: `Active key is not old enough to be rotated`,
+				Rotated: false,
+				Keys:    asAPIJWTKeys(keyring),
+			}, nil
+		}
+	}
+
+	keyring, err := crypto.RotateKeyring(retiredKeyTTL)
+	if err != nil {
+		log.Debug("Failed to rotate jwt keys: ", err)
+		return nil, err
+	}
+	jwk, err := crypto.GenerateJWKBasedOnEnv()
+	if err != nil {
+		log.Debug("Failed to generate JWK: ", err)
+		return nil, err
+	}
+	if err := memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJWK, jwk); err != nil {
+		log.Debug("Failed to update JWK: ", err)
+		return nil, err
+	}
+
+	// persist rotated keys, so that they are available across restarts
+	env, err := db.Provider.GetEnv(ctx)
+	if err != nil {
+		log.Debug("Failed to get env: ", err)
+		return nil, err
+	}
+	storeData, err := memorystore.Provider.GetEnvStore()
+	if err != nil {
+		log.Debug("Failed to get env store: ", err)
+		return nil, err
+	}
+	encryptedConfig, err := crypto.EncryptEnvData(storeData)
+	if err != nil {
+		log.Debug("Failed to encrypt env data: ", err)
+		return nil, err
+	}
+	env.EnvData = encryptedConfig
+	if _, err := db.Provider.UpdateEnv(ctx, env); err != nil {
+		log.Debug("Failed to update env: ", err)
+		return nil, err
+	}
+
+	return &model.RotateJWTKeysResponse{
+		Message: `JWT keys rotated successfully`,
+		Rotated: true,
+		Keys:    asAPIJWTKeys(keyring),
+	}, nil
+}
@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// SAMLIdentityProviderResolver resolver for getting saml identity provider by identifier
+func SAMLIdentityProviderResolver(ctx context.Context, params model.SAMLIdentityProviderRequest) (*model.SAMLIdentityProvider, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("error getting saml identity provider: ", err)
+		return nil, err
+	}
+	return samlIdentityProvider.AsAPISAMLIdentityProvider(), nil
+}
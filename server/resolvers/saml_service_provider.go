@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// SAMLServiceProviderResolver resolver for getting saml service provider by identifier
+func SAMLServiceProviderResolver(ctx context.Context, params model.SAMLServiceProviderRequest) (*model.SAMLServiceProvider, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("error getting saml service provider: ", err)
+		return nil, err
+	}
+	return samlServiceProvider.AsAPISAMLServiceProvider(), nil
+}
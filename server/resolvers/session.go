@@ -72,7 +72,8 @@ func SessionResolver(ctx context.Context, params *model.SessionQueryInput) (*mod
 	}
 
 	nonce := uuid.New().String()
-	authToken, err := token.CreateAuthToken(gc, user, claimRoles, scope, claims.LoginMethod, nonce, "")
+	// auth time of session is preserved while rolling it over
+	authToken, err := token.CreateAuthTokenForClient(gc, nil, user, claimRoles, scope, claims.LoginMethod, nonce, "", claims.IssuedAt, claims.GetSessionID(), "", nil)
 	if err != nil {
 		log.Debug("Failed to create auth token: ", err)
 		return res, err
@@ -0,0 +1,88 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// UnlinkIdentityResolver is a resolver for unlink identity mutation.
+// It removes the identity of login provider from logged in user,
+// last identity can be unlinked only if user can login with password
+func UnlinkIdentityResolver(ctx context.Context, params model.UnlinkIdentityRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	tokenData, err := token.GetUserIDFromSessionOrAccessToken(gc)
+	if err != nil {
+		log.Debug("Failed GetUserIDFromSessionOrAccessToken: ", err)
+		return nil, err
+	}
+	log := log.WithFields(log.Fields{
+		"user_id":     tokenData.UserID,
+		"identity_id": params.ID,
+	})
+	identity, err := db.Provider.GetIdentityByID(ctx, params.ID)
+	if err != nil || identity == nil || identity.UserID != tokenData.UserID {
+		log.Debug("Failed to get identity: ", err)
+		return nil, fmt.Errorf("identity not found")
+	}
+	user, err := db.Provider.GetUserByID(ctx, tokenData.UserID)
+	if err != nil {
+		log.Debug("Failed to get user: ", err)
+		return nil, err
+	}
+	identities, err := db.Provider.ListIdentities(ctx, &model.Pagination{
+		Limit: 100,
+	}, user.ID)
+	if err != nil {
+		log.Debug("Failed to get identities: ", err)
+		return nil, err
+	}
+	hasOtherIdentity := false
+	hasOtherProviderIdentity := false
+	for _, i := range identities.Identities {
+		if i.ID == params.ID {
+			continue
+		}
+		hasOtherIdentity = true
+		if i.Provider == identity.Provider {
+			hasOtherProviderIdentity = true
+		}
+	}
+	if !hasOtherIdentity && user.Password == nil {
+		log.Debug("Last identity of user without password can not be unlinked")
+		return nil, fmt.Errorf("identity can not be unlinked as it is the only login method of user")
+	}
+	err = db.Provider.DeleteIdentity(ctx, identity)
+	if err != nil {
+		log.Debug("Failed to delete identity: ", err)
+		return nil, err
+	}
+	// user can not login with provider once all identities of provider are unlinked
+	if !hasOtherProviderIdentity {
+		signupMethods := []string{}
+		for _, method := range strings.Split(user.SignupMethods, ",") {
+			if method != identity.Provider {
+				signupMethods = append(signupMethods, method)
+			}
+		}
+		user.SignupMethods = strings.Join(signupMethods, ",")
+		if _, err := db.Provider.UpdateUser(ctx, user); err != nil {
+			log.Debug("Failed to update user: ", err)
+			return nil, err
+		}
+	}
+	return &model.Response{
+		Message: `Identity unlinked successfully`,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// UpdateClientResolver resolver for update oauth client mutation
+func UpdateClientResolver(ctx context.Context, params model.UpdateClientRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	log := log.WithField("id", params.ID)
+	client, err := db.Provider.GetClientByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get client: ", err)
+		return nil, err
+	}
+	if err := validateClientParams(params.RedirectUris, params.GrantTypes, params.AccessTokenExpiryTime, params.RefreshTokenExpiryTime); err != nil {
+		log.Debug("Invalid client params: ", err)
+		return nil, err
+	}
+	if err := validateClientLogoutParams(params.PostLogoutRedirectUris, params.FrontchannelLogoutURI, params.BackchannelLogoutURI); err != nil {
+		log.Debug("Invalid client logout params: ", err)
+		return nil, err
+	}
+	if params.Name != nil {
+		if strings.TrimSpace(refs.StringValue(params.Name)) == "" {
+			log.Debug("empty name not allowed")
+			return nil, fmt.Errorf("empty name not allowed")
+		}
+		client.Name = strings.TrimSpace(refs.StringValue(params.Name))
+	}
+	if params.RedirectUris != nil {
+		client.RedirectURIs = strings.Join(params.RedirectUris, ",")
+	}
+	if params.GrantTypes != nil {
+		client.GrantTypes = strings.Join(params.GrantTypes, ",")
+	}
+	if params.Scopes != nil {
+		client.Scopes = strings.Join(params.Scopes, ",")
+	}
+	if params.AccessTokenExpiryTime != nil {
+		client.AccessTokenExpiryTime = refs.StringValue(params.AccessTokenExpiryTime)
+	}
+	if params.RefreshTokenExpiryTime != nil {
+		client.RefreshTokenExpiryTime = refs.StringValue(params.RefreshTokenExpiryTime)
+	}
+	if params.RequirePushedAuthorizationRequests != nil {
+		client.RequirePushedAuthorizationRequests = refs.BoolValue(params.RequirePushedAuthorizationRequests)
+	}
+	if params.RequireSignedRequestObject != nil {
+		client.RequireSignedRequestObject = refs.BoolValue(params.RequireSignedRequestObject)
+	}
+	if params.PostLogoutRedirectUris != nil {
+		client.PostLogoutRedirectURIs = strings.Join(params.PostLogoutRedirectUris, ",")
+	}
+	if params.FrontchannelLogoutURI != nil {
+		client.FrontchannelLogoutURI = refs.StringValue(params.FrontchannelLogoutURI)
+	}
+	if params.BackchannelLogoutURI != nil {
+		client.BackchannelLogoutURI = refs.StringValue(params.BackchannelLogoutURI)
+	}
+	if params.TokenExchangeAudiences != nil {
+		client.TokenExchangeAudiences = strings.Join(params.TokenExchangeAudiences, ",")
+	}
+	previousTokenEndpointAuthMethod := client.GetTokenEndpointAuthMethod()
+	if params.TokenEndpointAuthMethod != nil {
+		client.TokenEndpointAuthMethod = strings.TrimSpace(refs.StringValue(params.TokenEndpointAuthMethod))
+	}
+	// secret is stored as per token endpoint auth method of client, i.e. encrypted for client_secret_jwt
+	// and hashed otherwise, so new secret is required when client is switched to or from client_secret_jwt
+	if strings.TrimSpace(refs.StringValue(params.ClientSecret)) != "" {
+		hashedClientSecret, err := crypto.EncryptClientSecret(client.TokenEndpointAuthMethod, strings.TrimSpace(refs.StringValue(params.ClientSecret)))
+		if err != nil {
+			log.Debug("Failed to encrypt client secret: ", err)
+			return nil, err
+		}
+		client.ClientSecret = hashedClientSecret
+	} else if (previousTokenEndpointAuthMethod == constants.TokenEndpointAuthMethodClientSecretJWT) != (client.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodClientSecretJWT) {
+		log.Debug("Client secret is required for changing token endpoint auth method")
+		return nil, fmt.Errorf("client secret is required when token endpoint auth method is changed to or from %s", constants.TokenEndpointAuthMethodClientSecretJWT)
+	}
+	if params.Jwks != nil {
+		client.JWKS = strings.TrimSpace(refs.StringValue(params.Jwks))
+	}
+	if params.JwksURI != nil {
+		client.JWKSURI = strings.TrimSpace(refs.StringValue(params.JwksURI))
+	}
+	if err := validateClientAuthenticationParams(client.TokenEndpointAuthMethod, client.JWKS, client.JWKSURI, client.RequireSignedRequestObject); err != nil {
+		log.Debug("Invalid client authentication params: ", err)
+		return nil, err
+	}
+	if params.SubjectType != nil {
+		client.SubjectType = strings.TrimSpace(refs.StringValue(params.SubjectType))
+	}
+	if params.SectorIdentifierURI != nil {
+		client.SectorIdentifierURI = strings.TrimSpace(refs.StringValue(params.SectorIdentifierURI))
+	}
+	// sector identifier uri is fetched for validation, hence it is validated only when it is affected
+	if params.SubjectType != nil || params.SectorIdentifierURI != nil || params.RedirectUris != nil {
+		if err := validateClientSubjectParams(client); err != nil {
+			log.Debug("Invalid client subject params: ", err)
+			return nil, err
+		}
+	}
+	if _, err := db.Provider.UpdateClient(ctx, client); err != nil {
+		log.Debug("failed to update client: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `Client updated successfully`,
+	}, nil
+}
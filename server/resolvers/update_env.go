@@ -18,6 +18,7 @@ import (
 	"github.com/authorizerdev/authorizer/server/graph/model"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 	"github.com/authorizerdev/authorizer/server/oauth"
+	"github.com/authorizerdev/authorizer/server/refs"
 	"github.com/authorizerdev/authorizer/server/token"
 	"github.com/authorizerdev/authorizer/server/utils"
 )
@@ -261,6 +262,25 @@ func UpdateEnvResolver(ctx context.Context, params model.UpdateEnvInput) (*model
 		return res, fmt.Errorf("error un-marshalling params: %t", err)
 	}
 
+	// empty value resets the lifetime to default, idle timeout & max age are disabled with empty value
+	durationParams := map[string]*string{
+		constants.EnvKeyAccessTokenExpiryTime:  params.AccessTokenExpiryTime,
+		constants.EnvKeyRefreshTokenExpiryTime: params.RefreshTokenExpiryTime,
+		constants.EnvKeySessionExpiryTime:      params.SessionExpiryTime,
+		constants.EnvKeySessionIdleTimeout:     params.SessionIdleTimeout,
+		constants.EnvKeySessionMaxAge:          params.SessionMaxAge,
+		constants.EnvKeyOTPExpiryTime:          params.OtpExpiryTime,
+	}
+	for key, val := range durationParams {
+		if refs.StringValue(val) == "" {
+			continue
+		}
+		if _, err := utils.ParseDurationInSeconds(*val); err != nil {
+			log.Debug("Invalid duration for ", key, ": ", err)
+			return res, fmt.Errorf("invalid %s: %s", key, err.Error())
+		}
+	}
+
 	// in case of admin secret change update the cookie with new hash
 	if params.AdminSecret != nil {
 		if params.OldAdminSecret == nil {
@@ -376,6 +396,12 @@ func UpdateEnvResolver(ctx context.Context, params model.UpdateEnvInput) (*model
 
 	// Update local store
 	memorystore.Provider.UpdateEnvStore(updatedData)
+	// if jwt keys are changed, previous key is retired
+	if _, err := crypto.SyncKeyringWithEnv(crypto.DefaultRetiredSigningKeyTTL); err != nil {
+		log.Debug("Failed to sync jwt keyring: ", err)
+		return res, err
+	}
+	updatedData[constants.EnvKeyJwtKeyring], _ = memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtKeyring)
 	jwk, err := crypto.GenerateJWKBasedOnEnv()
 	if err != nil {
 		log.Debug("Failed to generate JWK: ", err)
@@ -0,0 +1,83 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// UpdateIdentityProviderResolver resolver for update upstream identity provider mutation
+func UpdateIdentityProviderResolver(ctx context.Context, params model.UpdateIdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	log := log.WithField("id", params.ID)
+	identityProvider, err := db.Provider.GetIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get identity provider: ", err)
+		return nil, err
+	}
+	if params.DiscoveryURL != nil {
+		identityProvider.DiscoveryURL = strings.TrimSpace(refs.StringValue(params.DiscoveryURL))
+	}
+	if params.AuthorizationURL != nil {
+		identityProvider.AuthorizationURL = strings.TrimSpace(refs.StringValue(params.AuthorizationURL))
+	}
+	if params.TokenURL != nil {
+		identityProvider.TokenURL = strings.TrimSpace(refs.StringValue(params.TokenURL))
+	}
+	if params.UserinfoURL != nil {
+		identityProvider.UserInfoURL = strings.TrimSpace(refs.StringValue(params.UserinfoURL))
+	}
+	if err := validateIdentityProviderEndpoints(identityProvider); err != nil {
+		log.Debug("Invalid identity provider endpoints: ", err)
+		return nil, err
+	}
+	if params.ClientID != nil {
+		if strings.TrimSpace(refs.StringValue(params.ClientID)) == "" {
+			log.Debug("empty client id not allowed")
+			return nil, fmt.Errorf("empty client id not allowed")
+		}
+		identityProvider.ClientID = strings.TrimSpace(refs.StringValue(params.ClientID))
+	}
+	if strings.TrimSpace(refs.StringValue(params.ClientSecret)) != "" {
+		encryptedClientSecret, err := crypto.EncryptAES(strings.TrimSpace(refs.StringValue(params.ClientSecret)))
+		if err != nil {
+			log.Debug("Failed to encrypt client secret: ", err)
+			return nil, err
+		}
+		identityProvider.ClientSecret = encryptedClientSecret
+	}
+	if params.Scopes != nil {
+		identityProvider.Scopes = strings.Join(params.Scopes, ",")
+	}
+	if params.ClaimMapping != nil {
+		identityProvider.ClaimMapping, err = getIdentityProviderClaimMapping(params.ClaimMapping)
+		if err != nil {
+			log.Debug("Invalid claim mapping: ", err)
+			return nil, err
+		}
+	}
+	if _, err := db.Provider.UpdateIdentityProvider(ctx, identityProvider); err != nil {
+		log.Debug("failed to update identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `Identity provider updated successfully`,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// UpdateSAMLIdentityProviderResolver resolver for update saml identity provider mutation
+func UpdateSAMLIdentityProviderResolver(ctx context.Context, params model.UpdateSAMLIdentityProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	log := log.WithField("id", params.ID)
+	samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get saml identity provider: ", err)
+		return nil, err
+	}
+	if params.MetadataURL != nil {
+		samlIdentityProvider.MetadataURL = strings.TrimSpace(refs.StringValue(params.MetadataURL))
+	}
+	if params.MetadataXML != nil {
+		samlIdentityProvider.MetadataXML = strings.TrimSpace(refs.StringValue(params.MetadataXML))
+	}
+	// metadata is fetched again from metadata url, hence update also refreshes pinned metadata
+	if err := validateSAMLIdentityProviderMetadata(ctx, samlIdentityProvider); err != nil {
+		log.Debug("Invalid saml identity provider metadata: ", err)
+		return nil, err
+	}
+	if params.AttributeMapping != nil {
+		samlIdentityProvider.AttributeMapping, err = getIdentityProviderClaimMapping(params.AttributeMapping)
+		if err != nil {
+			log.Debug("Invalid attribute mapping: ", err)
+			return nil, err
+		}
+	}
+	if params.AllowedEmailDomains != nil {
+		samlIdentityProvider.AllowedEmailDomains, err = getAllowedEmailDomains(params.AllowedEmailDomains)
+		if err != nil {
+			log.Debug("Invalid allowed email domains: ", err)
+			return nil, err
+		}
+	}
+	if _, err := db.Provider.UpdateSAMLIdentityProvider(ctx, samlIdentityProvider); err != nil {
+		log.Debug("failed to update saml identity provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `SAML identity provider updated successfully`,
+	}, nil
+}
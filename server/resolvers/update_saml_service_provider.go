@@ -0,0 +1,84 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/authorizerdev/authorizer/server/utils"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// UpdateSAMLServiceProviderResolver resolver for update saml service provider mutation
+func UpdateSAMLServiceProviderResolver(ctx context.Context, params model.UpdateSAMLServiceProviderRequest) (*model.Response, error) {
+	gc, err := utils.GinContextFromContext(ctx)
+	if err != nil {
+		log.Debug("Failed to get GinContext: ", err)
+		return nil, err
+	}
+	if !token.IsSuperAdmin(gc) {
+		log.Debug("Not logged in as super admin")
+		return nil, fmt.Errorf("unauthorized")
+	}
+	log := log.WithField("id", params.ID)
+	samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByID(ctx, params.ID)
+	if err != nil {
+		log.Debug("failed to get saml service provider: ", err)
+		return nil, err
+	}
+	if params.Name != nil {
+		if strings.TrimSpace(refs.StringValue(params.Name)) == "" {
+			log.Debug("empty name not allowed")
+			return nil, fmt.Errorf("empty name not allowed")
+		}
+		samlServiceProvider.Name = strings.TrimSpace(refs.StringValue(params.Name))
+	}
+	if params.EntityID != nil {
+		entityID := strings.TrimSpace(refs.StringValue(params.EntityID))
+		if entityID == "" {
+			log.Debug("empty entity id not allowed")
+			return nil, fmt.Errorf("empty entity id not allowed")
+		}
+		if existingSAMLServiceProvider, err := db.Provider.GetSAMLServiceProviderByEntityID(ctx, entityID); err == nil && existingSAMLServiceProvider != nil && existingSAMLServiceProvider.ID != samlServiceProvider.ID {
+			log.Debug("SAML service provider already exists: ", entityID)
+			return nil, fmt.Errorf("saml service provider with entity id %s already exists", entityID)
+		}
+		samlServiceProvider.EntityID = entityID
+	}
+	if params.AcsURL != nil {
+		acsURL := strings.TrimSpace(refs.StringValue(params.AcsURL))
+		if !validators.IsValidSAMLACSURL(acsURL) {
+			log.Debug("Invalid acs url: ", acsURL)
+			return nil, fmt.Errorf("invalid acs url %s", acsURL)
+		}
+		samlServiceProvider.ACSURL = acsURL
+	}
+	if params.NameIDFormat != nil {
+		nameIDFormat := strings.TrimSpace(refs.StringValue(params.NameIDFormat))
+		if !validators.IsValidSAMLNameIDFormat(nameIDFormat) {
+			log.Debug("Invalid name id format: ", nameIDFormat)
+			return nil, fmt.Errorf("invalid name id format %s", nameIDFormat)
+		}
+		samlServiceProvider.NameIDFormat = nameIDFormat
+	}
+	if params.AttributeMapping != nil {
+		samlServiceProvider.AttributeMapping, err = getSAMLAttributeMapping(params.AttributeMapping)
+		if err != nil {
+			log.Debug("Invalid attribute mapping: ", err)
+			return nil, err
+		}
+	}
+	if _, err := db.Provider.UpdateSAMLServiceProvider(ctx, samlServiceProvider); err != nil {
+		log.Debug("failed to update saml service provider: ", err)
+		return nil, err
+	}
+	return &model.Response{
+		Message: `SAML service provider updated successfully`,
+	}, nil
+}
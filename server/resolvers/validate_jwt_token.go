@@ -45,7 +45,8 @@ func ValidateJwtTokenResolver(ctx context.Context, params model.ValidateJWTToken
 		log.Debug("Failed to parse JWT token: ", err)
 		return nil, err
 	}
-	userID = claims["sub"].(string)
+	subject := claims["sub"].(string)
+	userID = token.GetUserIDFromSubject(subject)
 
 	// access_token and refresh_token should be validated from session store as well
 	if tokenType == constants.TokenTypeAccessToken || tokenType == constants.TokenTypeRefreshToken {
@@ -66,12 +67,12 @@ func ValidateJwtTokenResolver(ctx context.Context, params model.ValidateJWTToken
 
 	// we cannot validate nonce in case of id_token as that token is not persisted in session store
 	if nonce != "" {
-		if ok, err := token.ValidateJWTClaims(claims, hostname, nonce, userID); !ok || err != nil {
+		if ok, err := token.ValidateJWTClaims(ctx, claims, hostname, nonce, subject); !ok || err != nil {
 			log.Debug("Failed to parse jwt token: ", err)
 			return nil, errors.New("invalid claims")
 		}
 	} else {
-		if ok, err := token.ValidateJWTTokenWithoutNonce(claims, hostname, userID); !ok || err != nil {
+		if ok, err := token.ValidateJWTTokenWithoutNonce(ctx, claims, hostname, subject); !ok || err != nil {
 			log.Debug("Failed to parse jwt token without nonce: ", err)
 			return nil, errors.New("invalid claims")
 		}
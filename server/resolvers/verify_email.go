@@ -46,7 +46,7 @@ func VerifyEmailResolver(ctx context.Context, params model.VerifyEmailInput) (*m
 		return res, fmt.Errorf(`invalid token: %s`, err.Error())
 	}
 
-	if ok, err := token.ValidateJWTClaims(claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
+	if ok, err := token.ValidateJWTClaims(ctx, claim, hostname, verificationRequest.Nonce, verificationRequest.Email); !ok || err != nil {
 		log.Debug("Failed to validate jwt claims: ", err)
 		return res, fmt.Errorf(`invalid token: %s`, err.Error())
 	}
@@ -33,7 +33,24 @@ func InitRouter(log *logrus.Logger) *gin.Engine {
 	router.GET("/userinfo", handlers.UserInfoHandler())
 	router.GET("/logout", handlers.LogoutHandler())
 	router.POST("/oauth/token", handlers.TokenHandler())
-	router.POST("/oauth/revoke", handlers.RevokeRefreshTokenHandler())
+	router.POST("/oauth/revoke", handlers.RevokeTokenHandler())
+	router.POST("/oauth/introspect", handlers.IntrospectHandler())
+	router.POST("/oauth/device/code", handlers.DeviceCodeHandler())
+	router.POST("/oauth/par", handlers.PushedAuthorizationRequestHandler())
+	router.POST("/oauth/consent", handlers.ConsentHandler())
+	router.GET("/oauth/logout", handlers.EndSessionHandler())
+	router.POST("/oauth/logout", handlers.EndSessionHandler())
+	router.POST("/oauth/register", handlers.ClientRegistrationHandler())
+	router.GET("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	router.PUT("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	router.DELETE("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	// SAML identity provider routes
+	router.GET("/saml/metadata", handlers.SAMLMetadataHandler())
+	router.GET("/saml/sso", handlers.SAMLSSOHandler())
+	router.POST("/saml/sso", handlers.SAMLSSOHandler())
+	// SAML service provider routes for upstream saml identity providers, login is via /oauth_login/:oauth_provider
+	router.GET("/saml_metadata/:saml_provider", handlers.SAMLServiceProviderMetadataHandler())
+	router.POST("/saml_acs/:saml_provider", handlers.SAMLACSHandler())
 
 	router.LoadHTMLGlob("templates/*")
 	// login page app related routes.
@@ -0,0 +1,71 @@
+package saml
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// AuthnRequestExpiresIn is the time in seconds within which user should login for resuming saml authentication request
+	AuthnRequestExpiresIn = 600
+
+	authnRequestStatePrefix = "saml_authn_request:"
+)
+
+// AuthnRequest is the saml authentication request waiting for user login.
+// ID is used for resuming the request via /saml/sso once user has logged in
+type AuthnRequest struct {
+	ID         string `json:"id"`
+	Request    []byte `json:"request"`
+	RelayState string `json:"relay_state"`
+	ReceivedAt int64  `json:"received_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+// IsExpired returns true if user has not logged in on time
+func (a *AuthnRequest) IsExpired() bool {
+	return a.ExpiresAt < time.Now().Unix()
+}
+
+// SetAuthnRequest saves the saml authentication request in state store with newly generated id
+func SetAuthnRequest(req *gosaml.IdpAuthnRequest) (*AuthnRequest, error) {
+	authnRequest := &AuthnRequest{
+		ID:         uuid.New().String(),
+		Request:    req.RequestBuffer,
+		RelayState: req.RelayState,
+		ReceivedAt: req.Now.Unix(),
+		ExpiresAt:  time.Now().Unix() + AuthnRequestExpiresIn,
+	}
+	data, err := json.Marshal(authnRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := memorystore.Provider.SetState(authnRequestStatePrefix+authnRequest.ID, string(data)); err != nil {
+		return nil, err
+	}
+	return authnRequest, nil
+}
+
+// ConsumeAuthnRequest returns the saml authentication request for given id
+// and removes it from state store, as it can be resumed only once
+func ConsumeAuthnRequest(id string) (*AuthnRequest, error) {
+	data, err := memorystore.Provider.GetState(authnRequestStatePrefix + id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid authn request")
+	}
+	memorystore.Provider.RemoveState(authnRequestStatePrefix + id)
+	var authnRequest AuthnRequest
+	if err := json.Unmarshal([]byte(data), &authnRequest); err != nil {
+		return nil, err
+	}
+	if authnRequest.IsExpired() {
+		return nil, fmt.Errorf("authn request has expired")
+	}
+	return &authnRequest, nil
+}
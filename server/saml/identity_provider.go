@@ -0,0 +1,171 @@
+package saml
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+const (
+	nameIDFormatEmail      = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	nameIDFormatPersistent = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	attributeNameFormat    = "urn:oasis:names:tc:SAML:2.0:attrname-format:basic"
+)
+
+// GetIdentityProvider returns the saml identity provider of instance,
+// assertions are signed with SAML_IDP_PRIVATE_KEY & metadata urls are based on hostname
+func GetIdentityProvider(hostname string) (*gosaml.IdentityProvider, error) {
+	privateKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ParseRsaPrivateKeyFromPemStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := crypto.ParseCertificateFromPemStr(certificate)
+	if err != nil {
+		return nil, err
+	}
+	metadataURL, err := url.Parse(hostname + "/saml/metadata")
+	if err != nil {
+		return nil, err
+	}
+	ssoURL, err := url.Parse(hostname + "/saml/sso")
+	if err != nil {
+		return nil, err
+	}
+	return &gosaml.IdentityProvider{
+		Key:                     key,
+		Certificate:             cert,
+		Logger:                  log.StandardLogger(),
+		MetadataURL:             *metadataURL,
+		SSOURL:                  *ssoURL,
+		ServiceProviderProvider: serviceProviderProvider{},
+		SignatureMethod:         dsig.RSASHA256SignatureMethod,
+	}, nil
+}
+
+// serviceProviderProvider returns the metadata of saml service providers registered by admin
+type serviceProviderProvider struct{}
+
+// GetServiceProvider returns the metadata of service provider for given entity id,
+// only the registered acs url is allowed for sending assertions
+func (serviceProviderProvider) GetServiceProvider(r *http.Request, serviceProviderID string) (*gosaml.EntityDescriptor, error) {
+	samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByEntityID(r.Context(), serviceProviderID)
+	if err != nil || samlServiceProvider == nil {
+		log.Debug("Failed to get saml service provider: ", err)
+		return nil, os.ErrNotExist
+	}
+	return &gosaml.EntityDescriptor{
+		EntityID: samlServiceProvider.EntityID,
+		SPSSODescriptors: []gosaml.SPSSODescriptor{
+			{
+				AssertionConsumerServices: []gosaml.IndexedEndpoint{
+					{
+						Binding:  gosaml.HTTPPostBinding,
+						Location: samlServiceProvider.ACSURL,
+						Index:    1,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// NewSession returns the saml session of user for the service provider,
+// subject & attributes of assertion are based on name id format & attribute mapping of service provider
+func NewSession(user *models.User, samlServiceProvider *models.SAMLServiceProvider, sessionData *token.SessionData) *gosaml.Session {
+	session := &gosaml.Session{
+		ID:           sessionData.Nonce,
+		CreateTime:   time.Unix(sessionData.IssuedAt, 0),
+		ExpireTime:   time.Unix(sessionData.ExpiresAt, 0),
+		Index:        fmt.Sprintf("%x", sha256.Sum256([]byte(sessionData.Nonce))),
+		NameID:       refs.StringValue(user.Email),
+		NameIDFormat: nameIDFormatEmail,
+	}
+	if samlServiceProvider.NameIDFormat == constants.SAMLNameIDFormatPersistent {
+		session.NameID = user.ID
+		session.NameIDFormat = nameIDFormatPersistent
+	}
+
+	attributeMapping := samlServiceProvider.GetAttributeMapping()
+	attributes := make([]string, 0, len(attributeMapping))
+	for attribute := range attributeMapping {
+		attributes = append(attributes, attribute)
+	}
+	// sorted for consistent order of attributes in assertion
+	sort.Strings(attributes)
+	for _, attribute := range attributes {
+		values := []gosaml.AttributeValue{}
+		for _, value := range getUserFieldValues(user, sessionData.Roles, attributeMapping[attribute]) {
+			values = append(values, gosaml.AttributeValue{
+				Type:  "xs:string",
+				Value: value,
+			})
+		}
+		if len(values) == 0 {
+			continue
+		}
+		session.CustomAttributes = append(session.CustomAttributes, gosaml.Attribute{
+			Name:       attribute,
+			NameFormat: attributeNameFormat,
+			Values:     values,
+		})
+	}
+	return session
+}
+
+// getUserFieldValues returns the values of user field, roles is the only multi valued field
+// and roles of browser session are used for it, same as the roles of tokens issued for the session
+func getUserFieldValues(user *models.User, roles []string, field string) []string {
+	value := ""
+	switch field {
+	case "id":
+		value = user.ID
+	case "email":
+		value = refs.StringValue(user.Email)
+	case "given_name":
+		value = refs.StringValue(user.GivenName)
+	case "family_name":
+		value = refs.StringValue(user.FamilyName)
+	case "middle_name":
+		value = refs.StringValue(user.MiddleName)
+	case "nickname":
+		value = refs.StringValue(user.Nickname)
+	case "picture":
+		value = refs.StringValue(user.Picture)
+	case "gender":
+		value = refs.StringValue(user.Gender)
+	case "birthdate":
+		value = refs.StringValue(user.Birthdate)
+	case "phone_number":
+		value = refs.StringValue(user.PhoneNumber)
+	case "roles":
+		return roles
+	}
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
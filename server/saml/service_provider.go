@@ -0,0 +1,178 @@
+package saml
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/oauth"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/validators"
+)
+
+// identityProviderMetadataMaxSize is the max size of metadata fetched from metadata url of saml identity provider
+const identityProviderMetadataMaxSize = 1 << 20
+
+// GetServiceProvider returns the saml service provider of instance for upstream saml identity provider,
+// pinned metadata of identity provider is used for validating the signature of assertions
+func GetServiceProvider(samlIdentityProvider *models.SAMLIdentityProvider, hostname string) (*gosaml.ServiceProvider, error) {
+	serviceProvider, err := newServiceProvider(samlIdentityProvider, hostname)
+	if err != nil {
+		return nil, err
+	}
+	serviceProvider.IDPMetadata, err = ParseIdentityProviderMetadata([]byte(samlIdentityProvider.MetadataXML))
+	if err != nil {
+		return nil, err
+	}
+	return serviceProvider, nil
+}
+
+// GetServiceProviderMetadata returns the metadata of saml service provider for upstream saml identity provider,
+// it is used for configuring the identity provider hence metadata of identity provider is not required
+func GetServiceProviderMetadata(samlIdentityProvider *models.SAMLIdentityProvider, hostname string) (*gosaml.EntityDescriptor, error) {
+	serviceProvider, err := newServiceProvider(samlIdentityProvider, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return serviceProvider.Metadata(), nil
+}
+
+// newServiceProvider returns the saml service provider, entity id of service provider is its metadata url.
+// Authn requests are signed with the same key which is used for signing assertions in identity provider mode
+func newServiceProvider(samlIdentityProvider *models.SAMLIdentityProvider, hostname string) (*gosaml.ServiceProvider, error) {
+	privateKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeySAMLIdPCertificate)
+	if err != nil {
+		return nil, err
+	}
+	key, err := crypto.ParseRsaPrivateKeyFromPemStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := crypto.ParseCertificateFromPemStr(certificate)
+	if err != nil {
+		return nil, err
+	}
+	metadataURL, err := url.Parse(hostname + "/saml_metadata/" + samlIdentityProvider.Name)
+	if err != nil {
+		return nil, err
+	}
+	acsURL, err := url.Parse(hostname + "/saml_acs/" + samlIdentityProvider.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &gosaml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		Key:               key,
+		Certificate:       cert,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		AuthnNameIDFormat: gosaml.UnspecifiedNameIDFormat,
+		SignatureMethod:   dsig.RSASHA256SignatureMethod,
+	}, nil
+}
+
+// FetchIdentityProviderMetadata fetches the metadata of saml identity provider from metadata url,
+// it is fetched only when identity provider is added or updated and is pinned in metadata xml
+func FetchIdentityProviderMetadata(ctx context.Context, metadataURL string) ([]byte, error) {
+	if !validators.IsValidIdentityProviderURL(metadataURL) {
+		return nil, fmt.Errorf("invalid url %s", metadataURL)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch saml identity provider metadata: %s", err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch saml identity provider metadata: %s", res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, identityProviderMetadataMaxSize))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseIdentityProviderMetadata(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ParseIdentityProviderMetadata parses the metadata xml of saml identity provider,
+// for metadata with multiple entities the first entity having identity provider descriptor is used
+func ParseIdentityProviderMetadata(data []byte) (*gosaml.EntityDescriptor, error) {
+	entities := []gosaml.EntityDescriptor{}
+	entity := gosaml.EntityDescriptor{}
+	if err := xml.Unmarshal(data, &entity); err == nil {
+		entities = append(entities, entity)
+	} else {
+		entitiesDescriptor := gosaml.EntitiesDescriptor{}
+		if err := xml.Unmarshal(data, &entitiesDescriptor); err != nil {
+			return nil, fmt.Errorf("invalid saml metadata: %s", err.Error())
+		}
+		entities = entitiesDescriptor.EntityDescriptors
+	}
+	for i := range entities {
+		for _, idpSSODescriptor := range entities[i].IDPSSODescriptors {
+			if len(idpSSODescriptor.SingleSignOnServices) > 0 {
+				return &entities[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid saml metadata: single sign on service of identity provider not found")
+}
+
+// GetSAMLAssertionAttributes returns the attributes of assertion keyed by name & friendly name,
+// attribute having single value is returned as value instead of list
+func GetSAMLAssertionAttributes(assertion *gosaml.Assertion) map[string]interface{} {
+	attributes := map[string]interface{}{}
+	for _, attributeStatement := range assertion.AttributeStatements {
+		for _, attribute := range attributeStatement.Attributes {
+			values := []interface{}{}
+			for _, value := range attribute.Values {
+				values = append(values, value.Value)
+			}
+			var claim interface{} = values
+			if len(values) == 1 {
+				claim = values[0]
+			}
+			attributes[attribute.Name] = claim
+			if attribute.FriendlyName != "" {
+				attributes[attribute.FriendlyName] = claim
+			}
+		}
+	}
+	return attributes
+}
+
+// GetSAMLIdentityProviderUser returns the user with fields mapped from attributes of verified assertion.
+// Name id is used as email when email attribute is not present and name id is an email address
+func GetSAMLIdentityProviderUser(samlIdentityProvider *models.SAMLIdentityProvider, assertion *gosaml.Assertion) *models.User {
+	user := oauth.GetMappedUser(samlIdentityProvider.GetAttributeMapping(), GetSAMLAssertionAttributes(assertion))
+	if user.Email == nil && assertion.Subject != nil && assertion.Subject.NameID != nil {
+		nameID := strings.TrimSpace(assertion.Subject.NameID.Value)
+		if validators.IsValidEmail(nameID) {
+			user.Email = refs.NewStringRef(nameID)
+		}
+	}
+	return user
+}
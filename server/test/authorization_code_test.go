@@ -0,0 +1,165 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func authorizationCodeTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should exchange authorization code only by client to which it was issued`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		redirectURI := "https://code.example.com/callback"
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "authorization code client",
+			RedirectUris: []string{redirectURI},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		otherClient, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "other authorization code client",
+			RedirectUris: []string{"https://other.code.example.com/callback"},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: otherClient.Client.ID})
+		publicClient, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "public authorization code client",
+			RedirectUris:            []string{redirectURI},
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodNone),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: publicClient.Client.ID})
+		req.Header.Del("Cookie")
+
+		email := "authorization_code." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		for _, clientID := range []string{client.Client.ClientID, publicClient.Client.ClientID} {
+			_, err = db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+				UserID:   verifyRes.User.ID,
+				ClientID: clientID,
+				Scopes:   "openid,profile,email",
+			})
+			assert.NoError(t, err)
+		}
+
+		codeVerifier := "authorization_code_test_code_verifier_0123456789"
+		hash := sha256.Sum256([]byte(codeVerifier))
+		codeChallenge := base64.RawURLEncoding.EncodeToString(hash[:])
+		// getCode logs in & returns the code issued by /authorize for existing session
+		getCode := func(clientID string) string {
+			loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+				Email:    refs.NewStringRef(email),
+				Password: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+			assert.NoError(t, err)
+			nonce, _ := claims["nonce"].(string)
+			sessionToken, err := memorystore.Provider.GetUserSession(constants.AuthRecipeMethodBasicAuth+":"+verifyRes.User.ID, constants.TokenTypeSessionToken+"_"+nonce)
+			assert.NoError(t, err)
+			header := http.Header{}
+			header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken))
+			res := getRequest(t, s, "/authorize?"+url.Values{
+				"client_id":      {clientID},
+				"redirect_uri":   {redirectURI},
+				"response_type":  {constants.ResponseTypeCode},
+				"response_mode":  {constants.ResponseModeQuery},
+				"state":          {"authorization_code_state"},
+				"prompt":         {constants.PromptNone},
+				"code_challenge": {codeChallenge},
+			}.Encode(), header)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusFound, res.StatusCode)
+			location, err := url.Parse(res.Header.Get("Location"))
+			assert.NoError(t, err)
+			code := location.Query().Get("code")
+			assert.NotEmpty(t, code)
+			return code
+		}
+		exchange := func(code string, params url.Values) (int, map[string]interface{}) {
+			data := url.Values{
+				"grant_type":    {constants.GrantTypeAuthorizationCode},
+				"code":          {code},
+				"code_verifier": {codeVerifier},
+				"redirect_uri":  {redirectURI},
+				"client_id":     {client.Client.ClientID},
+				"client_secret": {client.ClientSecret},
+			}
+			for key, values := range params {
+				data[key] = values
+			}
+			return postForm(t, s, "/oauth/token", data, nil)
+		}
+
+		// confidential client should authenticate even when code verifier is sent
+		status, body := exchange(getCode(client.Client.ClientID), url.Values{"client_secret": {""}})
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		// code of another client cannot be exchanged with own credentials
+		status, body = exchange(getCode(client.Client.ClientID), url.Values{
+			"client_id":     {otherClient.Client.ClientID},
+			"client_secret": {otherClient.ClientSecret},
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		// redirect uri should be same as of authorization request
+		status, body = exchange(getCode(client.Client.ClientID), url.Values{"redirect_uri": {"https://code.example.com/other"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+		status, body = exchange(getCode(client.Client.ClientID), url.Values{"redirect_uri": {""}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		// code verifier cannot be skipped by authenticating with client secret
+		status, body = exchange(getCode(client.Client.ClientID), url.Values{"code_verifier": {""}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_code_verifier", body["error"])
+
+		status, body = exchange(getCode(client.Client.ClientID), nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, body["access_token"])
+
+		// public client is identified by client id & code verifier
+		status, body = exchange(getCode(publicClient.Client.ClientID), url.Values{
+			"client_id":     {publicClient.Client.ClientID},
+			"client_secret": {""},
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, body["access_token"])
+	})
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func authorizationResponseTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should create jwt secured authorization response`, func(t *testing.T) {
+		createContext(s)
+		clientID := uuid.New().String()
+		code := uuid.New().String()
+		hostname := parsers.GetHost(s.GinContext)
+
+		responseToken, err := token.CreateAuthorizationResponseToken(clientID, hostname, map[string]interface{}{
+			"code":  code,
+			"state": "test-state",
+			"iss":   "https://example.com",
+		})
+		assert.NoError(t, err)
+		claims, err := token.ParseJWTToken(responseToken)
+		assert.NoError(t, err)
+		assert.Equal(t, code, claims["code"])
+		assert.Equal(t, "test-state", claims["state"])
+		assert.Equal(t, clientID, claims["aud"])
+		// response params cannot override issuer of response
+		assert.Equal(t, hostname, claims["iss"])
+		assert.NotEmpty(t, claims["exp"])
+	})
+
+	t.Run(`should send jwt secured authorization response from authorize endpoint`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		redirectURI := "https://jarm.example.com/callback"
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "jarm client",
+			RedirectUris: []string{redirectURI},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		req.Header.Del("Cookie")
+
+		// signing algorithm of authorization response is advertised
+		res := getRequest(t, s, "/.well-known/openid-configuration", nil)
+		var config map[string]interface{}
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+		res.Body.Close()
+		jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{jwtType}, config["authorization_signing_alg_values_supported"])
+
+		email := "jarm." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		_, err = db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+			UserID:   verifyRes.User.ID,
+			ClientID: client.Client.ClientID,
+			Scopes:   "openid,profile,email",
+		})
+		assert.NoError(t, err)
+
+		// session is rolled over on each authorization, hence user logs in before each request
+		sessionCookie := func() http.Header {
+			loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+				Email:    refs.NewStringRef(email),
+				Password: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+			assert.NoError(t, err)
+			nonce, _ := claims["nonce"].(string)
+			sessionToken, err := memorystore.Provider.GetUserSession(constants.AuthRecipeMethodBasicAuth+":"+verifyRes.User.ID, constants.TokenTypeSessionToken+"_"+nonce)
+			assert.NoError(t, err)
+			header := http.Header{}
+			header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken))
+			return header
+		}
+		authorize := func(responseMode string, header http.Header) *http.Response {
+			params := url.Values{
+				"client_id":      {client.Client.ClientID},
+				"redirect_uri":   {redirectURI},
+				"response_type":  {constants.ResponseTypeCode},
+				"response_mode":  {responseMode},
+				"state":          {"jarm_state"},
+				"prompt":         {constants.PromptNone},
+				"code_challenge": {uuid.New().String()},
+			}
+			return getRequest(t, s, "/authorize?"+params.Encode(), header)
+		}
+		assertResponse := func(response string) map[string]interface{} {
+			claims, err := token.ParseJWTToken(response)
+			assert.NoError(t, err)
+			assert.Equal(t, client.Client.ClientID, claims["aud"])
+			assert.Equal(t, parsers.GetHost(s.GinContext), claims["iss"])
+			assert.Equal(t, "jarm_state", claims["state"])
+			return claims
+		}
+
+		// query.jwt sends response in query of redirect uri
+		res = authorize(constants.ResponseModeQueryJWT, sessionCookie())
+		res.Body.Close()
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		assert.NoError(t, err)
+		assert.Equal(t, "jarm.example.com", location.Host)
+		assert.Empty(t, location.Query().Get("code"))
+		claims := assertResponse(location.Query().Get("response"))
+		assert.NotEmpty(t, claims["code"])
+
+		// fragment.jwt sends response in fragment of redirect uri
+		res = authorize(constants.ResponseModeFragmentJWT, sessionCookie())
+		res.Body.Close()
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		location, err = url.Parse(res.Header.Get("Location"))
+		assert.NoError(t, err)
+		assert.Empty(t, location.Query().Get("response"))
+		fragment, err := url.ParseQuery(location.Fragment)
+		assert.NoError(t, err)
+		claims = assertResponse(fragment.Get("response"))
+		assert.NotEmpty(t, claims["code"])
+
+		// form_post.jwt posts response to redirect uri
+		res = authorize(constants.ResponseModeFormPostJWT, sessionCookie())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `action="`+redirectURI+`"`)
+		matches := regexp.MustCompile(`value="([^"]+)" name="response"`).FindStringSubmatch(string(body))
+		if assert.Len(t, matches, 2) {
+			claims = assertResponse(html.UnescapeString(matches[1]))
+			assert.NotEmpty(t, claims["code"])
+		}
+
+		// error is also sent as jwt secured response
+		res = authorize(constants.ResponseModeQueryJWT, nil)
+		res.Body.Close()
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		location, err = url.Parse(res.Header.Get("Location"))
+		assert.NoError(t, err)
+		claims = assertResponse(location.Query().Get("response"))
+		assert.Equal(t, "login_required", claims["error"])
+		assert.Empty(t, claims["code"])
+	})
+}
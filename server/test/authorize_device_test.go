@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func authorizeDeviceTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should authorize device with user code`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "authorize_device." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+
+		userCode, err := token.NewUserCode()
+		assert.NoError(t, err)
+		deviceAuthorization := &token.DeviceAuthorization{
+			DeviceCode: "authorize_device_" + userCode,
+			UserCode:   userCode,
+			ClientID:   "test",
+			Status:     token.DeviceAuthorizationStatusPending,
+			Interval:   token.DeviceCodePollingInterval,
+			ExpiresAt:  time.Now().Unix() + token.DeviceCodeExpiresIn,
+		}
+		err = token.SetDeviceAuthorization(deviceAuthorization)
+		assert.NoError(t, err)
+
+		_, err = resolvers.AuthorizeDeviceResolver(ctx, model.AuthorizeDeviceRequest{
+			UserCode: userCode,
+		})
+		assert.NotNil(t, err, "unauthorized")
+
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		assert.NotNil(t, verificationRequest)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, verifyRes.AccessToken)
+
+		s.GinContext.Request.Header.Set("Authorization", "Bearer "+*verifyRes.AccessToken)
+		ctx = context.WithValue(req.Context(), "GinContextKey", s.GinContext)
+		_, err = resolvers.AuthorizeDeviceResolver(ctx, model.AuthorizeDeviceRequest{
+			UserCode: "invalid",
+		})
+		assert.NotNil(t, err, "invalid user code")
+
+		// user code should be case insensitive
+		res, err := resolvers.AuthorizeDeviceResolver(ctx, model.AuthorizeDeviceRequest{
+			UserCode: token.NormalizeUserCode(userCode),
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+
+		updatedDeviceAuthorization, err := token.GetDeviceAuthorization(deviceAuthorization.DeviceCode)
+		assert.NoError(t, err)
+		assert.Equal(t, token.DeviceAuthorizationStatusApproved, updatedDeviceAuthorization.Status)
+		assert.Equal(t, verifyRes.User.ID, updatedDeviceAuthorization.UserID)
+
+		// already processed user code cannot be reused
+		_, err = resolvers.AuthorizeDeviceResolver(ctx, model.AuthorizeDeviceRequest{
+			UserCode: userCode,
+			Deny:     refs.NewBoolRef(true),
+		})
+		assert.NotNil(t, err, "invalid user code")
+		token.RemoveDeviceAuthorization(updatedDeviceAuthorization)
+		s.GinContext.Request.Header.Set("Authorization", "")
+		cleanData(email)
+	})
+}
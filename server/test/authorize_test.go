@@ -0,0 +1,215 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func authorizeTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should handle prompt, max_age, login_hint & acr_values`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		redirectURI := "https://authorize.example.com/callback"
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "authorize client",
+			RedirectUris: []string{redirectURI},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		req.Header.Del("Cookie")
+
+		email := "authorize." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + verifyRes.User.ID
+
+		login := func() (http.Header, string) {
+			loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+				Email:    refs.NewStringRef(email),
+				Password: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+			assert.NoError(t, err)
+			nonce, _ := claims["nonce"].(string)
+			sessionToken, err := memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+nonce)
+			assert.NoError(t, err)
+			sessionCookie := http.Header{}
+			sessionCookie.Set("Cookie", fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken))
+			return sessionCookie, nonce
+		}
+		authorize := func(path string, header http.Header) (int, *url.URL, string) {
+			res := getRequest(t, s, path, header)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			location, err := url.Parse(res.Header.Get("Location"))
+			assert.NoError(t, err)
+			return res.StatusCode, location, string(body)
+		}
+		authorizePath := func(extraParams url.Values) string {
+			params := url.Values{
+				"client_id":     {client.Client.ClientID},
+				"redirect_uri":  {redirectURI},
+				"response_type": {constants.ResponseTypeToken},
+				"response_mode": {constants.ResponseModeQuery},
+				"state":         {"authorize_state"},
+			}
+			for key, values := range extraParams {
+				params[key] = values
+			}
+			return "/authorize?" + params.Encode()
+		}
+		// login page resumes authorization request with request_uri after login
+		getResumePath := func(location *url.URL) string {
+			resumeURI, err := url.Parse(location.Query().Get("redirect_uri"))
+			assert.NoError(t, err)
+			assert.Equal(t, "/authorize", resumeURI.Path)
+			return resumeURI.RequestURI()
+		}
+
+		// invalid prompt & max_age are rejected
+		status, _, _ := authorize(authorizePath(url.Values{"prompt": {"none login"}}), nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _, _ = authorize(authorizePath(url.Values{"max_age": {"-1"}}), nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// prompt=none without session sends login_required error to client
+		status, location, _ := authorize(authorizePath(url.Values{"prompt": {constants.PromptNone}}), nil)
+		assert.Equal(t, http.StatusFound, status)
+		assert.True(t, strings.HasPrefix(location.String(), redirectURI))
+		assert.Equal(t, "login_required", location.Query().Get("error"))
+		assert.Equal(t, "authorize_state", location.Query().Get("state"))
+
+		// login_hint is passed to login page
+		status, location, _ = authorize(authorizePath(url.Values{"login_hint": {email}}), nil)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "/app", location.Path)
+		assert.Equal(t, email, location.Query().Get("login_hint"))
+		assert.Empty(t, location.Query().Get("prompt"))
+
+		sessionCookie, sessionNonce := login()
+
+		// consent is required till user grants the scopes to client
+		status, location, _ = authorize(authorizePath(url.Values{"prompt": {constants.PromptNone}}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "consent_required", location.Query().Get("error"))
+		status, _, page := authorize(authorizePath(nil), sessionCookie)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, page, "consent_challenge")
+
+		_, err = db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+			UserID:   verifyRes.User.ID,
+			ClientID: client.Client.ClientID,
+			Scopes:   "openid,profile,email",
+		})
+		assert.NoError(t, err)
+
+		// prompt=consent asks for consent even though scopes are granted
+		status, _, page = authorize(authorizePath(url.Values{"prompt": {constants.PromptConsent}}), sessionCookie)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, page, "consent_challenge")
+
+		// first acr value is reflected as acr claim of id token
+		status, location, _ = authorize(authorizePath(url.Values{
+			"prompt":     {constants.PromptNone},
+			"acr_values": {"urn:authorizer:acr:1 urn:authorizer:acr:2"},
+		}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.True(t, strings.HasPrefix(location.String(), redirectURI))
+		assert.NotEmpty(t, location.Query().Get("access_token"))
+		idTokenClaims, err := token.ParseJWTToken(location.Query().Get("id_token"))
+		assert.NoError(t, err)
+		assert.Equal(t, "urn:authorizer:acr:1", idTokenClaims["acr"])
+
+		// claims request needs consent for the scopes covering requested claims
+		sessionCookie, _ = login()
+		status, location, _ = authorize(authorizePath(url.Values{
+			"prompt": {constants.PromptNone},
+			"claims": {`{"id_token":{"phone_number":null}}`},
+		}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "consent_required", location.Query().Get("error"))
+		status, _, page = authorize(authorizePath(url.Values{
+			"claims": {`{"id_token":{"phone_number":null}}`},
+		}), sessionCookie)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, page, "phone")
+
+		// session is rolled over, hence latest session cookie is used
+		sessionCookie, sessionNonce = login()
+		time.Sleep(time.Second * 2)
+
+		// max_age which is not elapsed uses existing session
+		status, location, _ = authorize(authorizePath(url.Values{"max_age": {"3600"}}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.NotEmpty(t, location.Query().Get("access_token"))
+		sessionCookie, sessionNonce = login()
+		time.Sleep(time.Second * 2)
+
+		// elapsed max_age with prompt=none sends login_required error to client
+		status, location, _ = authorize(authorizePath(url.Values{"max_age": {"1"}, "prompt": {constants.PromptNone}}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.True(t, strings.HasPrefix(location.String(), redirectURI))
+		assert.Equal(t, "login_required", location.Query().Get("error"))
+
+		// elapsed max_age & prompt=login show login page, but session is not ended
+		status, location, _ = authorize(authorizePath(url.Values{"max_age": {"1"}}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "/app", location.Path)
+		assert.Equal(t, constants.PromptLogin, location.Query().Get("prompt"))
+		status, location, _ = authorize(authorizePath(url.Values{"prompt": {constants.PromptLogin}}), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "/app", location.Path)
+		assert.Equal(t, constants.PromptLogin, location.Query().Get("prompt"))
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+sessionNonce)
+		assert.NoError(t, err)
+
+		// resumed request cannot be completed with session authenticated before re-authentication was requested
+		status, location, _ = authorize(getResumePath(location), sessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.Equal(t, "/app", location.Path)
+		assert.Equal(t, constants.PromptLogin, location.Query().Get("prompt"))
+
+		// resumed request is completed once user logs in again
+		newSessionCookie, _ := login()
+		status, location, _ = authorize(getResumePath(location), newSessionCookie)
+		assert.Equal(t, http.StatusFound, status)
+		assert.True(t, strings.HasPrefix(location.String(), redirectURI))
+		assert.NotEmpty(t, location.Query().Get("access_token"))
+		assert.Equal(t, "authorize_state", location.Query().Get("state"))
+	})
+}
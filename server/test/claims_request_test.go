@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func claimsRequestTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should release user claims as per scopes and claims request`, func(t *testing.T) {
+		_, ctx := createContext(s)
+		email := "claims." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		user, err := db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+		user.GivenName = refs.NewStringRef("Bob")
+		user.PhoneNumber = refs.NewStringRef("2234567890")
+
+		userClaims := token.GetUserClaims(user, []string{"openid", "email"})
+		assert.Equal(t, email, userClaims["email"])
+		assert.NotContains(t, userClaims, "given_name")
+		assert.NotContains(t, userClaims, "phone_number")
+		assert.NotContains(t, userClaims, "app_data")
+		assert.NotContains(t, userClaims, "id")
+
+		userClaims = token.GetUserClaims(user, []string{"openid", "profile", "phone"})
+		assert.Equal(t, "Bob", userClaims["given_name"])
+		assert.Equal(t, "2234567890", userClaims["phone_number"])
+		assert.NotContains(t, userClaims, "email")
+
+		_, err = token.ParseClaimsRequest("invalid")
+		assert.Error(t, err)
+		claimsRequest, err := token.ParseClaimsRequest(`{"id_token":{"phone_number":{"essential":true},"app_data":null},"userinfo":{"given_name":null}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"app_data", "phone_number"}, claimsRequest.IDToken)
+		assert.Equal(t, []string{"given_name"}, claimsRequest.UserInfo)
+
+		// only the standard claims can be requested, and they are consented via the scopes covering them
+		assert.Equal(t, []string{"phone", "profile"}, token.GetClaimsRequestScopes(claimsRequest))
+		assert.Empty(t, token.GetClaimsRequestScopes(nil))
+
+		// requested claims are not released without granted scopes
+		nonce := uuid.New().String()
+		authToken, err := token.CreateAuthTokenForClient(s.GinContext, nil, user, verifyRes.User.Roles, []string{"openid", "email"}, constants.AuthRecipeMethodBasicAuth, nonce, "", 0, "", "", claimsRequest)
+		assert.NoError(t, err)
+		idTokenClaims, err := token.ParseJWTToken(authToken.IDToken.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, email, idTokenClaims["email"])
+		assert.NotContains(t, idTokenClaims, "phone_number")
+		assert.NotContains(t, idTokenClaims, "given_name")
+		assert.NotContains(t, idTokenClaims, "app_data")
+		assert.NotContains(t, idTokenClaims, "signup_methods")
+		accessTokenClaims, err := token.ParseJWTToken(authToken.AccessToken.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, claimsRequest, token.GetClaimsRequest(accessTokenClaims))
+
+		cleanData(email)
+	})
+}
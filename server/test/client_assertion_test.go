@@ -0,0 +1,253 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+// createClientAssertion creates client assertion signed with given key
+func createClientAssertion(t *testing.T, key *ecdsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", keyID))
+	assert.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	jws, err := signer.Sign(payload)
+	assert.NoError(t, err)
+	assertion, err := jws.CompactSerialize()
+	assert.NoError(t, err)
+	return assertion
+}
+
+func clientAssertionTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should validate private key jwt client assertion`, func(t *testing.T) {
+		createContext(s)
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		keyID := uuid.New().String()
+		jwks, err := json.Marshal(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: keyID, Algorithm: "ES256", Use: "sig"}},
+		})
+		assert.NoError(t, err)
+		client := &models.Client{
+			ClientID:                uuid.New().String(),
+			TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodPrivateKeyJWT,
+			JWKS:                    string(jwks),
+		}
+		claims := func(aud string) map[string]interface{} {
+			return map[string]interface{}{
+				"iss": client.ClientID,
+				"sub": client.ClientID,
+				"aud": aud,
+				"jti": uuid.New().String(),
+				"exp": time.Now().Add(time.Minute).Unix(),
+			}
+		}
+		tokenEndpoint := parsers.GetHost(s.GinContext) + "/oauth/token"
+
+		assertion := createClientAssertion(t, key, keyID, claims(tokenEndpoint))
+		sub, err := token.GetClientAssertionSubject(assertion)
+		assert.NoError(t, err)
+		assert.Equal(t, client.ClientID, sub)
+		assert.NoError(t, token.ValidateClientAssertion(s.GinContext, client, assertion))
+		// assertion cannot be replayed
+		assert.Error(t, token.ValidateClientAssertion(s.GinContext, client, assertion))
+
+		// assertion for other audience is rejected
+		assert.Error(t, token.ValidateClientAssertion(s.GinContext, client, createClientAssertion(t, key, keyID, claims("https://example.com/oauth/token"))))
+
+		// assertion signed with unregistered key is rejected
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		assert.Error(t, token.ValidateClientAssertion(s.GinContext, client, createClientAssertion(t, otherKey, keyID, claims(tokenEndpoint))))
+
+		// expired assertion is rejected
+		expiredClaims := claims(tokenEndpoint)
+		expiredClaims["exp"] = time.Now().Add(-time.Hour).Unix()
+		assert.Error(t, token.ValidateClientAssertion(s.GinContext, client, createClientAssertion(t, key, keyID, expiredClaims)))
+	})
+	t.Run(`should authenticate private key jwt client at par & introspection endpoints`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		keyID := uuid.New().String()
+		jwks, err := json.Marshal(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: keyID, Algorithm: "ES256", Use: "sig"}},
+		})
+		assert.NoError(t, err)
+		redirectURI := "https://client-assertion.example.com/callback"
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "private key jwt client",
+			RedirectUris:            []string{redirectURI},
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodPrivateKeyJWT),
+			Jwks:                    refs.NewStringRef(string(jwks)),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+		clientID := res.Client.ClientID
+
+		// jwks uri should use https
+		_, err = resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "http jwks uri client",
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodPrivateKeyJWT),
+			JwksURI:                 refs.NewStringRef("http://client-assertion.example.com/jwks.json"),
+		})
+		assert.Error(t, err)
+		req.Header.Del("Cookie")
+
+		clientAuth := func(endpoint string) url.Values {
+			return url.Values{
+				"client_assertion_type": {constants.ClientAssertionTypeJWTBearer},
+				"client_assertion": {createClientAssertion(t, key, keyID, map[string]interface{}{
+					"iss": clientID,
+					"sub": clientID,
+					"aud": parsers.GetHost(s.GinContext) + endpoint,
+					"jti": uuid.New().String(),
+					"exp": time.Now().Add(time.Minute).Unix(),
+				})},
+			}
+		}
+		parData := func(auth url.Values) url.Values {
+			data := url.Values{
+				"redirect_uri":          {redirectURI},
+				"response_type":         {constants.ResponseTypeCode},
+				"state":                 {"client_assertion_state"},
+				"code_challenge":        {"client_assertion_challenge"},
+				"code_challenge_method": {"S256"},
+			}
+			for key, value := range auth {
+				data[key] = value
+			}
+			return data
+		}
+
+		// client id is not enough for confidential client
+		status, body := postForm(t, s, "/oauth/par", parData(url.Values{"client_id": {clientID}}), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, body = postForm(t, s, "/oauth/par", parData(clientAuth("/oauth/par")), nil)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEmpty(t, body["request_uri"])
+
+		introspectData := func(auth url.Values) url.Values {
+			data := url.Values{
+				"token": {"invalid-token"},
+			}
+			for key, value := range auth {
+				data[key] = value
+			}
+			return data
+		}
+		status, body = postForm(t, s, "/oauth/introspect", introspectData(url.Values{"client_id": {clientID}}), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, body = postForm(t, s, "/oauth/introspect", introspectData(clientAuth("/oauth/introspect")), nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, body["active"])
+	})
+	t.Run(`should authenticate client secret jwt client at token endpoint`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "client secret jwt client",
+			GrantTypes:              []string{constants.GrantTypeClientCredentials},
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodClientSecretJWT),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+		// secret cannot be kept when client is switched from client_secret_jwt
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                      res.Client.ID,
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodClientSecretBasic),
+		})
+		assert.Error(t, err)
+		req.Header.Del("Cookie")
+		clientID := res.Client.ClientID
+		clientSecret := res.ClientSecret
+
+		createSecretAssertion := func(secret string) string {
+			signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)}, nil)
+			assert.NoError(t, err)
+			payload, err := json.Marshal(map[string]interface{}{
+				"iss": clientID,
+				"sub": clientID,
+				"aud": parsers.GetHost(s.GinContext) + "/oauth/token",
+				"jti": uuid.New().String(),
+				"exp": time.Now().Add(time.Minute).Unix(),
+			})
+			assert.NoError(t, err)
+			jws, err := signer.Sign(payload)
+			assert.NoError(t, err)
+			assertion, err := jws.CompactSerialize()
+			assert.NoError(t, err)
+			return assertion
+		}
+		tokenData := func(auth url.Values) url.Values {
+			data := url.Values{
+				"grant_type": {constants.GrantTypeClientCredentials},
+				"client_id":  {clientID},
+			}
+			for key, value := range auth {
+				data[key] = value
+			}
+			return data
+		}
+
+		// client secret cannot be sent as it is
+		status, body := postForm(t, s, "/oauth/token", tokenData(url.Values{"client_secret": {clientSecret}}), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		// assertion signed with other secret is rejected
+		status, body = postForm(t, s, "/oauth/token", tokenData(url.Values{
+			"client_assertion_type": {constants.ClientAssertionTypeJWTBearer},
+			"client_assertion":      {createSecretAssertion(uuid.New().String())},
+		}), nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, body = postForm(t, s, "/oauth/token", tokenData(url.Values{
+			"client_assertion_type": {constants.ClientAssertionTypeJWTBearer},
+			"client_assertion":      {createSecretAssertion(clientSecret)},
+		}), nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, body["access_token"])
+
+		// client secret jwt is advertised in discovery document
+		discoveryRes := getRequest(t, s, "/.well-known/openid-configuration", nil)
+		defer discoveryRes.Body.Close()
+		var discovery map[string]interface{}
+		assert.NoError(t, json.NewDecoder(discoveryRes.Body).Decode(&discovery))
+		assert.Contains(t, discovery["token_endpoint_auth_methods_supported"], constants.TokenEndpointAuthMethodClientSecretJWT)
+		assert.Contains(t, discovery["token_endpoint_auth_signing_alg_values_supported"], "HS256")
+	})
+}
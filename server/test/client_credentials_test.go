@@ -0,0 +1,91 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func clientCredentialsTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should issue access token with client_credentials grant`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:       "client credentials client",
+			GrantTypes: []string{constants.GrantTypeClientCredentials},
+			Scopes:     []string{"read", "write"},
+		})
+		assert.NoError(t, err)
+		clientID := res.Client.ClientID
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+
+		basicAuth := func(secret string) http.Header {
+			r, _ := http.NewRequest(http.MethodPost, "/", nil)
+			r.SetBasicAuth(clientID, secret)
+			return r.Header
+		}
+
+		// invalid client secret is rejected
+		status, body := postForm(t, s, "/oauth/token", url.Values{
+			"grant_type": {constants.GrantTypeClientCredentials},
+			"scope":      {"read"},
+		}, basicAuth("invalid-secret"))
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		// scope not registered for client is rejected
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type": {constants.GrantTypeClientCredentials},
+			"scope":      {"read admin"},
+		}, basicAuth(res.ClientSecret))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_scope", body["error"])
+
+		// grant type not registered for client is rejected
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":    {constants.GrantTypeRefreshToken},
+			"refresh_token": {"token"},
+		}, basicAuth(res.ClientSecret))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "unauthorized_client", body["error"])
+
+		// user scopes are dropped as there is no user involved, client secret can be sent in body
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":    {constants.GrantTypeClientCredentials},
+			"scope":         {"openid read"},
+			"client_id":     {clientID},
+			"client_secret": {res.ClientSecret},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Bearer", body["token_type"])
+		assert.Equal(t, "read", body["scope"])
+		assert.Greater(t, body["expires_in"], float64(0))
+		assert.Nil(t, body["refresh_token"])
+		assert.Nil(t, body["id_token"])
+
+		accessToken, _ := body["access_token"].(string)
+		claims, err := token.ParseJWTToken(accessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, clientID, claims["client_id"])
+		assert.Equal(t, clientID, claims["aud"])
+		assert.Equal(t, constants.TokenTypeAccessToken, claims["token_type"])
+		assert.Equal(t, []interface{}{"read"}, claims["scope"])
+		assert.Nil(t, claims["sub"])
+	})
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+func clientRegistrationTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should register client dynamically`, func(t *testing.T) {
+		metadata := map[string]interface{}{
+			"client_name":   "dynamic client",
+			"redirect_uris": []string{"https://app.example.com/callback"},
+			"grant_types":   []string{constants.GrantTypeAuthorizationCode, constants.GrantTypeRefreshToken},
+			"scope":         "openid profile",
+		}
+
+		// registration is disabled unless initial access token is configured
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyClientRegistrationInitialAccessToken, "")
+		status, body := sendJSON(t, s, http.MethodPost, "/oauth/register", metadata, "")
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, "access_denied", body["error"])
+
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyClientRegistrationInitialAccessToken, "initial-token-1, initial-token-2")
+		defer memorystore.Provider.UpdateEnvVariable(constants.EnvKeyClientRegistrationInitialAccessToken, "")
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", metadata, "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_token", body["error"])
+		status, _ = sendJSON(t, s, http.MethodPost, "/oauth/register", metadata, "invalid-token")
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		// invalid metadata is rejected
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", map[string]interface{}{
+			"grant_types": []string{constants.GrantTypeAuthorizationCode},
+		}, "initial-token-2")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_redirect_uri", body["error"])
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", map[string]interface{}{
+			"redirect_uris": []string{"https://app.example.com/callback"},
+			"grant_types":   []string{"password"},
+		}, "initial-token-2")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_client_metadata", body["error"])
+		// jwks uri should use https
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", map[string]interface{}{
+			"redirect_uris":              []string{"https://app.example.com/callback"},
+			"token_endpoint_auth_method": constants.TokenEndpointAuthMethodPrivateKeyJWT,
+			"jwks_uri":                   "http://app.example.com/jwks.json",
+		}, "initial-token-2")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_client_metadata", body["error"])
+
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", metadata, "initial-token-2")
+		assert.Equal(t, http.StatusCreated, status)
+		clientID, _ := body["client_id"].(string)
+		registrationAccessToken, _ := body["registration_access_token"].(string)
+		assert.NotEmpty(t, clientID)
+		assert.NotEmpty(t, body["client_secret"])
+		assert.NotEmpty(t, registrationAccessToken)
+		assert.Equal(t, "dynamic client", body["client_name"])
+		assert.Equal(t, "openid profile", body["scope"])
+		assert.Equal(t, constants.TokenEndpointAuthMethodClientSecretBasic, body["token_endpoint_auth_method"])
+		registrationClientURI := "/oauth/register/" + clientID
+		assert.Equal(t, s.Server.URL+registrationClientURI, body["registration_client_uri"])
+
+		// client configuration requires registration access token of client
+		status, _ = sendJSON(t, s, http.MethodGet, registrationClientURI, nil, "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, _ = sendJSON(t, s, http.MethodGet, registrationClientURI, nil, "initial-token-2")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, body = sendJSON(t, s, http.MethodGet, registrationClientURI, nil, registrationAccessToken)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, clientID, body["client_id"])
+		assert.Nil(t, body["client_secret"])
+
+		metadata["client_id"] = clientID
+		metadata["client_name"] = "updated dynamic client"
+		status, body = sendJSON(t, s, http.MethodPut, registrationClientURI, metadata, registrationAccessToken)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "updated dynamic client", body["client_name"])
+
+		status, _ = sendJSON(t, s, http.MethodDelete, registrationClientURI, nil, registrationAccessToken)
+		assert.Equal(t, http.StatusNoContent, status)
+		status, _ = sendJSON(t, s, http.MethodGet, registrationClientURI, nil, registrationAccessToken)
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		// client secret is issued for client_secret_jwt, auth method cannot be switched without new secret
+		status, body = sendJSON(t, s, http.MethodPost, "/oauth/register", map[string]interface{}{
+			"redirect_uris":              []string{"https://app.example.com/callback"},
+			"token_endpoint_auth_method": constants.TokenEndpointAuthMethodClientSecretJWT,
+		}, "initial-token-2")
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEmpty(t, body["client_secret"])
+		assert.Equal(t, constants.TokenEndpointAuthMethodClientSecretJWT, body["token_endpoint_auth_method"])
+		clientID, _ = body["client_id"].(string)
+		clientSecret, _ := body["client_secret"].(string)
+		registrationAccessToken, _ = body["registration_access_token"].(string)
+		registrationClientURI = "/oauth/register/" + clientID
+		status, body = sendJSON(t, s, http.MethodPut, registrationClientURI, map[string]interface{}{
+			"client_id":                  clientID,
+			"client_secret":              clientSecret,
+			"redirect_uris":              []string{"https://app.example.com/callback"},
+			"token_endpoint_auth_method": constants.TokenEndpointAuthMethodClientSecretBasic,
+		}, registrationAccessToken)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_client_metadata", body["error"])
+		status, _ = sendJSON(t, s, http.MethodPut, registrationClientURI, map[string]interface{}{
+			"client_id":                  clientID,
+			"client_secret":              clientSecret,
+			"redirect_uris":              []string{"https://app.example.com/callback"},
+			"token_endpoint_auth_method": constants.TokenEndpointAuthMethodClientSecretJWT,
+		}, registrationAccessToken)
+		assert.Equal(t, http.StatusOK, status)
+		status, _ = sendJSON(t, s, http.MethodDelete, registrationClientURI, nil, registrationAccessToken)
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func clientTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run("should manage oauth clients", func(t *testing.T) {
+		req, ctx := createContext(s)
+		_, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name: "test client",
+		})
+		assert.Error(t, err)
+
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		_, err = resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:       "test client",
+			GrantTypes: []string{"password"},
+		})
+		assert.Error(t, err)
+
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "test client",
+			RedirectUris: []string{"https://example.com/callback"},
+			GrantTypes:   []string{constants.GrantTypeClientCredentials},
+			Scopes:       []string{"read"},
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ClientSecret)
+		assert.NotEmpty(t, res.Client.ClientID)
+		assert.Equal(t, []string{constants.GrantTypeClientCredentials}, res.Client.GrantTypes)
+
+		_, err = resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:     "duplicate client",
+			ClientID: refs.NewStringRef(res.Client.ClientID),
+		})
+		assert.Error(t, err)
+
+		client, err := resolvers.ClientResolver(ctx, model.ClientRequest{
+			ID: res.Client.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, res.Client.ClientID, client.ClientID)
+
+		clients, err := resolvers.ClientsResolver(ctx, &model.PaginatedInput{})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(clients.Clients), 1)
+
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                                 res.Client.ID,
+			Name:                               refs.NewStringRef("updated client"),
+			ClientSecret:                       refs.NewStringRef("new-secret"),
+			RequirePushedAuthorizationRequests: refs.NewBoolRef(true),
+			PostLogoutRedirectUris:             []string{"https://example.com/logout"},
+			BackchannelLogoutURI:               refs.NewStringRef("https://example.com/backchannel-logout"),
+			TokenExchangeAudiences:             []string{"orders-service"},
+		})
+		assert.NoError(t, err)
+
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                     res.Client.ID,
+			PostLogoutRedirectUris: []string{"invalid uri"},
+		})
+		assert.Error(t, err)
+
+		dbClient, err := db.Provider.GetClientByClientID(ctx, res.Client.ClientID)
+		assert.NoError(t, err)
+		assert.Equal(t, "updated client", dbClient.Name)
+		assert.True(t, validators.IsValidClientSecret(dbClient, "new-secret"))
+		assert.False(t, validators.IsValidClientSecret(dbClient, res.ClientSecret))
+		assert.True(t, dbClient.RequirePushedAuthorizationRequests)
+		assert.Equal(t, []string{"https://example.com/logout"}, dbClient.GetPostLogoutRedirectURIs())
+		assert.Equal(t, "https://example.com/backchannel-logout", dbClient.BackchannelLogoutURI)
+		assert.True(t, validators.IsValidClientTokenExchangeAudience(dbClient, "orders-service"))
+		assert.True(t, validators.IsValidClientTokenExchangeAudience(dbClient, dbClient.ClientID))
+		assert.False(t, validators.IsValidClientTokenExchangeAudience(dbClient, "billing-service"))
+
+		// private_key_jwt clients should have keys and cannot use client secret
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                      res.Client.ID,
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodPrivateKeyJWT),
+		})
+		assert.Error(t, err)
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                      res.Client.ID,
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodPrivateKeyJWT),
+			JwksURI:                 refs.NewStringRef("https://example.com/jwks.json"),
+		})
+		assert.NoError(t, err)
+		dbClient, err = db.Provider.GetClientByClientID(ctx, res.Client.ClientID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.TokenEndpointAuthMethodPrivateKeyJWT, dbClient.GetTokenEndpointAuthMethod())
+		assert.Equal(t, "https://example.com/jwks.json", dbClient.JWKSURI)
+		assert.False(t, validators.IsValidClientSecret(dbClient, "new-secret"))
+
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                         res.Client.ID,
+			RequireSignedRequestObject: refs.NewBoolRef(true),
+		})
+		assert.NoError(t, err)
+		dbClient, err = db.Provider.GetClientByClientID(ctx, res.Client.ClientID)
+		assert.NoError(t, err)
+		assert.True(t, dbClient.RequireSignedRequestObject)
+		// signed request object cannot be required without keys of client
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:                      res.Client.ID,
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodClientSecretBasic),
+			JwksURI:                 refs.NewStringRef(""),
+		})
+		assert.Error(t, err)
+
+		// pairwise clients with redirect uris on different hosts require sector identifier uri
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:           res.Client.ID,
+			SubjectType:  refs.NewStringRef(constants.SubjectTypePairwise),
+			RedirectUris: []string{"https://app.example.com/callback", "https://other.example.com/callback"},
+		})
+		assert.Error(t, err)
+		_, err = resolvers.UpdateClientResolver(ctx, model.UpdateClientRequest{
+			ID:           res.Client.ID,
+			SubjectType:  refs.NewStringRef(constants.SubjectTypePairwise),
+			RedirectUris: []string{"https://app.example.com/callback"},
+		})
+		assert.NoError(t, err)
+		dbClient, err = db.Provider.GetClientByClientID(ctx, res.Client.ClientID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.SubjectTypePairwise, dbClient.GetSubjectType())
+
+		_, err = resolvers.DeleteClientResolver(ctx, model.ClientRequest{
+			ID: res.Client.ID,
+		})
+		assert.NoError(t, err)
+		_, err = resolvers.ClientResolver(ctx, model.ClientRequest{
+			ID: res.Client.ID,
+		})
+		assert.Error(t, err)
+	})
+	t.Run("should require redirect uri for client with multiple redirect uris", func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "multiple redirect uris client",
+			RedirectUris: []string{"https://app.example.com/callback", "https://app.example.com/silent-callback"},
+			GrantTypes:   []string{constants.GrantTypeAuthorizationCode},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+
+		params := url.Values{
+			"client_id":      {res.Client.ClientID},
+			"response_type":  {constants.ResponseTypeCode},
+			"response_mode":  {constants.ResponseModeQuery},
+			"state":          {"state"},
+			"code_challenge": {"challenge"},
+		}
+		authorizeRes := getRequest(t, s, "/authorize?"+params.Encode(), nil)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		params.Set("redirect_uri", "https://app.example.com/other-callback")
+		authorizeRes = getRequest(t, s, "/authorize?"+params.Encode(), nil)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// user is redirected to login page as there is no session
+		params.Set("redirect_uri", "https://app.example.com/silent-callback")
+		authorizeRes = getRequest(t, s, "/authorize?"+params.Encode(), nil)
+		assert.Equal(t, http.StatusFound, authorizeRes.StatusCode)
+	})
+}
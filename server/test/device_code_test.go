@@ -0,0 +1,184 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func deviceCodeTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should authenticate confidential clients and poll device code`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		confidentialClient, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:       "device confidential client",
+			GrantTypes: []string{constants.GrantTypeDeviceCode},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: confidentialClient.Client.ID})
+		publicClient, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "device public client",
+			GrantTypes:              []string{constants.GrantTypeDeviceCode},
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodNone),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: publicClient.Client.ID})
+
+		// confidential client cannot start device flow without client secret
+		status, body := postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id": {confidentialClient.Client.ClientID},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		status, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id":     {confidentialClient.Client.ClientID},
+			"client_secret": {"invalid-secret"},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		status, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id":     {confidentialClient.Client.ClientID},
+			"client_secret": {confidentialClient.ClientSecret},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		confidentialDeviceCode, _ := body["device_code"].(string)
+		assert.NotEmpty(t, confidentialDeviceCode)
+
+		// confidential client cannot poll without client secret
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":  {constants.GrantTypeDeviceCode},
+			"client_id":   {confidentialClient.Client.ClientID},
+			"device_code": {confidentialDeviceCode},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":    {constants.GrantTypeDeviceCode},
+			"client_id":     {confidentialClient.Client.ClientID},
+			"client_secret": {confidentialClient.ClientSecret},
+			"device_code":   {confidentialDeviceCode},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "authorization_pending", body["error"])
+
+		// public client is identified by client id only
+		status, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id": {publicClient.Client.ClientID},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		deviceCode, _ := body["device_code"].(string)
+		assert.NotEmpty(t, deviceCode)
+		assert.NotEmpty(t, body["user_code"])
+		assert.Equal(t, float64(token.DeviceCodePollingInterval), body["interval"])
+
+		poll := func(deviceCode string) (int, map[string]interface{}) {
+			return postForm(t, s, "/oauth/token", url.Values{
+				"grant_type":  {constants.GrantTypeDeviceCode},
+				"client_id":   {publicClient.Client.ClientID},
+				"device_code": {deviceCode},
+			}, nil)
+		}
+
+		// device code of other client is rejected
+		status, body = poll(confidentialDeviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "authorization_pending", body["error"])
+
+		// polling before interval elapses increases the interval
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "slow_down", body["error"])
+		assert.Equal(t, float64(2*token.DeviceCodePollingInterval), body["interval"])
+
+		// denied device authorization is consumed
+		deviceAuthorization, err := token.GetDeviceAuthorization(deviceCode)
+		assert.NoError(t, err)
+		deviceAuthorization.Status = token.DeviceAuthorizationStatusDenied
+		assert.NoError(t, token.SetDeviceAuthorization(deviceAuthorization))
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "access_denied", body["error"])
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		// expired device code is rejected
+		_, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id": {publicClient.Client.ClientID},
+		}, nil)
+		deviceCode, _ = body["device_code"].(string)
+		deviceAuthorization, err = token.GetDeviceAuthorization(deviceCode)
+		assert.NoError(t, err)
+		deviceAuthorization.ExpiresAt = time.Now().Unix() - 1
+		assert.NoError(t, token.SetDeviceAuthorization(deviceAuthorization))
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "expired_token", body["error"])
+
+		// approved device code is exchanged for tokens only once
+		email := "device_code." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		_, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id": {publicClient.Client.ClientID},
+		}, nil)
+		deviceCode, _ = body["device_code"].(string)
+		deviceAuthorization, err = token.GetDeviceAuthorization(deviceCode)
+		assert.NoError(t, err)
+		deviceAuthorization.Status = token.DeviceAuthorizationStatusApproved
+		deviceAuthorization.UserID = verifyRes.User.ID
+		deviceAuthorization.LoginMethod = constants.AuthRecipeMethodBasicAuth
+		assert.NoError(t, token.SetDeviceAuthorization(deviceAuthorization))
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusOK, status)
+		assert.NotEmpty(t, body["access_token"])
+		assert.NotEmpty(t, body["id_token"])
+		status, body = poll(deviceCode)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", body["error"])
+
+		// public client cannot send client secret
+		_, body = postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id":     {publicClient.Client.ClientID},
+			"client_secret": {publicClient.ClientSecret},
+		}, nil)
+		assert.Equal(t, "invalid_client", body["error"])
+	})
+}
@@ -0,0 +1,246 @@
+package test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	authorizercrypto "github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+// createDPoPProof creates DPoP proof signed with given key for graphql request
+func createDPoPProof(t *testing.T, s TestSetup, key *ecdsa.PrivateKey, accessToken string) string {
+	return createDPoPProofForRequest(t, key, "POST", "http://"+s.Server.Listener.Addr().String()+"/graphql", time.Now().Unix(), accessToken)
+}
+
+// createDPoPProofForRequest creates DPoP proof signed with given key for given method & uri,
+// ath claim is set only when access token is given
+func createDPoPProofForRequest(t *testing.T, key *ecdsa.PrivateKey, htm, htu string, iat int64, accessToken string) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, (&jose.SignerOptions{EmbedJWK: true}).WithType("dpop+jwt"))
+	assert.NoError(t, err)
+	proofClaims := map[string]interface{}{
+		"jti": uuid.New().String(),
+		"htm": htm,
+		"htu": htu,
+		"iat": iat,
+	}
+	if accessToken != "" {
+		ath := sha256.Sum256([]byte(accessToken))
+		proofClaims["ath"] = base64.RawURLEncoding.EncodeToString(ath[:])
+	}
+	claims, err := json.Marshal(proofClaims)
+	assert.NoError(t, err)
+	jws, err := signer.Sign(claims)
+	assert.NoError(t, err)
+	proof, err := jws.CompactSerialize()
+	assert.NoError(t, err)
+	return proof
+}
+
+func dpopTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should validate dpop proof for dpop bound access token`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "dpop." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		user, err := db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		jwk := jose.JSONWebKey{Key: key.Public()}
+		thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+		assert.NoError(t, err)
+		jkt := base64.RawURLEncoding.EncodeToString(thumbprint)
+
+		nonce := uuid.New().String()
+		authToken, err := token.CreateAuthTokenForClient(s.GinContext, nil, user, verifyRes.User.Roles, []string{"openid"}, constants.AuthRecipeMethodBasicAuth, nonce, "", 0, "", jkt, nil)
+		assert.NoError(t, err)
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + user.ID
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+nonce, authToken.AccessToken.Token, authToken.AccessToken.ExpiresAt)
+		claims, err := token.ParseJWTToken(authToken.AccessToken.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, jkt, token.GetDPoPJKT(claims))
+
+		// bound token cannot be used as bearer token
+		s.GinContext.Request.Header.Set("Authorization", "Bearer "+authToken.AccessToken.Token)
+		ctx = context.WithValue(req.Context(), "GinContextKey", s.GinContext)
+		_, err = resolvers.ProfileResolver(ctx)
+		assert.Error(t, err)
+
+		// proof signed with different key is rejected
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		s.GinContext.Request.Header.Set("Authorization", "DPoP "+authToken.AccessToken.Token)
+		s.GinContext.Request.Header.Set("DPoP", createDPoPProof(t, s, otherKey, authToken.AccessToken.Token))
+		_, err = resolvers.ProfileResolver(ctx)
+		assert.Error(t, err)
+
+		proof := createDPoPProof(t, s, key, authToken.AccessToken.Token)
+		s.GinContext.Request.Header.Set("DPoP", proof)
+		profile, err := resolvers.ProfileResolver(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, email, refs.StringValue(profile.Email))
+
+		// proof cannot be replayed
+		_, err = resolvers.ProfileResolver(ctx)
+		assert.Error(t, err)
+
+		s.GinContext.Request.Header.Set("Authorization", "")
+		s.GinContext.Request.Header.Del("DPoP")
+		cleanData(email)
+	})
+	t.Run(`should bind tokens issued by token endpoint to dpop key`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := authorizercrypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                    "dpop client",
+			GrantTypes:              []string{constants.GrantTypeDeviceCode, constants.GrantTypeRefreshToken},
+			TokenEndpointAuthMethod: refs.NewStringRef(constants.TokenEndpointAuthMethodNone),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		req.Header.Del("Cookie")
+
+		email := "dpop_token." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+
+		_, body := postForm(t, s, "/oauth/device/code", url.Values{
+			"client_id": {client.Client.ClientID},
+			"scope":     {"openid offline_access"},
+		}, nil)
+		deviceCode, _ := body["device_code"].(string)
+		assert.NotEmpty(t, deviceCode)
+		deviceAuthorization, err := token.GetDeviceAuthorization(deviceCode)
+		assert.NoError(t, err)
+		deviceAuthorization.Status = token.DeviceAuthorizationStatusApproved
+		deviceAuthorization.UserID = verifyRes.User.ID
+		deviceAuthorization.LoginMethod = constants.AuthRecipeMethodBasicAuth
+		assert.NoError(t, token.SetDeviceAuthorization(deviceAuthorization))
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		jwk := jose.JSONWebKey{Key: key.Public()}
+		thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+		assert.NoError(t, err)
+		jkt := base64.RawURLEncoding.EncodeToString(thumbprint)
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		tokenURI := "http://" + s.Server.Listener.Addr().String() + "/oauth/token"
+		requestToken := func(data url.Values, proof string) (int, map[string]interface{}) {
+			header := http.Header{}
+			if proof != "" {
+				header.Set(token.DPoPHeader, proof)
+			}
+			return postForm(t, s, "/oauth/token", data, header)
+		}
+		deviceCodeData := url.Values{
+			"grant_type":  {constants.GrantTypeDeviceCode},
+			"client_id":   {client.Client.ClientID},
+			"device_code": {deviceCode},
+		}
+
+		// proof should match method, uri & current time of request
+		now := time.Now().Unix()
+		for _, proof := range []string{
+			createDPoPProofForRequest(t, key, "GET", tokenURI, now, ""),
+			createDPoPProofForRequest(t, key, "POST", "http://"+s.Server.Listener.Addr().String()+"/oauth/introspect", now, ""),
+			createDPoPProofForRequest(t, key, "POST", tokenURI, now-2*token.DPoPProofMaxAge, ""),
+			createDPoPProofForRequest(t, key, "POST", tokenURI, now+2*token.DPoPProofMaxAge, ""),
+		} {
+			status, body := requestToken(deviceCodeData, proof)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "invalid_dpop_proof", body["error"])
+		}
+
+		// tokens are bound to key of proof via cnf.jkt claim
+		proof := createDPoPProofForRequest(t, key, "POST", tokenURI, time.Now().Unix(), "")
+		status, body := requestToken(deviceCodeData, proof)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, token.DPoPTokenType, body["token_type"])
+		accessToken, _ := body["access_token"].(string)
+		refreshToken, _ := body["refresh_token"].(string)
+		assert.NotEmpty(t, refreshToken)
+		claims, err := token.ParseJWTToken(accessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, jkt, token.GetDPoPJKT(claims))
+		claims, err = token.ParseJWTToken(refreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, jkt, token.GetDPoPJKT(claims))
+
+		// proof cannot be replayed
+		refreshTokenData := url.Values{
+			"grant_type":    {constants.GrantTypeRefreshToken},
+			"client_id":     {client.Client.ClientID},
+			"refresh_token": {refreshToken},
+		}
+		status, body = requestToken(refreshTokenData, proof)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_dpop_proof", body["error"])
+
+		// bound refresh token can only be used with proof of same key
+		status, body = requestToken(refreshTokenData, "")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_dpop_proof", body["error"])
+		status, body = requestToken(refreshTokenData, createDPoPProofForRequest(t, otherKey, "POST", tokenURI, time.Now().Unix(), ""))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_dpop_proof", body["error"])
+
+		status, body = requestToken(refreshTokenData, createDPoPProofForRequest(t, key, "POST", tokenURI, time.Now().Unix(), ""))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, token.DPoPTokenType, body["token_type"])
+		newRefreshToken, _ := body["refresh_token"].(string)
+		claims, err = token.ParseJWTToken(newRefreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, jkt, token.GetDPoPJKT(claims))
+	})
+}
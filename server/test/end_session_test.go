@@ -0,0 +1,172 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func endSessionTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should confirm logout and notify only clients of session`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		// back-channel logout uris of clients, logout tokens are sent to channel of client
+		logoutTokens := map[string]chan string{}
+		backchannelLogoutServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logoutTokens[r.URL.Path] <- r.FormValue("logout_token")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer backchannelLogoutServer.Close()
+		addClient := func(name string) *model.AddClientResponse {
+			logoutTokens["/"+name] = make(chan string, 1)
+			res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+				Name:                   name,
+				RedirectUris:           []string{"https://" + name + ".example.com/callback"},
+				PostLogoutRedirectUris: []string{"https://" + name + ".example.com/logout"},
+				BackchannelLogoutURI:   refs.NewStringRef(backchannelLogoutServer.URL + "/" + name),
+			})
+			assert.NoError(t, err)
+			return res
+		}
+		sessionClient := addClient("session_client")
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: sessionClient.Client.ID})
+		otherClient := addClient("other_client")
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: otherClient.Client.ID})
+		req.Header.Del("Cookie")
+
+		email := "end_session." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(email)
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		user, err := db.Provider.GetUserByID(ctx, verifyRes.User.ID)
+		assert.NoError(t, err)
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + user.ID
+
+		login := func() (string, string) {
+			loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+				Email:    refs.NewStringRef(email),
+				Password: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(loginRes.IDToken))
+			assert.NoError(t, err)
+			sessionID, _ := claims["sid"].(string)
+			assert.NotEmpty(t, sessionID)
+			accessTokenClaims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+			assert.NoError(t, err)
+			sessionToken, err := memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+accessTokenClaims["nonce"].(string))
+			assert.NoError(t, err)
+			return sessionID, sessionToken
+		}
+		sessionID, sessionToken := login()
+		sessionCookie := http.Header{}
+		sessionCookie.Set("Cookie", fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken))
+
+		// session id is preserved for tokens issued to client within session
+		dbClient, err := db.Provider.GetClientByClientID(ctx, sessionClient.Client.ClientID)
+		assert.NoError(t, err)
+		clientToken, err := token.CreateAuthTokenForClient(s.GinContext, dbClient, user, []string{"user"}, []string{"openid"}, constants.AuthRecipeMethodBasicAuth, "end_session_nonce", "", 0, sessionID, "", nil)
+		assert.NoError(t, err)
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+clientToken.FingerPrint, clientToken.FingerPrintHash, clientToken.SessionTokenExpiresAt)
+		clientIDTokenClaims, err := token.ParseJWTToken(clientToken.IDToken.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, clientIDTokenClaims["sid"])
+
+		// logout without id_token_hint is confirmed by user
+		res := getRequest(t, s, "/oauth/logout", sessionCookie)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		page, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.NoError(t, err)
+		match := regexp.MustCompile(`name="logout_challenge" value="([^"]+)"`).FindStringSubmatch(string(page))
+		assert.Len(t, match, 2)
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_end_session_nonce")
+		assert.NoError(t, err)
+
+		status, _ := postForm(t, s, "/oauth/logout", url.Values{
+			"logout_challenge": {"invalid"},
+		}, sessionCookie)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = postForm(t, s, "/oauth/logout", url.Values{
+			"logout_challenge": {match[1]},
+		}, sessionCookie)
+		assert.Equal(t, http.StatusOK, status)
+		// all the rolled over sessions are terminated
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_end_session_nonce")
+		assert.Error(t, err)
+
+		// only the client which participated in session is notified
+		select {
+		case logoutToken := <-logoutTokens["/session_client"]:
+			claims, err := token.ParseJWTToken(logoutToken)
+			assert.NoError(t, err)
+			assert.Equal(t, sessionID, claims["sid"])
+			assert.Equal(t, sessionClient.Client.ClientID, claims["aud"])
+		case <-time.After(5 * time.Second):
+			t.Error("logout token is not sent to client of session")
+		}
+		select {
+		case <-logoutTokens["/other_client"]:
+			t.Error("logout token is sent to client which did not participate in session")
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		// logout challenge can be used only once
+		status, _ = postForm(t, s, "/oauth/logout", url.Values{
+			"logout_challenge": {match[1]},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// logout with id_token_hint is not confirmed, post logout redirect uri should be registered for client
+		_, _ = login()
+		clientToken, err = token.CreateAuthTokenForClient(s.GinContext, dbClient, user, []string{"user"}, []string{"openid"}, constants.AuthRecipeMethodBasicAuth, "end_session_hint_nonce", "", 0, "", "", nil)
+		assert.NoError(t, err)
+		hint := url.QueryEscape(clientToken.IDToken.Token)
+		res = getRequest(t, s, "/oauth/logout?id_token_hint="+hint+"&post_logout_redirect_uri="+url.QueryEscape("https://other_client.example.com/logout"), nil)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		res = getRequest(t, s, "/oauth/logout?id_token_hint="+hint+"&state=end_session_state&post_logout_redirect_uri="+url.QueryEscape("https://session_client.example.com/logout"), nil)
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		assert.Equal(t, "https://session_client.example.com/logout?state=end_session_state", res.Header.Get("Location"))
+		select {
+		case logoutToken := <-logoutTokens["/session_client"]:
+			claims, err := token.ParseJWTToken(logoutToken)
+			assert.NoError(t, err)
+			assert.Equal(t, clientToken.SessionID, claims["sid"])
+		case <-time.After(5 * time.Second):
+			t.Error("logout token is not sent to client of session")
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/oauth"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+)
+
+func identityProviderTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run("should manage upstream identity providers", func(t *testing.T) {
+		req, ctx := createContext(s)
+		params := model.AddIdentityProviderRequest{
+			Name:         "keycloak",
+			DiscoveryURL: refs.NewStringRef("https://keycloak.example.com/realms/test/.well-known/openid-configuration"),
+			ClientID:     "authorizer",
+			ClientSecret: "secret",
+		}
+		_, err := resolvers.AddIdentityProviderResolver(ctx, params)
+		assert.Error(t, err)
+
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		// name of built in provider is reserved
+		invalidNameParams := params
+		invalidNameParams.Name = constants.AuthRecipeMethodGoogle
+		_, err = resolvers.AddIdentityProviderResolver(ctx, invalidNameParams)
+		assert.Error(t, err)
+
+		// either discovery url or all the endpoints are required
+		missingEndpointsParams := params
+		missingEndpointsParams.DiscoveryURL = nil
+		missingEndpointsParams.AuthorizationURL = refs.NewStringRef("https://keycloak.example.com/auth")
+		_, err = resolvers.AddIdentityProviderResolver(ctx, missingEndpointsParams)
+		assert.Error(t, err)
+
+		invalidClaimMappingParams := params
+		invalidClaimMappingParams.ClaimMapping = map[string]interface{}{"password": "password"}
+		_, err = resolvers.AddIdentityProviderResolver(ctx, invalidClaimMappingParams)
+		assert.Error(t, err)
+
+		_, err = resolvers.AddIdentityProviderResolver(ctx, params)
+		assert.NoError(t, err)
+		_, err = resolvers.AddIdentityProviderResolver(ctx, params)
+		assert.Error(t, err)
+
+		identityProvider, err := db.Provider.GetIdentityProviderByName(ctx, params.Name)
+		assert.NoError(t, err)
+		assert.NotEqual(t, params.ClientSecret, identityProvider.ClientSecret)
+		identityProviderRes, err := resolvers.IdentityProviderResolver(ctx, model.IdentityProviderRequest{
+			ID: identityProvider.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, params.Name, identityProviderRes.Name)
+		assert.Equal(t, []string{"openid", "profile", "email"}, identityProviderRes.Scopes)
+
+		identityProviders, err := resolvers.IdentityProvidersResolver(ctx, &model.PaginatedInput{})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(identityProviders.IdentityProviders), 1)
+
+		meta, err := resolvers.MetaResolver(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, meta.IdentityProviders, params.Name)
+
+		_, err = resolvers.UpdateIdentityProviderResolver(ctx, model.UpdateIdentityProviderRequest{
+			ID:           identityProvider.ID,
+			Scopes:       []string{"openid", "email", "roles"},
+			ClaimMapping: map[string]interface{}{"roles": "realm_access.roles"},
+		})
+		assert.NoError(t, err)
+		identityProvider, err = db.Provider.GetIdentityProviderByID(ctx, identityProvider.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"openid", "email", "roles"}, identityProvider.GetScopes())
+
+		// only the roles configured for instance are mapped from claims
+		rolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyRoles)
+		assert.NoError(t, err)
+		role := strings.Split(rolesString, ",")[0]
+		user := oauth.GetIdentityProviderUser(identityProvider, map[string]interface{}{
+			"sub":        "upstream-user",
+			"email":      "idp_user@authorizer.dev",
+			"given_name": "IdP",
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{role, "offline_access"},
+			},
+		})
+		assert.Equal(t, "idp_user@authorizer.dev", refs.StringValue(user.Email))
+		assert.Equal(t, "IdP", refs.StringValue(user.GivenName))
+		assert.Equal(t, role, user.Roles)
+
+		// email is trusted only when identity provider marks it verified
+		assert.False(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"email": "idp_user@authorizer.dev"}))
+		assert.False(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"email_verified": false}))
+		assert.True(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"email_verified": true}))
+		assert.True(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"email_verified": "true"}))
+		identityProvider.ClaimMapping = `{"email_verified":"verified_email"}`
+		assert.False(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"email_verified": true}))
+		assert.True(t, oauth.IsIdentityProviderEmailVerified(identityProvider, map[string]interface{}{"verified_email": true}))
+
+		_, err = resolvers.DeleteIdentityProviderResolver(ctx, model.IdentityProviderRequest{
+			ID: identityProvider.ID,
+		})
+		assert.NoError(t, err)
+		_, err = db.Provider.GetIdentityProviderByName(ctx, params.Name)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func identityTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should link and unlink identities`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "identity." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+
+		_, err := resolvers.IdentitiesResolver(ctx, nil)
+		assert.NotNil(t, err, "unauthorized")
+		_, err = resolvers.LinkIdentityResolver(ctx, model.LinkIdentityRequest{
+			Provider: constants.AuthRecipeMethodGithub,
+		})
+		assert.NotNil(t, err, "unauthorized")
+
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, verifyRes.AccessToken)
+		userID := verifyRes.User.ID
+
+		s.GinContext.Request.Header.Set("Authorization", "Bearer "+*verifyRes.AccessToken)
+		ctx = context.WithValue(req.Context(), "GinContextKey", s.GinContext)
+		defer s.GinContext.Request.Header.Del("Authorization")
+
+		identities, err := resolvers.IdentitiesResolver(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, identities.Identities, 0)
+
+		// link token is returned as part of oauth_login url
+		linkRes, err := resolvers.LinkIdentityResolver(ctx, model.LinkIdentityRequest{
+			Provider:    constants.AuthRecipeMethodGithub,
+			RedirectURI: refs.NewStringRef("http://localhost:3000/profile"),
+		})
+		assert.NoError(t, err)
+		authorizationURL, err := url.Parse(linkRes.AuthorizationURL)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(authorizationURL.Path, "/oauth_login/"+constants.AuthRecipeMethodGithub))
+		assert.Equal(t, "http://localhost:3000/profile", authorizationURL.Query().Get("redirect_uri"))
+		linkRequest, err := token.GetIdentityLinkRequest(authorizationURL.Query().Get("link_token"))
+		assert.NoError(t, err)
+		assert.Equal(t, userID, linkRequest.UserID)
+		assert.Equal(t, constants.AuthRecipeMethodGithub, linkRequest.Provider)
+		// link token can be used only once
+		_, err = token.ConsumeIdentityLinkRequest(linkRequest.ID)
+		assert.NoError(t, err)
+		_, err = token.GetIdentityLinkRequest(linkRequest.ID)
+		assert.Error(t, err)
+
+		// link is accepted only within browser session of user who requested it
+		otherEmail := "other.identity." + s.TestInfo.Email
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(otherEmail),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		defer cleanData(otherEmail)
+		otherVerificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, otherEmail, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		_, err = resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: otherVerificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		getSessionCookie := func(email string) string {
+			loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+				Email:    refs.NewStringRef(email),
+				Password: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+			assert.NoError(t, err)
+			nonce, _ := claims["nonce"].(string)
+			sessionToken, err := memorystore.Provider.GetUserSession(constants.AuthRecipeMethodBasicAuth+":"+loginRes.User.ID, constants.TokenTypeSessionToken+"_"+nonce)
+			assert.NoError(t, err)
+			return fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken)
+		}
+		err = token.ValidateIdentityLinkSession(s.GinContext, linkRequest)
+		assert.Error(t, err)
+		req.Header.Set("Cookie", getSessionCookie(otherEmail))
+		err = token.ValidateIdentityLinkSession(s.GinContext, linkRequest)
+		assert.Error(t, err)
+		req.Header.Set("Cookie", getSessionCookie(email))
+		err = token.ValidateIdentityLinkSession(s.GinContext, linkRequest)
+		assert.NoError(t, err)
+		req.Header.Del("Cookie")
+
+		// identity is linked by oauth callback once user logs in with provider
+		identity, err := db.Provider.AddIdentity(ctx, &models.Identity{
+			UserID:         userID,
+			Provider:       constants.AuthRecipeMethodGithub,
+			ProviderUserID: "identity_test_github_id",
+			Email:          "github." + email,
+			RawProfile:     `{"id":"identity_test_github_id"}`,
+		})
+		assert.NoError(t, err)
+		user, err := db.Provider.GetUserByID(ctx, userID)
+		assert.NoError(t, err)
+		user.SignupMethods = user.SignupMethods + "," + constants.AuthRecipeMethodGithub
+		_, err = db.Provider.UpdateUser(ctx, user)
+		assert.NoError(t, err)
+
+		identity, err = db.Provider.GetIdentityByProviderUserID(ctx, constants.AuthRecipeMethodGithub, "identity_test_github_id")
+		assert.NoError(t, err)
+		assert.Equal(t, userID, identity.UserID)
+		identities, err = resolvers.IdentitiesResolver(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, identities.Identities, 1)
+		assert.Equal(t, constants.AuthRecipeMethodGithub, identities.Identities[0].Provider)
+		assert.Equal(t, "identity_test_github_id", identities.Identities[0].ProviderUserID)
+		assert.Equal(t, "github."+email, refs.StringValue(identities.Identities[0].Email))
+
+		// identity of another user can not be unlinked
+		otherIdentity, err := db.Provider.AddIdentity(ctx, &models.Identity{
+			UserID:         "identity_test_other_user",
+			Provider:       constants.AuthRecipeMethodGoogle,
+			ProviderUserID: "identity_test_google_id",
+		})
+		assert.NoError(t, err)
+		_, err = resolvers.UnlinkIdentityResolver(ctx, model.UnlinkIdentityRequest{
+			ID: otherIdentity.AsAPIIdentity().ID,
+		})
+		assert.Error(t, err)
+		err = db.Provider.DeleteIdentity(ctx, otherIdentity)
+		assert.NoError(t, err)
+
+		// user can still login with password once identity is unlinked
+		_, err = resolvers.UnlinkIdentityResolver(ctx, model.UnlinkIdentityRequest{
+			ID: identities.Identities[0].ID,
+		})
+		assert.NoError(t, err)
+		identities, err = resolvers.IdentitiesResolver(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, identities.Identities, 0)
+		user, err = db.Provider.GetUserByID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.AuthRecipeMethodBasicAuth, user.SignupMethods)
+
+		// last identity of user without password can not be unlinked
+		user.Password = nil
+		user.SignupMethods = constants.AuthRecipeMethodGithub
+		_, err = db.Provider.UpdateUser(ctx, user)
+		assert.NoError(t, err)
+		identity, err = db.Provider.AddIdentity(ctx, &models.Identity{
+			UserID:         userID,
+			Provider:       constants.AuthRecipeMethodGithub,
+			ProviderUserID: "identity_test_github_id",
+		})
+		assert.NoError(t, err)
+		_, err = resolvers.UnlinkIdentityResolver(ctx, model.UnlinkIdentityRequest{
+			ID: identity.AsAPIIdentity().ID,
+		})
+		assert.Error(t, err)
+
+		err = db.Provider.DeleteIdentity(ctx, identity)
+		assert.NoError(t, err)
+		cleanData(email)
+	})
+}
@@ -106,6 +106,20 @@ func TestResolvers(t *testing.T) {
 			updateWebhookTest(t, s)
 			webhookTest(t, s)
 			webhooksTest(t, s)
+			clientTest(t, s)
+			clientCredentialsTest(t, s)
+			tokenExchangeTest(t, s)
+			clientRegistrationTest(t, s)
+			pushedAuthorizationRequestTest(t, s)
+			authorizeTest(t, s)
+			authorizationCodeTest(t, s)
+			clientAssertionTest(t, s)
+			requestObjectTest(t, s)
+			authorizationResponseTest(t, s)
+			identityProviderTest(t, s)
+			samlServiceProviderTest(t, s)
+			samlIdentityProviderTest(t, s)
+			ldapTest(t, s)
 			//usersTest(t, s)
 			userTest(t, s)
 			deleteUserTest(t, s)
@@ -118,6 +132,8 @@ func TestResolvers(t *testing.T) {
 			revokeAccessTest(t, s)
 			enableAccessTest(t, s)
 			generateJWTkeyTest(t, s)
+			rotateJWTKeysTest(t, s)
+			jwksTest(t, s)
 			addEmailTemplateTest(t, s)
 			updateEmailTemplateTest(t, s)
 			emailTemplatesTest(t, s)
@@ -137,10 +153,22 @@ func TestResolvers(t *testing.T) {
 			resetPasswordTest(t, s)
 			verifyEmailTest(t, s)
 			sessionTests(t, s)
+			sessionTimeoutTest(t, s)
 			profileTests(t, s)
+			authorizeDeviceTest(t, s)
+			deviceCodeTest(t, s)
+			oauthGrantsTest(t, s)
+			revokeTokenTest(t, s)
+			introspectTest(t, s)
+			refreshTokenReuseTest(t, s)
+			identityTest(t, s)
+			dpopTest(t, s)
+			pairwiseSubjectTest(t, s)
+			claimsRequestTest(t, s)
 			updateProfileTests(t, s)
 			magicLinkLoginTests(t, s)
 			logoutTests(t, s)
+			endSessionTest(t, s)
 			metaTests(t, s)
 			inviteUserTest(t, s)
 			validateJwtTokenTest(t, s)
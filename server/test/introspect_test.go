@@ -0,0 +1,133 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func introspectTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should introspect access token and refresh token`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "introspect." + s.TestInfo.Email
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:       "introspection client",
+			GrantTypes: []string{constants.GrantTypeClientCredentials},
+			Scopes:     []string{"read"},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+		req.Header.Del("Cookie")
+
+		clientAuth := url.Values{
+			"client_id":     {res.Client.ClientID},
+			"client_secret": {res.ClientSecret},
+		}
+		introspect := func(tokenString, tokenTypeHint string, auth url.Values) (int, map[string]interface{}) {
+			data := url.Values{
+				"token":           {tokenString},
+				"token_type_hint": {tokenTypeHint},
+			}
+			for key, value := range auth {
+				data[key] = value
+			}
+			return postForm(t, s, "/oauth/introspect", data, nil)
+		}
+
+		status, body := postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":    {constants.GrantTypeClientCredentials},
+			"scope":         {"read"},
+			"client_id":     {res.Client.ClientID},
+			"client_secret": {res.ClientSecret},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		clientAccessToken, _ := body["access_token"].(string)
+
+		// client should be authenticated
+		status, body = introspect(clientAccessToken, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, _ = introspect(clientAccessToken, "", url.Values{
+			"client_id":     {res.Client.ClientID},
+			"client_secret": {"invalid-secret"},
+		})
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		status, body = introspect("", "", clientAuth)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, body = introspect(clientAccessToken, "", clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, body["active"])
+		assert.Equal(t, res.Client.ClientID, body["client_id"])
+		assert.Equal(t, constants.TokenTypeAccessToken, body["token_type"])
+		assert.Equal(t, "read", body["scope"])
+		assert.Nil(t, body["sub"])
+
+		status, body = introspect("invalid_token", "", clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, body["active"])
+		assert.Len(t, body, 1)
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "offline_access"},
+		})
+		assert.NoError(t, err)
+		accessToken := refs.StringValue(loginRes.AccessToken)
+		refreshToken := refs.StringValue(loginRes.RefreshToken)
+
+		status, body = introspect(accessToken, constants.TokenTypeAccessToken, clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, body["active"])
+		assert.Equal(t, verifyRes.User.ID, body["sub"])
+		assert.Equal(t, constants.TokenTypeAccessToken, body["token_type"])
+
+		// token type hint only decides the order of lookup
+		status, body = introspect(refreshToken, constants.TokenTypeAccessToken, clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, body["active"])
+		assert.Equal(t, constants.TokenTypeRefreshToken, body["token_type"])
+
+		// revoked tokens are not active
+		assert.NoError(t, token.RevokeToken(accessToken, ""))
+		status, body = introspect(accessToken, constants.TokenTypeAccessToken, clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, body["active"])
+		status, body = introspect(refreshToken, constants.TokenTypeRefreshToken, clientAuth)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, body["active"])
+
+		cleanData(email)
+	})
+}
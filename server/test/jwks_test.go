@@ -0,0 +1,64 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+func jwksTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should publish only asymmetric public keys`, func(t *testing.T) {
+		getJWKs := func() []map[string]interface{} {
+			res := getRequest(t, s, "/.well-known/jwks.json", nil)
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			var body struct {
+				Keys []map[string]interface{} `json:"keys"`
+			}
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			return body.Keys
+		}
+
+		keys := getJWKs()
+		assert.NotEmpty(t, keys)
+		for _, key := range keys {
+			assert.NotEqual(t, "oct", key["kty"])
+			assert.Nil(t, key["d"])
+		}
+
+		keyring, err := crypto.GetKeyring()
+		assert.NoError(t, err)
+		jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+		assert.NoError(t, err)
+		jwk, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJWK)
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, crypto.SaveKeyring(keyring))
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtType, jwtType)
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJWK, jwk)
+		}()
+
+		activeKey, err := crypto.NewSigningKey("HS256", crypto.SigningKeyStatusActive)
+		assert.NoError(t, err)
+		nextKey, err := crypto.NewSigningKey("HS512", crypto.SigningKeyStatusNext)
+		assert.NoError(t, err)
+		assert.NoError(t, crypto.SaveKeyring([]*crypto.SigningKey{activeKey, nextKey}))
+		assert.NoError(t, memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJwtType, "HS256"))
+		hmacJWK, err := activeKey.GetJWK()
+		assert.NoError(t, err)
+		assert.NoError(t, memorystore.Provider.UpdateEnvVariable(constants.EnvKeyJWK, hmacJWK))
+
+		// secret of HMAC keys is not exposed neither from keyring nor from env JWK
+		jwks, err := crypto.GetJWKs()
+		assert.NoError(t, err)
+		assert.Empty(t, jwks)
+		assert.Empty(t, getJWKs())
+	})
+}
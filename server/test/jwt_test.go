@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -65,7 +66,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -77,7 +78,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -89,7 +90,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -108,7 +109,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -124,7 +125,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -140,7 +141,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -159,7 +160,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -175,7 +176,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -191,7 +192,7 @@ func TestJwt(t *testing.T) {
 			c, err := token.ParseJWTToken(jwtToken)
 			assert.NoError(t, err)
 			assert.Equal(t, c["email"].(string), claims["email"])
-			valid, err := token.ValidateJWTClaims(c, hostname, nonce, subject)
+			valid, err := token.ValidateJWTClaims(context.Background(), c, hostname, nonce, subject)
 			assert.NoError(t, err)
 			assert.True(t, valid)
 		})
@@ -0,0 +1,272 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+)
+
+// ldapTestEntry is the entry of stub ldap server, entry can be bound with its dn & password
+type ldapTestEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+func ldapTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run("should login with ldap", func(t *testing.T) {
+		_, ctx := createContext(s)
+		email := "ldap." + s.TestInfo.Email
+		userEntry := &ldapTestEntry{
+			dn:       "uid=ldap_user,ou=people,dc=authorizer,dc=dev",
+			password: "ldap_password",
+			attributes: map[string][]string{
+				"uid":       {"ldap_user"},
+				"mail":      {email},
+				"givenName": {"LDAP"},
+				"sn":        {"User"},
+				"memberOf":  {"cn=admins,ou=groups,dc=authorizer,dc=dev", "cn=staff,ou=groups,dc=authorizer,dc=dev"},
+			},
+		}
+		ldapURL, caCertificate := startLDAPTestServer(t, []*ldapTestEntry{
+			{
+				dn:       "cn=service,dc=authorizer,dc=dev",
+				password: "service_password",
+			},
+			userEntry,
+		})
+
+		rolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyRoles)
+		assert.NoError(t, err)
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyRoles, rolesString+",ldap_admin")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPURL, ldapURL)
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPTLSCACertificate, caCertificate)
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPBindDN, "cn=service,dc=authorizer,dc=dev")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPBindPassword, "service_password")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPSearchBase, "dc=authorizer,dc=dev")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPUserFilter, "(|(mail={email})(uid={email}))")
+		// role which is not configured for instance is not assigned
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPGroupRoleMapping, `{"CN=Admins, OU=Groups, DC=authorizer, DC=dev": "ldap_admin", "cn=staff,ou=groups,dc=authorizer,dc=dev": "staff"}`)
+		defer func() {
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyRoles, rolesString)
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPURL, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPTLSCACertificate, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPStartTLS, false)
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPBindDN, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPBindPassword, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPSearchBase, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPUserFilter, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPGroupRoleMapping, "")
+		}()
+
+		// password is not sent over ldap:// without start tls
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPURL, "ldap://"+strings.TrimPrefix(ldapURL, "ldaps://"))
+		_, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef("ldap_user"),
+			Password: "ldap_password",
+		})
+		assert.Error(t, err)
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyLDAPURL, ldapURL)
+
+		_, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef("ldap_user"),
+			Password: "invalid_password",
+		})
+		assert.Error(t, err)
+		_, err = db.Provider.GetUserByEmail(ctx, email)
+		assert.Error(t, err)
+
+		// shadow user is created on first login
+		res, err := resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef("ldap_user"),
+			Password: "ldap_password",
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, res.AccessToken)
+		assert.Equal(t, email, refs.StringValue(res.User.Email))
+		assert.Equal(t, []string{"ldap_admin"}, res.User.Roles)
+		user, err := db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.AuthRecipeMethodLDAP, user.SignupMethods)
+		assert.Equal(t, "LDAP", refs.StringValue(user.GivenName))
+		assert.NotNil(t, user.EmailVerifiedAt)
+		assert.Nil(t, user.Password)
+
+		// shadow user is updated from directory on each login
+		userEntry.attributes["givenName"] = []string{"Directory"}
+		userEntry.attributes["memberOf"] = []string{"cn=staff,ou=groups,dc=authorizer,dc=dev"}
+		res, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: "ldap_password",
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, res.AccessToken)
+		user, err = db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+		assert.Equal(t, "Directory", refs.StringValue(user.GivenName))
+		defaultRoles, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyDefaultRoles)
+		assert.NoError(t, err)
+		assert.Equal(t, defaultRoles, user.Roles)
+
+		// password of ldap user is always verified by directory
+		_, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: "invalid_password",
+		})
+		assert.Error(t, err)
+
+		cleanData(email)
+
+		// existing user who has not signed up via ldap is not linked to directory entry with same email
+		basicAuthEmail := "ldap_basic_auth." + s.TestInfo.Email
+		_, err = resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(basicAuthEmail),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		assert.NoError(t, err)
+		defer cleanData(basicAuthEmail)
+		userEntry.attributes["mail"] = []string{basicAuthEmail}
+		_, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef("ldap_user"),
+			Password: "ldap_password",
+		})
+		assert.Error(t, err)
+		user, err = db.Provider.GetUserByEmail(ctx, basicAuthEmail)
+		assert.NoError(t, err)
+		assert.NotContains(t, user.SignupMethods, constants.AuthRecipeMethodLDAP)
+	})
+}
+
+// startLDAPTestServer starts the stub ldaps server which supports bind & search operations,
+// search returns the entries having an attribute value used in filter.
+// Url of server is returned along with the self signed certificate of server
+func startLDAPTestServer(t *testing.T, entries []*ldapTestEntry) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	assert.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{certificate}, PrivateKey: key}},
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveLDAPTestConn(conn, entries)
+		}
+	}()
+	return "ldaps://" + listener.Addr().String(), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}))
+}
+
+func serveLDAPTestConn(conn net.Conn, entries []*ldapTestEntry) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			dn, _ := op.Children[1].Value.(string)
+			password := op.Children[2].Data.String()
+			resultCode := goldap.LDAPResultInvalidCredentials
+			for _, entry := range entries {
+				if entry.dn == dn && entry.password == password {
+					resultCode = goldap.LDAPResultSuccess
+				}
+			}
+			conn.Write(newLDAPTestResult(messageID, goldap.ApplicationBindResponse, resultCode).Bytes())
+		case goldap.ApplicationSearchRequest:
+			filter, _ := goldap.DecompileFilter(op.Children[6])
+			for _, entry := range entries {
+				if !isLDAPTestEntryMatched(entry, filter) {
+					continue
+				}
+				res := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+				res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+				searchEntry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+				searchEntry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.dn, "Object Name"))
+				attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+				for name, values := range entry.attributes {
+					attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+					attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+					attributeValues := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+					for _, value := range values {
+						attributeValues.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+					}
+					attribute.AppendChild(attributeValues)
+					attributes.AppendChild(attribute)
+				}
+				searchEntry.AppendChild(attributes)
+				res.AppendChild(searchEntry)
+				conn.Write(res.Bytes())
+			}
+			conn.Write(newLDAPTestResult(messageID, goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess).Bytes())
+		case goldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func isLDAPTestEntryMatched(entry *ldapTestEntry, filter string) bool {
+	for name, values := range entry.attributes {
+		for _, value := range values {
+			if strings.Contains(filter, "("+name+"="+value+")") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newLDAPTestResult(messageID int64, tag ber.Tag, resultCode int) *ber.Packet {
+	res := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	res.AppendChild(result)
+	return res
+}
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func oauthGrantsTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should list and revoke oauth grants`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "oauth_grants." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+
+		_, err := resolvers.OAuthGrantsResolver(ctx, nil)
+		assert.NotNil(t, err, "unauthorized")
+
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		assert.NotNil(t, verificationRequest)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, verifyRes.AccessToken)
+
+		oauthGrant, err := db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+			UserID:   verifyRes.User.ID,
+			ClientID: "oauth_grants_test_client",
+			Scopes:   "openid,profile",
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, oauthGrant)
+
+		s.GinContext.Request.Header.Set("Authorization", "Bearer "+*verifyRes.AccessToken)
+		ctx = context.WithValue(req.Context(), "GinContextKey", s.GinContext)
+		oauthGrants, err := resolvers.OAuthGrantsResolver(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, oauthGrants.OauthGrants, 1)
+		assert.Equal(t, "oauth_grants_test_client", oauthGrants.OauthGrants[0].ClientID)
+		assert.Equal(t, []string{"openid", "profile"}, oauthGrants.OauthGrants[0].Scopes)
+
+		_, err = resolvers.RevokeOAuthGrantResolver(ctx, model.RevokeOAuthGrantRequest{
+			ClientID: "invalid_client",
+		})
+		assert.Error(t, err)
+
+		res, err := resolvers.RevokeOAuthGrantResolver(ctx, model.RevokeOAuthGrantRequest{
+			ClientID: "oauth_grants_test_client",
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Message)
+
+		oauthGrants, err = resolvers.OAuthGrantsResolver(ctx, nil)
+		assert.NoError(t, err)
+		assert.Len(t, oauthGrants.OauthGrants, 0)
+		s.GinContext.Request.Header.Set("Authorization", "")
+		cleanData(email)
+	})
+	t.Run(`should revoke tokens of oauth grant and check session before consuming consent`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		redirectURI := "https://oauth-grants.example.com/callback"
+		addClient := func(name string) *models.Client {
+			res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+				Name:         name,
+				RedirectUris: []string{redirectURI},
+			})
+			assert.NoError(t, err)
+			client, err := db.Provider.GetClientByClientID(ctx, res.Client.ClientID)
+			assert.NoError(t, err)
+			return client
+		}
+		client := addClient("oauth grants client")
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.ID})
+		otherClient := addClient("oauth grants other client")
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: otherClient.ID})
+		req.Header.Del("Cookie")
+
+		signup := func(email string) (*models.User, http.Header) {
+			_, err := resolvers.SignupResolver(ctx, model.SignUpInput{
+				Email:           refs.NewStringRef(email),
+				Password:        s.TestInfo.Password,
+				ConfirmPassword: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+			assert.NoError(t, err)
+			verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+				Token: verificationRequest.Token,
+			})
+			assert.NoError(t, err)
+			user, err := db.Provider.GetUserByID(ctx, verifyRes.User.ID)
+			assert.NoError(t, err)
+			claims, err := token.ParseJWTToken(refs.StringValue(verifyRes.AccessToken))
+			assert.NoError(t, err)
+			sessionToken, err := memorystore.Provider.GetUserSession(constants.AuthRecipeMethodBasicAuth+":"+user.ID, constants.TokenTypeSessionToken+"_"+claims["nonce"].(string))
+			assert.NoError(t, err)
+			sessionCookie := http.Header{}
+			sessionCookie.Set("Cookie", fmt.Sprintf("%s=%s", constants.AppCookieName+"_session", sessionToken))
+			return user, sessionCookie
+		}
+		email := "oauth_grants_revoke." + s.TestInfo.Email
+		user, sessionCookie := signup(email)
+		defer cleanData(email)
+		otherEmail := "oauth_grants_other." + s.TestInfo.Email
+		_, otherSessionCookie := signup(otherEmail)
+		defer cleanData(otherEmail)
+
+		// consent challenge is not consumed by session of other user
+		postConsent := func(consentChallenge string, header http.Header) *http.Response {
+			data := url.Values{
+				"consent_challenge": {consentChallenge},
+				"action":            {"deny"},
+			}
+			req, err := http.NewRequest(http.MethodPost, s.Server.URL+"/oauth/consent", strings.NewReader(data.Encode()))
+			assert.NoError(t, err)
+			for key, values := range header {
+				req.Header[key] = values
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			httpClient := &http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+			res, err := httpClient.Do(req)
+			assert.NoError(t, err)
+			res.Body.Close()
+			return res
+		}
+		consentRequest, err := token.SetConsentRequest(&token.ConsentRequest{
+			UserID:       user.ID,
+			ClientID:     client.ClientID,
+			Scope:        []string{"openid"},
+			RedirectURI:  redirectURI,
+			ResponseMode: constants.ResponseModeQuery,
+			State:        "oauth_grants_state",
+		})
+		assert.NoError(t, err)
+		res := postConsent(consentRequest.ID, otherSessionCookie)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		res = postConsent(consentRequest.ID, sessionCookie)
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		assert.NoError(t, err)
+		assert.Equal(t, "access_denied", location.Query().Get("error"))
+		res = postConsent(consentRequest.ID, sessionCookie)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		// revoking oauth grant revokes tokens issued to client
+		_, err = db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+			UserID:   user.ID,
+			ClientID: client.ClientID,
+			Scopes:   "openid,offline_access",
+		})
+		assert.NoError(t, err)
+		scope := []string{"openid", "offline_access"}
+		clientToken, err := token.CreateAuthTokenForClient(s.GinContext, client, user, []string{"user"}, scope, constants.AuthRecipeMethodBasicAuth, "oauth_grants_nonce", "", 0, "", "", nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, clientToken.RefreshToken)
+		otherClientToken, err := token.CreateAuthTokenForClient(s.GinContext, otherClient, user, []string{"user"}, scope, constants.AuthRecipeMethodBasicAuth, "oauth_grants_other_nonce", "", 0, "", "", nil)
+		assert.NoError(t, err)
+		_, err = token.ParseJWTToken(clientToken.AccessToken.Token)
+		assert.NoError(t, err)
+
+		req.Header.Set("Cookie", sessionCookie.Get("Cookie"))
+		defer req.Header.Del("Cookie")
+		_, err = resolvers.RevokeOAuthGrantResolver(ctx, model.RevokeOAuthGrantRequest{
+			ClientID: client.ClientID,
+		})
+		assert.NoError(t, err)
+		_, err = token.ParseJWTToken(clientToken.AccessToken.Token)
+		assert.Error(t, err)
+		_, err = token.ParseJWTToken(clientToken.RefreshToken.Token)
+		assert.Error(t, err)
+		_, err = token.ParseJWTToken(otherClientToken.AccessToken.Token)
+		assert.NoError(t, err)
+
+		// oauth grants of user are deleted along with user
+		_, err = db.Provider.AddOAuthGrant(ctx, &models.OAuthGrant{
+			UserID:   user.ID,
+			ClientID: otherClient.ClientID,
+			Scopes:   "openid",
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, db.Provider.DeleteUser(ctx, user))
+		_, err = db.Provider.GetOAuthGrant(ctx, user.ID, otherClient.ClientID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func pairwiseSubjectTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should issue pairwise subject for pairwise client`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		email := "pairwise." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		user, err := db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+
+		client, err := db.Provider.AddClient(ctx, &models.Client{
+			ClientID:     uuid.New().String(),
+			Name:         "pairwise",
+			RedirectURIs: "https://app.example.com/callback",
+			SubjectType:  constants.SubjectTypePairwise,
+		})
+		assert.NoError(t, err)
+		otherClient := &models.Client{
+			ClientID:     uuid.New().String(),
+			RedirectURIs: "https://other.example.com/callback",
+			SubjectType:  constants.SubjectTypePairwise,
+		}
+
+		subject, err := token.GetSubject(client, user.ID)
+		assert.NoError(t, err)
+		assert.NotEqual(t, user.ID, subject)
+		sameSubject, err := token.GetSubject(client, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, subject, sameSubject)
+		otherSubject, err := token.GetSubject(otherClient, user.ID)
+		assert.NoError(t, err)
+		assert.NotEqual(t, subject, otherSubject)
+		// subject is resolved to user id without any state being tracked for it
+		assert.Equal(t, user.ID, token.GetUserIDFromSubject(subject))
+		assert.Equal(t, user.ID, token.GetUserIDFromSubject(otherSubject))
+		_, err = memorystore.Provider.GetUserSession("pairwise_subject", subject)
+		assert.Error(t, err)
+		tamperedSubject := subject[:len(subject)-2] + "AA"
+		if tamperedSubject == subject {
+			tamperedSubject = subject[:len(subject)-2] + "BB"
+		}
+		assert.Equal(t, tamperedSubject, token.GetUserIDFromSubject(tamperedSubject))
+
+		nonce := uuid.New().String()
+		authToken, err := token.CreateAuthTokenForClient(s.GinContext, client, user, verifyRes.User.Roles, []string{"openid"}, constants.AuthRecipeMethodBasicAuth, nonce, "", 0, "", "", nil)
+		assert.NoError(t, err)
+		idTokenClaims, err := token.ParseJWTToken(authToken.IDToken.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, subject, idTokenClaims["sub"])
+
+		// access token with pairwise subject can be used to access user profile
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + user.ID
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+nonce, authToken.AccessToken.Token, authToken.AccessToken.ExpiresAt)
+		s.GinContext.Request.Header.Set("Authorization", "Bearer "+authToken.AccessToken.Token)
+		ctx = context.WithValue(req.Context(), "GinContextKey", s.GinContext)
+		profile, err := resolvers.ProfileResolver(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, email, refs.StringValue(profile.Email))
+
+		s.GinContext.Request.Header.Set("Authorization", "")
+		assert.NoError(t, db.Provider.DeleteClient(ctx, client))
+		cleanData(email)
+	})
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func pushedAuthorizationRequestTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should push authorization request and consume request_uri once`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		redirectURI := "https://par.example.com/callback"
+		res, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                               "par client",
+			RedirectUris:                       []string{redirectURI},
+			RequirePushedAuthorizationRequests: refs.NewBoolRef(true),
+		})
+		assert.NoError(t, err)
+		clientID := res.Client.ClientID
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: res.Client.ID})
+
+		params := url.Values{
+			"client_id":             {clientID},
+			"client_secret":         {res.ClientSecret},
+			"response_type":         {constants.ResponseTypeCode},
+			"redirect_uri":          {redirectURI},
+			"state":                 {"par_state"},
+			"code_challenge":        {"par_code_challenge"},
+			"code_challenge_method": {"S256"},
+		}
+		push := func() string {
+			status, body := postForm(t, s, "/oauth/par", params, nil)
+			assert.Equal(t, http.StatusCreated, status)
+			assert.Equal(t, float64(token.PushedAuthorizationRequestExpiresIn), body["expires_in"])
+			requestURI, _ := body["request_uri"].(string)
+			assert.Contains(t, requestURI, token.PushedAuthorizationRequestURIPrefix)
+			return requestURI
+		}
+		authorize := func(clientID, requestURI string) *http.Response {
+			return getRequest(t, s, "/authorize?client_id="+url.QueryEscape(clientID)+"&request_uri="+url.QueryEscape(requestURI), nil)
+		}
+
+		// client should authenticate to push authorization request
+		withoutSecret := url.Values{}
+		for k, v := range params {
+			withoutSecret[k] = v
+		}
+		withoutSecret.Del("client_secret")
+		status, body := postForm(t, s, "/oauth/par", withoutSecret, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		// redirect uri should be registered for client
+		invalidRedirectURI := url.Values{}
+		for k, v := range params {
+			invalidRedirectURI[k] = v
+		}
+		invalidRedirectURI.Set("redirect_uri", "https://attacker.example.com/callback")
+		status, _ = postForm(t, s, "/oauth/par", invalidRedirectURI, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// client requiring pushed authorization requests cannot send params in query
+		authorizeRes := getRequest(t, s, "/authorize?client_id="+url.QueryEscape(clientID)+"&response_type=code&state=par_state&code_challenge=par_code_challenge&redirect_uri="+url.QueryEscape(redirectURI), nil)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// request_uri can be used only once
+		requestURI := push()
+		authorizeRes = authorize(clientID, requestURI)
+		assert.Equal(t, http.StatusFound, authorizeRes.StatusCode)
+		authorizeRes = authorize(clientID, requestURI)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// request_uri is bound to client which pushed it
+		requestURI = push()
+		authorizeRes = authorize("test", requestURI)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+		authorizeRes = authorize(clientID, requestURI)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// expired request_uri is rejected, "par:" is the state key prefix of pushed authorization requests
+		requestURI = push()
+		data, err := memorystore.Provider.GetState("par:" + requestURI)
+		assert.NoError(t, err)
+		var pushedAuthorizationRequest token.PushedAuthorizationRequest
+		assert.NoError(t, json.Unmarshal([]byte(data), &pushedAuthorizationRequest))
+		pushedAuthorizationRequest.ExpiresAt = time.Now().Unix() - 1
+		expired, err := json.Marshal(pushedAuthorizationRequest)
+		assert.NoError(t, err)
+		assert.NoError(t, memorystore.Provider.SetState("par:"+requestURI, string(expired)))
+		authorizeRes = authorize(clientID, requestURI)
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// unknown request_uri is rejected
+		authorizeRes = authorize(clientID, token.PushedAuthorizationRequestURIPrefix+"unknown")
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+
+		// request_uri other than the one of pushed authorization request is rejected without fetching it
+		fetched := false
+		requestObjectServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetched = true
+		}))
+		defer requestObjectServer.Close()
+		authorizeRes = authorize(clientID, requestObjectServer.URL+"/request.jwt")
+		assert.Equal(t, http.StatusBadRequest, authorizeRes.StatusCode)
+		assert.False(t, fetched)
+	})
+}
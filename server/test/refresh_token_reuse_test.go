@@ -0,0 +1,122 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func refreshTokenReuseTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should revoke refresh token family when rotated refresh token is reused`, func(t *testing.T) {
+		_, ctx := createContext(s)
+		email := "refresh_token_reuse." + s.TestInfo.Email
+		clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+		assert.NoError(t, err)
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		_, err = resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+		loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "offline_access"},
+		})
+		assert.NoError(t, err)
+		refreshToken := refs.StringValue(loginRes.RefreshToken)
+		claims, err := token.ParseJWTToken(refreshToken)
+		assert.NoError(t, err)
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + loginRes.User.ID
+		loginNonce := claims["nonce"].(string)
+
+		refresh := func(refreshToken string) (int, map[string]interface{}) {
+			return postForm(t, s, "/oauth/token", url.Values{
+				"grant_type":    {constants.GrantTypeRefreshToken},
+				"client_id":     {clientID},
+				"refresh_token": {refreshToken},
+			}, nil)
+		}
+
+		status, body := refresh(refreshToken)
+		assert.Equal(t, http.StatusOK, status)
+		rotatedRefreshToken, _ := body["refresh_token"].(string)
+		rotatedAccessToken, _ := body["access_token"].(string)
+		assert.NotEmpty(t, rotatedRefreshToken)
+		// rotated refresh token & its session are removed before response is sent
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeRefreshToken+"_"+loginNonce)
+		assert.Error(t, err)
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+loginNonce)
+		assert.Error(t, err)
+
+		status, body = refresh(rotatedRefreshToken)
+		assert.Equal(t, http.StatusOK, status)
+		latestRefreshToken, _ := body["refresh_token"].(string)
+		latestAccessToken, _ := body["access_token"].(string)
+		latestClaims, err := token.ParseJWTToken(latestRefreshToken)
+		assert.NoError(t, err)
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+latestClaims["nonce"].(string))
+		assert.NoError(t, err)
+
+		// rotated refresh tokens are tracked even when many sessions are created afterwards
+		for i := 0; i < 1100; i++ {
+			memorystore.Provider.SetUserSession(fmt.Sprintf("refresh_token_reuse_filler:%d", i), "key", "value", latestClaims["exp"].(int64))
+		}
+		for i := 0; i < 1100; i++ {
+			memorystore.Provider.DeleteAllUserSessions(fmt.Sprintf("refresh_token_reuse_filler:%d", i))
+		}
+
+		// reuse of first refresh token revokes all the tokens & sessions of family
+		status, body = refresh(refreshToken)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "unauthorized", body["error"])
+		status, _ = refresh(latestRefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		_, err = token.ValidateAccessToken(s.GinContext, rotatedAccessToken)
+		assert.Error(t, err)
+		_, err = token.ValidateAccessToken(s.GinContext, latestAccessToken)
+		assert.Error(t, err)
+		_, err = memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+latestClaims["nonce"].(string))
+		assert.Error(t, err)
+
+		// refresh token is marked as rotated before new tokens are issued,
+		// hence concurrent request which validated the refresh token before its session was removed is rejected
+		loginRes, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "offline_access"},
+		})
+		assert.NoError(t, err)
+		refreshToken = refs.StringValue(loginRes.RefreshToken)
+		claims, err = token.ParseJWTToken(refreshToken)
+		assert.NoError(t, err)
+		status, body = refresh(refreshToken)
+		assert.Equal(t, http.StatusOK, status)
+		rotatedRefreshToken, _ = body["refresh_token"].(string)
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeRefreshToken+"_"+claims["nonce"].(string), refreshToken, claims["exp"].(int64))
+		status, _ = refresh(refreshToken)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, _ = refresh(rotatedRefreshToken)
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		cleanData(email)
+	})
+}
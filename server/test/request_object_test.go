@@ -0,0 +1,108 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func requestObjectTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should validate signed request object`, func(t *testing.T) {
+		createContext(s)
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		keyID := uuid.New().String()
+		jwks, err := json.Marshal(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: keyID, Algorithm: "ES256", Use: "sig"}},
+		})
+		assert.NoError(t, err)
+		client := &models.Client{
+			ClientID:                   uuid.New().String(),
+			JWKS:                       string(jwks),
+			RequireSignedRequestObject: true,
+		}
+		claims := func() map[string]interface{} {
+			return map[string]interface{}{
+				"iss":           client.ClientID,
+				"aud":           parsers.GetHost(s.GinContext),
+				"client_id":     client.ClientID,
+				"response_type": "code",
+				"scope":         "openid email",
+				"max_age":       300,
+				"claims": map[string]interface{}{
+					"userinfo": map[string]interface{}{"email": nil},
+				},
+				"jti": uuid.New().String(),
+				"exp": time.Now().Add(time.Minute).Unix(),
+			}
+		}
+
+		requestObjectClaims := claims()
+		params, err := token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, requestObjectClaims))
+		assert.NoError(t, err)
+		assert.Equal(t, "code", params["response_type"])
+		assert.Equal(t, "openid email", params["scope"])
+		assert.Equal(t, "300", params["max_age"])
+		claimsRequest, err := token.ParseClaimsRequest(params["claims"])
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"email"}, claimsRequest.UserInfo)
+
+		// request object signed with unregistered key is rejected
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, otherKey, keyID, claims()))
+		assert.Error(t, err)
+
+		// request object for other authorization server is rejected
+		otherAudienceClaims := claims()
+		otherAudienceClaims["aud"] = "https://example.com"
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, otherAudienceClaims))
+		assert.Error(t, err)
+
+		// request object of other client is rejected
+		otherClientClaims := claims()
+		otherClientClaims["client_id"] = uuid.New().String()
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, otherClientClaims))
+		assert.Error(t, err)
+
+		// expired request object is rejected
+		expiredClaims := claims()
+		expiredClaims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, expiredClaims))
+		assert.Error(t, err)
+
+		// request object can be used only once
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, requestObjectClaims))
+		assert.Error(t, err)
+
+		// aud, jti & exp are required
+		for _, claim := range []string{"aud", "jti", "exp"} {
+			missingClaims := claims()
+			delete(missingClaims, claim)
+			_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, missingClaims))
+			assert.Error(t, err, claim)
+		}
+
+		// request object with exp beyond max lifetime is rejected
+		longLivedClaims := claims()
+		longLivedClaims["exp"] = time.Now().Add(time.Duration(token.RequestObjectMaxLifetime+3600) * time.Second).Unix()
+		_, err = token.ValidateRequestObject(s.GinContext, client, createClientAssertion(t, key, keyID, longLivedClaims))
+		assert.Error(t, err)
+
+		// unsigned request object is rejected
+		_, err = token.ValidateRequestObject(s.GinContext, client, "eyJhbGciOiJub25lIn0.eyJzY29wZSI6Im9wZW5pZCJ9.")
+		assert.Error(t, err)
+	})
+}
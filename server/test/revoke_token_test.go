@@ -0,0 +1,159 @@
+package test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func revokeTokenTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should revoke access token and refresh token`, func(t *testing.T) {
+		_, ctx := createContext(s)
+		email := "revoke_token." + s.TestInfo.Email
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		_, err = resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+
+		_, err = resolvers.RevokeResolver(ctx, model.OAuthRevokeInput{})
+		assert.Error(t, err)
+
+		loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "profile", "offline_access"},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, loginRes.AccessToken)
+		assert.NotNil(t, loginRes.RefreshToken)
+		accessToken := refs.StringValue(loginRes.AccessToken)
+		claims, err := token.ParseJWTToken(accessToken)
+		assert.NoError(t, err)
+
+		// id token cannot be revoked
+		_, err = resolvers.RevokeResolver(ctx, model.OAuthRevokeInput{
+			Token: loginRes.IDToken,
+		})
+		assert.Error(t, err)
+
+		res, err := resolvers.RevokeResolver(ctx, model.OAuthRevokeInput{
+			Token:         refs.NewStringRef(accessToken),
+			TokenTypeHint: refs.NewStringRef(constants.TokenTypeAccessToken),
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Message)
+		_, err = token.ValidateAccessToken(s.GinContext, accessToken)
+		assert.Error(t, err)
+		// refresh token of same session is also revoked
+		_, err = token.ValidateRefreshToken(s.GinContext, refs.StringValue(loginRes.RefreshToken))
+		assert.Error(t, err)
+
+		// revoked token is rejected even if session entry is present
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + loginRes.User.ID
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeAccessToken+"_"+claims["nonce"].(string), accessToken, claims["exp"].(int64))
+		_, err = token.ParseJWTToken(accessToken)
+		assert.Error(t, err)
+
+		// revoked token stays rejected when many sessions are created afterwards
+		for i := 0; i < 1100; i++ {
+			memorystore.Provider.SetUserSession(fmt.Sprintf("revoke_token_filler:%d", i), "key", "value", claims["exp"].(int64))
+		}
+		_, err = token.ParseJWTToken(accessToken)
+		assert.Error(t, err)
+		for i := 0; i < 1100; i++ {
+			memorystore.Provider.DeleteAllUserSessions(fmt.Sprintf("revoke_token_filler:%d", i))
+		}
+
+		// invalid token is treated as revoked
+		_, err = resolvers.RevokeResolver(ctx, model.OAuthRevokeInput{
+			Token: refs.NewStringRef("invalid_token"),
+		})
+		assert.NoError(t, err)
+
+		// refresh_token input is supported for backward compatibility
+		loginRes, err = resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "profile", "offline_access"},
+		})
+		assert.NoError(t, err)
+		_, err = resolvers.RevokeResolver(ctx, model.OAuthRevokeInput{
+			RefreshToken: loginRes.RefreshToken,
+		})
+		assert.NoError(t, err)
+		_, err = token.ValidateRefreshToken(s.GinContext, refs.StringValue(loginRes.RefreshToken))
+		assert.Error(t, err)
+		_, err = token.ValidateAccessToken(s.GinContext, refs.StringValue(loginRes.AccessToken))
+		assert.Error(t, err)
+
+		cleanData(email)
+	})
+	t.Run(`should authenticate confidential client at revocation endpoint`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:       "revoke token client",
+			GrantTypes: []string{constants.GrantTypeClientCredentials},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		req.Header.Del("Cookie")
+
+		status, body := postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":    {constants.GrantTypeClientCredentials},
+			"client_id":     {client.Client.ClientID},
+			"client_secret": {client.ClientSecret},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		accessToken, _ := body["access_token"].(string)
+		assert.NotEmpty(t, accessToken)
+
+		// confidential client cannot revoke tokens without credentials
+		status, body = postForm(t, s, "/oauth/revoke", url.Values{
+			"token":     {accessToken},
+			"client_id": {client.Client.ClientID},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, body = postForm(t, s, "/oauth/revoke", url.Values{
+			"token":         {accessToken},
+			"client_id":     {client.Client.ClientID},
+			"client_secret": {"invalid-secret"},
+		}, nil)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		header := http.Header{}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(client.Client.ClientID+":"+client.ClientSecret)))
+		status, _ = postForm(t, s, "/oauth/revoke", url.Values{
+			"token": {accessToken},
+		}, header)
+		assert.Equal(t, http.StatusOK, status)
+	})
+}
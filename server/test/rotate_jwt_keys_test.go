@@ -0,0 +1,83 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func rotateJWTKeysTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	req, ctx := createContext(s)
+	t.Run(`should rotate jwt keys`, func(t *testing.T) {
+		res, err := resolvers.RotateJWTKeysResolver(ctx, nil)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		claims := jwt.MapClaims{
+			"sub": "rotate_jwt_keys_test",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+		oldToken, err := token.SignJWTToken(claims)
+		assert.NoError(t, err)
+		oldActiveKey, err := crypto.GetActiveSigningKey()
+		assert.NoError(t, err)
+
+		res, err = resolvers.RotateJWTKeysResolver(ctx, &model.RotateJWTKeysInput{})
+		assert.NoError(t, err)
+		assert.True(t, res.Rotated)
+		statuses := map[string]string{}
+		for _, key := range res.Keys {
+			statuses[key.Kid] = key.Status
+		}
+		assert.Equal(t, crypto.SigningKeyStatusRetired, statuses[oldActiveKey.KeyID])
+
+		newActiveKey, err := crypto.GetActiveSigningKey()
+		assert.NoError(t, err)
+		assert.NotEqual(t, oldActiveKey.KeyID, newActiveKey.KeyID)
+
+		// tokens signed with retired key are still valid
+		_, err = token.ParseJWTToken(oldToken)
+		assert.NoError(t, err)
+
+		newToken, err := token.SignJWTToken(claims)
+		assert.NoError(t, err)
+		parsedToken, _, err := new(jwt.Parser).ParseUnverified(newToken, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, newActiveKey.KeyID, parsedToken.Header["kid"])
+		_, err = token.ParseJWTToken(newToken)
+		assert.NoError(t, err)
+
+		jwks, err := crypto.GetJWKs()
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(jwks), 3)
+
+		res, err = resolvers.RotateJWTKeysResolver(ctx, &model.RotateJWTKeysInput{
+			IfOlderThan: refs.NewStringRef("720h"),
+		})
+		assert.NoError(t, err)
+		assert.False(t, res.Rotated)
+
+		keys, err := resolvers.JWTKeysResolver(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, len(res.Keys), len(keys.Keys))
+	})
+}
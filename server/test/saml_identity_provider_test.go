@@ -0,0 +1,186 @@
+package test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/saml"
+)
+
+func samlIdentityProviderTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run("should manage saml identity providers", func(t *testing.T) {
+		req, ctx := createContext(s)
+		identityProvider, err := saml.GetIdentityProvider("https://idp.example.com")
+		assert.NoError(t, err)
+		metadata, err := xml.Marshal(identityProvider.Metadata())
+		assert.NoError(t, err)
+		params := model.AddSAMLIdentityProviderRequest{
+			Name:        "okta",
+			MetadataXML: refs.NewStringRef(string(metadata)),
+		}
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, params)
+		assert.Error(t, err)
+
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		// either metadata url or valid metadata xml is required
+		missingMetadataParams := params
+		missingMetadataParams.MetadataXML = nil
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, missingMetadataParams)
+		assert.Error(t, err)
+
+		invalidMetadataParams := params
+		invalidMetadataParams.MetadataXML = refs.NewStringRef("<EntityDescriptor></EntityDescriptor>")
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, invalidMetadataParams)
+		assert.Error(t, err)
+
+		invalidAttributeMappingParams := params
+		invalidAttributeMappingParams.AttributeMapping = map[string]interface{}{"password": "password"}
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, invalidAttributeMappingParams)
+		assert.Error(t, err)
+
+		invalidEmailDomainParams := params
+		invalidEmailDomainParams.AllowedEmailDomains = []string{"user@example.com"}
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, invalidEmailDomainParams)
+		assert.Error(t, err)
+
+		// metadata url should use https
+		httpMetadataParams := params
+		httpMetadataParams.MetadataXML = nil
+		httpMetadataParams.MetadataURL = refs.NewStringRef("http://idp.example.com/metadata")
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, httpMetadataParams)
+		assert.Error(t, err)
+
+		// metadata fetched from metadata url is pinned, so it is not fetched on login
+		metadataRequests := 0
+		metadataServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metadataRequests++
+			w.Write(metadata)
+		}))
+		defer metadataServer.Close()
+		defaultTransport := http.DefaultClient.Transport
+		http.DefaultClient.Transport = metadataServer.Client().Transport
+		defer func() { http.DefaultClient.Transport = defaultTransport }()
+		metadataURLParams := params
+		metadataURLParams.Name = "okta-metadata-url"
+		metadataURLParams.MetadataXML = nil
+		metadataURLParams.MetadataURL = refs.NewStringRef(metadataServer.URL + "/metadata")
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, metadataURLParams)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metadataRequests)
+		metadataURLIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(ctx, metadataURLParams.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, string(metadata), metadataURLIdentityProvider.MetadataXML)
+		_, err = saml.GetServiceProvider(metadataURLIdentityProvider, "https://authorizer.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metadataRequests)
+		_, err = resolvers.DeleteSAMLIdentityProviderResolver(ctx, model.SAMLIdentityProviderRequest{
+			ID: metadataURLIdentityProvider.ID,
+		})
+		assert.NoError(t, err)
+
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, params)
+		assert.NoError(t, err)
+		_, err = resolvers.AddSAMLIdentityProviderResolver(ctx, params)
+		assert.Error(t, err)
+		// name is shared with oauth identity providers
+		_, err = resolvers.AddIdentityProviderResolver(ctx, model.AddIdentityProviderRequest{
+			Name:         params.Name,
+			DiscoveryURL: refs.NewStringRef("https://okta.example.com/.well-known/openid-configuration"),
+			ClientID:     "authorizer",
+			ClientSecret: "secret",
+		})
+		assert.Error(t, err)
+
+		samlIdentityProvider, err := db.Provider.GetSAMLIdentityProviderByName(ctx, params.Name)
+		assert.NoError(t, err)
+		samlIdentityProviderRes, err := resolvers.SAMLIdentityProviderResolver(ctx, model.SAMLIdentityProviderRequest{
+			ID: samlIdentityProvider.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, params.Name, samlIdentityProviderRes.Name)
+
+		samlIdentityProviders, err := resolvers.SAMLIdentityProvidersResolver(ctx, &model.PaginatedInput{})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(samlIdentityProviders.SamlIdentityProviders), 1)
+
+		meta, err := resolvers.MetaResolver(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, meta.IdentityProviders, params.Name)
+
+		_, err = resolvers.UpdateSAMLIdentityProviderResolver(ctx, model.UpdateSAMLIdentityProviderRequest{
+			ID:               samlIdentityProvider.ID,
+			AttributeMapping: map[string]interface{}{"email": "mail", "roles": "groups"},
+		})
+		assert.NoError(t, err)
+		samlIdentityProvider, err = db.Provider.GetSAMLIdentityProviderByID(ctx, samlIdentityProvider.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "mail", samlIdentityProvider.GetAttributeMapping()["email"])
+
+		// existing users are matched by email only for allowed email domains
+		assert.False(t, samlIdentityProvider.IsEmailMatchAllowed("user@authorizer.dev"))
+		_, err = resolvers.UpdateSAMLIdentityProviderResolver(ctx, model.UpdateSAMLIdentityProviderRequest{
+			ID:                  samlIdentityProvider.ID,
+			AllowedEmailDomains: []string{" Authorizer.dev "},
+		})
+		assert.NoError(t, err)
+		samlIdentityProvider, err = db.Provider.GetSAMLIdentityProviderByID(ctx, samlIdentityProvider.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"authorizer.dev"}, samlIdentityProvider.GetAllowedEmailDomains())
+		assert.True(t, samlIdentityProvider.IsEmailMatchAllowed("user@AUTHORIZER.dev"))
+		assert.False(t, samlIdentityProvider.IsEmailMatchAllowed("user@sub.authorizer.dev"))
+		assert.False(t, samlIdentityProvider.IsEmailMatchAllowed("user@example.com"))
+
+		serviceProviderMetadata, err := saml.GetServiceProviderMetadata(samlIdentityProvider, "https://authorizer.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://authorizer.example.com/saml_metadata/okta", serviceProviderMetadata.EntityID)
+		assert.Equal(t, "https://authorizer.example.com/saml_acs/okta", serviceProviderMetadata.SPSSODescriptors[0].AssertionConsumerServices[0].Location)
+
+		// only the roles configured for instance are mapped from attributes
+		rolesString, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyRoles)
+		assert.NoError(t, err)
+		role := strings.Split(rolesString, ",")[0]
+		user := saml.GetSAMLIdentityProviderUser(samlIdentityProvider, &gosaml.Assertion{
+			Subject: &gosaml.Subject{
+				NameID: &gosaml.NameID{Value: "saml_idp_user@authorizer.dev"},
+			},
+			AttributeStatements: []gosaml.AttributeStatement{
+				{
+					Attributes: []gosaml.Attribute{
+						{Name: "given_name", Values: []gosaml.AttributeValue{{Value: "SAML"}}},
+						{Name: "groups", Values: []gosaml.AttributeValue{{Value: role}, {Value: "everyone"}}},
+					},
+				},
+			},
+		})
+		assert.Equal(t, "saml_idp_user@authorizer.dev", refs.StringValue(user.Email))
+		assert.Equal(t, "SAML", refs.StringValue(user.GivenName))
+		assert.Equal(t, role, user.Roles)
+
+		_, err = resolvers.DeleteSAMLIdentityProviderResolver(ctx, model.SAMLIdentityProviderRequest{
+			ID: samlIdentityProvider.ID,
+		})
+		assert.NoError(t, err)
+		_, err = db.Provider.GetSAMLIdentityProviderByName(ctx, params.Name)
+		assert.Error(t, err)
+	})
+}
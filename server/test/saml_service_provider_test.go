@@ -0,0 +1,113 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/saml"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func samlServiceProviderTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run("should manage saml service providers", func(t *testing.T) {
+		req, ctx := createContext(s)
+		params := model.AddSAMLServiceProviderRequest{
+			Name:     "salesforce",
+			EntityID: "https://saml.example.com/metadata",
+			AcsURL:   "https://saml.example.com/acs",
+		}
+		_, err := resolvers.AddSAMLServiceProviderResolver(ctx, params)
+		assert.Error(t, err)
+
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		invalidACSURLParams := params
+		invalidACSURLParams.AcsURL = "ftp://saml.example.com/acs"
+		_, err = resolvers.AddSAMLServiceProviderResolver(ctx, invalidACSURLParams)
+		assert.Error(t, err)
+
+		invalidNameIDFormatParams := params
+		invalidNameIDFormatParams.NameIDFormat = refs.NewStringRef("transient")
+		_, err = resolvers.AddSAMLServiceProviderResolver(ctx, invalidNameIDFormatParams)
+		assert.Error(t, err)
+
+		invalidAttributeMappingParams := params
+		invalidAttributeMappingParams.AttributeMapping = map[string]interface{}{"password": "password"}
+		_, err = resolvers.AddSAMLServiceProviderResolver(ctx, invalidAttributeMappingParams)
+		assert.Error(t, err)
+
+		_, err = resolvers.AddSAMLServiceProviderResolver(ctx, params)
+		assert.NoError(t, err)
+		_, err = resolvers.AddSAMLServiceProviderResolver(ctx, params)
+		assert.Error(t, err)
+
+		samlServiceProvider, err := db.Provider.GetSAMLServiceProviderByEntityID(ctx, params.EntityID)
+		assert.NoError(t, err)
+		samlServiceProviderRes, err := resolvers.SAMLServiceProviderResolver(ctx, model.SAMLServiceProviderRequest{
+			ID: samlServiceProvider.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, params.Name, samlServiceProviderRes.Name)
+		assert.Equal(t, constants.SAMLNameIDFormatEmail, samlServiceProviderRes.NameIDFormat)
+
+		samlServiceProviders, err := resolvers.SAMLServiceProvidersResolver(ctx, &model.PaginatedInput{})
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(samlServiceProviders.SamlServiceProviders), 1)
+
+		_, err = resolvers.UpdateSAMLServiceProviderResolver(ctx, model.UpdateSAMLServiceProviderRequest{
+			ID:               samlServiceProvider.ID,
+			NameIDFormat:     refs.NewStringRef(constants.SAMLNameIDFormatPersistent),
+			AttributeMapping: map[string]interface{}{"mail": "email", "groups": "roles"},
+		})
+		assert.NoError(t, err)
+		samlServiceProvider, err = db.Provider.GetSAMLServiceProviderByID(ctx, samlServiceProvider.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.SAMLNameIDFormatPersistent, samlServiceProvider.NameIDFormat)
+
+		// subject & attributes of assertion are based on configuration of service provider
+		user := &models.User{
+			ID:    "saml-user",
+			Email: refs.NewStringRef("saml_user@authorizer.dev"),
+		}
+		session := saml.NewSession(user, samlServiceProvider, &token.SessionData{
+			Subject:   user.ID,
+			Roles:     []string{"user", "admin"},
+			Nonce:     "nonce",
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		})
+		assert.Equal(t, user.ID, session.NameID)
+		assert.Len(t, session.CustomAttributes, 2)
+		assert.Equal(t, "groups", session.CustomAttributes[0].Name)
+		assert.Len(t, session.CustomAttributes[0].Values, 2)
+		assert.Equal(t, "mail", session.CustomAttributes[1].Name)
+		assert.Equal(t, "saml_user@authorizer.dev", session.CustomAttributes[1].Values[0].Value)
+
+		identityProvider, err := saml.GetIdentityProvider("https://authorizer.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://authorizer.example.com/saml/metadata", identityProvider.Metadata().EntityID)
+
+		_, err = resolvers.DeleteSAMLServiceProviderResolver(ctx, model.SAMLServiceProviderRequest{
+			ID: samlServiceProvider.ID,
+		})
+		assert.NoError(t, err)
+		_, err = db.Provider.GetSAMLServiceProviderByEntityID(ctx, params.EntityID)
+		assert.Error(t, err)
+	})
+}
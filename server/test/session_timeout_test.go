@@ -0,0 +1,94 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func sessionTimeoutTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should expire session on inactivity and after max age`, func(t *testing.T) {
+		_, ctx := createContext(s)
+		email := "session_timeout." + s.TestInfo.Email
+
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionExpiryTime, "2h")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeyRefreshTokenExpiryTime, "3h")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionIdleTimeout, "3s")
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionMaxAge, "1h")
+		defer func() {
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionExpiryTime, "8760h")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeyRefreshTokenExpiryTime, "8760h")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionIdleTimeout, "")
+			memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionMaxAge, "")
+		}()
+
+		resolvers.SignupResolver(ctx, model.SignUpInput{
+			Email:           refs.NewStringRef(email),
+			Password:        s.TestInfo.Password,
+			ConfirmPassword: s.TestInfo.Password,
+		})
+		verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+		assert.NoError(t, err)
+		_, err = resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+			Token: verificationRequest.Token,
+		})
+		assert.NoError(t, err)
+
+		loginRes, err := resolvers.LoginResolver(ctx, model.LoginInput{
+			Email:    refs.NewStringRef(email),
+			Password: s.TestInfo.Password,
+			Scope:    []string{"openid", "email", "profile", "offline_access"},
+		})
+		assert.NoError(t, err)
+		claims, err := token.ParseJWTToken(refs.StringValue(loginRes.AccessToken))
+		assert.NoError(t, err)
+		sessionKey := constants.AuthRecipeMethodBasicAuth + ":" + loginRes.User.ID
+		sessionToken, err := memorystore.Provider.GetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+claims["nonce"].(string))
+		assert.NoError(t, err)
+
+		// lifetimes are limited by max age
+		sessionData, err := token.ValidateBrowserSession(s.GinContext, sessionToken)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, sessionData.ExpiresAt, sessionData.IssuedAt+int64(time.Hour.Seconds()))
+		refreshTokenClaims, err := token.ValidateRefreshToken(s.GinContext, refs.StringValue(loginRes.RefreshToken))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, refreshTokenClaims["exp"].(int64), sessionData.IssuedAt+int64(time.Hour.Seconds()))
+
+		// session is extended on use
+		time.Sleep(2 * time.Second)
+		_, err = token.ValidateBrowserSession(s.GinContext, sessionToken)
+		assert.NoError(t, err)
+		time.Sleep(2 * time.Second)
+		_, err = token.ValidateBrowserSession(s.GinContext, sessionToken)
+		assert.NoError(t, err)
+
+		// session expires on inactivity
+		time.Sleep(4 * time.Second)
+		_, err = token.ValidateBrowserSession(s.GinContext, sessionToken)
+		assert.Error(t, err)
+
+		// session authenticated before max age cannot be rolled over
+		memorystore.Provider.UpdateEnvVariable(constants.EnvKeySessionIdleTimeout, "")
+		user, err := db.Provider.GetUserByEmail(ctx, email)
+		assert.NoError(t, err)
+		authTime := time.Now().Add(-2 * time.Hour).Unix()
+		sessionData, sessionToken, sessionExpiresAt, err := token.CreateSessionToken(user, "session_timeout_nonce", sessionData.Roles, sessionData.Scope, constants.AuthRecipeMethodBasicAuth, authTime, sessionData.SessionID)
+		assert.NoError(t, err)
+		assert.Equal(t, authTime+int64(time.Hour.Seconds()), sessionData.ExpiresAt)
+		memorystore.Provider.SetUserSession(sessionKey, constants.TokenTypeSessionToken+"_"+sessionData.Nonce, sessionToken, sessionExpiresAt)
+		_, err = token.ValidateBrowserSession(s.GinContext, sessionToken)
+		assert.Error(t, err)
+
+		cleanData(email)
+	})
+}
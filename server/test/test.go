@@ -1,11 +1,16 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"testing"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -98,6 +103,73 @@ func createContext(s TestSetup) (*http.Request, context.Context) {
 	return req, ctx
 }
 
+// postForm sends form request to given path of test server and returns the status code with json response.
+// header is optional and used for client authentication & DPoP proof
+func postForm(t *testing.T, s TestSetup, path string, data url.Values, header http.Header) (int, map[string]interface{}) {
+	req, err := http.NewRequest(http.MethodPost, s.Server.URL+path, strings.NewReader(data.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body := map[string]interface{}{}
+	json.NewDecoder(res.Body).Decode(&body)
+	return res.StatusCode, body
+}
+
+// sendJSON sends request with json body to given path of test server and returns the status code with json response.
+// bearerToken is optional and sent as authorization header
+func sendJSON(t *testing.T, s TestSetup, method, path string, data interface{}, bearerToken string) (int, map[string]interface{}) {
+	reqBody, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(method, s.Server.URL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body := map[string]interface{}{}
+	json.NewDecoder(res.Body).Decode(&body)
+	return res.StatusCode, body
+}
+
+// getRequest sends get request to given path of test server without following redirects
+func getRequest(t *testing.T, s TestSetup, path string, header http.Header) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, s.Server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
 func testSetup() TestSetup {
 	testData := TestData{
 		Email:                       fmt.Sprintf("%d_authorizer_tester@yopmail.com", time.Now().Unix()),
@@ -146,7 +218,23 @@ func testSetup() TestSetup {
 	r.Use(middlewares.GinContextToContextMiddleware())
 	r.Use(middlewares.CORSMiddleware())
 
+	r.LoadHTMLGlob("../../templates/*")
 	r.POST("/graphql", handlers.GraphqlHandler())
+	r.GET("/authorize", handlers.AuthorizeHandler())
+	r.GET("/.well-known/jwks.json", handlers.JWKsHandler())
+	r.GET("/.well-known/openid-configuration", handlers.OpenIDConfigurationHandler())
+	r.POST("/oauth/register", handlers.ClientRegistrationHandler())
+	r.GET("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	r.PUT("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	r.DELETE("/oauth/register/:client_id", handlers.ClientConfigurationHandler())
+	r.POST("/oauth/consent", handlers.ConsentHandler())
+	r.POST("/oauth/token", handlers.TokenHandler())
+	r.POST("/oauth/introspect", handlers.IntrospectHandler())
+	r.POST("/oauth/revoke", handlers.RevokeTokenHandler())
+	r.POST("/oauth/device/code", handlers.DeviceCodeHandler())
+	r.POST("/oauth/par", handlers.PushedAuthorizationRequestHandler())
+	r.GET("/oauth/logout", handlers.EndSessionHandler())
+	r.POST("/oauth/logout", handlers.EndSessionHandler())
 
 	server := httptest.NewServer(r)
 
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/graph/model"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/refs"
+	"github.com/authorizerdev/authorizer/server/resolvers"
+	"github.com/authorizerdev/authorizer/server/token"
+)
+
+func tokenExchangeTest(t *testing.T, s TestSetup) {
+	t.Helper()
+	t.Run(`should exchange subject token with token exchange grant`, func(t *testing.T) {
+		req, ctx := createContext(s)
+		adminSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAdminSecret)
+		assert.NoError(t, err)
+		h, err := crypto.EncryptPassword(adminSecret)
+		assert.NoError(t, err)
+		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+
+		audience := "https://api.token-exchange.example.com"
+		client, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:                   "token exchange client",
+			GrantTypes:             []string{constants.GrantTypeTokenExchange},
+			TokenExchangeAudiences: []string{audience},
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: client.Client.ID})
+		pairwiseClient, err := resolvers.AddClientResolver(ctx, model.AddClientRequest{
+			Name:         "pairwise token exchange client",
+			RedirectUris: []string{"https://pairwise.token-exchange.example.com/callback"},
+			GrantTypes:   []string{constants.GrantTypeTokenExchange},
+			SubjectType:  refs.NewStringRef(constants.SubjectTypePairwise),
+		})
+		assert.NoError(t, err)
+		defer resolvers.DeleteClientResolver(ctx, model.ClientRequest{ID: pairwiseClient.Client.ID})
+		req.Header.Del("Cookie")
+
+		signup := func(email string) (string, string) {
+			_, err := resolvers.SignupResolver(ctx, model.SignUpInput{
+				Email:           refs.NewStringRef(email),
+				Password:        s.TestInfo.Password,
+				ConfirmPassword: s.TestInfo.Password,
+			})
+			assert.NoError(t, err)
+			verificationRequest, err := db.Provider.GetVerificationRequestByEmail(ctx, email, constants.VerificationTypeBasicAuthSignup)
+			assert.NoError(t, err)
+			verifyRes, err := resolvers.VerifyEmailResolver(ctx, model.VerifyEmailInput{
+				Token: verificationRequest.Token,
+			})
+			assert.NoError(t, err)
+			return verifyRes.User.ID, refs.StringValue(verifyRes.AccessToken)
+		}
+		email := "token_exchange." + s.TestInfo.Email
+		actorEmail := "actor.token_exchange." + s.TestInfo.Email
+		userID, subjectToken := signup(email)
+		defer cleanData(email)
+		actorUserID, actorToken := signup(actorEmail)
+		defer cleanData(actorEmail)
+		subjectClaims, err := token.ParseJWTToken(subjectToken)
+		assert.NoError(t, err)
+		subjectScope := []string{}
+		for _, scope := range subjectClaims["scope"].([]interface{}) {
+			subjectScope = append(subjectScope, scope.(string))
+		}
+		assert.NotContains(t, subjectScope, "phone")
+
+		exchange := func(params url.Values) (int, map[string]interface{}) {
+			data := url.Values{
+				"grant_type":         {constants.GrantTypeTokenExchange},
+				"client_id":          {client.Client.ClientID},
+				"client_secret":      {client.ClientSecret},
+				"subject_token":      {subjectToken},
+				"subject_token_type": {constants.TokenTypeIdentifierAccessToken},
+				"audience":           {audience},
+				"scope":              {"openid"},
+			}
+			for key, values := range params {
+				data[key] = values
+			}
+			return postForm(t, s, "/oauth/token", data, nil)
+		}
+
+		// client should authenticate
+		status, body := exchange(url.Values{"client_secret": {""}})
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+		status, body = exchange(url.Values{"client_secret": {"invalid-secret"}})
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_client", body["error"])
+
+		// scope wider than subject token is rejected
+		status, body = exchange(url.Values{"scope": {"openid phone"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_scope", body["error"])
+
+		// audience outside token exchange audiences of client is rejected
+		status, body = exchange(url.Values{"audience": {"https://other.token-exchange.example.com"}})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_target", body["error"])
+
+		status, body = exchange(nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, constants.TokenTypeIdentifierAccessToken, body["issued_token_type"])
+		assert.Equal(t, "openid", body["scope"])
+		claims, err := token.ParseJWTToken(body["access_token"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, audience, claims["aud"])
+		assert.Equal(t, client.Client.ClientID, claims["client_id"])
+		assert.Equal(t, userID, claims["sub"])
+		assert.Nil(t, claims["act"])
+
+		// actor token is set as act claim, act claim of subject token is nested within it
+		status, body = exchange(url.Values{
+			"audience":         {client.Client.ClientID},
+			"actor_token":      {actorToken},
+			"actor_token_type": {constants.TokenTypeIdentifierAccessToken},
+		})
+		assert.Equal(t, http.StatusOK, status)
+		delegatedToken, _ := body["access_token"].(string)
+		claims, err = token.ParseJWTToken(delegatedToken)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims["sub"])
+		assert.Equal(t, map[string]interface{}{"sub": actorUserID}, claims["act"])
+		status, body = exchange(url.Values{
+			"subject_token":    {delegatedToken},
+			"actor_token":      {actorToken},
+			"actor_token_type": {constants.TokenTypeIdentifierAccessToken},
+		})
+		assert.Equal(t, http.StatusOK, status)
+		claims, err = token.ParseJWTToken(body["access_token"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"sub": actorUserID,
+			"act": map[string]interface{}{"sub": actorUserID},
+		}, claims["act"])
+
+		// subject token with pairwise subject is resolved to the user
+		status, body = postForm(t, s, "/oauth/token", url.Values{
+			"grant_type":         {constants.GrantTypeTokenExchange},
+			"client_id":          {pairwiseClient.Client.ClientID},
+			"client_secret":      {pairwiseClient.ClientSecret},
+			"subject_token":      {subjectToken},
+			"subject_token_type": {constants.TokenTypeIdentifierAccessToken},
+		}, nil)
+		assert.Equal(t, http.StatusOK, status)
+		pairwiseToken, _ := body["access_token"].(string)
+		claims, err = token.ParseJWTToken(pairwiseToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, userID, claims["sub"])
+		status, body = exchange(url.Values{"subject_token": {pairwiseToken}})
+		assert.Equal(t, http.StatusOK, status)
+		claims, err = token.ParseJWTToken(body["access_token"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims["sub"])
+	})
+}
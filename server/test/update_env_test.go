@@ -30,6 +30,12 @@ func updateEnvTests(t *testing.T, s TestSetup) {
 		h, err := crypto.EncryptPassword(adminSecret)
 		assert.Nil(t, err)
 		req.Header.Set("Cookie", fmt.Sprintf("%s=%s", constants.AdminCookieName, h))
+		invalidDuration := "invalid"
+		_, err = resolvers.UpdateEnvResolver(ctx, model.UpdateEnvInput{
+			SessionIdleTimeout: &invalidDuration,
+		})
+		assert.Error(t, err)
+
 		newURL := "https://test.com"
 		disableLoginPage := true
 		allowedOrigins := []string{"http://localhost:8080"}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/robertkrimen/otto"
 
 	"github.com/authorizerdev/authorizer/server/constants"
@@ -32,6 +33,8 @@ type JWTToken struct {
 // Token object to hold the finger print and refresh token information
 type Token struct {
 	FingerPrint string `json:"fingerprint"`
+	// SessionID is the id of session (sid claim) to which tokens belong
+	SessionID string `json:"sid"`
 	// Session Token
 	FingerPrintHash       string    `json:"fingerprint_hash"`
 	SessionTokenExpiresAt int64     `json:"expires_at"`
@@ -41,24 +44,53 @@ type Token struct {
 }
 
 // SessionData
+// IssuedAt is the time when user was authenticated,
+// it is preserved when session is rolled over and used as auth_time claim of id token.
+// SessionID is preserved as well, while nonce changes each time session is rolled over
 type SessionData struct {
 	Subject     string   `json:"sub"`
 	Roles       []string `json:"roles"`
 	Scope       []string `json:"scope"`
 	Nonce       string   `json:"nonce"`
+	SessionID   string   `json:"sid"`
 	IssuedAt    int64    `json:"iat"`
 	ExpiresAt   int64    `json:"exp"`
 	LoginMethod string   `json:"login_method"`
 }
 
+// GetSessionID returns the id of session,
+// sessions created before session id was introduced are identified by nonce
+func (s *SessionData) GetSessionID() string {
+	if s.SessionID == "" {
+		return s.Nonce
+	}
+	return s.SessionID
+}
+
 // CreateAuthToken creates a new auth token when userlogs in
 func CreateAuthToken(gc *gin.Context, user *models.User, roles, scope []string, loginMethod, nonce string, code string) (*Token, error) {
+	return CreateAuthTokenForClient(gc, nil, user, roles, scope, loginMethod, nonce, code, 0, "", "", nil)
+}
+
+// CreateAuthTokenForClient creates a new auth token for given oauth client.
+// If client is nil, token is issued for the default client configured via env.
+// authTime is the time when user was authenticated, 0 means user is authenticated now.
+// sessionID is the id of session which is rolled over, empty means new session is created.
+// dpopJKT is the thumbprint of DPoP key to which access & refresh tokens are bound, empty for bearer tokens.
+// claimsRequest is the claims request parameter of authorization request, nil if claims were not requested
+func CreateAuthTokenForClient(gc *gin.Context, client *models.Client, user *models.User, roles, scope []string, loginMethod, nonce string, code string, authTime int64, sessionID, dpopJKT string, claimsRequest *ClaimsRequest) (*Token, error) {
 	hostname := parsers.GetHost(gc)
-	_, fingerPrintHash, sessionTokenExpiresAt, err := CreateSessionToken(user, nonce, roles, scope, loginMethod)
+	if authTime == 0 {
+		authTime = time.Now().Unix()
+	}
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	sessionData, fingerPrintHash, sessionTokenExpiresAt, err := CreateSessionToken(user, nonce, roles, scope, loginMethod, authTime, sessionID)
 	if err != nil {
 		return nil, err
 	}
-	accessToken, accessTokenExpiresAt, err := CreateAccessToken(user, roles, scope, hostname, nonce, loginMethod)
+	accessToken, accessTokenExpiresAt, err := CreateAccessToken(client, user, roles, scope, hostname, nonce, loginMethod, sessionID, dpopJKT, claimsRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -79,20 +111,21 @@ func CreateAuthToken(gc *gin.Context, user *models.User, roles, scope []string,
 		codeHashString = base64.RawURLEncoding.EncodeToString(codeHashDigest)
 	}
 
-	idToken, idTokenExpiresAt, err := CreateIDToken(user, roles, hostname, nonce, atHashString, codeHashString, loginMethod)
+	idToken, idTokenExpiresAt, err := CreateIDToken(client, user, roles, scope, hostname, nonce, atHashString, codeHashString, loginMethod, authTime, sessionID, claimsRequest)
 	if err != nil {
 		return nil, err
 	}
 
 	res := &Token{
 		FingerPrint:           nonce,
+		SessionID:             sessionID,
 		FingerPrintHash:       fingerPrintHash,
 		SessionTokenExpiresAt: sessionTokenExpiresAt,
 		AccessToken:           &JWTToken{Token: accessToken, ExpiresAt: accessTokenExpiresAt},
 		IDToken:               &JWTToken{Token: idToken, ExpiresAt: idTokenExpiresAt},
 	}
 	if utils.StringSliceContains(scope, "offline_access") {
-		refreshToken, refreshTokenExpiresAt, err := CreateRefreshToken(user, roles, scope, hostname, nonce, loginMethod)
+		refreshToken, refreshTokenExpiresAt, err := CreateRefreshToken(client, user, roles, scope, hostname, nonce, loginMethod, authTime, sessionID, dpopJKT, claimsRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -100,19 +133,46 @@ func CreateAuthToken(gc *gin.Context, user *models.User, roles, scope []string,
 		res.RefreshToken = &JWTToken{Token: refreshToken, ExpiresAt: refreshTokenExpiresAt}
 	}
 
+	// client is notified on logout as long as session or its refresh token is valid
+	clientID, err := getClientID(client)
+	if err != nil {
+		return nil, err
+	}
+	sessionExpiresAt := sessionData.ExpiresAt
+	if res.RefreshToken != nil && res.RefreshToken.ExpiresAt > sessionExpiresAt {
+		sessionExpiresAt = res.RefreshToken.ExpiresAt
+	}
+	if err := AddSessionClient(sessionID, clientID, sessionExpiresAt); err != nil {
+		log.Debug("Failed to add client to session: ", err)
+	}
+
 	return res, nil
 }
 
 // CreateSessionToken creates a new session token
-func CreateSessionToken(user *models.User, nonce string, roles, scope []string, loginMethod string) (*SessionData, string, int64, error) {
-	expiresAt := time.Now().AddDate(1, 0, 0).Unix()
+// authTime is the time when user was authenticated, 0 means user is authenticated now.
+// sessionID is the id of session which is rolled over, empty means new session is created.
+// Returned expiry is the expiry of session store entry, which is limited by SESSION_IDLE_TIMEOUT
+func CreateSessionToken(user *models.User, nonce string, roles, scope []string, loginMethod string, authTime int64, sessionID string) (*SessionData, string, int64, error) {
+	if authTime == 0 {
+		authTime = time.Now().Unix()
+	}
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	expiresAt := limitToSessionMaxAge(time.Now().Add(utils.GetDurationStoreEnvVariable(constants.EnvKeySessionExpiryTime, time.Hour*8760)).Unix(), authTime)
+	// nonce is recorded, so that session can be terminated with its id
+	if err := AddSessionNonce(sessionID, nonce, expiresAt); err != nil {
+		log.Debug("Failed to add nonce to session: ", err)
+	}
 	fingerPrintMap := &SessionData{
 		Nonce:       nonce,
+		SessionID:   sessionID,
 		Roles:       roles,
 		Subject:     user.ID,
 		Scope:       scope,
 		LoginMethod: loginMethod,
-		IssuedAt:    time.Now().Unix(),
+		IssuedAt:    authTime,
 		ExpiresAt:   expiresAt,
 	}
 	fingerPrintBytes, _ := json.Marshal(fingerPrintMap)
@@ -121,31 +181,114 @@ func CreateSessionToken(user *models.User, nonce string, roles, scope []string,
 		return nil, "", 0, err
 	}
 
-	return fingerPrintMap, fingerPrintHash, expiresAt, nil
+	return fingerPrintMap, fingerPrintHash, getSessionStoreExpiresAt(expiresAt), nil
+}
+
+// getSessionStoreExpiresAt returns the expiry of session store entry for session expiring at expiresAt,
+// with SESSION_IDLE_TIMEOUT store entry expires on inactivity and it is extended each time session is used
+func getSessionStoreExpiresAt(expiresAt int64) int64 {
+	idleTimeout := utils.GetDurationStoreEnvVariable(constants.EnvKeySessionIdleTimeout, 0)
+	if idleTimeout == 0 {
+		return expiresAt
+	}
+	idleExpiresAt := time.Now().Add(idleTimeout).Unix()
+	if idleExpiresAt < expiresAt {
+		return idleExpiresAt
+	}
+	return expiresAt
+}
+
+// limitToSessionMaxAge limits expiresAt to SESSION_MAX_AGE from authTime,
+// so that session cannot be extended beyond it by rolling over session or rotating refresh token
+func limitToSessionMaxAge(expiresAt, authTime int64) int64 {
+	maxAge := utils.GetDurationStoreEnvVariable(constants.EnvKeySessionMaxAge, 0)
+	if maxAge == 0 || authTime == 0 {
+		return expiresAt
+	}
+	maxExpiresAt := time.Unix(authTime, 0).Add(maxAge).Unix()
+	if maxExpiresAt < expiresAt {
+		return maxExpiresAt
+	}
+	return expiresAt
+}
+
+// getClientID returns the client id for which token is issued
+func getClientID(client *models.Client) (string, error) {
+	if client != nil {
+		return client.ClientID, nil
+	}
+	return memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+}
+
+// getAccessTokenExpiryBound returns access token lifetime configured for client
+// and falls back to ACCESS_TOKEN_EXPIRY_TIME
+func getAccessTokenExpiryBound(client *models.Client) (time.Duration, error) {
+	if client != nil && client.AccessTokenExpiryTime != "" {
+		if expiryBound, err := utils.ParseDurationInSeconds(client.AccessTokenExpiryTime); err == nil {
+			return expiryBound, nil
+		}
+	}
+	expireTime, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAccessTokenExpiryTime)
+	if err != nil {
+		return 0, err
+	}
+	expiryBound, err := utils.ParseDurationInSeconds(expireTime)
+	if err != nil {
+		expiryBound = time.Minute * 30
+	}
+	return expiryBound, nil
+}
+
+// getRefreshTokenExpiryBound returns refresh token lifetime configured for client
+// and falls back to REFRESH_TOKEN_EXPIRY_TIME
+func getRefreshTokenExpiryBound(client *models.Client) time.Duration {
+	if client != nil && client.RefreshTokenExpiryTime != "" {
+		if expiryBound, err := utils.ParseDurationInSeconds(client.RefreshTokenExpiryTime); err == nil {
+			return expiryBound
+		}
+	}
+	// defaults to 1 year
+	return utils.GetDurationStoreEnvVariable(constants.EnvKeyRefreshTokenExpiryTime, time.Hour*8760)
 }
 
 // CreateRefreshToken util to create JWT token
-func CreateRefreshToken(user *models.User, roles, scopes []string, hostname, nonce, loginMethod string) (string, int64, error) {
-	// expires in 1 year
-	expiryBound := time.Hour * 8760
+// auth_time & requested claims are part of refresh token, so that they are preserved in tokens issued on refresh.
+// Refresh token does not outlive SESSION_MAX_AGE from auth_time
+func CreateRefreshToken(client *models.Client, user *models.User, roles, scopes []string, hostname, nonce, loginMethod string, authTime int64, sessionID, dpopJKT string, claimsRequest *ClaimsRequest) (string, int64, error) {
+	expiryBound := getRefreshTokenExpiryBound(client)
 	expiresAt := time.Now().Add(expiryBound).Unix()
-	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+	if authTime != 0 {
+		expiresAt = limitToSessionMaxAge(expiresAt, authTime)
+	}
+	clientID, err := getClientID(client)
+	if err != nil {
+		return "", 0, err
+	}
+	subject, err := GetSubject(client, user.ID)
 	if err != nil {
 		return "", 0, err
 	}
 	customClaims := jwt.MapClaims{
 		"iss":           hostname,
 		"aud":           clientID,
-		"sub":           user.ID,
+		"sub":           subject,
 		"exp":           expiresAt,
 		"iat":           time.Now().Unix(),
+		"auth_time":     authTime,
 		"token_type":    constants.TokenTypeRefreshToken,
 		"roles":         roles,
 		"scope":         scopes,
 		"nonce":         nonce,
+		"sid":           sessionID,
 		"login_method":  loginMethod,
 		"allowed_roles": strings.Split(user.Roles, ","),
 	}
+	if dpopJKT != "" {
+		customClaims["cnf"] = map[string]string{"jkt": dpopJKT}
+	}
+	if claimsRequest != nil {
+		customClaims[RequestedClaimsKey] = claimsRequest
+	}
 
 	token, err := SignJWTToken(customClaims)
 	if err != nil {
@@ -156,18 +299,19 @@ func CreateRefreshToken(user *models.User, roles, scopes []string, hostname, non
 }
 
 // CreateAccessToken util to create JWT token, based on
-// user information, roles config and CUSTOM_ACCESS_TOKEN_SCRIPT
-func CreateAccessToken(user *models.User, roles, scopes []string, hostName, nonce, loginMethod string) (string, int64, error) {
-	expireTime, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAccessTokenExpiryTime)
+// user information, roles config and CUSTOM_ACCESS_TOKEN_SCRIPT.
+// Requested claims are part of access token, so that they are released from userinfo
+func CreateAccessToken(client *models.Client, user *models.User, roles, scopes []string, hostName, nonce, loginMethod, sessionID, dpopJKT string, claimsRequest *ClaimsRequest) (string, int64, error) {
+	expiryBound, err := getAccessTokenExpiryBound(client)
 	if err != nil {
 		return "", 0, err
 	}
-	expiryBound, err := utils.ParseDurationInSeconds(expireTime)
+	expiresAt := time.Now().Add(expiryBound).Unix()
+	clientID, err := getClientID(client)
 	if err != nil {
-		expiryBound = time.Minute * 30
+		return "", 0, err
 	}
-	expiresAt := time.Now().Add(expiryBound).Unix()
-	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+	subject, err := GetSubject(client, user.ID)
 	if err != nil {
 		return "", 0, err
 	}
@@ -175,7 +319,8 @@ func CreateAccessToken(user *models.User, roles, scopes []string, hostName, nonc
 		"iss":           hostName,
 		"aud":           clientID,
 		"nonce":         nonce,
-		"sub":           user.ID,
+		"sid":           sessionID,
+		"sub":           subject,
 		"exp":           expiresAt,
 		"iat":           time.Now().Unix(),
 		"token_type":    constants.TokenTypeAccessToken,
@@ -184,6 +329,12 @@ func CreateAccessToken(user *models.User, roles, scopes []string, hostName, nonc
 		"login_method":  loginMethod,
 		"allowed_roles": strings.Split(user.Roles, ","),
 	}
+	if dpopJKT != "" {
+		customClaims["cnf"] = map[string]string{"jkt": dpopJKT}
+	}
+	if claimsRequest != nil {
+		customClaims[RequestedClaimsKey] = claimsRequest
+	}
 	// check for the extra access token script
 	accessTokenScript, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyCustomAccessTokenScript)
 	if err != nil {
@@ -227,7 +378,37 @@ func CreateAccessToken(user *models.User, roles, scopes []string, hostName, nonc
 	return token, expiresAt, nil
 }
 
-// GetAccessToken returns the access token from the request (either from header or cookie)
+// CreateClientAccessToken util to create JWT token for client_credentials grant
+// token is issued to the client itself, hence it does not contain user specific claims like sub
+func CreateClientAccessToken(client *models.Client, scopes []string, hostName, nonce, dpopJKT string) (string, int64, error) {
+	expiryBound, err := getAccessTokenExpiryBound(client)
+	if err != nil {
+		return "", 0, err
+	}
+	expiresAt := time.Now().Add(expiryBound).Unix()
+	customClaims := jwt.MapClaims{
+		"iss":        hostName,
+		"aud":        client.ClientID,
+		"client_id":  client.ClientID,
+		"nonce":      nonce,
+		"exp":        expiresAt,
+		"iat":        time.Now().Unix(),
+		"token_type": constants.TokenTypeAccessToken,
+		"scope":      scopes,
+	}
+	if dpopJKT != "" {
+		customClaims["cnf"] = map[string]string{"jkt": dpopJKT}
+	}
+	token, err := SignJWTToken(customClaims)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// GetAccessToken returns the access token from the request (either from header or cookie).
+// Token can be sent with Bearer or DPoP scheme
 func GetAccessToken(gc *gin.Context) (string, error) {
 	// try to check in auth header for cookie
 	auth := gc.Request.Header.Get("Authorization")
@@ -240,16 +421,32 @@ func GetAccessToken(gc *gin.Context) (string, error) {
 		return "", fmt.Errorf(`unauthorized`)
 	}
 
-	if strings.ToLower(authSplit[0]) != "bearer" {
+	scheme := strings.ToLower(authSplit[0])
+	if scheme != "bearer" && scheme != "dpop" {
 		return "", fmt.Errorf(`not a bearer token`)
 	}
 
-	token := strings.TrimPrefix(auth, "Bearer ")
-	return token, nil
+	return authSplit[1], nil
 }
 
 // Function to validate access token for authorizer apis (profile, update_profile)
+// DPoP bound access token should be sent with DPoP proof of the key to which it is bound
 func ValidateAccessToken(gc *gin.Context, accessToken string) (map[string]interface{}, error) {
+	res, err := ValidateAccessTokenWithoutDPoPProof(gc, accessToken)
+	if err != nil {
+		return res, err
+	}
+	if jkt := GetDPoPJKT(res); jkt != "" {
+		if err := validateDPoPBoundAccessToken(gc, accessToken, jkt); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// ValidateAccessTokenWithoutDPoPProof validates access token without checking DPoP proof,
+// it is used when token is not presented for accessing resource e.g. token introspection & exchange
+func ValidateAccessTokenWithoutDPoPProof(gc *gin.Context, accessToken string) (map[string]interface{}, error) {
 	res := make(map[string]interface{})
 
 	if accessToken == "" {
@@ -261,7 +458,12 @@ func ValidateAccessToken(gc *gin.Context, accessToken string) (map[string]interf
 		return res, err
 	}
 
-	userID := res["sub"].(string)
+	// tokens issued via client_credentials grant are not bound to any user
+	subject, ok := res["sub"].(string)
+	if !ok || subject == "" {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+	userID := GetUserIDFromSubject(subject)
 	nonce := res["nonce"].(string)
 	loginMethod := res["login_method"]
 	sessionKey := userID
@@ -279,7 +481,7 @@ func ValidateAccessToken(gc *gin.Context, accessToken string) (map[string]interf
 	}
 
 	hostname := parsers.GetHost(gc)
-	if ok, err := ValidateJWTClaims(res, hostname, nonce, userID); !ok || err != nil {
+	if ok, err := ValidateJWTClaims(gc, res, hostname, nonce, subject); !ok || err != nil {
 		return res, err
 	}
 
@@ -287,6 +489,8 @@ func ValidateAccessToken(gc *gin.Context, accessToken string) (map[string]interf
 		return res, fmt.Errorf(`unauthorized: invalid token type`)
 	}
 
+	// pairwise subject is resolved, so that callers can lookup user
+	res["sub"] = userID
 	return res, nil
 }
 
@@ -303,8 +507,12 @@ func ValidateRefreshToken(gc *gin.Context, refreshToken string) (map[string]inte
 		return res, err
 	}
 
-	userID := res["sub"].(string)
-	nonce := res["nonce"].(string)
+	subject, ok := res["sub"].(string)
+	if !ok || subject == "" {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+	userID := GetUserIDFromSubject(subject)
+	nonce, _ := res["nonce"].(string)
 	loginMethod := res["login_method"]
 	sessionKey := userID
 	if loginMethod != nil && loginMethod != "" {
@@ -320,7 +528,7 @@ func ValidateRefreshToken(gc *gin.Context, refreshToken string) (map[string]inte
 	}
 
 	hostname := parsers.GetHost(gc)
-	if ok, err := ValidateJWTClaims(res, hostname, nonce, userID); !ok || err != nil {
+	if ok, err := ValidateJWTClaims(gc, res, hostname, nonce, subject); !ok || err != nil {
 		return res, err
 	}
 
@@ -328,6 +536,55 @@ func ValidateRefreshToken(gc *gin.Context, refreshToken string) (map[string]inte
 		return res, fmt.Errorf(`unauthorized: invalid token type`)
 	}
 
+	// SESSION_MAX_AGE might be reduced after refresh token is issued
+	if authTime, ok := res["auth_time"].(float64); ok && limitToSessionMaxAge(res["exp"].(int64), int64(authTime)) < time.Now().Unix() {
+		return res, fmt.Errorf(`unauthorized: session expired`)
+	}
+
+	// pairwise subject is resolved, so that callers can lookup user
+	res["sub"] = userID
+	return res, nil
+}
+
+// ValidateClientAccessToken validates access token issued via client_credentials grant
+func ValidateClientAccessToken(gc *gin.Context, accessToken string) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+
+	if accessToken == "" {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+
+	res, err := ParseJWTToken(accessToken)
+	if err != nil {
+		return res, err
+	}
+
+	clientID, ok := res["client_id"].(string)
+	if !ok || clientID == "" {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+	nonce, _ := res["nonce"].(string)
+	token, err := memorystore.Provider.GetUserSession(constants.GrantTypeClientCredentials+":"+clientID, constants.TokenTypeAccessToken+"_"+nonce)
+	if nonce == "" || err != nil {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+
+	if token != accessToken {
+		return res, fmt.Errorf(`unauthorized`)
+	}
+
+	if res["aud"] != clientID {
+		return res, fmt.Errorf(`invalid audience`)
+	}
+
+	if res["iss"] != parsers.GetHost(gc) {
+		return res, fmt.Errorf(`invalid issuer`)
+	}
+
+	if res["token_type"] != constants.TokenTypeAccessToken {
+		return res, fmt.Errorf(`unauthorized: invalid token type`)
+	}
+
 	return res, nil
 }
 
@@ -361,10 +618,19 @@ func ValidateBrowserSession(gc *gin.Context, encryptedSession string) (*SessionD
 		return nil, fmt.Errorf(`unauthorized: invalid nonce`)
 	}
 
-	if res.ExpiresAt < time.Now().Unix() {
+	// SESSION_MAX_AGE might be reduced after session is created
+	expiresAt := limitToSessionMaxAge(res.ExpiresAt, res.IssuedAt)
+	if expiresAt < time.Now().Unix() {
 		return nil, fmt.Errorf(`unauthorized: token expired`)
 	}
 
+	// sliding session is extended on use
+	if utils.GetDurationStoreEnvVariable(constants.EnvKeySessionIdleTimeout, 0) > 0 {
+		if err := memorystore.Provider.SetUserSession(sessionStoreKey, constants.TokenTypeSessionToken+"_"+res.Nonce, token, getSessionStoreExpiresAt(expiresAt)); err != nil {
+			log.Debug("Failed to extend browser session: ", err)
+		}
+	}
+
 	return &res, nil
 }
 
@@ -372,39 +638,42 @@ func ValidateBrowserSession(gc *gin.Context, encryptedSession string) (*SessionD
 // user information, roles config and CUSTOM_ACCESS_TOKEN_SCRIPT
 // For response_type (code) / authorization_code grant nonce should be empty
 // for implicit flow it should be present to verify with actual state
-func CreateIDToken(user *models.User, roles []string, hostname, nonce, atHash, cHash, loginMethod string) (string, int64, error) {
-	expireTime, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyAccessTokenExpiryTime)
+// authTime is the time when user was authenticated, sessionID is used as sid claim.
+// User claims are released as per scopes & claims requested for id token
+func CreateIDToken(client *models.Client, user *models.User, roles, scopes []string, hostname, nonce, atHash, cHash, loginMethod string, authTime int64, sessionID string, claimsRequest *ClaimsRequest) (string, int64, error) {
+	expiryBound, err := getAccessTokenExpiryBound(client)
 	if err != nil {
 		return "", 0, err
 	}
-	expiryBound, err := utils.ParseDurationInSeconds(expireTime)
-	if err != nil {
-		expiryBound = time.Minute * 30
-	}
 	expiresAt := time.Now().Add(expiryBound).Unix()
 	resUser := user.AsAPIUser()
 	userBytes, _ := json.Marshal(&resUser)
-	var userMap map[string]interface{}
-	json.Unmarshal(userBytes, &userMap)
 	claimKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtRoleClaim)
 	if err != nil {
 		claimKey = "roles"
 	}
 
-	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+	clientID, err := getClientID(client)
+	if err != nil {
+		return "", 0, err
+	}
+	subject, err := GetSubject(client, user.ID)
 	if err != nil {
 		return "", 0, err
 	}
 
+	// sid is the id of session, used by front-channel & back-channel logout
 	customClaims := jwt.MapClaims{
 		"iss":           hostname,
 		"aud":           clientID,
-		"sub":           user.ID,
+		"sub":           subject,
 		"exp":           expiresAt,
 		"iat":           time.Now().Unix(),
+		"auth_time":     authTime,
 		"token_type":    constants.TokenTypeIdentityToken,
 		"allowed_roles": strings.Split(user.Roles, ","),
 		"login_method":  loginMethod,
+		"sid":           sessionID,
 		claimKey:        roles,
 	}
 	// split nonce to see if its authorization code grant method
@@ -415,10 +684,11 @@ func CreateIDToken(user *models.User, roles []string, hostname, nonce, atHash, c
 		customClaims["nonce"] = nonce
 		customClaims["at_hash"] = atHash
 	}
-	for k, v := range userMap {
-		if k != "roles" {
-			customClaims[k] = v
-		}
+	if claimsRequest != nil && claimsRequest.ACR != "" {
+		customClaims["acr"] = claimsRequest.ACR
+	}
+	for k, v := range GetUserClaims(user, scopes) {
+		customClaims[k] = v
 	}
 	// check for the extra access token script
 	accessTokenScript, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyCustomAccessTokenScript)
@@ -486,6 +756,7 @@ type SessionOrAccessTokenData struct {
 	UserID      string
 	LoginMethod string
 	Nonce       string
+	SessionID   string
 }
 
 // GetUserIDFromSessionOrAccessToken returns the user id from the session or access token
@@ -512,6 +783,7 @@ func GetUserIDFromSessionOrAccessToken(gc *gin.Context) (*SessionOrAccessTokenDa
 			UserID:      claims.Subject,
 			LoginMethod: claims.LoginMethod,
 			Nonce:       claims.Nonce,
+			SessionID:   claims.GetSessionID(),
 		}, nil
 	}
 	// If not session, then validate the access token
@@ -520,9 +792,16 @@ func GetUserIDFromSessionOrAccessToken(gc *gin.Context) (*SessionOrAccessTokenDa
 		log.Debug("Failed to validate access token: ", err)
 		return nil, fmt.Errorf(`unauthorized`)
 	}
+	nonce := claims["nonce"].(string)
+	// access tokens issued before session id was introduced are identified by nonce
+	sessionID, _ := claims["sid"].(string)
+	if sessionID == "" {
+		sessionID = nonce
+	}
 	return &SessionOrAccessTokenData{
 		UserID:      claims["sub"].(string),
 		LoginMethod: claims["login_method"].(string),
-		Nonce:       claims["nonce"].(string),
+		Nonce:       nonce,
+		SessionID:   sessionID,
 	}, nil
 }
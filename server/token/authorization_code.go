@@ -0,0 +1,42 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const authorizationCodeClientStatePrefix = "authorization_code_client:"
+
+// AuthorizationCodeClient is the client & redirect uri of authorization request for which code is issued.
+// Code is bound to them when authorization request is received, as the same code is issued after login
+// by /authorize, login resolvers or oauth callback. RedirectURI is empty when it was not sent with authorization request
+type AuthorizationCodeClient struct {
+	ClientID    string `json:"client_id"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+// SetAuthorizationCodeClient binds the authorization code to the client & redirect uri of authorization request
+func SetAuthorizationCodeClient(code string, codeClient *AuthorizationCodeClient) error {
+	data, err := json.Marshal(codeClient)
+	if err != nil {
+		return err
+	}
+	return memorystore.Provider.SetState(authorizationCodeClientStatePrefix+code, string(data))
+}
+
+// ConsumeAuthorizationCodeClient returns the client & redirect uri to which authorization code is bound
+// and removes it from state store, as code can be exchanged only once
+func ConsumeAuthorizationCodeClient(code string) (*AuthorizationCodeClient, error) {
+	data, err := memorystore.Provider.GetState(authorizationCodeClientStatePrefix + code)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("authorization code is not bound to any client")
+	}
+	memorystore.Provider.RemoveState(authorizationCodeClientStatePrefix + code)
+	var codeClient AuthorizationCodeClient
+	if err := json.Unmarshal([]byte(data), &codeClient); err != nil {
+		return nil, err
+	}
+	return &codeClient, nil
+}
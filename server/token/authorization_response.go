@@ -0,0 +1,25 @@
+package token
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// authorizationResponseExpiresIn is the lifetime of jwt secured authorization response in seconds
+const authorizationResponseExpiresIn = 600
+
+// CreateAuthorizationResponseToken creates jwt secured authorization response (JARM)
+// containing the authorization response params, e.g. code & state or error & state.
+// Response is signed with instance key, so that client can verify it was issued by authorizer
+func CreateAuthorizationResponseToken(clientID, hostname string, params map[string]interface{}) (string, error) {
+	customClaims := jwt.MapClaims{}
+	for key, value := range params {
+		customClaims[key] = value
+	}
+	customClaims["iss"] = hostname
+	customClaims["aud"] = clientID
+	customClaims["iat"] = time.Now().Unix()
+	customClaims["exp"] = time.Now().Add(authorizationResponseExpiresIn * time.Second).Unix()
+	return SignJWTToken(customClaims)
+}
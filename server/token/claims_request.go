@@ -0,0 +1,141 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+const (
+	// RequestedClaimsKey is the claim of access & refresh token holding the claims requested via claims request parameter,
+	// so that they are preserved while refreshing tokens
+	RequestedClaimsKey = "requested_claims"
+
+	claimsRequestStatePrefix = "claims_request:"
+)
+
+// ScopeClaims are the user claims released for standard scopes as per OIDC core spec
+var ScopeClaims = map[string][]string{
+	"profile": {"name", "family_name", "given_name", "middle_name", "nickname", "preferred_username", "profile", "picture", "website", "gender", "birthdate", "zoneinfo", "locale", "updated_at"},
+	"email":   {"email", "email_verified"},
+	"phone":   {"phone_number", "phone_number_verified"},
+	"address": {"address"},
+}
+
+// ClaimsRequest is the claims request parameter of authorization request (OIDC core 5.5).
+// Only the names of requested claims are kept, as essential & value of claims are not enforced.
+// ACR is the authentication context class requested via acr_values, which is reflected as acr claim of id token
+type ClaimsRequest struct {
+	UserInfo []string `json:"userinfo,omitempty"`
+	IDToken  []string `json:"id_token,omitempty"`
+	ACR      string   `json:"acr,omitempty"`
+}
+
+// ParseClaimsRequest parses the claims request parameter, empty parameter returns nil
+func ParseClaimsRequest(claims string) (*ClaimsRequest, error) {
+	if claims == "" {
+		return nil, nil
+	}
+	var data map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(claims), &data); err != nil {
+		return nil, fmt.Errorf("invalid claims: %s", err.Error())
+	}
+	claimNames := func(members map[string]interface{}) []string {
+		names := []string{}
+		for name := range members {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	return &ClaimsRequest{
+		UserInfo: claimNames(data["userinfo"]),
+		IDToken:  claimNames(data["id_token"]),
+	}, nil
+}
+
+// GetClaimsRequest returns the claims request stored in given token claims, nil if it is not present
+func GetClaimsRequest(claims map[string]interface{}) *ClaimsRequest {
+	requestedClaims, ok := claims[RequestedClaimsKey]
+	if !ok || requestedClaims == nil {
+		return nil
+	}
+	data, err := json.Marshal(requestedClaims)
+	if err != nil {
+		return nil
+	}
+	var claimsRequest ClaimsRequest
+	if err := json.Unmarshal(data, &claimsRequest); err != nil {
+		return nil
+	}
+	return &claimsRequest
+}
+
+// SetClaimsRequest saves the claims request for authorization code,
+// so that it can be used while exchanging code for tokens
+func SetClaimsRequest(code string, claimsRequest *ClaimsRequest) error {
+	data, err := json.Marshal(claimsRequest)
+	if err != nil {
+		return err
+	}
+	return memorystore.Provider.SetState(claimsRequestStatePrefix+code, string(data))
+}
+
+// ConsumeClaimsRequest returns the claims request saved for authorization code
+// and removes it from state store. nil is returned if claims were not requested
+func ConsumeClaimsRequest(code string) *ClaimsRequest {
+	data, err := memorystore.Provider.GetState(claimsRequestStatePrefix + code)
+	if err != nil || data == "" {
+		return nil
+	}
+	memorystore.Provider.RemoveState(claimsRequestStatePrefix + code)
+	var claimsRequest ClaimsRequest
+	if err := json.Unmarshal([]byte(data), &claimsRequest); err != nil {
+		return nil
+	}
+	return &claimsRequest
+}
+
+// GetClaimsRequestScopes returns the standard scopes covering the claims requested via claims request,
+// they are added to the scopes of authorization request so that user consents to the requested claims
+func GetClaimsRequestScopes(claimsRequest *ClaimsRequest) []string {
+	scopes := []string{}
+	if claimsRequest == nil {
+		return scopes
+	}
+	requestedClaims := append(append([]string{}, claimsRequest.IDToken...), claimsRequest.UserInfo...)
+	for scope, claims := range ScopeClaims {
+		for _, claim := range requestedClaims {
+			if utils.StringSliceContains(claims, claim) {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// GetUserClaims returns the claims of user which are released for given scopes.
+// Only the claims of granted scopes are released, rest of the user fields are never released
+func GetUserClaims(user *models.User, scope []string) map[string]interface{} {
+	releasedClaims := []string{}
+	for _, s := range scope {
+		releasedClaims = append(releasedClaims, ScopeClaims[s]...)
+	}
+
+	userBytes, _ := json.Marshal(user.AsAPIUser())
+	var userMap map[string]interface{}
+	json.Unmarshal(userBytes, &userMap)
+	res := map[string]interface{}{}
+	for _, claim := range releasedClaims {
+		if val, ok := userMap[claim]; ok && val != nil {
+			res[claim] = val
+		}
+	}
+	return res
+}
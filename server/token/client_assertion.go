@@ -0,0 +1,221 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+const (
+	// ClientAssertionLeeway is the allowed clock skew in seconds while validating exp & nbf of client assertion
+	ClientAssertionLeeway = 60
+
+	// clientAssertionStatePrefix is the state store namespace used to track jti of used client assertions
+	clientAssertionStatePrefix = "client_assertion:"
+	// clientJWKSMaxSize is the max size of json web key set fetched from jwks uri of client
+	clientJWKSMaxSize = 1 << 20
+)
+
+// ClientAssertionSigningAlgorithms are the algorithms supported for signing client assertion with private key,
+// symmetric algorithms are not allowed as assertion is verified with public keys of client
+var ClientAssertionSigningAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// ClientSecretAssertionSigningAlgorithms are the algorithms supported for signing client assertion with client secret
+var ClientSecretAssertionSigningAlgorithms = []string{"HS256", "HS384", "HS512"}
+
+// clientJWTClaims are the registered claims of jwt signed by client
+// i.e. client assertion (RFC 7523) & request object (RFC 9101)
+type clientJWTClaims struct {
+	Issuer    string      `json:"iss"`
+	Subject   string      `json:"sub"`
+	Audience  interface{} `json:"aud"`
+	JTI       string      `json:"jti"`
+	ExpiresAt int64       `json:"exp"`
+	NotBefore int64       `json:"nbf"`
+}
+
+// audiences returns aud claim as list, as it can be either string or array of strings
+func (c *clientJWTClaims) audiences() []string {
+	switch aud := c.Audience.(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		res := []string{}
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return []string{}
+	}
+}
+
+// GetClientAssertionSubject returns the sub claim of client assertion without validating it.
+// It is used to identify the client when client_id is not sent with client assertion
+func GetClientAssertionSubject(assertion string) (string, error) {
+	jws, err := jose.ParseSigned(assertion)
+	if err != nil {
+		return "", fmt.Errorf("invalid client assertion: %s", err.Error())
+	}
+	var claims clientJWTClaims
+	if err := json.Unmarshal(jws.UnsafePayloadWithoutVerification(), &claims); err != nil {
+		return "", fmt.Errorf("invalid client assertion: %s", err.Error())
+	}
+	return claims.Subject, nil
+}
+
+// ValidateClientAssertion validates the client assertion sent for private_key_jwt & client_secret_jwt client authentication (RFC 7523).
+// Assertion should be signed with one of the keys in jwks / jwks uri of client, or with client secret for client_secret_jwt.
+// iss & sub should be client id and aud should be issuer, token endpoint or the endpoint receiving the assertion.
+// Every assertion can be used only once, jti of assertion is tracked till the assertion expires
+func ValidateClientAssertion(gc *gin.Context, client *models.Client, assertion string) error {
+	jws, err := jose.ParseSigned(assertion)
+	if err != nil {
+		return fmt.Errorf("invalid client assertion: %s", err.Error())
+	}
+	var payload []byte
+	if client.GetTokenEndpointAuthMethod() == constants.TokenEndpointAuthMethodClientSecretJWT {
+		payload, err = verifyClientSecretSignature(client, jws)
+	} else {
+		payload, err = verifyClientSignature(client, jws)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid client assertion: %s", err.Error())
+	}
+
+	var claims clientJWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return fmt.Errorf("invalid client assertion: %s", err.Error())
+	}
+	if claims.Issuer != client.ClientID || claims.Subject != client.ClientID {
+		return fmt.Errorf("invalid client assertion: iss and sub should be client id")
+	}
+	hostname := parsers.GetHost(gc)
+	isValidAudience := false
+	for _, aud := range claims.audiences() {
+		if aud == hostname || aud == hostname+"/oauth/token" || aud == hostname+gc.Request.URL.Path {
+			isValidAudience = true
+			break
+		}
+	}
+	if !isValidAudience {
+		return fmt.Errorf("invalid client assertion: aud should be issuer or endpoint")
+	}
+	if claims.JTI == "" {
+		return fmt.Errorf("invalid client assertion: jti is required")
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt == 0 || claims.ExpiresAt < now-ClientAssertionLeeway {
+		return fmt.Errorf("invalid client assertion: assertion has expired")
+	}
+	if claims.NotBefore > now+ClientAssertionLeeway {
+		return fmt.Errorf("invalid client assertion: assertion is not valid yet")
+	}
+
+	// assertion can be used only once
+	jtiKey := clientAssertionStatePrefix + client.ClientID + ":" + claims.JTI
+	if val, err := memorystore.Provider.GetState(jtiKey); err == nil && val != "" {
+		return fmt.Errorf("invalid client assertion: assertion is already used")
+	}
+	return memorystore.Provider.SetStateWithExpiration(jtiKey, claims.JTI, claims.ExpiresAt+ClientAssertionLeeway)
+}
+
+// verifyClientSignature verifies the jws signed with one of the keys in jwks / jwks uri of client
+// and returns the payload of jws
+func verifyClientSignature(client *models.Client, jws *jose.JSONWebSignature) ([]byte, error) {
+	if len(jws.Signatures) != 1 {
+		return nil, fmt.Errorf("single signature is required")
+	}
+	header := jws.Signatures[0].Header
+	if !utils.StringSliceContains(ClientAssertionSigningAlgorithms, header.Algorithm) {
+		return nil, fmt.Errorf("unsupported algorithm %s", header.Algorithm)
+	}
+
+	keySet, err := getClientJWKS(client)
+	if err != nil {
+		return nil, err
+	}
+	keys := keySet.Keys
+	if header.KeyID != "" {
+		keys = keySet.Key(header.KeyID)
+	}
+	for _, key := range keys {
+		if key.Use == "enc" || !key.IsPublic() {
+			continue
+		}
+		if payload, err := jws.Verify(key); err == nil {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("signature is invalid")
+}
+
+// verifyClientSecretSignature verifies the jws signed with secret of client using client_secret_jwt
+// and returns the payload of jws
+func verifyClientSecretSignature(client *models.Client, jws *jose.JSONWebSignature) ([]byte, error) {
+	if len(jws.Signatures) != 1 {
+		return nil, fmt.Errorf("single signature is required")
+	}
+	header := jws.Signatures[0].Header
+	if !utils.StringSliceContains(ClientSecretAssertionSigningAlgorithms, header.Algorithm) {
+		return nil, fmt.Errorf("unsupported algorithm %s", header.Algorithm)
+	}
+	clientSecret, err := crypto.DecryptClientSecret(client.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := jws.Verify([]byte(clientSecret))
+	if err != nil {
+		return nil, fmt.Errorf("signature is invalid")
+	}
+	return payload, nil
+}
+
+// getClientJWKS returns the json web key set registered for client,
+// keys are fetched from jwks uri when jwks is not registered
+func getClientJWKS(client *models.Client) (*jose.JSONWebKeySet, error) {
+	data := []byte(client.JWKS)
+	if client.JWKS == "" {
+		if client.JWKSURI == "" {
+			return nil, fmt.Errorf("client does not have jwks")
+		}
+		if !strings.HasPrefix(client.JWKSURI, "https://") {
+			return nil, fmt.Errorf("jwks uri should use https")
+		}
+		httpClient := &http.Client{
+			Timeout: 10 * time.Second,
+		}
+		res, err := httpClient.Get(client.JWKSURI)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch jwks: %s", res.Status)
+		}
+		data, err = io.ReadAll(io.LimitReader(res.Body, clientJWKSMaxSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+	var keySet jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keySet); err != nil {
+		return nil, err
+	}
+	return &keySet, nil
+}
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// ConsentRequestExpiresIn is the time in seconds within which user should respond to consent page
+	ConsentRequestExpiresIn = 600
+
+	consentRequestStatePrefix = "consent:"
+)
+
+// ConsentRequest is the authorization request waiting for user consent.
+// ID is used as consent_challenge in consent page.
+// Params are the authorization request params which are used to resume authorization request once consent is given
+type ConsentRequest struct {
+	ID           string            `json:"id"`
+	UserID       string            `json:"user_id"`
+	ClientID     string            `json:"client_id"`
+	Scope        []string          `json:"scope"`
+	RedirectURI  string            `json:"redirect_uri"`
+	ResponseMode string            `json:"response_mode"`
+	State        string            `json:"state"`
+	Params       map[string]string `json:"params"`
+	ExpiresAt    int64             `json:"expires_at"`
+}
+
+// IsExpired returns true if user has not responded to consent page in time
+func (c *ConsentRequest) IsExpired() bool {
+	return c.ExpiresAt < time.Now().Unix()
+}
+
+// SetConsentRequest saves the consent request in state store with newly generated id
+func SetConsentRequest(consentRequest *ConsentRequest) (*ConsentRequest, error) {
+	consentRequest.ID = uuid.New().String()
+	consentRequest.ExpiresAt = time.Now().Unix() + ConsentRequestExpiresIn
+	data, err := json.Marshal(consentRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := memorystore.Provider.SetState(consentRequestStatePrefix+consentRequest.ID, string(data)); err != nil {
+		return nil, err
+	}
+	return consentRequest, nil
+}
+
+// GetConsentRequest returns the consent request for given consent challenge
+func GetConsentRequest(id string) (*ConsentRequest, error) {
+	data, err := memorystore.Provider.GetState(consentRequestStatePrefix + id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid consent challenge")
+	}
+	var consentRequest ConsentRequest
+	if err := json.Unmarshal([]byte(data), &consentRequest); err != nil {
+		return nil, err
+	}
+	if consentRequest.IsExpired() {
+		return nil, fmt.Errorf("consent challenge has expired")
+	}
+	return &consentRequest, nil
+}
+
+// ConsumeConsentRequest returns the consent request for given consent challenge
+// and removes it from state store, as consent challenge can be used only once
+func ConsumeConsentRequest(id string) (*ConsentRequest, error) {
+	consentRequest, err := GetConsentRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := memorystore.Provider.RemoveState(consentRequestStatePrefix + id); err != nil {
+		return nil, err
+	}
+	return consentRequest, nil
+}
@@ -0,0 +1,113 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// DeviceCodeExpiresIn is the lifetime of device code in seconds
+	DeviceCodeExpiresIn = 600
+	// DeviceCodePollingInterval is the minimum polling interval for device code in seconds
+	DeviceCodePollingInterval = 5
+
+	// DeviceAuthorizationStatusPending is the status till user approves or denies the device
+	DeviceAuthorizationStatusPending = "pending"
+	// DeviceAuthorizationStatusApproved is the status once user approves the device
+	DeviceAuthorizationStatusApproved = "approved"
+	// DeviceAuthorizationStatusDenied is the status once user denies the device
+	DeviceAuthorizationStatusDenied = "denied"
+
+	deviceCodeStatePrefix = "device_code:"
+	userCodeStatePrefix   = "device_user_code:"
+	// userCodeCharset excludes vowels and similar looking characters as per RFC 8628
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+)
+
+// DeviceAuthorization is the device authorization request stored in state store
+type DeviceAuthorization struct {
+	DeviceCode   string   `json:"device_code"`
+	UserCode     string   `json:"user_code"`
+	ClientID     string   `json:"client_id"`
+	Scope        []string `json:"scope"`
+	Status       string   `json:"status"`
+	Interval     int64    `json:"interval"`
+	ExpiresAt    int64    `json:"expires_at"`
+	LastPolledAt int64    `json:"last_polled_at"`
+	UserID       string   `json:"user_id"`
+	LoginMethod  string   `json:"login_method"`
+}
+
+// IsExpired returns true if device code has expired
+func (d *DeviceAuthorization) IsExpired() bool {
+	return d.ExpiresAt < time.Now().Unix()
+}
+
+// NewUserCode generates user code in XXXX-XXXX format
+func NewUserCode() (string, error) {
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeCharset))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeCharset[n.Int64()]
+	}
+	return string(code[:userCodeLength/2]) + "-" + string(code[userCodeLength/2:]), nil
+}
+
+// NormalizeUserCode normalizes the user code entered by user
+// so that it is case insensitive and ignores separators
+func NormalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(strings.TrimSpace(userCode))
+	userCode = strings.ReplaceAll(userCode, "-", "")
+	userCode = strings.ReplaceAll(userCode, " ", "")
+	return userCode
+}
+
+// SetDeviceAuthorization saves device authorization in state store
+func SetDeviceAuthorization(deviceAuthorization *DeviceAuthorization) error {
+	data, err := json.Marshal(deviceAuthorization)
+	if err != nil {
+		return err
+	}
+	if err := memorystore.Provider.SetState(deviceCodeStatePrefix+deviceAuthorization.DeviceCode, string(data)); err != nil {
+		return err
+	}
+	return memorystore.Provider.SetState(userCodeStatePrefix+NormalizeUserCode(deviceAuthorization.UserCode), deviceAuthorization.DeviceCode)
+}
+
+// GetDeviceAuthorization returns device authorization for given device code
+func GetDeviceAuthorization(deviceCode string) (*DeviceAuthorization, error) {
+	data, err := memorystore.Provider.GetState(deviceCodeStatePrefix + deviceCode)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid device code")
+	}
+	var deviceAuthorization DeviceAuthorization
+	if err := json.Unmarshal([]byte(data), &deviceAuthorization); err != nil {
+		return nil, err
+	}
+	return &deviceAuthorization, nil
+}
+
+// GetDeviceAuthorizationByUserCode returns device authorization for given user code
+func GetDeviceAuthorizationByUserCode(userCode string) (*DeviceAuthorization, error) {
+	deviceCode, err := memorystore.Provider.GetState(userCodeStatePrefix + NormalizeUserCode(userCode))
+	if err != nil || deviceCode == "" {
+		return nil, fmt.Errorf("invalid user code")
+	}
+	return GetDeviceAuthorization(deviceCode)
+}
+
+// RemoveDeviceAuthorization removes device authorization from state store
+func RemoveDeviceAuthorization(deviceAuthorization *DeviceAuthorization) {
+	memorystore.Provider.RemoveState(deviceCodeStatePrefix + deviceAuthorization.DeviceCode)
+	memorystore.Provider.RemoveState(userCodeStatePrefix + NormalizeUserCode(deviceAuthorization.UserCode))
+}
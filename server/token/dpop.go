@@ -0,0 +1,154 @@
+package token
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+const (
+	// DPoPHeader is the request header used to send DPoP proof (RFC 9449)
+	DPoPHeader = "DPoP"
+	// DPoPTokenType is the token type of access tokens bound to DPoP key
+	DPoPTokenType = "DPoP"
+	// DPoPProofMaxAge is the allowed difference in seconds between iat of DPoP proof and current time
+	DPoPProofMaxAge = 60
+
+	dpopProofTyp = "dpop+jwt"
+	// dpopProofStatePrefix is the state store namespace used to track jti of used DPoP proofs
+	dpopProofStatePrefix = "dpop_proof:"
+)
+
+// DPoPSigningAlgorithms are the algorithms supported for signing DPoP proof,
+// symmetric algorithms are not allowed as proof is verified with public key sent in proof
+var DPoPSigningAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// dpopProofClaims are the claims of DPoP proof
+type dpopProofClaims struct {
+	JTI string `json:"jti"`
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	IAT int64  `json:"iat"`
+	ATH string `json:"ath"`
+}
+
+// ValidateDPoPProof validates the DPoP proof sent in DPoP header for current request
+// and returns JWK thumbprint of the key used for signing proof.
+// accessToken is set when proof is sent along with access token, so that ath claim is validated.
+// Every proof can be used only once, jti of proof is tracked till the proof expires.
+// State store with expiration is used, as jti should not be evicted before proof expires
+func ValidateDPoPProof(gc *gin.Context, proof, accessToken string) (string, error) {
+	jws, err := jose.ParseSigned(proof)
+	if err != nil {
+		return "", fmt.Errorf("invalid dpop proof: %s", err.Error())
+	}
+	if len(jws.Signatures) != 1 {
+		return "", fmt.Errorf("invalid dpop proof: single signature is required")
+	}
+	header := jws.Signatures[0].Header
+	if typ, _ := header.ExtraHeaders[jose.HeaderType].(string); typ != dpopProofTyp {
+		return "", fmt.Errorf("invalid dpop proof: typ should be %s", dpopProofTyp)
+	}
+	if !utils.StringSliceContains(DPoPSigningAlgorithms, header.Algorithm) {
+		return "", fmt.Errorf("invalid dpop proof: unsupported algorithm %s", header.Algorithm)
+	}
+	jwk := header.JSONWebKey
+	if jwk == nil || !jwk.IsPublic() || !jwk.Valid() {
+		return "", fmt.Errorf("invalid dpop proof: public jwk is required")
+	}
+	payload, err := jws.Verify(jwk)
+	if err != nil {
+		return "", fmt.Errorf("invalid dpop proof: %s", err.Error())
+	}
+
+	var claims dpopProofClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("invalid dpop proof: %s", err.Error())
+	}
+	if claims.JTI == "" {
+		return "", fmt.Errorf("invalid dpop proof: jti is required")
+	}
+	if claims.HTM != gc.Request.Method {
+		return "", fmt.Errorf("invalid dpop proof: htm does not match request method")
+	}
+	if !isDPoPProofURI(claims.HTU, parsers.GetHost(gc)+gc.Request.URL.Path) {
+		return "", fmt.Errorf("invalid dpop proof: htu does not match request uri")
+	}
+	now := time.Now().Unix()
+	if claims.IAT < now-DPoPProofMaxAge || claims.IAT > now+DPoPProofMaxAge {
+		return "", fmt.Errorf("invalid dpop proof: iat is not within acceptable range")
+	}
+	if accessToken != "" {
+		hash := sha256.Sum256([]byte(accessToken))
+		if claims.ATH != base64.RawURLEncoding.EncodeToString(hash[:]) {
+			return "", fmt.Errorf("invalid dpop proof: ath does not match access token")
+		}
+	}
+
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("invalid dpop proof: %s", err.Error())
+	}
+	jkt := base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	// proof can be used only once
+	jtiKey := dpopProofStatePrefix + jkt + ":" + claims.JTI
+	if val, err := memorystore.Provider.GetState(jtiKey); err == nil && val != "" {
+		return "", fmt.Errorf("invalid dpop proof: proof is already used")
+	}
+	if err := memorystore.Provider.SetStateWithExpiration(jtiKey, claims.JTI, claims.IAT+DPoPProofMaxAge); err != nil {
+		return "", err
+	}
+	return jkt, nil
+}
+
+// GetDPoPJKT returns the jwk thumbprint to which token is bound via cnf claim.
+// It returns empty string for bearer tokens
+func GetDPoPJKT(claims map[string]interface{}) string {
+	cnf, ok := claims["cnf"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	jkt, _ := cnf["jkt"].(string)
+	return jkt
+}
+
+// validateDPoPBoundAccessToken validates that DPoP bound access token is sent with DPoP scheme
+// and proof signed with the key to which token is bound
+func validateDPoPBoundAccessToken(gc *gin.Context, accessToken, jkt string) error {
+	auth := gc.Request.Header.Get("Authorization")
+	if !strings.HasPrefix(strings.ToLower(auth), "dpop ") {
+		return fmt.Errorf(`unauthorized: dpop bound token should be sent with DPoP scheme`)
+	}
+	proofJKT, err := ValidateDPoPProof(gc, gc.Request.Header.Get(DPoPHeader), accessToken)
+	if err != nil {
+		return err
+	}
+	if proofJKT != jkt {
+		return fmt.Errorf(`unauthorized: dpop proof key does not match token`)
+	}
+	return nil
+}
+
+// isDPoPProofURI compares htu claim with request uri without query and fragment
+func isDPoPProofURI(htu, requestURI string) bool {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	return strings.TrimSuffix(u.String(), "/") == strings.TrimSuffix(requestURI, "/")
+}
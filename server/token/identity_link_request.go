@@ -0,0 +1,92 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/cookie"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// IdentityLinkRequestExpiresIn is the time in seconds within which user should login with provider to link identity
+	IdentityLinkRequestExpiresIn = 600
+
+	identityLinkRequestStatePrefix = "identity_link:"
+)
+
+// IdentityLinkRequest is the request of logged in user to link identity of login provider.
+// ID is used as link_token in oauth_login, so identity returned by provider is linked to user instead of login
+type IdentityLinkRequest struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Provider  string `json:"provider"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// IsExpired returns true if user has not logged in with provider in time
+func (l *IdentityLinkRequest) IsExpired() bool {
+	return l.ExpiresAt < time.Now().Unix()
+}
+
+// SetIdentityLinkRequest saves the identity link request in state store with newly generated id
+func SetIdentityLinkRequest(linkRequest *IdentityLinkRequest) (*IdentityLinkRequest, error) {
+	linkRequest.ID = uuid.New().String()
+	linkRequest.ExpiresAt = time.Now().Unix() + IdentityLinkRequestExpiresIn
+	data, err := json.Marshal(linkRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := memorystore.Provider.SetState(identityLinkRequestStatePrefix+linkRequest.ID, string(data)); err != nil {
+		return nil, err
+	}
+	return linkRequest, nil
+}
+
+// GetIdentityLinkRequest returns the identity link request for given link token
+func GetIdentityLinkRequest(id string) (*IdentityLinkRequest, error) {
+	data, err := memorystore.Provider.GetState(identityLinkRequestStatePrefix + id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid link token")
+	}
+	var linkRequest IdentityLinkRequest
+	if err := json.Unmarshal([]byte(data), &linkRequest); err != nil {
+		return nil, err
+	}
+	if linkRequest.IsExpired() {
+		return nil, fmt.Errorf("link token has expired")
+	}
+	return &linkRequest, nil
+}
+
+// ConsumeIdentityLinkRequest returns the identity link request for given link token
+// and removes it from state store, as link token can be used only once
+func ConsumeIdentityLinkRequest(id string) (*IdentityLinkRequest, error) {
+	linkRequest, err := GetIdentityLinkRequest(id)
+	memorystore.Provider.RemoveState(identityLinkRequestStatePrefix + id)
+	if err != nil {
+		return nil, err
+	}
+	return linkRequest, nil
+}
+
+// ValidateIdentityLinkSession returns error unless browser session of request belongs to the user who requested the link.
+// Otherwise link token sent to another user would link identity of that user to the account of requester
+func ValidateIdentityLinkSession(gc *gin.Context, linkRequest *IdentityLinkRequest) error {
+	sessionToken, err := cookie.GetSession(gc)
+	if err != nil {
+		return fmt.Errorf("unauthorized")
+	}
+	sessionData, err := ValidateBrowserSession(gc, sessionToken)
+	if err != nil {
+		return fmt.Errorf("unauthorized")
+	}
+	if sessionData.Subject != linkRequest.UserID {
+		return fmt.Errorf("session does not belong to the user who requested the link")
+	}
+	return nil
+}
@@ -1,17 +1,42 @@
 package token
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt"
 
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db"
 	"github.com/authorizerdev/authorizer/server/memorystore"
 )
 
 // SignJWTToken common util to sing jwt token
+// Token is signed with active key of keyring and key id is set as kid header
 func SignJWTToken(claims jwt.MapClaims) (string, error) {
+	activeKey, err := crypto.GetActiveSigningKey()
+	if err != nil {
+		return signJWTTokenWithEnvKey(claims)
+	}
+	signingMethod := jwt.GetSigningMethod(activeKey.Algorithm)
+	if signingMethod == nil {
+		return "", errors.New("unsupported signing method")
+	}
+	key, err := activeKey.GetSigningKey()
+	if err != nil {
+		return "", err
+	}
+	t := jwt.NewWithClaims(signingMethod, claims)
+	t.Header["kid"] = activeKey.KeyID
+	return t.SignedString(key)
+}
+
+// signJWTTokenWithEnvKey signs jwt token with key configured via env
+// it is used when keyring is not initialized
+func signJWTTokenWithEnvKey(claims jwt.MapClaims) (string, error) {
 	jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
 	if err != nil {
 		return "", err
@@ -60,51 +85,26 @@ func SignJWTToken(claims jwt.MapClaims) (string, error) {
 }
 
 // ParseJWTToken common util to parse jwt token
+// Key used for verifying the token is picked from keyring using kid header,
+// tokens without kid header are verified with key configured via env.
+// Revoked tokens & tokens issued to client before user revoked its oauth grant
+// are rejected even if they are not expired
 func ParseJWTToken(token string) (jwt.MapClaims, error) {
-	jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
-	if err != nil {
-		return nil, err
-	}
-	signingMethod := jwt.GetSigningMethod(jwtType)
-
 	var claims jwt.MapClaims
-
-	switch signingMethod {
-	case jwt.SigningMethodHS256, jwt.SigningMethodHS384, jwt.SigningMethodHS512:
-		_, err = jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
-			jwtSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtSecret)
-			if err != nil {
-				return nil, err
-			}
-			return []byte(jwtSecret), nil
-		})
-	case jwt.SigningMethodRS256, jwt.SigningMethodRS384, jwt.SigningMethodRS512:
-		_, err = jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
-			jwtPublicKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPublicKey)
-			if err != nil {
-				return nil, err
-			}
-			key, err := crypto.ParseRsaPublicKeyFromPemStr(jwtPublicKey)
-			if err != nil {
-				return nil, err
-			}
-			return key, nil
-		})
-	case jwt.SigningMethodES256, jwt.SigningMethodES384, jwt.SigningMethodES512:
-		_, err = jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
-			jwtPublicKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPublicKey)
-			if err != nil {
-				return nil, err
-			}
-			key, err := crypto.ParseEcdsaPublicKeyFromPemStr(jwtPublicKey)
-			if err != nil {
-				return nil, err
-			}
-			return key, nil
-		})
-	default:
-		err = errors.New("unsupported signing method")
-	}
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		keyID, ok := t.Header["kid"].(string)
+		if !ok || keyID == "" {
+			return getEnvVerificationKey()
+		}
+		key, err := crypto.GetSigningKeyByID(keyID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != key.Algorithm {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.GetVerificationKey()
+	})
 	if err != nil {
 		return claims, err
 	}
@@ -117,16 +117,54 @@ func ParseJWTToken(token string) (jwt.MapClaims, error) {
 	claims["exp"] = intExp
 	claims["iat"] = intIat
 
+	// revocation list is checked only for verified tokens,
+	// so that forged tokens do not cost a store lookup
+	if IsTokenRevoked(token) || isRevokedByOAuthGrant(claims) {
+		return claims, errors.New("token has been revoked")
+	}
+
 	return claims, nil
 }
 
+// getEnvVerificationKey returns the key configured via env for verifying tokens
+func getEnvVerificationKey() (interface{}, error) {
+	jwtType, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtType)
+	if err != nil {
+		return nil, err
+	}
+	signingMethod := jwt.GetSigningMethod(jwtType)
+
+	switch signingMethod {
+	case jwt.SigningMethodHS256, jwt.SigningMethodHS384, jwt.SigningMethodHS512:
+		jwtSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtSecret)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(jwtSecret), nil
+	case jwt.SigningMethodRS256, jwt.SigningMethodRS384, jwt.SigningMethodRS512:
+		jwtPublicKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.ParseRsaPublicKeyFromPemStr(jwtPublicKey)
+	case jwt.SigningMethodES256, jwt.SigningMethodES384, jwt.SigningMethodES512:
+		jwtPublicKey, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyJwtPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.ParseEcdsaPublicKeyFromPemStr(jwtPublicKey)
+	default:
+		return nil, errors.New("unsupported signing method")
+	}
+}
+
 // ValidateJWTClaims common util to validate claims
-func ValidateJWTClaims(claims jwt.MapClaims, hostname, nonce, subject string) (bool, error) {
+func ValidateJWTClaims(ctx context.Context, claims jwt.MapClaims, hostname, nonce, subject string) (bool, error) {
 	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
 	if err != nil {
 		return false, err
 	}
-	if claims["aud"] != clientID {
+	if claims["aud"] != clientID && !isRegisteredClientAudience(ctx, claims["aud"]) {
 		return false, errors.New("invalid audience")
 	}
 
@@ -146,12 +184,12 @@ func ValidateJWTClaims(claims jwt.MapClaims, hostname, nonce, subject string) (b
 }
 
 // ValidateJWTTokenWithoutNonce common util to validate claims without nonce
-func ValidateJWTTokenWithoutNonce(claims jwt.MapClaims, hostname, subject string) (bool, error) {
+func ValidateJWTTokenWithoutNonce(ctx context.Context, claims jwt.MapClaims, hostname, subject string) (bool, error) {
 	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
 	if err != nil {
 		return false, err
 	}
-	if claims["aud"] != clientID {
+	if claims["aud"] != clientID && !isRegisteredClientAudience(ctx, claims["aud"]) {
 		return false, errors.New("invalid audience")
 	}
 
@@ -164,3 +202,44 @@ func ValidateJWTTokenWithoutNonce(claims jwt.MapClaims, hostname, subject string
 	}
 	return true, nil
 }
+
+// registeredClientAudienceCacheTTL is the duration for which registered client is cached as valid audience,
+// so that every token validation does not need database lookup
+const registeredClientAudienceCacheTTL = time.Minute
+
+// registeredClientAudiences caches the client ids found in client registry with the time till they are valid
+var registeredClientAudiences = struct {
+	sync.Mutex
+	expiresAt map[string]int64
+}{expiresAt: map[string]int64{}}
+
+// isRegisteredClientAudience checks if audience is one of the clients registered in client registry
+func isRegisteredClientAudience(ctx context.Context, aud interface{}) bool {
+	clientID, ok := aud.(string)
+	if !ok || clientID == "" {
+		return false
+	}
+	now := time.Now().Unix()
+	registeredClientAudiences.Lock()
+	expiresAt := registeredClientAudiences.expiresAt[clientID]
+	registeredClientAudiences.Unlock()
+	if expiresAt > now {
+		return true
+	}
+	client, err := db.Provider.GetClientByClientID(ctx, clientID)
+	if err != nil || client == nil {
+		return false
+	}
+	registeredClientAudiences.Lock()
+	registeredClientAudiences.expiresAt[clientID] = now + int64(registeredClientAudienceCacheTTL.Seconds())
+	registeredClientAudiences.Unlock()
+	return true
+}
+
+// InvalidateClientAudience removes the client from registered client audience cache,
+// it should be called when client is deleted from client registry
+func InvalidateClientAudience(clientID string) {
+	registeredClientAudiences.Lock()
+	defer registeredClientAudiences.Unlock()
+	delete(registeredClientAudiences.expiresAt, clientID)
+}
@@ -0,0 +1,66 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// LogoutRequestExpiresIn is the time in seconds within which user should confirm logout
+	LogoutRequestExpiresIn = 600
+
+	logoutRequestStatePrefix = "logout:"
+)
+
+// LogoutRequest is the logout request sent without id_token_hint, waiting for user confirmation.
+// ID is used as logout_challenge in logout confirmation page
+type LogoutRequest struct {
+	ID                    string `json:"id"`
+	PostLogoutRedirectURI string `json:"post_logout_redirect_uri"`
+	ExpiresAt             int64  `json:"expires_at"`
+}
+
+// IsExpired returns true if user has not confirmed logout in time
+func (l *LogoutRequest) IsExpired() bool {
+	return l.ExpiresAt < time.Now().Unix()
+}
+
+// SetLogoutRequest saves the logout request in state store with newly generated id
+func SetLogoutRequest(postLogoutRedirectURI string) (*LogoutRequest, error) {
+	logoutRequest := &LogoutRequest{
+		ID:                    uuid.New().String(),
+		PostLogoutRedirectURI: postLogoutRedirectURI,
+		ExpiresAt:             time.Now().Unix() + LogoutRequestExpiresIn,
+	}
+	data, err := json.Marshal(logoutRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := memorystore.Provider.SetStateWithExpiration(logoutRequestStatePrefix+logoutRequest.ID, string(data), logoutRequest.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return logoutRequest, nil
+}
+
+// ConsumeLogoutRequest returns the logout request for given logout challenge
+// and removes it from state store, as logout challenge can be used only once
+func ConsumeLogoutRequest(id string) (*LogoutRequest, error) {
+	data, err := memorystore.Provider.GetState(logoutRequestStatePrefix + id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid logout challenge")
+	}
+	memorystore.Provider.RemoveState(logoutRequestStatePrefix + id)
+	var logoutRequest LogoutRequest
+	if err := json.Unmarshal([]byte(data), &logoutRequest); err != nil {
+		return nil, err
+	}
+	if logoutRequest.IsExpired() {
+		return nil, fmt.Errorf("logout challenge has expired")
+	}
+	return &logoutRequest, nil
+}
@@ -0,0 +1,120 @@
+package token
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/parsers"
+)
+
+const (
+	// BackchannelLogoutEvent is the event identifier of logout token
+	BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+	// logoutTokenExpiresIn is the lifetime of logout token in seconds
+	logoutTokenExpiresIn = 120
+)
+
+// CreateLogoutToken creates logout token for given client as per OIDC back-channel logout spec.
+// Logout token must not contain nonce claim
+func CreateLogoutToken(clientID, hostname, userID, sessionID string) (string, error) {
+	customClaims := jwt.MapClaims{
+		"iss": hostname,
+		"aud": clientID,
+		"sub": userID,
+		"sid": sessionID,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(logoutTokenExpiresIn * time.Second).Unix(),
+		"jti": uuid.New().String(),
+		"events": map[string]interface{}{
+			BackchannelLogoutEvent: map[string]interface{}{},
+		},
+	}
+	return SignJWTToken(customClaims)
+}
+
+// ParseIDTokenHint parses the id_token_hint sent with logout request.
+// Expired id tokens are accepted as hint, as long as they are issued by authorizer
+func ParseIDTokenHint(gc *gin.Context, idTokenHint string) (jwt.MapClaims, error) {
+	claims, err := ParseJWTToken(idTokenHint)
+	if err != nil {
+		validationErr, ok := err.(*jwt.ValidationError)
+		if !ok || validationErr.Errors != jwt.ValidationErrorExpired {
+			return nil, err
+		}
+	}
+	if claims["iss"] != parsers.GetHost(gc) {
+		return nil, fmt.Errorf("invalid issuer")
+	}
+	if claims["token_type"] != constants.TokenTypeIdentityToken {
+		return nil, fmt.Errorf("invalid token type")
+	}
+	if sub, ok := claims["sub"].(string); !ok || sub == "" {
+		return nil, fmt.Errorf("invalid subject")
+	}
+	return claims, nil
+}
+
+// NotifyBackchannelLogout sends logout token to back-channel logout uri
+// of given clients, which participated in user session that is terminated
+func NotifyBackchannelLogout(hostname, userID, sessionID string, clients []*models.Client) {
+	httpClient := &http.Client{Timeout: time.Second * 10}
+	for _, client := range clients {
+		if client.BackchannelLogoutURI == "" {
+			continue
+		}
+		log := log.WithField("client_id", client.ClientID)
+		subject, err := GetSubject(client, userID)
+		if err != nil {
+			log.Debug("Failed to get subject: ", err)
+			continue
+		}
+		logoutToken, err := CreateLogoutToken(client.ClientID, hostname, subject, sessionID)
+		if err != nil {
+			log.Debug("Failed to create logout token: ", err)
+			continue
+		}
+		form := url.Values{}
+		form.Set("logout_token", logoutToken)
+		res, err := httpClient.Post(client.BackchannelLogoutURI, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+		if err != nil {
+			log.Debug("Failed to send logout token: ", err)
+			continue
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+			log.Debug("Back-channel logout failed with status: ", res.StatusCode)
+		}
+	}
+}
+
+// GetFrontchannelLogoutURIs returns front-channel logout uris of given clients
+// with iss & sid query params, to be rendered as iframes once user session is terminated
+func GetFrontchannelLogoutURIs(hostname, sessionID string, clients []*models.Client) []string {
+	logoutURIs := []string{}
+	for _, client := range clients {
+		frontchannelLogoutURI := client.FrontchannelLogoutURI
+		if frontchannelLogoutURI == "" {
+			continue
+		}
+		params := url.Values{}
+		params.Set("iss", hostname)
+		params.Set("sid", sessionID)
+		if strings.Contains(frontchannelLogoutURI, "?") {
+			frontchannelLogoutURI += "&" + params.Encode()
+		} else {
+			frontchannelLogoutURI += "?" + params.Encode()
+		}
+		logoutURIs = append(logoutURIs, frontchannelLogoutURI)
+	}
+	return logoutURIs
+}
@@ -0,0 +1,59 @@
+package token
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// revokedOAuthGrantStatePrefix is the state store namespace holding the time
+// at which user revoked the oauth grant of client
+const revokedOAuthGrantStatePrefix = "revoked_oauth_grant:"
+
+func revokedOAuthGrantKey(userID, clientID string) string {
+	return revokedOAuthGrantStatePrefix + userID + ":" + clientID
+}
+
+// RevokeOAuthGrantTokens revokes all the tokens issued to client on behalf of user till now.
+// Revocation time is kept till the tokens issued before it can be valid
+func RevokeOAuthGrantTokens(client *models.Client, userID string) error {
+	accessTokenExpiryBound, err := getAccessTokenExpiryBound(client)
+	if err != nil {
+		return err
+	}
+	expiryBound := getRefreshTokenExpiryBound(client)
+	if accessTokenExpiryBound > expiryBound {
+		expiryBound = accessTokenExpiryBound
+	}
+	now := time.Now()
+	return memorystore.Provider.SetStateWithExpiration(revokedOAuthGrantKey(userID, client.ClientID), strconv.FormatInt(now.Unix(), 10), now.Add(expiryBound).Unix())
+}
+
+// isRevokedByOAuthGrant returns true if token was issued to client on behalf of user
+// before user revoked the oauth grant of client. Tokens of default client are never revoked by grant
+func isRevokedByOAuthGrant(claims jwt.MapClaims) bool {
+	subject, _ := claims["sub"].(string)
+	// tokens issued via token exchange have client_id claim as audience is the target service
+	clientID, _ := claims["client_id"].(string)
+	if clientID == "" {
+		clientID, _ = claims["aud"].(string)
+	}
+	if subject == "" || clientID == "" || utils.IsDefaultClientID(clientID) {
+		return false
+	}
+	revokedAt, err := memorystore.Provider.GetState(revokedOAuthGrantKey(GetUserIDFromSubject(subject), clientID))
+	if err != nil || revokedAt == "" {
+		return false
+	}
+	revokedAtUnix, err := strconv.ParseInt(revokedAt, 10, 64)
+	if err != nil {
+		return false
+	}
+	issuedAt, _ := claims["iat"].(int64)
+	return issuedAt <= revokedAtUnix
+}
@@ -0,0 +1,91 @@
+package token
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// getPairwiseSubjectCipher returns AES-GCM cipher keyed with pairwise subject salt,
+// along with salt which is used for deriving nonce of subject
+func getPairwiseSubjectCipher() (cipher.AEAD, []byte, error) {
+	salt, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyPairwiseSubjectSalt)
+	if err != nil || salt == "" {
+		return nil, nil, fmt.Errorf("pairwise subject salt is not configured")
+	}
+	key := sha256.Sum256([]byte("pairwise_subject:" + salt))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, []byte(salt), nil
+}
+
+// GetSubject returns the sub claim of user for given client.
+// Clients with pairwise subject type get user id encrypted with nonce derived from sector identifier & user id,
+// so that subject is stable for sector, different sectors cannot correlate the user,
+// and subject can be resolved back to user id without keeping any state
+func GetSubject(client *models.Client, userID string) (string, error) {
+	if client == nil || client.GetSubjectType() != constants.SubjectTypePairwise {
+		return userID, nil
+	}
+	aead, salt, err := getPairwiseSubjectCipher()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(client.GetSectorIdentifier() + ":" + userID))
+	nonce := mac.Sum(nil)[:aead.NonceSize()]
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(userID), nil)), nil
+}
+
+// GetUserIDFromSubject returns the user id for sub claim of token.
+// Pairwise subject is decrypted to user id, rest of the subjects are user id
+func GetUserIDFromSubject(subject string) string {
+	data, err := base64.RawURLEncoding.DecodeString(subject)
+	if err != nil {
+		return subject
+	}
+	aead, _, err := getPairwiseSubjectCipher()
+	if err != nil || len(data) < aead.NonceSize()+aead.Overhead() {
+		return subject
+	}
+	userID, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return subject
+	}
+	return string(userID)
+}
+
+// GetTokenSubject returns the sub claim to be exposed for validated token claims,
+// validated claims have user id as sub, which is converted back to subject of the client to which token is issued
+func GetTokenSubject(gc *gin.Context, claims map[string]interface{}) string {
+	userID, _ := claims["sub"].(string)
+	clientID, ok := claims["client_id"].(string)
+	if !ok {
+		clientID, _ = claims["aud"].(string)
+	}
+	client, err := utils.GetClientByClientID(gc, clientID)
+	if err != nil {
+		return userID
+	}
+	subject, err := GetSubject(client, userID)
+	if err != nil {
+		return userID
+	}
+	return subject
+}
@@ -0,0 +1,94 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+const (
+	// PushedAuthorizationRequestExpiresIn is the lifetime of request_uri in seconds
+	PushedAuthorizationRequestExpiresIn = 60
+	// LoginAuthorizationRequestExpiresIn is the lifetime of request_uri in seconds,
+	// which is used to resume authorization request after user logs in
+	LoginAuthorizationRequestExpiresIn = 1800
+	// PushedAuthorizationRequestURIPrefix is the prefix of request_uri as per RFC 9126
+	PushedAuthorizationRequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+	pushedAuthorizationRequestStatePrefix = "par:"
+)
+
+// PushedAuthorizationRequest is the authorization request pushed by client
+// and stored in state store till it is used by authorize request.
+// MinAuthTime is set when request is resumed after re-authentication,
+// session authenticated before it cannot be used for the request
+type PushedAuthorizationRequest struct {
+	RequestURI  string            `json:"request_uri"`
+	ClientID    string            `json:"client_id"`
+	Params      map[string]string `json:"params"`
+	MinAuthTime int64             `json:"min_auth_time,omitempty"`
+	ExpiresAt   int64             `json:"expires_at"`
+}
+
+// IsExpired returns true if request_uri has expired
+func (p *PushedAuthorizationRequest) IsExpired() bool {
+	return p.ExpiresAt < time.Now().Unix()
+}
+
+// SetPushedAuthorizationRequest saves authorization request params for client
+// and returns the stored request with newly generated request_uri
+func SetPushedAuthorizationRequest(clientID string, params map[string]string) (*PushedAuthorizationRequest, error) {
+	return setAuthorizationRequest(clientID, params, 0, PushedAuthorizationRequestExpiresIn)
+}
+
+// SetLoginAuthorizationRequest saves authorization request params for client,
+// so that authorization request can be resumed with request_uri after user logs in.
+// minAuthTime is the time after which user should have logged in, 0 if any session can be used
+func SetLoginAuthorizationRequest(clientID string, params map[string]string, minAuthTime int64) (*PushedAuthorizationRequest, error) {
+	return setAuthorizationRequest(clientID, params, minAuthTime, LoginAuthorizationRequestExpiresIn)
+}
+
+func setAuthorizationRequest(clientID string, params map[string]string, minAuthTime, expiresIn int64) (*PushedAuthorizationRequest, error) {
+	pushedAuthorizationRequest := &PushedAuthorizationRequest{
+		RequestURI:  PushedAuthorizationRequestURIPrefix + uuid.New().String(),
+		ClientID:    clientID,
+		Params:      params,
+		MinAuthTime: minAuthTime,
+		ExpiresAt:   time.Now().Unix() + expiresIn,
+	}
+	data, err := json.Marshal(pushedAuthorizationRequest)
+	if err != nil {
+		return nil, err
+	}
+	// request_uri is removed from store once it expires, even when it is never used
+	if err := memorystore.Provider.SetStateWithExpiration(pushedAuthorizationRequestStatePrefix+pushedAuthorizationRequest.RequestURI, string(data), pushedAuthorizationRequest.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return pushedAuthorizationRequest, nil
+}
+
+// ConsumePushedAuthorizationRequest returns the authorization request for given request_uri
+// and removes it from state store, as request_uri can be used only once
+func ConsumePushedAuthorizationRequest(requestURI string) (*PushedAuthorizationRequest, error) {
+	if !strings.HasPrefix(requestURI, PushedAuthorizationRequestURIPrefix) {
+		return nil, fmt.Errorf("invalid request_uri")
+	}
+	data, err := memorystore.Provider.GetState(pushedAuthorizationRequestStatePrefix + requestURI)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("invalid request_uri")
+	}
+	memorystore.Provider.RemoveState(pushedAuthorizationRequestStatePrefix + requestURI)
+	var pushedAuthorizationRequest PushedAuthorizationRequest
+	if err := json.Unmarshal([]byte(data), &pushedAuthorizationRequest); err != nil {
+		return nil, err
+	}
+	if pushedAuthorizationRequest.IsExpired() {
+		return nil, fmt.Errorf("request_uri has expired")
+	}
+	return &pushedAuthorizationRequest, nil
+}
@@ -0,0 +1,53 @@
+package token
+
+import (
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+// Refresh tokens issued for a session form a family.
+// Family starts with the session nonce and every rotation links
+// the rotated nonce to the nonce of refresh token issued in its place.
+
+// rotatedRefreshTokenStatePrefix is the state store prefix of links from rotated refresh token nonce
+// to the nonce issued in its place. State store does not evict entries before their expiry unlike session store,
+// hence reuse of rotated refresh token is detected even when many sessions are created
+const rotatedRefreshTokenStatePrefix = "rotated_refresh_token:"
+
+// rotatedRefreshTokenKey returns the state store key which links
+// rotated refresh token nonce to the nonce issued in its place
+func rotatedRefreshTokenKey(sessionKey, nonce string) string {
+	return rotatedRefreshTokenStatePrefix + sessionKey + ":" + nonce
+}
+
+// SetRotatedRefreshToken marks the refresh token with given nonce as rotated
+// and links it to the nonce of newly issued refresh token.
+// Link is kept till the rotated refresh token expires
+func SetRotatedRefreshToken(sessionKey, nonce, newNonce string, expiresAt int64) error {
+	return memorystore.Provider.SetStateWithExpiration(rotatedRefreshTokenKey(sessionKey, nonce), newNonce, expiresAt)
+}
+
+// IsRefreshTokenRotated returns true if the refresh token with given nonce was already rotated
+func IsRefreshTokenRotated(sessionKey, nonce string) bool {
+	nextNonce, err := memorystore.Provider.GetState(rotatedRefreshTokenKey(sessionKey, nonce))
+	return err == nil && nextNonce != ""
+}
+
+// RevokeRefreshTokenFamily revokes all the sessions issued in the family of
+// refresh token with given nonce, if the refresh token was already rotated.
+// Session of reused refresh token is revoked as well, as browser session
+// started with login shares the nonce of first refresh token of family.
+// It returns true if the refresh token was reused
+func RevokeRefreshTokenFamily(sessionKey, nonce string) bool {
+	nextNonce, err := memorystore.Provider.GetState(rotatedRefreshTokenKey(sessionKey, nonce))
+	if err != nil || nextNonce == "" {
+		return false
+	}
+	memorystore.Provider.DeleteUserSession(sessionKey, nonce)
+	visited := map[string]bool{nonce: true}
+	for nextNonce != "" && !visited[nextNonce] {
+		visited[nextNonce] = true
+		memorystore.Provider.DeleteUserSession(sessionKey, nextNonce)
+		nextNonce, _ = memorystore.Provider.GetState(rotatedRefreshTokenKey(sessionKey, nextNonce))
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/parsers"
+)
+
+const (
+	// RequestObjectMaxLifetime is the max lifetime in seconds of request object, exp should not be later than it
+	RequestObjectMaxLifetime = 3600
+
+	// requestObjectStatePrefix is the state store namespace used to track jti of used request objects
+	requestObjectStatePrefix = "request_object:"
+)
+
+// ValidateRequestObject validates the request object sent with authorization request (RFC 9101)
+// and returns the authorization request params contained in it.
+// Request object should be signed with one of the keys in jwks / jwks uri of client,
+// iss & client_id should be client id when present and aud should be issuer.
+// exp is bounded by RequestObjectMaxLifetime and every request object can be used only once,
+// jti of request object is tracked till it expires
+func ValidateRequestObject(gc *gin.Context, client *models.Client, requestObject string) (map[string]string, error) {
+	jws, err := jose.ParseSigned(requestObject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request object: %s", err.Error())
+	}
+	payload, err := verifyClientSignature(client, jws)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request object: %s", err.Error())
+	}
+
+	var claims clientJWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid request object: %s", err.Error())
+	}
+	if claims.Issuer != "" && claims.Issuer != client.ClientID {
+		return nil, fmt.Errorf("invalid request object: iss should be client id")
+	}
+	hostname := parsers.GetHost(gc)
+	isValidAudience := false
+	for _, aud := range claims.audiences() {
+		if aud == hostname {
+			isValidAudience = true
+			break
+		}
+	}
+	if !isValidAudience {
+		return nil, fmt.Errorf("invalid request object: aud should be issuer")
+	}
+	if claims.JTI == "" {
+		return nil, fmt.Errorf("invalid request object: jti is required")
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt == 0 || claims.ExpiresAt < now-ClientAssertionLeeway {
+		return nil, fmt.Errorf("invalid request object: request object has expired")
+	}
+	if claims.ExpiresAt > now+RequestObjectMaxLifetime+ClientAssertionLeeway {
+		return nil, fmt.Errorf("invalid request object: exp is too far in future")
+	}
+	if claims.NotBefore > now+ClientAssertionLeeway {
+		return nil, fmt.Errorf("invalid request object: request object is not valid yet")
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("invalid request object: %s", err.Error())
+	}
+	// claims other than string are converted to authorization request param format,
+	// e.g. max_age is number & claims request is json object
+	params := map[string]string{}
+	for key, value := range data {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			params[key] = v
+		case float64:
+			params[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			params[key] = strconv.FormatBool(v)
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid request object: %s", err.Error())
+			}
+			params[key] = string(b)
+		}
+	}
+	if clientID, ok := params["client_id"]; ok && clientID != client.ClientID {
+		return nil, fmt.Errorf("invalid request object: client_id does not match")
+	}
+
+	// request object can be used only once
+	jtiKey := requestObjectStatePrefix + client.ClientID + ":" + claims.JTI
+	if val, err := memorystore.Provider.GetState(jtiKey); err == nil && val != "" {
+		return nil, fmt.Errorf("invalid request object: request object is already used")
+	}
+	if err := memorystore.Provider.SetStateWithExpiration(jtiKey, claims.JTI, claims.ExpiresAt+ClientAssertionLeeway); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,79 @@
+package token
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+// revokedTokenStatePrefix is the state store prefix under which revoked tokens are kept till they expire.
+// State store does not evict entries before their expiry unlike session store,
+// hence revoked token cannot become valid again when many sessions are created
+const revokedTokenStatePrefix = "revoked_token:"
+
+var (
+	// ErrTokenClientMismatch is returned when token is revoked by a client to which it was not issued
+	ErrTokenClientMismatch = errors.New("token was not issued to the client")
+	// ErrUnsupportedTokenType is returned when token other than access token & refresh token is revoked
+	ErrUnsupportedTokenType = errors.New("token type is not supported for revocation")
+)
+
+// revokedTokenKey returns the hash of token used as state store key
+func revokedTokenKey(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// IsTokenRevoked returns true if token is in the list of revoked tokens
+func IsTokenRevoked(token string) bool {
+	value, err := memorystore.Provider.GetState(revokedTokenStatePrefix + revokedTokenKey(token))
+	return err == nil && value != ""
+}
+
+// RevokeToken revokes the access token or refresh token as per RFC 7009.
+// Session entries of token are deleted, hence access token also revokes refresh token of same session & vice versa.
+// Tokens are self-contained jwt, so token is also added to the list of revoked tokens till it expires.
+// When clientID is not empty, token should be issued to the client
+func RevokeToken(token, clientID string) error {
+	claims, err := ParseJWTToken(token)
+	if err != nil {
+		return err
+	}
+	tokenType, _ := claims["token_type"].(string)
+	if tokenType != constants.TokenTypeAccessToken && tokenType != constants.TokenTypeRefreshToken {
+		return ErrUnsupportedTokenType
+	}
+	// tokens issued via token exchange have client_id claim as audience is the target service
+	tokenClientID, _ := claims["client_id"].(string)
+	if tokenClientID == "" {
+		tokenClientID, _ = claims["aud"].(string)
+	}
+	if clientID != "" && tokenClientID != clientID {
+		return ErrTokenClientMismatch
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	subject, _ := claims["sub"].(string)
+	sessionKey := ""
+	if subject != "" {
+		sessionKey = GetUserIDFromSubject(subject)
+		if loginMethod, ok := claims["login_method"].(string); ok && loginMethod != "" {
+			sessionKey = loginMethod + ":" + sessionKey
+		}
+	} else if tokenClientID != "" {
+		// tokens issued via client_credentials grant are not bound to any user
+		sessionKey = constants.GrantTypeClientCredentials + ":" + tokenClientID
+	}
+	if sessionKey != "" && nonce != "" {
+		memorystore.Provider.DeleteUserSession(sessionKey, nonce)
+	}
+
+	expiresAt, _ := claims["exp"].(int64)
+	if expiresAt <= time.Now().Unix() {
+		return nil
+	}
+	return memorystore.Provider.SetStateWithExpiration(revokedTokenStatePrefix+revokedTokenKey(token), tokenType, expiresAt)
+}
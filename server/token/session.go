@@ -0,0 +1,106 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+const (
+	sessionStatePrefix = "session:"
+	// maxSessionNonces is the number of latest nonces kept for session,
+	// older nonces are already removed as session is rolled over on each use
+	maxSessionNonces = 100
+)
+
+// Session is the record of user session identified by session id (sid claim).
+// Session id is same for the session cookie, id tokens & logout tokens,
+// while nonce of session changes each time session is rolled over.
+// ClientIDs are the clients to which tokens were issued within session,
+// only these clients are notified when session is terminated
+type Session struct {
+	Nonces    []string `json:"nonces"`
+	ClientIDs []string `json:"client_ids"`
+	ExpiresAt int64    `json:"expires_at"`
+}
+
+func getSession(sessionID string) *Session {
+	session := &Session{}
+	data, err := memorystore.Provider.GetState(sessionStatePrefix + sessionID)
+	if err != nil || data == "" {
+		return session
+	}
+	if err := json.Unmarshal([]byte(data), session); err != nil {
+		log.Debug("Failed to parse session: ", err)
+	}
+	return session
+}
+
+func setSession(sessionID string, session *Session, expiresAt int64) error {
+	if expiresAt > session.ExpiresAt {
+		session.ExpiresAt = expiresAt
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return memorystore.Provider.SetStateWithExpiration(sessionStatePrefix+sessionID, string(data), session.ExpiresAt)
+}
+
+// AddSessionNonce records the nonce with which session is rolled over
+func AddSessionNonce(sessionID, nonce string, expiresAt int64) error {
+	if sessionID == "" || nonce == "" {
+		return nil
+	}
+	session := getSession(sessionID)
+	if !utils.StringSliceContains(session.Nonces, nonce) {
+		session.Nonces = append(session.Nonces, nonce)
+	}
+	if len(session.Nonces) > maxSessionNonces {
+		session.Nonces = session.Nonces[len(session.Nonces)-maxSessionNonces:]
+	}
+	return setSession(sessionID, session, expiresAt)
+}
+
+// AddSessionClient records the client to which tokens are issued within session
+func AddSessionClient(sessionID, clientID string, expiresAt int64) error {
+	if sessionID == "" || clientID == "" {
+		return nil
+	}
+	session := getSession(sessionID)
+	if utils.StringSliceContains(session.ClientIDs, clientID) && session.ExpiresAt >= expiresAt {
+		return nil
+	}
+	if !utils.StringSliceContains(session.ClientIDs, clientID) {
+		session.ClientIDs = append(session.ClientIDs, clientID)
+	}
+	return setSession(sessionID, session, expiresAt)
+}
+
+// EndSession removes the user session entries of all the nonces of session along with session record,
+// and returns the clients to which tokens were issued within session, so that they can be notified about logout
+func EndSession(ctx context.Context, sessionKey, sessionID string) []*models.Client {
+	if sessionID == "" {
+		return nil
+	}
+	session := getSession(sessionID)
+	memorystore.Provider.RemoveState(sessionStatePrefix + sessionID)
+	for _, nonce := range session.Nonces {
+		memorystore.Provider.DeleteUserSession(sessionKey, nonce)
+	}
+	clients := []*models.Client{}
+	for _, clientID := range session.ClientIDs {
+		client, err := utils.GetClientByClientID(ctx, clientID)
+		if err != nil || client == nil {
+			log.Debug("Failed to get client of session: ", clientID)
+			continue
+		}
+		clients = append(clients, client)
+	}
+	return clients
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+)
+
+// CreateExchangedAccessToken util to create access token via token exchange (RFC 8693).
+// Token is issued for the user of subject token & given audience with downscoped scopes,
+// act claim is set when token is issued to an actor acting on behalf of user
+func CreateExchangedAccessToken(client *models.Client, user *models.User, roles, scopes []string, audience, hostName, nonce, loginMethod string, act map[string]interface{}, dpopJKT string) (string, int64, error) {
+	expiryBound, err := getAccessTokenExpiryBound(client)
+	if err != nil {
+		return "", 0, err
+	}
+	expiresAt := time.Now().Add(expiryBound).Unix()
+	subject, err := GetSubject(client, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	customClaims := jwt.MapClaims{
+		"iss":           hostName,
+		"aud":           audience,
+		"client_id":     client.ClientID,
+		"nonce":         nonce,
+		"sub":           subject,
+		"exp":           expiresAt,
+		"iat":           time.Now().Unix(),
+		"token_type":    constants.TokenTypeAccessToken,
+		"scope":         scopes,
+		"roles":         roles,
+		"login_method":  loginMethod,
+		"allowed_roles": strings.Split(user.Roles, ","),
+	}
+	if act != nil {
+		customClaims["act"] = act
+	}
+	if dpopJKT != "" {
+		customClaims["cnf"] = map[string]string{"jkt": dpopJKT}
+	}
+	token, err := SignJWTToken(customClaims)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiresAt, nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+)
+
+// IsDefaultClientID returns true if given client id is the CLIENT_ID configured via env
+func IsDefaultClientID(clientID string) bool {
+	defaultClientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+	if err != nil || defaultClientID == "" {
+		return false
+	}
+	return defaultClientID == clientID
+}
+
+// GetDefaultClient returns the client configured via CLIENT_ID & CLIENT_SECRET env.
+// It is used by authorizer dashboard, login app and existing integrations,
+// hence all the grant types except token exchange are allowed and redirect uris are validated against ALLOWED_ORIGINS.
+// Token exchange is allowed only for registered clients as it is controlled by client policy
+func GetDefaultClient() (*models.Client, error) {
+	clientID, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientID)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Client{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		GrantTypes:   strings.Join([]string{constants.GrantTypeAuthorizationCode, constants.GrantTypeRefreshToken, constants.GrantTypeClientCredentials, constants.GrantTypeDeviceCode}, ","),
+	}, nil
+}
+
+// GetClientByClientID returns the oauth client for given client id.
+// CLIENT_ID configured via env is resolved as default client,
+// rest of the clients are resolved from the client registry in database
+func GetClientByClientID(ctx context.Context, clientID string) (*models.Client, error) {
+	if IsDefaultClientID(clientID) {
+		return GetDefaultClient()
+	}
+	return db.Provider.GetClientByClientID(ctx, clientID)
+}
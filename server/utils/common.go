@@ -2,6 +2,7 @@ package utils
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/authorizerdev/authorizer/server/constants"
 	"github.com/authorizerdev/authorizer/server/memorystore"
@@ -80,6 +81,20 @@ func GetOrganization() map[string]interface{} {
 	return organization
 }
 
+// GetDurationStoreEnvVariable returns the duration env variable,
+// defaultValue is returned when env variable is not set or is not a valid duration
+func GetDurationStoreEnvVariable(key string, defaultValue time.Duration) time.Duration {
+	val, err := memorystore.Provider.GetStringStoreEnvVariable(key)
+	if err != nil || val == "" {
+		return defaultValue
+	}
+	duration, err := ParseDurationInSeconds(val)
+	if err != nil {
+		return defaultValue
+	}
+	return duration
+}
+
 // GetForgotPasswordURL to get url for given token and hostname
 func GetForgotPasswordURL(token, redirectURI string) string {
 	verificationURL := redirectURI + "?token=" + token
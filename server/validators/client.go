@@ -0,0 +1,246 @@
+package validators
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/crypto"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/memorystore"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IsValidClientSecret validates the client secret sent by client for authentication.
+// Clients using private_key_jwt, client_secret_jwt and public clients cannot authenticate with client secret
+func IsValidClientSecret(client *models.Client, clientSecret string) bool {
+	if client == nil || client.IsClientAssertionRequired() || client.IsPublic() {
+		return false
+	}
+	return IsMatchingClientSecret(client, clientSecret)
+}
+
+// IsMatchingClientSecret validates if given secret is the secret of client.
+// Secret of default client is stored as plain text in env, secret of clients using client_secret_jwt
+// is stored encrypted, while secret of other registered clients is stored as hash
+func IsMatchingClientSecret(client *models.Client, clientSecret string) bool {
+	if client == nil || clientSecret == "" || client.ClientSecret == "" {
+		return false
+	}
+	if utils.IsDefaultClientID(client.ClientID) {
+		return subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 1
+	}
+	if client.GetTokenEndpointAuthMethod() == constants.TokenEndpointAuthMethodClientSecretJWT {
+		decryptedClientSecret, err := crypto.DecryptClientSecret(client.ClientSecret)
+		return err == nil && subtle.ConstantTimeCompare([]byte(decryptedClientSecret), []byte(clientSecret)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(client.ClientSecret), []byte(clientSecret)) == nil
+}
+
+// IsValidClientRedirectURI validates redirect uri for given client.
+// Default client validates it against ALLOWED_ORIGINS,
+// registered clients should have exact redirect uri registered
+func IsValidClientRedirectURI(client *models.Client, redirectURI string) bool {
+	if client == nil {
+		return false
+	}
+	if utils.IsDefaultClientID(client.ClientID) {
+		return IsValidOrigin(redirectURI)
+	}
+	return utils.StringSliceContains(client.GetRedirectURIs(), redirectURI)
+}
+
+// IsValidClientPostLogoutRedirectURI validates post logout redirect uri for given client.
+// Default client validates it against ALLOWED_ORIGINS,
+// registered clients should have exact post logout redirect uri registered
+func IsValidClientPostLogoutRedirectURI(client *models.Client, postLogoutRedirectURI string) bool {
+	if client == nil {
+		return false
+	}
+	if utils.IsDefaultClientID(client.ClientID) {
+		return IsValidOrigin(postLogoutRedirectURI)
+	}
+	return utils.StringSliceContains(client.GetPostLogoutRedirectURIs(), postLogoutRedirectURI)
+}
+
+// IsValidClientGrantType validates if given grant type is allowed for client
+func IsValidClientGrantType(client *models.Client, grantType string) bool {
+	if client == nil {
+		return false
+	}
+	return utils.StringSliceContains(client.GetGrantTypes(), grantType)
+}
+
+// IsValidClientTokenExchangeAudience validates if client is allowed to exchange tokens for given audience.
+// Client can always exchange tokens for itself, other audiences should be allowed by client policy
+func IsValidClientTokenExchangeAudience(client *models.Client, audience string) bool {
+	if client == nil || audience == "" {
+		return false
+	}
+	if audience == client.ClientID {
+		return true
+	}
+	return utils.StringSliceContains(client.GetTokenExchangeAudiences(), audience)
+}
+
+// IsValidClientScope validates if all the requested scopes are allowed for client.
+// Clients without scopes configured are allowed to request any scope
+func IsValidClientScope(client *models.Client, scopes []string) bool {
+	if client == nil {
+		return false
+	}
+	allowedScopes := client.GetScopes()
+	if len(allowedScopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if !utils.StringSliceContains(allowedScopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidGrantType validates if given grant type is supported by authorizer
+func IsValidGrantType(grantType string) bool {
+	switch grantType {
+	case constants.GrantTypeAuthorizationCode, constants.GrantTypeRefreshToken, constants.GrantTypeClientCredentials, constants.GrantTypeDeviceCode, constants.GrantTypeTokenExchange:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidTokenEndpointAuthMethod validates if given client authentication method is supported by token endpoint,
+// none is used by public clients
+func IsValidTokenEndpointAuthMethod(method string) bool {
+	switch method {
+	case constants.TokenEndpointAuthMethodClientSecretBasic, constants.TokenEndpointAuthMethodClientSecretPost, constants.TokenEndpointAuthMethodClientSecretJWT, constants.TokenEndpointAuthMethodPrivateKeyJWT, constants.TokenEndpointAuthMethodNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidJWKS validates if given json web key set has at least one key
+// and all the keys are valid public keys
+func IsValidJWKS(jwks string) bool {
+	var keySet jose.JSONWebKeySet
+	if err := json.Unmarshal([]byte(jwks), &keySet); err != nil || len(keySet.Keys) == 0 {
+		return false
+	}
+	for _, key := range keySet.Keys {
+		if !key.Valid() || !key.IsPublic() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidJWKSURI validates if given uri can be used for fetching json web key set of client, only https uris are allowed
+func IsValidJWKSURI(jwksURI string) bool {
+	u, err := url.Parse(jwksURI)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != ""
+}
+
+// IsValidSubjectType validates if given subject type is supported by authorizer
+func IsValidSubjectType(subjectType string) bool {
+	return subjectType == constants.SubjectTypePublic || subjectType == constants.SubjectTypePairwise
+}
+
+// IsValidClientSectorIdentifier validates the sector identifier used for pairwise subject of client.
+// Redirect uris of client should have single host unless sector identifier uri is configured.
+// Sector identifier uri should be https uri which returns json array containing all the redirect uris of client
+func IsValidClientSectorIdentifier(client *models.Client) bool {
+	if client.SectorIdentifierURI == "" {
+		if client.GetSubjectType() != constants.SubjectTypePairwise {
+			return true
+		}
+		hosts := map[string]bool{}
+		for _, redirectURI := range client.GetRedirectURIs() {
+			if u, err := url.Parse(redirectURI); err == nil {
+				hosts[u.Hostname()] = true
+			}
+		}
+		return len(hosts) <= 1
+	}
+
+	u, err := url.Parse(client.SectorIdentifierURI)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return false
+	}
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	res, err := httpClient.Get(client.SectorIdentifierURI)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false
+	}
+	var redirectURIs []string
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&redirectURIs); err != nil {
+		return false
+	}
+	for _, redirectURI := range client.GetRedirectURIs() {
+		if !utils.StringSliceContains(redirectURIs, redirectURI) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidRedirectURI validates if given uri can be registered as client redirect uri.
+// It should be an absolute uri without fragment
+func IsValidRedirectURI(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != "" && u.Fragment == ""
+}
+
+// IsValidClientRegistrationAccessToken validates the registration access token
+// issued for dynamically registered client
+func IsValidClientRegistrationAccessToken(client *models.Client, registrationAccessToken string) bool {
+	if client == nil || registrationAccessToken == "" || client.RegistrationAccessToken == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(client.RegistrationAccessToken), []byte(registrationAccessToken)) == nil
+}
+
+// IsClientRegistrationEnabled returns true if dynamic client registration is enabled,
+// registration is enabled only when CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN is set
+func IsClientRegistrationEnabled() bool {
+	initialAccessTokens, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientRegistrationInitialAccessToken)
+	return err == nil && strings.TrimSpace(initialAccessTokens) != ""
+}
+
+// IsValidClientRegistrationInitialAccessToken validates the initial access token
+// against CLIENT_REGISTRATION_INITIAL_ACCESS_TOKEN. It is always invalid when env is not set
+func IsValidClientRegistrationInitialAccessToken(initialAccessToken string) bool {
+	initialAccessTokens, err := memorystore.Provider.GetStringStoreEnvVariable(constants.EnvKeyClientRegistrationInitialAccessToken)
+	if err != nil || initialAccessToken == "" {
+		return false
+	}
+	for _, t := range strings.Split(initialAccessTokens, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(initialAccessToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+var identityProviderNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// reservedIdentityProviderNames are the auth methods of authorizer,
+// identity provider cannot use them as its name is used as login method
+var reservedIdentityProviderNames = []string{
+	constants.AuthRecipeMethodBasicAuth,
+	constants.AuthRecipeMethodMobileBasicAuth,
+	constants.AuthRecipeMethodMagicLinkLogin,
+	constants.AuthRecipeMethodMobileOTP,
+	constants.AuthRecipeMethodGoogle,
+	constants.AuthRecipeMethodGithub,
+	constants.AuthRecipeMethodFacebook,
+	constants.AuthRecipeMethodLinkedIn,
+	constants.AuthRecipeMethodApple,
+	constants.AuthRecipeMethodDiscord,
+	constants.AuthRecipeMethodTwitter,
+	constants.AuthRecipeMethodMicrosoft,
+	constants.AuthRecipeMethodTwitch,
+	constants.AuthRecipeMethodRoblox,
+	constants.AuthRecipeMethodLDAP,
+}
+
+// IsValidIdentityProviderName validates if given name can be used for identity provider.
+// Name is used in /oauth_login/:provider path, hence only lower case letters, digits, _ & - are allowed
+func IsValidIdentityProviderName(name string) bool {
+	return identityProviderNameRegex.MatchString(name) && !utils.StringSliceContains(reservedIdentityProviderNames, name)
+}
+
+// IsValidIdentityProviderURL validates if given url can be used as endpoint of identity provider,
+// only https is allowed as user info & metadata fetched from it are trusted
+func IsValidIdentityProviderURL(identityProviderURL string) bool {
+	u, err := url.Parse(identityProviderURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != ""
+}
+
+// IsValidIdentityProviderClaimMapping validates if claim mapping only maps the supported user fields
+func IsValidIdentityProviderClaimMapping(claimMapping map[string]interface{}) bool {
+	for field, claim := range claimMapping {
+		if !utils.StringSliceContains(models.IdentityProviderClaimMappingFields, field) {
+			return false
+		}
+		if claimName, ok := claim.(string); !ok || claimName == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package validators
+
+import (
+	"net/url"
+
+	"github.com/authorizerdev/authorizer/server/constants"
+	"github.com/authorizerdev/authorizer/server/db/models"
+	"github.com/authorizerdev/authorizer/server/utils"
+)
+
+// IsValidSAMLNameIDFormat validates if given name id format is supported
+func IsValidSAMLNameIDFormat(nameIDFormat string) bool {
+	return nameIDFormat == constants.SAMLNameIDFormatEmail || nameIDFormat == constants.SAMLNameIDFormatPersistent
+}
+
+// IsValidSAMLACSURL validates if given url can be used as assertion consumer service url of service provider
+func IsValidSAMLACSURL(acsURL string) bool {
+	u, err := url.Parse(acsURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" && u.Fragment == ""
+}
+
+// IsValidSAMLAttributeMapping validates if attribute mapping only maps the supported user fields
+func IsValidSAMLAttributeMapping(attributeMapping map[string]interface{}) bool {
+	for attribute, field := range attributeMapping {
+		if attribute == "" {
+			return false
+		}
+		if fieldName, ok := field.(string); !ok || !utils.StringSliceContains(models.SAMLAttributeMappingFields, fieldName) {
+			return false
+		}
+	}
+	return true
+}